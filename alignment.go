@@ -0,0 +1,123 @@
+package readgo
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"runtime"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// fieldAlignmentRule reports struct types whose field order wastes at least
+// ValidatorConfig.MinPaddingWaste bytes of padding compared with the best
+// order, which the finding suggests
+type fieldAlignmentRule struct {
+	min int
+}
+
+// Name returns the rule name
+func (r *fieldAlignmentRule) Name() string {
+	return "field_alignment"
+}
+
+// configure reads the padding threshold
+func (r *fieldAlignmentRule) configure(cfg *ValidatorConfig) {
+	r.min = defaultMinPaddingWaste
+	if cfg != nil {
+		r.min = limit(cfg.MinPaddingWaste, defaultMinPaddingWaste)
+	}
+}
+
+// Check reports the struct types of the package that could be smaller
+func (r *fieldAlignmentRule) Check(ctx context.Context, pkg *packages.Package) []Finding {
+	if pkg.TypesInfo == nil {
+		return nil
+	}
+	sizes := pkg.TypesSizes
+	if sizes == nil {
+		sizes = types.SizesFor("gc", runtime.GOARCH)
+	}
+
+	var findings []Finding
+	for _, file := range pkg.Syntax {
+		if ctx.Err() != nil {
+			break
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			spec, ok := n.(*ast.TypeSpec)
+			if !ok || spec.TypeParams != nil {
+				return true
+			}
+			if _, ok := spec.Type.(*ast.StructType); !ok {
+				return true
+			}
+			obj := pkg.TypesInfo.Defs[spec.Name]
+			if obj == nil {
+				return true
+			}
+			st, ok := obj.Type().Underlying().(*types.Struct)
+			if !ok || st.NumFields() < 2 {
+				return true
+			}
+
+			order := optimalFieldOrder(st, sizes)
+			size, best := sizes.Sizeof(st), sizes.Sizeof(reorderFields(st, order))
+			if size-best < int64(r.min) {
+				return true
+			}
+
+			names := make([]string, len(order))
+			for i, f := range order {
+				names[i] = st.Field(f).Name()
+			}
+			pos := pkg.Fset.Position(spec.Name.Pos())
+			findings = append(findings, Finding{
+				Message: fmt.Sprintf("struct %s is %d bytes but would be %d with its fields ordered %s",
+					spec.Name.Name, size, best, strings.Join(names, ", ")),
+				File:   pos.Filename,
+				Line:   pos.Line,
+				Column: pos.Column,
+			})
+			return true
+		})
+	}
+	return findings
+}
+
+// optimalFieldOrder returns the indices of the fields of st in an order that
+// minimizes padding: zero-sized fields first, since a trailing one is
+// padded, then by decreasing alignment and size, otherwise keeping the
+// declared order
+func optimalFieldOrder(st *types.Struct, sizes types.Sizes) []int {
+	order := make([]int, st.NumFields())
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := st.Field(order[i]).Type(), st.Field(order[j]).Type()
+		sizeA, sizeB := sizes.Sizeof(a), sizes.Sizeof(b)
+		if (sizeA == 0) != (sizeB == 0) {
+			return sizeA == 0
+		}
+		if alignA, alignB := sizes.Alignof(a), sizes.Alignof(b); alignA != alignB {
+			return alignA > alignB
+		}
+		return sizeA > sizeB
+	})
+	return order
+}
+
+// reorderFields returns a struct with the fields of st in the given order
+func reorderFields(st *types.Struct, order []int) *types.Struct {
+	fields := make([]*types.Var, len(order))
+	tags := make([]string, len(order))
+	for i, f := range order {
+		fields[i] = st.Field(f)
+		tags[i] = st.Tag(f)
+	}
+	return types.NewStruct(fields, tags)
+}
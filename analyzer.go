@@ -2,24 +2,37 @@ package readgo
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
 	"go/types"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/tools/go/packages"
 )
 
 // DefaultAnalyzer implements the CodeAnalyzer interface
 type DefaultAnalyzer struct {
-	workDir string
-	cache   *Cache
-	reader  SourceReader
+	workDir    string
+	cache      *Cache
+	stdlibTTL  time.Duration
+	missingTTL time.Duration // TTL of lookups that found nothing
+	disk       *diskCache
+	reader     SourceReader
+	extractors []Extractor
+	logger     *slog.Logger
+	tracer     trace.Tracer
+	observer   Observer
 }
 
 // NewAnalyzer creates a new DefaultAnalyzer instance
@@ -29,15 +42,59 @@ func NewAnalyzer(opts ...Option) *DefaultAnalyzer {
 		opt(options)
 	}
 
+	cache := options.Cache
+	if cache == nil {
+		cache = NewCache(options.CacheTTL).WithMaxEntries(options.MaxCacheSize).WithMaxBytes(options.MaxCacheBytes)
+	}
+	logger := loggerOrDiscard(options.Logger)
 	return &DefaultAnalyzer{
-		workDir: options.WorkDir,
-		cache:   NewCache(options.CacheTTL),
-		reader:  NewSourceReader(options.WorkDir),
+		workDir:    options.WorkDir,
+		cache:      cache,
+		stdlibTTL:  options.StdlibCacheTTL,
+		missingTTL: options.NotFoundCacheTTL,
+		disk:       newDiskCache(options.DiskCacheDir, logger),
+		reader:     &DefaultReader{workDir: options.WorkDir, logger: options.Logger, tp: options.TracerProvider},
+		extractors: options.Extractors,
+		logger:     logger,
+		tracer:     newTracer(options.TracerProvider),
+		observer:   options.Observer,
 	}
 }
 
+// RegisterExtractor adds a custom extractor to the analyzer.
+// It is not safe to call concurrently with analysis.
+func (a *DefaultAnalyzer) RegisterExtractor(e Extractor) {
+	a.extractors = append(a.extractors, e)
+}
+
+// runExtractors runs all registered extractors over pkg and stores the
+// entities they return in result.Extensions
+func (a *DefaultAnalyzer) runExtractors(ctx context.Context, pkg *packages.Package, result *AnalysisResult) error {
+	for _, e := range a.extractors {
+		entities, err := e.Extract(ctx, pkg)
+		if err != nil {
+			return &AnalysisError{
+				Op:      fmt.Sprintf("run extractor %s", e.Name()),
+				Path:    pkg.PkgPath,
+				Wrapped: err,
+			}
+		}
+		if len(entities) == 0 {
+			continue
+		}
+		if result.Extensions == nil {
+			result.Extensions = make(map[string][]Entity)
+		}
+		result.Extensions[e.Name()] = append(result.Extensions[e.Name()], entities...)
+	}
+	return nil
+}
+
 // AnalyzeFile analyzes a specific Go source file
-func (a *DefaultAnalyzer) AnalyzeFile(ctx context.Context, filePath string) (*AnalysisResult, error) {
+func (a *DefaultAnalyzer) AnalyzeFile(ctx context.Context, filePath string) (result *AnalysisResult, err error) {
+	start := time.Now()
+	defer func() { a.observeAnalysis("file", start, err) }()
+
 	// Read file content
 	content, err := a.reader.ReadSourceFile(ctx, filePath, ReadOptions{
 		IncludeComments: true,
@@ -54,14 +111,16 @@ func (a *DefaultAnalyzer) AnalyzeFile(ctx context.Context, filePath string) (*An
 		return nil, &AnalysisError{Op: "parse file", Path: filePath, Wrapped: err}
 	}
 
-	result := &AnalysisResult{
-		Name:       filepath.Base(filePath),
-		Path:       filePath,
-		StartTime:  time.Now().Format(time.RFC3339),
-		AnalyzedAt: time.Now(),
-		Types:      make([]TypeInfo, 0),
-		Functions:  make([]FunctionInfo, 0),
-		Imports:    make([]string, 0),
+	result = &AnalysisResult{
+		Name:          filepath.Base(filePath),
+		Path:          filePath,
+		StartTime:     time.Now().Format(time.RFC3339),
+		AnalyzedAt:    time.Now(),
+		SchemaVersion: SchemaVersion,
+		ToolVersion:   Version(),
+		Types:         make([]TypeInfo, 0),
+		Functions:     make([]FunctionInfo, 0),
+		Imports:       make([]string, 0),
 	}
 
 	// Collect imports
@@ -69,6 +128,12 @@ func (a *DefaultAnalyzer) AnalyzeFile(ctx context.Context, filePath string) (*An
 		path := strings.Trim(imp.Path.Value, `"`)
 		result.Imports = append(result.Imports, path)
 	}
+	dir := filepath.Dir(filePath)
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(a.workDir, dir)
+	}
+	result.Dependencies = dependencies(result.Imports, modulePath(dir))
+	result.Module = readModuleInfo(findGoMod(dir))
 
 	// Analyze declarations
 	for _, decl := range file.Decls {
@@ -80,6 +145,7 @@ func (a *DefaultAnalyzer) AnalyzeFile(ctx context.Context, filePath string) (*An
 						info := TypeInfo{
 							Name:       typeSpec.Name.Name,
 							Package:    file.Name.Name,
+							Kind:       syntaxTypeKind(typeSpec),
 							IsExported: typeSpec.Name.IsExported(),
 						}
 
@@ -116,11 +182,7 @@ func (a *DefaultAnalyzer) AnalyzeFile(ctx context.Context, filePath string) (*An
 			}
 		case *ast.FuncDecl:
 			if d.Name != nil {
-				result.Functions = append(result.Functions, FunctionInfo{
-					Name:       d.Name.Name,
-					Package:    file.Name.Name,
-					IsExported: d.Name.IsExported(),
-				})
+				result.Functions = append(result.Functions, a.functionInfo(fset, file.Name.Name, d))
 			}
 		}
 	}
@@ -130,18 +192,18 @@ func (a *DefaultAnalyzer) AnalyzeFile(ctx context.Context, filePath string) (*An
 
 // FindType finds a type in the given package
 func (a *DefaultAnalyzer) FindType(ctx context.Context, pkgPath, typeName string) (result *TypeInfo, err error) {
+	var sources []string // Files the result depends on
 	if a.cache != nil {
-		key := TypeCacheKey{
-			Package:  pkgPath,
-			TypeName: typeName,
-		}
-		if cached, ok := a.cache.GetType(key); ok {
+		key := a.typeKey(pkgPath, typeName, "")
+		cached, diskKey, ok := a.lookupType(ctx, key)
+		if ok {
+			if cached == nil {
+				return nil, notFoundError(key)
+			}
 			return cached, nil
 		}
 		defer func() {
-			if err == nil && result != nil {
-				a.cache.SetType(key, result)
-			}
+			a.cacheLookup(key, diskKey, result, err, sources)
 		}()
 	}
 
@@ -153,21 +215,7 @@ func (a *DefaultAnalyzer) FindType(ctx context.Context, pkgPath, typeName string
 	}
 
 	// Load the package
-	cfg := &packages.Config{
-		Mode: packages.NeedName |
-			packages.NeedFiles |
-			packages.NeedCompiledGoFiles |
-			packages.NeedImports |
-			packages.NeedTypes |
-			packages.NeedTypesSizes |
-			packages.NeedSyntax |
-			packages.NeedTypesInfo |
-			packages.NeedDeps,
-		Dir: a.workDir,
-		Env: append(os.Environ(), "GO111MODULE=on"),
-	}
-
-	pkgs, err := packages.Load(cfg, pkgPath)
+	pkgs, err := a.load(ctx, a.workDir, pkgPath)
 	if err != nil {
 		return nil, &TypeLookupError{
 			TypeName: typeName,
@@ -185,6 +233,7 @@ func (a *DefaultAnalyzer) FindType(ctx context.Context, pkgPath, typeName string
 	}
 
 	pkg := pkgs[0]
+	sources = packageSources(pkg)
 
 	// First try to find in the package's scope
 	obj := pkg.Types.Scope().Lookup(typeName)
@@ -202,6 +251,7 @@ func (a *DefaultAnalyzer) FindType(ctx context.Context, pkgPath, typeName string
 			Package:    pkgPath,
 			IsExported: typeObj.Exported(),
 			Type:       typeObj.Type().Underlying().String(),
+			Kind:       typeKind(typeObj),
 		}
 		return result, nil
 	}
@@ -213,11 +263,13 @@ func (a *DefaultAnalyzer) FindType(ctx context.Context, pkgPath, typeName string
 			if !ok {
 				continue
 			}
+			sources = packageSources(pkg, imp)
 			result = &TypeInfo{
 				Name:       typeObj.Name(),
 				Package:    importPath,
 				IsExported: typeObj.Exported(),
 				Type:       typeObj.Type().Underlying().String(),
+				Kind:       typeKind(typeObj),
 			}
 			return result, nil
 		}
@@ -232,19 +284,18 @@ func (a *DefaultAnalyzer) FindType(ctx context.Context, pkgPath, typeName string
 
 // FindInterface finds an interface in the given package
 func (a *DefaultAnalyzer) FindInterface(ctx context.Context, pkgPath, interfaceName string) (result *TypeInfo, err error) {
+	var sources []string // Files the result depends on
 	if a.cache != nil {
-		key := TypeCacheKey{
-			Package:  pkgPath,
-			TypeName: interfaceName,
-			Kind:     "interface",
-		}
-		if cached, ok := a.cache.GetType(key); ok {
+		key := a.typeKey(pkgPath, interfaceName, "interface")
+		cached, diskKey, ok := a.lookupType(ctx, key)
+		if ok {
+			if cached == nil {
+				return nil, notFoundError(key)
+			}
 			return cached, nil
 		}
 		defer func() {
-			if err == nil && result != nil {
-				a.cache.SetType(key, result)
-			}
+			a.cacheLookup(key, diskKey, result, err, sources)
 		}()
 	}
 
@@ -257,21 +308,7 @@ func (a *DefaultAnalyzer) FindInterface(ctx context.Context, pkgPath, interfaceN
 	}
 
 	// Load the package
-	cfg := &packages.Config{
-		Mode: packages.NeedName |
-			packages.NeedFiles |
-			packages.NeedCompiledGoFiles |
-			packages.NeedImports |
-			packages.NeedTypes |
-			packages.NeedTypesSizes |
-			packages.NeedSyntax |
-			packages.NeedTypesInfo |
-			packages.NeedDeps,
-		Dir: a.workDir,
-		Env: append(os.Environ(), "GO111MODULE=on"),
-	}
-
-	pkgs, err := packages.Load(cfg, pkgPath)
+	pkgs, err := a.load(ctx, a.workDir, pkgPath)
 	if err != nil {
 		return nil, &TypeLookupError{
 			TypeName: interfaceName,
@@ -291,6 +328,7 @@ func (a *DefaultAnalyzer) FindInterface(ctx context.Context, pkgPath, interfaceN
 	}
 
 	pkg := pkgs[0]
+	sources = packageSources(pkg)
 
 	// First try to find in the package's scope
 	obj := pkg.Types.Scope().Lookup(interfaceName)
@@ -317,6 +355,7 @@ func (a *DefaultAnalyzer) FindInterface(ctx context.Context, pkgPath, interfaceN
 			Package:    pkgPath,
 			IsExported: typeObj.Exported(),
 			Type:       typeObj.Type().Underlying().String(),
+			Kind:       typeKind(typeObj),
 		}
 		return result, nil
 	}
@@ -331,11 +370,13 @@ func (a *DefaultAnalyzer) FindInterface(ctx context.Context, pkgPath, interfaceN
 			if _, ok := typeObj.Type().Underlying().(*types.Interface); !ok {
 				continue
 			}
+			sources = packageSources(pkg, imp)
 			result = &TypeInfo{
 				Name:       typeObj.Name(),
 				Package:    importPath,
 				IsExported: typeObj.Exported(),
 				Type:       typeObj.Type().Underlying().String(),
+				Kind:       typeKind(typeObj),
 			}
 			return result, nil
 		}
@@ -349,8 +390,373 @@ func (a *DefaultAnalyzer) FindInterface(ctx context.Context, pkgPath, interfaceN
 	}
 }
 
+// FindFunction finds a package-level function in the given package, or in
+// one of its imports. The result's Type is the function signature and its
+// Kind is TypeKindFunc.
+func (a *DefaultAnalyzer) FindFunction(ctx context.Context, pkgPath, funcName string) (result *TypeInfo, err error) {
+	var sources []string // Files the result depends on
+	if a.cache != nil {
+		key := a.typeKey(pkgPath, funcName, "function")
+		cached, diskKey, ok := a.lookupType(ctx, key)
+		if ok {
+			if cached == nil {
+				return nil, notFoundError(key)
+			}
+			return cached, nil
+		}
+		defer func() {
+			a.cacheLookup(key, diskKey, result, err, sources)
+		}()
+	}
+
+	if funcName == "" {
+		return nil, &TypeLookupError{
+			Package: pkgPath,
+			Kind:    "function",
+			Wrapped: ErrInvalidInput,
+		}
+	}
+
+	// Load the package
+	pkgs, err := a.load(ctx, a.workDir, pkgPath)
+	if err != nil {
+		return nil, &TypeLookupError{
+			TypeName: funcName,
+			Package:  pkgPath,
+			Kind:     "function",
+			Wrapped:  err,
+		}
+	}
+
+	if len(pkgs) == 0 {
+		return nil, &TypeLookupError{
+			TypeName: funcName,
+			Package:  pkgPath,
+			Kind:     "function",
+			Wrapped:  fmt.Errorf("no packages found"),
+		}
+	}
+
+	pkg := pkgs[0]
+	sources = packageSources(pkg)
+
+	// First try to find in the package's scope
+	if obj := pkg.Types.Scope().Lookup(funcName); obj != nil {
+		fn, ok := obj.(*types.Func)
+		if !ok {
+			return nil, &TypeLookupError{
+				TypeName: funcName,
+				Package:  pkgPath,
+				Kind:     "function",
+				Wrapped:  fmt.Errorf("symbol is not a function"),
+			}
+		}
+		result = &TypeInfo{
+			Name:       fn.Name(),
+			Package:    pkgPath,
+			IsExported: fn.Exported(),
+			Type:       fn.Type().String(),
+			Kind:       TypeKindFunc,
+		}
+		return result, nil
+	}
+
+	// If not found, try to find in imported packages
+	for importPath, imp := range pkg.Imports {
+		if fn, ok := imp.Types.Scope().Lookup(funcName).(*types.Func); ok {
+			sources = packageSources(pkg, imp)
+			result = &TypeInfo{
+				Name:       fn.Name(),
+				Package:    importPath,
+				IsExported: fn.Exported(),
+				Type:       fn.Type().String(),
+				Kind:       TypeKindFunc,
+			}
+			return result, nil
+		}
+	}
+
+	return nil, &TypeLookupError{
+		TypeName: funcName,
+		Package:  pkgPath,
+		Kind:     "function",
+		Wrapped:  ErrNotFound,
+	}
+}
+
+// analyzerLoadMode is the information loaded for every analyzed package
+const analyzerLoadMode = packages.NeedName |
+	packages.NeedFiles |
+	packages.NeedCompiledGoFiles |
+	packages.NeedImports |
+	packages.NeedTypes |
+	packages.NeedTypesSizes |
+	packages.NeedSyntax |
+	packages.NeedTypesInfo |
+	packages.NeedDeps |
+	packages.NeedModule
+
+// loadPackages loads packages, replaced in tests
+var loadPackages = packages.Load
+
+// load loads the packages matching pattern from dir, reusing those loaded
+// by earlier calls while their sources are unchanged. Concurrent calls for
+// the same packages, also from other analyzers sharing the cache, wait for
+// a single load.
+func (a *DefaultAnalyzer) load(ctx context.Context, dir, pattern string) ([]*packages.Package, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	key := PackageCacheKey{Dir: absDir, Pattern: pattern}
+	return a.cache.loadPackages(ctx, key, func(ctx context.Context) ([]*packages.Package, []string, error) {
+		cfg := &packages.Config{
+			Context: ctx,
+			Mode:    analyzerLoadMode,
+			Dir:     dir,
+			Env:     append(os.Environ(), "GO111MODULE=on"),
+		}
+		a.logger.DebugContext(ctx, "loading packages", "dir", absDir, "pattern", pattern)
+		start := time.Now()
+		pkgs, err := loadTraced(a.tracer, a.observer, loadPackages, cfg, pattern)
+		if err != nil {
+			a.logger.DebugContext(ctx, "loading packages failed", "dir", absDir, "pattern", pattern, "error", err)
+			return nil, nil, err
+		}
+		a.logger.DebugContext(ctx, "loaded packages", "dir", absDir, "pattern", pattern, "packages", len(pkgs), "duration", time.Since(start))
+		return pkgs, loadedSources(absDir, pkgs), nil
+	})
+}
+
+// WarmCache loads the packages matching pkgPatterns in the background so
+// that later lookups in them, e.g. FindType in a heavy dependency, skip
+// loading. Each matched package is cached as if looked up on its own. The
+// returned channel receives the first error, or nil once all packages are
+// loaded, and is then closed. Warming does nothing if caching is disabled.
+func (a *DefaultAnalyzer) WarmCache(ctx context.Context, pkgPatterns ...string) <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		defer close(done)
+		done <- a.warmCache(ctx, pkgPatterns)
+	}()
+	return done
+}
+
+// warmCache loads pkgPatterns and caches each matched package under its
+// import path
+func (a *DefaultAnalyzer) warmCache(ctx context.Context, pkgPatterns []string) error {
+	if len(pkgPatterns) == 0 {
+		return &AnalysisError{Op: "warm cache", Wrapped: fmt.Errorf("%w: no package patterns", ErrInvalidInput)}
+	}
+	if a.cache == nil || a.cache.ttl <= 0 {
+		return nil
+	}
+	absDir, err := filepath.Abs(a.workDir)
+	if err != nil {
+		return &AnalysisError{Op: "warm cache", Path: a.workDir, Wrapped: err}
+	}
+
+	for _, pattern := range pkgPatterns {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		pkgs, err := a.load(ctx, a.workDir, pattern)
+		if err != nil {
+			return &AnalysisError{Op: "warm cache", Path: pattern, Wrapped: err}
+		}
+		for _, pkg := range pkgs {
+			if pkg.PkgPath == "" || pkg.PkgPath == pattern {
+				continue
+			}
+			single := []*packages.Package{pkg}
+			key := PackageCacheKey{Dir: absDir, Pattern: pkg.PkgPath}
+			a.cache.SetPackages(key, single, DependsOn(loadedSources(absDir, single)...))
+		}
+	}
+	return nil
+}
+
+// loadedSources lists the files and directories that packages loaded from
+// dir depend on: dir itself, and the Go files, their directories and the
+// go.mod of every loaded package and of the packages they import from the
+// same module. Directories are included so that added files are noticed.
+func loadedSources(dir string, pkgs []*packages.Package) []string {
+	sources := []string{dir}
+	seen := make(map[string]bool)
+	visited := make(map[string]bool)
+	add := func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			sources = append(sources, path)
+		}
+	}
+	var visit func(pkg *packages.Package, module string)
+	visit = func(pkg *packages.Package, module string) {
+		if visited[pkg.ID] {
+			return
+		}
+		visited[pkg.ID] = true
+		for _, file := range pkg.GoFiles {
+			add(file)
+			add(filepath.Dir(file))
+		}
+		if pkg.Module != nil && pkg.Module.GoMod != "" {
+			add(pkg.Module.GoMod)
+		}
+		for _, imp := range pkg.Imports {
+			if imp.Module != nil && imp.Module.Path == module {
+				visit(imp, module)
+			}
+		}
+	}
+	for _, pkg := range pkgs {
+		module := ""
+		if pkg.Module != nil {
+			module = pkg.Module.Path
+		}
+		visit(pkg, module)
+	}
+	return sources
+}
+
+// packageSources lists the files a lookup in pkg depends on: the Go files
+// and go.mod of pkg and the Go files of the given imports
+func packageSources(pkg *packages.Package, imports ...*packages.Package) []string {
+	sources := append([]string(nil), pkg.GoFiles...)
+	if pkg.Module != nil && pkg.Module.GoMod != "" {
+		sources = append(sources, pkg.Module.GoMod)
+	}
+	for _, imp := range imports {
+		sources = append(sources, imp.GoFiles...)
+	}
+	return sources
+}
+
+// cacheLookup stores the outcome of looking up key, which depends on
+// sources. Lookups that found nothing are kept for the not-found TTL, and
+// in memory only.
+func (a *DefaultAnalyzer) cacheLookup(key TypeCacheKey, diskKey string, result *TypeInfo, err error, sources []string) {
+	switch {
+	case err == nil && result != nil:
+		a.cache.SetType(key, result, a.lookupOptions(result, sources)...)
+		a.disk.set(diskKey, diskTypeEntry{Info: result, Sources: sources})
+	case errors.Is(err, ErrNotFound) && a.missingTTL > 0:
+		a.cache.SetTypeNotFound(key, DependsOn(sources...), ExpiresAfter(a.missingTTL))
+	}
+}
+
+// notFoundError returns the error of a lookup of key cached as not found,
+// matching the one returned when it was looked up
+func notFoundError(key TypeCacheKey) error {
+	return &TypeLookupError{
+		TypeName: key.TypeName,
+		Package:  key.Package,
+		Kind:     key.Kind,
+		Wrapped:  ErrNotFound,
+	}
+}
+
+// lookupOptions returns the cache settings of a lookup result derived from
+// sources. Standard library results are kept for the stdlib TTL.
+func (a *DefaultAnalyzer) lookupOptions(result *TypeInfo, sources []string) []EntryOption {
+	opts := []EntryOption{DependsOn(sources...)}
+	if a.stdlibTTL > 0 && isStandardImport(result.Package) {
+		opts = append(opts, ExpiresAfter(a.stdlibTTL))
+	}
+	return opts
+}
+
+// typeKey returns the cache key of looking up name of the given kind in
+// pkgPath, which is resolved from the working directory
+func (a *DefaultAnalyzer) typeKey(pkgPath, name, kind string) TypeCacheKey {
+	dir, err := filepath.Abs(a.workDir)
+	if err != nil {
+		dir = a.workDir
+	}
+	return TypeCacheKey{Dir: dir, Package: pkgPath, TypeName: name, Kind: kind}
+}
+
+// extractorNames returns the names of the registered extractors, which
+// tell apart the analyses of analyzers sharing a cache
+func (a *DefaultAnalyzer) extractorNames() string {
+	names := make([]string, len(a.extractors))
+	for i, e := range a.extractors {
+		names[i] = e.Name()
+	}
+	return strings.Join(names, ",")
+}
+
+// lookupType looks key up in the memory cache and then in the disk cache,
+// returning the disk cache key the result should be stored under on a miss
+func (a *DefaultAnalyzer) lookupType(ctx context.Context, key TypeCacheKey) (*TypeInfo, string, bool) {
+	ctx, span := a.tracer.Start(ctx, "readgo.cache.lookup", trace.WithAttributes(
+		attribute.String("readgo.cache.kind", "type"),
+		attribute.String("readgo.package", key.Package),
+		attribute.String("readgo.name", key.TypeName),
+	))
+	defer span.End()
+
+	if cached, ok := a.cache.GetType(key); ok {
+		a.logger.Log(ctx, LevelTrace, "type cache hit", "package", key.Package, "name", key.TypeName, "kind", key.Kind)
+		span.SetAttributes(cacheSpanAttributes(true, "memory")...)
+		return cached, "", true
+	}
+	diskKey := a.diskKey(a.workDir, "type:"+key.Kind, key.Package, key.TypeName)
+	var entry diskTypeEntry
+	if a.disk.get(diskKey, &entry) && entry.Info != nil {
+		a.logger.Log(ctx, LevelTrace, "type disk cache hit", "package", key.Package, "name", key.TypeName, "kind", key.Kind)
+		span.SetAttributes(cacheSpanAttributes(true, "disk")...)
+		// Stamped now, since the key proves the sources unchanged since
+		// the entry was written
+		a.cache.SetType(key, entry.Info, a.lookupOptions(entry.Info, entry.Sources)...)
+		return entry.Info, diskKey, true
+	}
+	span.SetAttributes(cacheSpanAttributes(false, "")...)
+	return nil, diskKey, false
+}
+
+// lookupAnalysis looks key up in the memory cache and then in the disk
+// cache under diskKey
+func (a *DefaultAnalyzer) lookupAnalysis(ctx context.Context, key AnalysisCacheKey, diskKey string) (*AnalysisResult, bool) {
+	ctx, span := a.tracer.Start(ctx, "readgo.cache.lookup", trace.WithAttributes(
+		attribute.String("readgo.cache.kind", "analysis"),
+		attribute.String("readgo.path", key.Path),
+	))
+	defer span.End()
+
+	if cached, ok := a.cache.GetAnalysis(key); ok {
+		a.logger.Log(ctx, LevelTrace, "analysis cache hit", "kind", key.Kind, "path", key.Path, "dir", key.Dir)
+		span.SetAttributes(cacheSpanAttributes(true, "memory")...)
+		return cached, true
+	}
+	if cached := new(AnalysisResult); a.disk.get(diskKey, cached) {
+		a.logger.Log(ctx, LevelTrace, "analysis disk cache hit", "kind", key.Kind, "path", key.Path, "dir", key.Dir)
+		span.SetAttributes(cacheSpanAttributes(true, "disk")...)
+		return cached, true
+	}
+	span.SetAttributes(cacheSpanAttributes(false, "")...)
+	return nil, false
+}
+
+// observeAnalysis reports an analysis of kind that started at start to the
+// observer
+func (a *DefaultAnalyzer) observeAnalysis(kind string, start time.Time, err error) {
+	if a.observer != nil {
+		a.observer.AnalysisDone(kind, time.Since(start), err)
+	}
+}
+
+// analysisDiskKey returns the disk cache key of an analysis result, or ""
+// when custom extractors run, since their entities may not survive JSON
+func (a *DefaultAnalyzer) analysisDiskKey(dir, kind, path string) string {
+	if len(a.extractors) > 0 {
+		return ""
+	}
+	return a.diskKey(dir, kind, path, "")
+}
+
 // AnalyzeProject analyzes a Go project at the specified path
-func (a *DefaultAnalyzer) AnalyzeProject(ctx context.Context, projectPath string) (*AnalysisResult, error) {
+func (a *DefaultAnalyzer) AnalyzeProject(ctx context.Context, projectPath string) (result *AnalysisResult, err error) {
 	if projectPath == "" {
 		projectPath = "."
 	}
@@ -365,30 +771,26 @@ func (a *DefaultAnalyzer) AnalyzeProject(ctx context.Context, projectPath string
 		}
 	}
 
+	key := AnalysisCacheKey{Kind: "project", Dir: absPath, Path: ".", Extractors: a.extractorNames()}
+	diskKey := a.analysisDiskKey(absPath, "project", ".")
+	if cached, ok := a.lookupAnalysis(ctx, key, diskKey); ok {
+		return cached, nil
+	}
+	start := time.Now()
+	defer func() { a.observeAnalysis("project", start, err) }()
+
 	// Create result
-	result := &AnalysisResult{
-		Name:       "main", // Use package name from the first package
-		Path:       absPath,
-		StartTime:  time.Now().Format(time.RFC3339),
-		AnalyzedAt: time.Now(),
+	result = &AnalysisResult{
+		Name:          "main", // Use package name from the first package
+		Path:          absPath,
+		StartTime:     time.Now().Format(time.RFC3339),
+		AnalyzedAt:    time.Now(),
+		SchemaVersion: SchemaVersion,
+		ToolVersion:   Version(),
 	}
 
 	// Load the package
-	cfg := &packages.Config{
-		Mode: packages.NeedName |
-			packages.NeedFiles |
-			packages.NeedCompiledGoFiles |
-			packages.NeedImports |
-			packages.NeedTypes |
-			packages.NeedTypesSizes |
-			packages.NeedSyntax |
-			packages.NeedTypesInfo |
-			packages.NeedDeps,
-		Dir: absPath,
-		Env: append(os.Environ(), "GO111MODULE=on"),
-	}
-
-	pkgs, err := packages.Load(cfg, "./...")
+	pkgs, err := a.load(ctx, absPath, "./...")
 	if err != nil {
 		return nil, &AnalysisError{
 			Op:      "analyze project",
@@ -397,64 +799,57 @@ func (a *DefaultAnalyzer) AnalyzeProject(ctx context.Context, projectPath string
 		}
 	}
 
+	var modPath string
 	for _, pkg := range pkgs {
-		// Extract types
-		for _, obj := range pkg.TypesInfo.Defs {
-			if obj == nil {
-				continue
-			}
-
-			if named, ok := obj.Type().(*types.Named); ok {
-				result.Types = append(result.Types, TypeInfo{
-					Name:       obj.Name(),
-					Package:    pkg.PkgPath,
-					Type:       named.String(),
-					IsExported: obj.Exported(),
-				})
-			}
+		if ctx.Err() != nil {
+			break
 		}
 
-		// Extract functions
-		for _, file := range pkg.Syntax {
-			ast.Inspect(file, func(n ast.Node) bool {
-				if funcDecl, ok := n.(*ast.FuncDecl); ok {
-					result.Functions = append(result.Functions, FunctionInfo{
-						Name:       funcDecl.Name.Name,
-						Package:    pkg.PkgPath,
-						IsExported: funcDecl.Name.IsExported(),
-					})
-				}
-				return true
-			})
+		if modPath == "" {
+			modPath = packageModulePath(pkg)
+			result.Module = readModuleInfo(packageGoMod(pkg))
 		}
-
-		// Extract imports
-		for _, imp := range pkg.Imports {
-			result.Imports = append(result.Imports, imp.PkgPath)
+		types, funcs, imports := len(result.Types), len(result.Functions), len(result.Imports)
+		if err := a.analyzeInto(ctx, pkg, result); err != nil {
+			return nil, err
 		}
+		pkgImports := slices.Clone(result.Imports[imports:])
+		result.Packages = append(result.Packages, PackageAnalysis{
+			Name:         pkg.Name,
+			Path:         pkg.PkgPath,
+			Types:        slices.Clone(result.Types[types:]),
+			Functions:    slices.Clone(result.Functions[funcs:]),
+			Imports:      pkgImports,
+			Dependencies: dependencies(pkgImports, modPath),
+		})
+	}
+	slices.SortFunc(result.Packages, func(a, b PackageAnalysis) int { return strings.Compare(a.Path, b.Path) })
+	result.Dependencies = dependencies(result.Imports, modPath)
+	result.Cancelled = ctx.Err() != nil
+	if !result.Cancelled {
+		a.cache.SetAnalysis(key, result, DependsOn(loadedSources(absPath, pkgs)...))
+		a.disk.set(diskKey, result)
 	}
 
 	return result, nil
 }
 
 // AnalyzePackage analyzes a Go package
-func (a *DefaultAnalyzer) AnalyzePackage(ctx context.Context, pkgPath string) (*AnalysisResult, error) {
+func (a *DefaultAnalyzer) AnalyzePackage(ctx context.Context, pkgPath string) (result *AnalysisResult, err error) {
+	absDir, err := filepath.Abs(a.workDir)
+	if err != nil {
+		return nil, &AnalysisError{Op: "analyze package", Path: pkgPath, Wrapped: err}
+	}
+	key := AnalysisCacheKey{Kind: "package", Dir: absDir, Path: pkgPath, Extractors: a.extractorNames()}
+	diskKey := a.analysisDiskKey(a.workDir, "package", pkgPath)
+	if cached, ok := a.lookupAnalysis(ctx, key, diskKey); ok {
+		return cached, nil
+	}
+	start := time.Now()
+	defer func() { a.observeAnalysis("package", start, err) }()
+
 	// Load the package
-	cfg := &packages.Config{
-		Mode: packages.NeedName |
-			packages.NeedFiles |
-			packages.NeedCompiledGoFiles |
-			packages.NeedImports |
-			packages.NeedTypes |
-			packages.NeedTypesSizes |
-			packages.NeedSyntax |
-			packages.NeedTypesInfo |
-			packages.NeedDeps,
-		Dir: a.workDir,
-		Env: append(os.Environ(), "GO111MODULE=on"),
-	}
-
-	pkgs, err := packages.Load(cfg, pkgPath)
+	pkgs, err := a.load(ctx, a.workDir, pkgPath)
 	if err != nil {
 		return nil, &AnalysisError{
 			Op:      "analyze package",
@@ -474,24 +869,47 @@ func (a *DefaultAnalyzer) AnalyzePackage(ctx context.Context, pkgPath string) (*
 	pkg := pkgs[0]
 
 	// Create result
-	result := &AnalysisResult{
-		Name:       pkg.Name,
-		Path:       pkg.PkgPath,
-		StartTime:  time.Now().Format(time.RFC3339),
-		AnalyzedAt: time.Now(),
+	result = &AnalysisResult{
+		Name:          pkg.Name,
+		Path:          pkg.PkgPath,
+		StartTime:     time.Now().Format(time.RFC3339),
+		AnalyzedAt:    time.Now(),
+		SchemaVersion: SchemaVersion,
+		ToolVersion:   Version(),
+	}
+
+	if err := a.analyzeInto(ctx, pkg, result); err != nil {
+		return nil, err
+	}
+	result.Dependencies = dependencies(result.Imports, packageModulePath(pkg))
+	result.Module = readModuleInfo(packageGoMod(pkg))
+	result.Cancelled = ctx.Err() != nil
+
+	if !result.Cancelled {
+		a.cache.SetAnalysis(key, result, DependsOn(loadedSources(absDir, pkgs)...))
+		a.disk.set(diskKey, result)
 	}
 
+	return result, nil
+}
+
+// analyzeInto adds the types, functions and imports of pkg to result, along
+// with the entities found in it by the registered extractors
+func (a *DefaultAnalyzer) analyzeInto(ctx context.Context, pkg *packages.Package, result *AnalysisResult) error {
 	// Extract types
 	for _, obj := range pkg.TypesInfo.Defs {
-		if obj == nil {
+		// Only type declarations, not variables of named types
+		if _, ok := obj.(*types.TypeName); !ok {
 			continue
 		}
 
-		if named, ok := obj.Type().(*types.Named); ok {
+		// Aliases are reported under their own name, with the aliased type
+		if named, ok := types.Unalias(obj.Type()).(*types.Named); ok {
 			result.Types = append(result.Types, TypeInfo{
 				Name:       obj.Name(),
 				Package:    pkg.PkgPath,
 				Type:       named.String(),
+				Kind:       typeKind(obj),
 				IsExported: obj.Exported(),
 			})
 		}
@@ -499,13 +917,12 @@ func (a *DefaultAnalyzer) AnalyzePackage(ctx context.Context, pkgPath string) (*
 
 	// Extract functions
 	for _, file := range pkg.Syntax {
+		if ctx.Err() != nil {
+			break
+		}
 		ast.Inspect(file, func(n ast.Node) bool {
 			if funcDecl, ok := n.(*ast.FuncDecl); ok {
-				result.Functions = append(result.Functions, FunctionInfo{
-					Name:       funcDecl.Name.Name,
-					Package:    pkg.PkgPath,
-					IsExported: funcDecl.Name.IsExported(),
-				})
+				result.Functions = append(result.Functions, a.functionInfo(pkg.Fset, pkg.PkgPath, funcDecl))
 			}
 			return true
 		})
@@ -516,12 +933,185 @@ func (a *DefaultAnalyzer) AnalyzePackage(ctx context.Context, pkgPath string) (*
 		result.Imports = append(result.Imports, imp.PkgPath)
 	}
 
-	return result, nil
+	return a.runExtractors(ctx, pkg, result)
+}
+
+// functionInfo describes the function declared by decl in pkgPath. The file
+// is reported relative to the working directory when it lies inside it.
+func (a *DefaultAnalyzer) functionInfo(fset *token.FileSet, pkgPath string, decl *ast.FuncDecl) FunctionInfo {
+	start := fset.Position(decl.Pos())
+	info := FunctionInfo{
+		Name:       decl.Name.Name,
+		Package:    pkgPath,
+		IsExported: decl.Name.IsExported(),
+		Signature:  funcSignature(fset, decl),
+		File:       a.relPath(start.Filename),
+		StartLine:  start.Line,
+		EndLine:    fset.Position(decl.End()).Line,
+		Doc:        decl.Doc.Text(),
+	}
+	if decl.Recv != nil && len(decl.Recv.List) > 0 {
+		info.IsMethod = true
+		info.Receiver = types.ExprString(decl.Recv.List[0].Type)
+	}
+	return info
+}
+
+// relPath returns file relative to the working directory if it lies inside
+// it, and unchanged otherwise
+func (a *DefaultAnalyzer) relPath(file string) string {
+	if !filepath.IsAbs(file) {
+		return file
+	}
+	base, err := filepath.Abs(a.workDir)
+	if err != nil {
+		return file
+	}
+	rel, err := filepath.Rel(base, file)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return file
+	}
+	return rel
+}
+
+// typeKind returns the kind of the type of obj
+func typeKind(obj types.Object) TypeKind {
+	if tn, ok := obj.(*types.TypeName); ok && tn.IsAlias() {
+		return TypeKindAlias
+	}
+	switch obj.Type().Underlying().(type) {
+	case *types.Struct:
+		return TypeKindStruct
+	case *types.Interface:
+		return TypeKindInterface
+	case *types.Signature:
+		return TypeKindFunc
+	case *types.Map:
+		return TypeKindMap
+	case *types.Slice:
+		return TypeKindSlice
+	case *types.Array:
+		return TypeKindArray
+	case *types.Pointer:
+		return TypeKindPointer
+	case *types.Chan:
+		return TypeKindChan
+	case *types.Basic:
+		return TypeKindBasic
+	default:
+		return ""
+	}
+}
+
+// syntaxTypeKind returns the kind of the type declared by spec as far as the
+// syntax tells, or "" for a type defined by another named type
+func syntaxTypeKind(spec *ast.TypeSpec) TypeKind {
+	if spec.Assign.IsValid() {
+		return TypeKindAlias
+	}
+	switch t := ast.Unparen(spec.Type).(type) {
+	case *ast.StructType:
+		return TypeKindStruct
+	case *ast.InterfaceType:
+		return TypeKindInterface
+	case *ast.FuncType:
+		return TypeKindFunc
+	case *ast.MapType:
+		return TypeKindMap
+	case *ast.ArrayType:
+		if t.Len == nil {
+			return TypeKindSlice
+		}
+		return TypeKindArray
+	case *ast.StarExpr:
+		return TypeKindPointer
+	case *ast.ChanType:
+		return TypeKindChan
+	case *ast.Ident:
+		// Predeclared types, such as int, error or any
+		if obj, ok := types.Universe.Lookup(t.Name).(*types.TypeName); ok {
+			switch obj.Type().Underlying().(type) {
+			case *types.Basic:
+				return TypeKindBasic
+			case *types.Interface:
+				return TypeKindInterface
+			}
+		}
+	}
+	return ""
 }
 
 // GetCacheStats returns cache statistics
-func (a *DefaultAnalyzer) GetCacheStats() map[string]interface{} {
-	stats := a.cache.Stats()
-	stats["enabled"] = a.cache.ttl > 0
-	return stats
+func (a *DefaultAnalyzer) GetCacheStats() CacheStats {
+	return a.cache.Stats()
+}
+
+// InvalidatePackage drops the cached entries derived from the package
+// pkgPath, see Cache.InvalidatePackage. The disk cache is left alone since
+// its entries are keyed by the sources they were computed from.
+func (a *DefaultAnalyzer) InvalidatePackage(pkgPath string) int {
+	return a.cache.InvalidatePackage(pkgPath)
+}
+
+// InvalidateAll drops every cached entry, also those of other analyzers
+// sharing the cache. The disk cache is left alone.
+func (a *DefaultAnalyzer) InvalidateAll() {
+	a.cache.Clear()
+}
+
+// ExportCache writes the cache to w, see Cache.Export
+func (a *DefaultAnalyzer) ExportCache(w io.Writer) error {
+	return a.cache.Export(w)
+}
+
+// ImportCache restores a cache written by ExportCache, see Cache.Import
+func (a *DefaultAnalyzer) ImportCache(r io.Reader) error {
+	return a.cache.Import(r)
+}
+
+// covers reports whether r includes the types or functions of pkgPath
+func (r *AnalysisResult) covers(pkgPath string) bool {
+	for _, t := range r.Types {
+		if t.Package == pkgPath {
+			return true
+		}
+	}
+	for _, f := range r.Functions {
+		if f.Package == pkgPath {
+			return true
+		}
+	}
+	return false
+}
+
+// clone returns a copy of r that shares no slices or maps with it. Entity
+// values are shared.
+func (r *AnalysisResult) clone() *AnalysisResult {
+	c := *r
+	c.Types = append([]TypeInfo(nil), r.Types...)
+	c.Functions = append([]FunctionInfo(nil), r.Functions...)
+	c.Imports = append([]string(nil), r.Imports...)
+	c.Dependencies = append([]Dependency(nil), r.Dependencies...)
+	if r.Packages != nil {
+		c.Packages = make([]PackageAnalysis, len(r.Packages))
+		for i, p := range r.Packages {
+			c.Packages[i] = p
+			c.Packages[i].Types = append([]TypeInfo(nil), p.Types...)
+			c.Packages[i].Functions = append([]FunctionInfo(nil), p.Functions...)
+			c.Packages[i].Imports = append([]string(nil), p.Imports...)
+			c.Packages[i].Dependencies = append([]Dependency(nil), p.Dependencies...)
+		}
+	}
+	if r.Module != nil {
+		m := *r.Module
+		m.Requires = append([]ModuleRequirement(nil), m.Requires...)
+		c.Module = &m
+	}
+	if r.Extensions != nil {
+		c.Extensions = make(map[string][]Entity, len(r.Extensions))
+		for name, entities := range r.Extensions {
+			c.Extensions[name] = append([]Entity(nil), entities...)
+		}
+	}
+	return &c
 }
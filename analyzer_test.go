@@ -2,12 +2,17 @@ package readgo
 
 import (
 	"context"
+	"errors"
+	"go/ast"
 	"os"
 	"path/filepath"
 	"reflect"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"golang.org/x/tools/go/packages"
 )
 
 func TestAnalyzeProject(t *testing.T) {
@@ -135,6 +140,23 @@ go 1.16
 			t.Errorf("Expected to find package %s in analysis results", pkgName)
 		}
 	}
+
+	// Each package keeps its own types
+	if len(result.Packages) != 2 {
+		t.Fatalf("Expected 2 packages, got %+v", result.Packages)
+	}
+	for i, want := range []struct{ path, typ string }{
+		{"testproject/src/handlers", "Handler"},
+		{"testproject/src/models", "User"},
+	} {
+		pkg := result.Packages[i]
+		if pkg.Path != want.path || len(pkg.Types) != 1 || pkg.Types[0].Name != want.typ {
+			t.Errorf("Packages[%d] = %+v, want %s with type %s", i, pkg, want.path, want.typ)
+		}
+	}
+	if models := result.FilterPackage("models").Packages; len(models) != 1 || models[0].Name != "models" {
+		t.Errorf("FilterPackage(models).Packages = %+v, want only models", models)
+	}
 }
 
 func TestAnalyzeFile(t *testing.T) {
@@ -342,7 +364,7 @@ func TestCacheEffectiveness(t *testing.T) {
 
 	// Check cache stats
 	stats := analyzer.GetCacheStats()
-	if hits, ok := stats["hits"].(int64); !ok || hits == 0 {
+	if stats.Types.Hits == 0 {
 		t.Error("Expected cache hits > 0")
 	}
 
@@ -354,6 +376,73 @@ func TestCacheEffectiveness(t *testing.T) {
 	}
 }
 
+// prefixExtractor reports top-level functions whose name starts with prefix
+type prefixExtractor struct {
+	prefix string
+}
+
+func (e prefixExtractor) Name() string { return "prefixed_funcs" }
+
+func (e prefixExtractor) Extract(ctx context.Context, pkg *packages.Package) ([]Entity, error) {
+	var entities []Entity
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || !strings.HasPrefix(fn.Name.Name, e.prefix) {
+				continue
+			}
+			pos := pkg.Fset.Position(fn.Pos())
+			entities = append(entities, Entity{
+				Name:    fn.Name.Name,
+				Package: pkg.PkgPath,
+				File:    pos.Filename,
+				Line:    pos.Line,
+			})
+		}
+	}
+	return entities, nil
+}
+
+// failingExtractor always returns an error
+type failingExtractor struct{}
+
+func (failingExtractor) Name() string { return "failing" }
+
+func (failingExtractor) Extract(ctx context.Context, pkg *packages.Package) ([]Entity, error) {
+	return nil, errors.New("boom")
+}
+
+func TestExtractors(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTestFiles(t, tmpDir)
+
+	analyzer := NewAnalyzer(
+		WithWorkDir(tmpDir),
+		WithExtractor(prefixExtractor{prefix: "Method"}),
+	)
+	result, err := analyzer.AnalyzePackage(context.Background(), "./testdata/basic")
+	if err != nil {
+		t.Fatalf("AnalyzePackage() error = %v", err)
+	}
+
+	entities := result.Extensions["prefixed_funcs"]
+	if len(entities) != 6 {
+		t.Fatalf("Expected 6 extracted entities, got %d: %v", len(entities), entities)
+	}
+	for _, e := range entities {
+		if !strings.HasPrefix(e.Name, "Method") || e.Line <= 0 || e.File == "" {
+			t.Errorf("Unexpected entity: %+v", e)
+		}
+	}
+
+	analyzer.RegisterExtractor(failingExtractor{})
+	_, err = analyzer.AnalyzePackage(context.Background(), "./testdata/basic")
+	var analysisErr *AnalysisError
+	if !errors.As(err, &analysisErr) {
+		t.Errorf("Expected AnalysisError from failing extractor, got %v", err)
+	}
+}
+
 // Helper functions for assertions
 func assertNoError(t *testing.T, err error) {
 	t.Helper()
@@ -453,3 +542,456 @@ func TestIsAllowedExtension(t *testing.T) {
 		})
 	}
 }
+
+var _ CodeAnalyzer = (*DefaultAnalyzer)(nil)
+
+func TestFindFunction(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTestFiles(t, tmpDir)
+
+	tests := []struct {
+		name     string
+		pkgPath  string
+		funcName string
+		wantType string
+		wantErr  bool
+		wantIs   error
+	}{
+		{
+			name:     "Valid function",
+			pkgPath:  "./testdata/basic",
+			funcName: "Method2",
+			wantType: "func(s string, i int) (bool, error)",
+		},
+		{
+			name:     "Not a function",
+			pkgPath:  "./testdata/basic",
+			funcName: "User",
+			wantErr:  true,
+		},
+		{
+			name:     "Non-existent function",
+			pkgPath:  "./testdata/basic",
+			funcName: "NonExistentFunction",
+			wantErr:  true,
+			wantIs:   ErrNotFound,
+		},
+		{
+			name:     "Empty name",
+			pkgPath:  "./testdata/basic",
+			funcName: "",
+			wantErr:  true,
+			wantIs:   ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analyzer := NewAnalyzer(
+				WithWorkDir(tmpDir),
+				WithCacheTTL(time.Minute),
+			)
+			result, err := analyzer.FindFunction(context.Background(), tt.pkgPath, tt.funcName)
+			if tt.wantErr {
+				var lookupErr *TypeLookupError
+				if !errors.As(err, &lookupErr) {
+					t.Fatalf("FindFunction() error = %v, want a TypeLookupError", err)
+				}
+				if tt.wantIs != nil && !errors.Is(err, tt.wantIs) {
+					t.Errorf("FindFunction() error = %v, want %v", err, tt.wantIs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FindFunction() error = %v", err)
+			}
+			if result.Name != tt.funcName || result.Type != tt.wantType || !result.IsExported {
+				t.Errorf("FindFunction() = %+v, want exported %s of type %s", result, tt.funcName, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestAnalyzerReusesPackages(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTestFiles(t, tmpDir)
+	ctx := context.Background()
+	analyzer := NewAnalyzer(WithWorkDir(tmpDir), WithCacheTTL(time.Minute))
+
+	first, err := analyzer.AnalyzePackage(ctx, "./testdata/basic")
+	if err != nil {
+		t.Fatalf("Failed to analyze package: %v", err)
+	}
+	for _, name := range []string{"Method1", "Method2", "Method3"} {
+		if _, err := analyzer.FindFunction(ctx, "./testdata/basic", name); err != nil {
+			t.Fatalf("Failed to find function %s: %v", name, err)
+		}
+	}
+	second, err := analyzer.AnalyzePackage(ctx, "./testdata/basic")
+	if err != nil {
+		t.Fatalf("Failed to analyze package: %v", err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("AnalyzePackage() = %+v from the cache, want %+v", second, first)
+	}
+
+	stats := analyzer.GetCacheStats()
+	if stats.Packages.Entries != 1 || stats.Packages.Hits != 3 || stats.Analyses.Hits != 1 {
+		t.Errorf("GetCacheStats() = %v, want 1 package loaded once and reused 3 times, 1 analysis hit", stats)
+	}
+
+	// Adding a file invalidates both the package and the analysis
+	content := "package basic\n\nfunc Method4() {}\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "testdata", "basic", "extra.go"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	third, err := analyzer.AnalyzePackage(ctx, "./testdata/basic")
+	if err != nil {
+		t.Fatalf("Failed to analyze package: %v", err)
+	}
+	if len(third.Functions) != len(first.Functions)+1 {
+		t.Errorf("AnalyzePackage() after adding a file found %d functions, want %d", len(third.Functions), len(first.Functions)+1)
+	}
+}
+
+func TestWarmCache(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTestFiles(t, tmpDir)
+	ctx := context.Background()
+	analyzer := NewAnalyzer(WithWorkDir(tmpDir), WithCacheTTL(time.Minute))
+
+	if err := <-analyzer.WarmCache(ctx, "container/..."); err != nil {
+		t.Fatalf("Failed to warm cache: %v", err)
+	}
+	stats := analyzer.GetCacheStats()
+	if stats.Packages.Entries != 4 || stats.Packages.Misses != 1 {
+		t.Fatalf("GetCacheStats() after warming = %v, want the pattern and its 3 packages loaded once", stats)
+	}
+
+	info, err := analyzer.FindType(ctx, "container/list", "Element")
+	if err != nil {
+		t.Fatalf("Failed to find type: %v", err)
+	}
+	if info.Package != "container/list" {
+		t.Errorf("FindType() package = %q, want container/list", info.Package)
+	}
+	if stats := analyzer.GetCacheStats(); stats.Packages.Hits != 1 || stats.Packages.Misses != 1 {
+		t.Errorf("GetCacheStats() = %v, want FindType to reuse the warmed package", stats)
+	}
+
+	if err := <-analyzer.WarmCache(ctx); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("WarmCache() without patterns = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestSharedCacheLoadsOnce(t *testing.T) {
+	var loads atomic.Int32
+	release := make(chan struct{})
+	load := loadPackages
+	loadPackages = func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+		loads.Add(1)
+		<-release // Hold the load until every lookup waits for it
+		return load(cfg, patterns...)
+	}
+	defer func() { loadPackages = load }()
+
+	tmpDir := t.TempDir()
+	setupTestFiles(t, tmpDir)
+	cache := NewCache(time.Minute)
+	analyzers := []*DefaultAnalyzer{
+		NewAnalyzer(WithWorkDir(tmpDir), WithCache(cache)),
+		NewAnalyzer(WithWorkDir(tmpDir), WithCache(cache)),
+	}
+
+	const lookups = 8
+	ctx := context.Background()
+	errs := make(chan error, lookups)
+	for i := 0; i < lookups; i++ {
+		analyzer := analyzers[i%len(analyzers)]
+		go func() {
+			_, err := analyzer.FindType(ctx, "./testdata/basic", "User")
+			errs <- err
+		}()
+	}
+	for cache.Stats().Packages.Misses != lookups {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	for i := 0; i < lookups; i++ {
+		if err := <-errs; err != nil {
+			t.Fatalf("Failed to find type: %v", err)
+		}
+	}
+	if n := loads.Load(); n != 1 {
+		t.Errorf("FindType() loaded the package %d times, want 1", n)
+	}
+
+	// A waiting caller gives up when its context is done
+	stuck, finished := make(chan struct{}), make(chan struct{})
+	defer func() {
+		close(stuck)
+		<-finished // Before loadPackages is restored
+	}()
+	loadPackages = func(cfg *packages.Config, patterns ...string) ([]*packages.Package, error) {
+		defer close(finished)
+		<-stuck
+		return nil, nil
+	}
+	cancelled, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := analyzers[0].FindType(cancelled, "fmt", "Stringer"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("FindType() with an expired context = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestFindTypeCachesNotFound(t *testing.T) {
+	tmpDir := t.TempDir()
+	file := filepath.Join(tmpDir, "p.go")
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example.com/p\n\ngo 1.22\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	if err := os.WriteFile(file, []byte("package p\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	ctx := context.Background()
+	analyzer := NewAnalyzer(WithWorkDir(tmpDir), WithCacheTTL(time.Minute))
+	for i := 0; i < 2; i++ {
+		_, err := analyzer.FindType(ctx, ".", "ID")
+		var lookupErr *TypeLookupError
+		if !errors.As(err, &lookupErr) || !errors.Is(err, ErrNotFound) || lookupErr.TypeName != "ID" {
+			t.Fatalf("FindType() error = %v, want a TypeLookupError wrapping ErrNotFound", err)
+		}
+	}
+	if stats := analyzer.GetCacheStats(); stats.Types.Hits != 1 || stats.Types.Entries != 1 {
+		t.Errorf("GetCacheStats() = %+v, want the second lookup answered from the cache", stats)
+	}
+
+	// Writing the type makes the cached miss stale
+	if err := os.WriteFile(file, []byte("package p\n\ntype ID int\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	if info, err := analyzer.FindType(ctx, ".", "ID"); err != nil || info.Type != "int" {
+		t.Errorf("FindType() after adding the type = %+v, %v, want int", info, err)
+	}
+
+	uncached := NewAnalyzer(WithWorkDir(tmpDir), WithCacheTTL(time.Minute), WithNotFoundCacheTTL(0))
+	if _, err := uncached.FindFunction(ctx, ".", "Missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("FindFunction() error = %v, want ErrNotFound", err)
+	}
+	if stats := uncached.GetCacheStats(); stats.Types.Entries != 0 {
+		t.Errorf("GetCacheStats() = %+v, want no lookup cached without a not-found TTL", stats)
+	}
+}
+
+func TestAnalyzeProjectStream(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"go.mod":         "module example.com/stream\n\ngo 1.20\n",
+		"main.go":        "package main\n\nimport \"example.com/stream/models\"\n\nfunc main() { _ = models.User{} }\n",
+		"models/user.go": "package models\n\nimport \"fmt\"\n\ntype User struct{ Name string }\n\nfunc (u User) String() string { return fmt.Sprint(u.Name) }\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	analyzer := NewAnalyzer(WithWorkDir(tmpDir))
+	var paths []string
+	for r := range analyzer.AnalyzeProjectStream(context.Background(), ".") {
+		if r.Err != nil {
+			t.Fatalf("AnalyzeProjectStream() error = %v", r.Err)
+		}
+		paths = append(paths, r.Path)
+		if r.Result.Path != r.Path {
+			t.Errorf("result path = %q, want %q", r.Result.Path, r.Path)
+		}
+		for _, typ := range r.Result.Types {
+			if typ.Package != r.Path {
+				t.Errorf("result of %s holds type %s of %s", r.Path, typ.Name, typ.Package)
+			}
+		}
+		if r.Path == "example.com/stream/models" {
+			want := []Dependency{{Path: "fmt", Kind: DependencyStandard}}
+			if !r.Result.covers(r.Path) || len(r.Result.Functions) != 1 || !reflect.DeepEqual(r.Result.Dependencies, want) {
+				t.Errorf("models result = %+v", r.Result)
+			}
+		}
+	}
+	if want := []string{"example.com/stream", "example.com/stream/models"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("streamed packages = %v, want %v", paths, want)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for r := range analyzer.AnalyzeProjectStream(ctx, ".") {
+		t.Errorf("AnalyzeProjectStream() after cancel sent %+v", r)
+	}
+
+	for r := range analyzer.AnalyzeProjectStream(context.Background(), "nonexistent") {
+		if r.Err == nil {
+			t.Errorf("AnalyzeProjectStream() of a missing directory sent %+v, want an error", r)
+		}
+	}
+}
+
+func TestTypeKinds(t *testing.T) {
+	tmpDir := t.TempDir()
+	src := `package kinds
+
+var v S
+
+type (
+	S struct{}
+	I interface{ M() }
+	A = S
+	F func() error
+	M map[string]int
+	L []int
+	R [4]byte
+	P *S
+	C chan int
+	B int
+	E error
+	N S
+)
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example.com/kinds\n\ngo 1.20\n"), 0644); err != nil {
+		t.Fatalf("Failed to write go.mod: %v", err)
+	}
+	file := filepath.Join(tmpDir, "kinds.go")
+	if err := os.WriteFile(file, []byte(src), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	want := map[string]TypeKind{
+		"S": TypeKindStruct, "I": TypeKindInterface, "A": TypeKindAlias, "F": TypeKindFunc,
+		"M": TypeKindMap, "L": TypeKindSlice, "R": TypeKindArray, "P": TypeKindPointer,
+		"C": TypeKindChan, "B": TypeKindBasic, "E": TypeKindInterface, "N": TypeKindStruct,
+	}
+	analyzer := NewAnalyzer(WithWorkDir(tmpDir))
+
+	pkgResult, err := analyzer.AnalyzePackage(context.Background(), ".")
+	if err != nil {
+		t.Fatalf("AnalyzePackage() error = %v", err)
+	}
+	fileResult, err := analyzer.AnalyzeFile(context.Background(), file)
+	if err != nil {
+		t.Fatalf("AnalyzeFile() error = %v", err)
+	}
+
+	for _, result := range []*AnalysisResult{pkgResult, fileResult} {
+		got := make(map[string]TypeKind)
+		for _, typ := range result.Types {
+			got[typ.Name] = typ.Kind
+		}
+		for name, kind := range want {
+			if name == "N" && result == fileResult {
+				kind = "" // The syntax does not tell the underlying type
+			}
+			if got[name] != kind {
+				t.Errorf("%s: kind of %s = %q, want %q", result.Name, name, got[name], kind)
+			}
+		}
+		if len(got) != len(want) {
+			t.Errorf("%s: types = %v, want only the declared types", result.Name, got)
+		}
+	}
+
+	for name, kind := range want {
+		info, err := analyzer.FindType(context.Background(), ".", name)
+		if err != nil {
+			t.Fatalf("FindType(%s) error = %v", name, err)
+		}
+		if info.Kind != kind {
+			t.Errorf("FindType(%s) kind = %q, want %q", name, info.Kind, kind)
+		}
+	}
+	if info, err := analyzer.FindInterface(context.Background(), "io", "Reader"); err != nil || info.Kind != TypeKindInterface {
+		t.Errorf("FindInterface(io.Reader) = %+v, %v, want an interface", info, err)
+	}
+}
+
+func TestFunctionInfo(t *testing.T) {
+	tmpDir := t.TempDir()
+	src := `package shop
+
+// Cart holds items
+type Cart struct{ items []string }
+
+// Add puts an item
+// into the cart.
+func (c *Cart) Add(item string) {
+	c.items = append(c.items, item)
+}
+
+func total(prices ...float64) (sum float64) {
+	for _, p := range prices {
+		sum += p
+	}
+	return sum
+}
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example.com/shop\n\ngo 1.20\n"), 0644); err != nil {
+		t.Fatalf("Failed to write go.mod: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "cart.go"), []byte(src), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	want := []FunctionInfo{
+		{
+			Name:       "Add",
+			IsExported: true,
+			Signature:  "func (c *Cart) Add(item string)",
+			Receiver:   "*Cart",
+			IsMethod:   true,
+			File:       "cart.go",
+			StartLine:  8,
+			EndLine:    10,
+			Doc:        "Add puts an item\ninto the cart.\n",
+		},
+		{
+			Name:      "total",
+			Signature: "func total(prices ...float64) (sum float64)",
+			File:      "cart.go",
+			StartLine: 12,
+			EndLine:   17,
+		},
+	}
+
+	analyzer := NewAnalyzer(WithWorkDir(tmpDir))
+	pkgResult, err := analyzer.AnalyzePackage(context.Background(), ".")
+	if err != nil {
+		t.Fatalf("AnalyzePackage() error = %v", err)
+	}
+	fileResult, err := analyzer.AnalyzeFile(context.Background(), "cart.go")
+	if err != nil {
+		t.Fatalf("AnalyzeFile() error = %v", err)
+	}
+
+	for _, tt := range []struct {
+		name    string
+		result  *AnalysisResult
+		pkgPath string
+	}{
+		{name: "package", result: pkgResult, pkgPath: "example.com/shop"},
+		{name: "file", result: fileResult, pkgPath: "shop"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var wantFuncs []FunctionInfo
+			for _, f := range want {
+				f.Package = tt.pkgPath
+				wantFuncs = append(wantFuncs, f)
+			}
+			if !reflect.DeepEqual(tt.result.Functions, wantFuncs) {
+				t.Errorf("Functions = %+v, want %+v", tt.result.Functions, wantFuncs)
+			}
+		})
+	}
+}
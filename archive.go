@@ -0,0 +1,170 @@
+package readgo
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// ArchiveReader implements SourceReader over the contents of a zip or tar
+// archive, such as a Go module zip or a release source tarball, without
+// extracting it. If every entry shares a single top-level directory
+// (e.g. "module@v1.2.3/"), that directory becomes the reader root.
+type ArchiveReader struct {
+	virtualReader
+	path string
+}
+
+// NewArchiveReader opens the archive at archivePath. Supported formats are
+// .zip, .tar, .tar.gz and .tgz. Entries larger than the maximum file size
+// are skipped.
+func NewArchiveReader(archivePath string) (*ArchiveReader, error) {
+	var (
+		files []*virtualFile
+		err   error
+	)
+
+	switch name := strings.ToLower(archivePath); {
+	case strings.HasSuffix(name, ".zip"):
+		files, err = readZipEntries(archivePath)
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		files, err = readTarEntries(archivePath, true)
+	case strings.HasSuffix(name, ".tar"):
+		files, err = readTarEntries(archivePath, false)
+	default:
+		return nil, fmt.Errorf("%w: unsupported archive format: %s", ErrInvalidInput, archivePath)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &ArchiveReader{
+		virtualReader: newVirtualReader(stripCommonRoot(files)),
+		path:          archivePath,
+	}, nil
+}
+
+// Path returns the path of the underlying archive
+func (r *ArchiveReader) Path() string {
+	return r.path
+}
+
+// readZipEntries lists and loads the regular files of a zip archive
+func readZipEntries(archivePath string) ([]*virtualFile, error) {
+	zr, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	var files []*virtualFile
+	for _, entry := range zr.File {
+		if !entry.Mode().IsRegular() || entry.UncompressedSize64 > maxFileSize {
+			continue
+		}
+		name := cleanVirtualPath(entry.Name)
+		if name == "" {
+			continue
+		}
+
+		rc, err := entry.Open()
+		if err != nil {
+			return nil, err
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, memoryFile(name, content, entry.Modified))
+	}
+	return files, nil
+}
+
+// readTarEntries lists and loads the regular files of a tar archive
+func readTarEntries(archivePath string, gzipped bool) ([]*virtualFile, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var src io.Reader = f
+	if gzipped {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		src = gz
+	}
+
+	var files []*virtualFile
+	tr := tar.NewReader(src)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg || hdr.Size > maxFileSize {
+			continue
+		}
+		name := cleanVirtualPath(hdr.Name)
+		if name == "" {
+			continue
+		}
+
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, memoryFile(name, content, hdr.ModTime))
+	}
+	return files, nil
+}
+
+// stripCommonRoot removes the top-level directory shared by all files. Go
+// module zips nest their contents under "module/path@version/", in which
+// case that whole prefix is removed.
+func stripCommonRoot(files []*virtualFile) []*virtualFile {
+	if len(files) == 0 {
+		return files
+	}
+
+	// Find the directory segments shared by every file
+	common := strings.Split(path.Dir(files[0].path), "/")
+	for _, f := range files[1:] {
+		segments := strings.Split(path.Dir(f.path), "/")
+		n := 0
+		for n < len(common) && n < len(segments) && common[n] == segments[n] {
+			n++
+		}
+		common = common[:n]
+	}
+	if len(common) == 0 || common[0] == "." {
+		return files
+	}
+
+	strip := 1
+	for i, segment := range common {
+		if strings.Contains(segment, "@") {
+			strip = i + 1
+			break
+		}
+	}
+
+	prefix := strings.Join(common[:strip], "/") + "/"
+	for _, f := range files {
+		f.path = strings.TrimPrefix(f.path, prefix)
+	}
+	return files
+}
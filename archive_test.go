@@ -0,0 +1,145 @@
+package readgo
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var archiveTestFiles = map[string]string{
+	"example.com/mod@v1.0.0/go.mod":           "module example.com/mod\n",
+	"example.com/mod@v1.0.0/mod.go":           "package mod\n\nfunc Hello() {}\n",
+	"example.com/mod@v1.0.0/internal/util.go": "package internal\n",
+}
+
+func writeTestZip(t *testing.T, path string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create zip: %v", err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for name, content := range archiveTestFiles {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("Failed to add %s: %v", name, err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Failed to close zip: %v", err)
+	}
+}
+
+func writeTestTarGz(t *testing.T, path string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create tarball: %v", err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for name, content := range archiveTestFiles {
+		hdr := &tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(content)),
+			ModTime:  time.Now(),
+			Typeflag: tar.TypeReg,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("Failed to add %s: %v", name, err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("Failed to close tar: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("Failed to close gzip: %v", err)
+	}
+}
+
+func TestArchiveReader(t *testing.T) {
+	tmpDir := t.TempDir()
+	archives := map[string]func(*testing.T, string){
+		"module.zip":    writeTestZip,
+		"source.tar.gz": writeTestTarGz,
+	}
+
+	for name, write := range archives {
+		t.Run(name, func(t *testing.T) {
+			archivePath := filepath.Join(tmpDir, name)
+			write(t, archivePath)
+
+			var reader SourceReader
+			reader, err := NewArchiveReader(archivePath)
+			if err != nil {
+				t.Fatalf("NewArchiveReader() error = %v", err)
+			}
+			ctx := context.Background()
+
+			content, err := reader.ReadSourceFile(ctx, "mod.go", ReadOptions{})
+			if err != nil {
+				t.Fatalf("ReadSourceFile() error = %v", err)
+			}
+			if string(content) != archiveTestFiles["example.com/mod@v1.0.0/mod.go"] {
+				t.Errorf("ReadSourceFile() content = %q", content)
+			}
+
+			if _, err := reader.ReadSourceFile(ctx, "missing.go", ReadOptions{}); !errors.Is(err, ErrNotFound) {
+				t.Errorf("ReadSourceFile() error = %v, want %v", err, ErrNotFound)
+			}
+
+			tree, err := reader.GetFileTree(ctx, ".", TreeOptions{ComputeHashes: true})
+			if err != nil {
+				t.Fatalf("GetFileTree() error = %v", err)
+			}
+			if len(tree.Children) != 3 {
+				t.Fatalf("Expected 3 top-level entries, got %d", len(tree.Children))
+			}
+			if dir := tree.Children[0]; dir.Name != "go.mod" {
+				t.Errorf("Expected children sorted by name, first is %s", dir.Name)
+			}
+			for _, f := range collectFiles(tree) {
+				if f.Hash == "" {
+					t.Errorf("Missing hash for %s", f.Path)
+				}
+			}
+
+			files, err := reader.GetPackageFiles(ctx, "internal", TreeOptions{})
+			if err != nil {
+				t.Fatalf("GetPackageFiles() error = %v", err)
+			}
+			if len(files) != 1 || files[0].Path != filepath.Join("internal", "util.go") {
+				t.Errorf("GetPackageFiles() = %v", files)
+			}
+
+			matches, err := reader.SearchFiles(ctx, ".go", TreeOptions{ExcludePatterns: []string{"internal"}})
+			if err != nil {
+				t.Fatalf("SearchFiles() error = %v", err)
+			}
+			if len(matches) != 1 || matches[0].Name != "mod.go" {
+				t.Errorf("SearchFiles() = %v", matches)
+			}
+		})
+	}
+
+	if _, err := NewArchiveReader(filepath.Join(tmpDir, "source.rar")); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("NewArchiveReader() error = %v, want %v", err, ErrInvalidInput)
+	}
+}
@@ -1,75 +1,714 @@
 package readgo
 
 import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"os"
 	"sync"
 	"time"
+
+	"golang.org/x/sync/singleflight"
+	"golang.org/x/tools/go/packages"
 )
 
-// Cache provides a simple in-memory cache for type information
+// Cache provides a simple in-memory cache for type information, loaded
+// packages, analysis results and validation results. Entries expire ttl
+// after they are stored, or as soon as a file they depend on changes. Each
+// kind of entry is capped separately; when a cap is reached the least
+// recently used entry is evicted. A Cache is safe for concurrent use and
+// may be shared by several analyzers, see WithCache.
 type Cache struct {
-	mu    sync.RWMutex
-	types map[TypeCacheKey]*TypeInfo
-	hits  int64
-	ttl   time.Duration
+	mu        sync.Mutex
+	types     *lru[TypeCacheKey, *TypeInfo]
+	packages  *lru[PackageCacheKey, []*packages.Package]
+	analyses  *lru[AnalysisCacheKey, *AnalysisResult]
+	results   *lru[ValidationCacheKey, *ValidationResult]
+	loads     singleflight.Group // Package loads in progress
+	ttl       time.Duration
+	maxBytes  int64 // 0 means unlimited
+	lastSweep time.Time
+	now       func() time.Time // Clock, replaced in tests
 }
 
 // TypeCacheKey is the key used for caching type information
 type TypeCacheKey struct {
+	Dir      string // Absolute directory the package is resolved from
 	Package  string
 	TypeName string
 	Kind     string
 }
 
-// NewCache creates a new cache with the given TTL
+// PackageCacheKey is the key used for caching loaded packages
+type PackageCacheKey struct {
+	Dir     string // Absolute directory the pattern was loaded from
+	Pattern string // Package pattern, e.g. "./..." or an import path
+}
+
+// AnalysisCacheKey is the key used for caching analysis results
+type AnalysisCacheKey struct {
+	Kind string // "package" or "project"
+	Dir  string // Absolute directory the path is resolved from
+	Path string // Analyzed package or project path
+
+	// Extractors lists the names of the extractors run, comma-separated
+	Extractors string
+}
+
+// ValidationCacheKey is the key used for caching validation results
+type ValidationCacheKey struct {
+	Scope string          // What was validated, e.g. "package:pkg/util"
+	Level ValidationLevel // Validation level
+	Hash  string          // Hash of the source files the result depends on
+}
+
+// EntryOption configures a single cache entry
+type EntryOption func(*entryOptions)
+
+// entryOptions holds the settings of a cache entry
+type entryOptions struct {
+	files []string
+	ttl   time.Duration // 0 means the TTL of the cache
+}
+
+// DependsOn makes an entry stale as soon as one of files is modified,
+// created or removed. Files are compared by size and modification time.
+func DependsOn(files ...string) EntryOption {
+	return func(o *entryOptions) {
+		o.files = append(o.files, files...)
+	}
+}
+
+// ExpiresAfter makes an entry expire ttl after it is stored instead of after
+// the TTL of the cache, e.g. to keep standard library lookups for hours
+// while workspace packages expire quickly. It does not enable a cache
+// whose TTL is zero.
+func ExpiresAfter(ttl time.Duration) EntryOption {
+	return func(o *entryOptions) {
+		o.ttl = ttl
+	}
+}
+
+// entrySettings applies opts
+func entrySettings(opts []EntryOption) entryOptions {
+	var o entryOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// NewCache creates a new cache with the given TTL and no size limit
 func NewCache(ttl time.Duration) *Cache {
 	return &Cache{
-		types: make(map[TypeCacheKey]*TypeInfo),
-		ttl:   ttl,
+		types:    newLRU[TypeCacheKey, *TypeInfo](),
+		packages: newLRU[PackageCacheKey, []*packages.Package](),
+		analyses: newLRU[AnalysisCacheKey, *AnalysisResult](),
+		results:  newLRU[ValidationCacheKey, *ValidationResult](),
+		ttl:      ttl,
+		now:      time.Now,
+	}
+}
+
+// WithMaxEntries limits each kind of entry to max, evicting the least
+// recently used ones beyond it; zero removes the limit
+func (c *Cache) WithMaxEntries(max int) *Cache {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.types.resize(max)
+	c.packages.resize(max)
+	c.analyses.resize(max)
+	c.results.resize(max)
+	return c
+}
+
+// WithMaxBytes limits the approximate size of all entries together to max
+// bytes; zero removes the limit. Beyond it, the least recently used entry
+// of the kind taking up the most space is evicted, which usually is a
+// loaded package. Sizes are estimates: results are measured by the length
+// of their JSON encoding and loaded packages by that of their sources.
+func (c *Cache) WithMaxBytes(max int64) *Cache {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if max < 0 {
+		max = 0
+	}
+	c.maxBytes = max
+	c.trimBytes()
+	return c
+}
+
+// trimBytes evicts entries until their total size is within the limit. The
+// caller must hold c.mu.
+func (c *Cache) trimBytes() {
+	for c.maxBytes > 0 {
+		var total int64
+		var largest sizedLRU
+		for _, l := range []sizedLRU{c.types, c.packages, c.analyses, c.results} {
+			total += l.size()
+			if largest == nil || l.size() > largest.size() {
+				largest = l
+			}
+		}
+		if total <= c.maxBytes {
+			return
+		}
+		largest.evictOldest()
 	}
 }
 
-// GetType retrieves a type from the cache
+// GetType retrieves a type from the cache. A lookup cached as not found,
+// see SetTypeNotFound, is reported as a nil type and true.
 func (c *Cache) GetType(key TypeCacheKey) (*TypeInfo, bool) {
 	if c == nil || c.ttl <= 0 {
 		return nil, false
 	}
 
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	if info, ok := c.types[key]; ok {
-		c.hits++
-		return info, true
-	}
-	return nil, false
+	return c.types.get(key, c.now())
 }
 
 // SetType stores a type in the cache
-func (c *Cache) SetType(key TypeCacheKey, info *TypeInfo) {
+func (c *Cache) SetType(key TypeCacheKey, info *TypeInfo, opts ...EntryOption) {
 	if c == nil || c.ttl <= 0 {
 		return
 	}
+	o := entrySettings(opts)
+	stamps := stampFiles(o.files)
+	size := jsonSize(info)
 
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.types[key] = info
+	c.sweep()
+	c.types.set(key, info, c.expiry(o), stamps, size)
+	c.trimBytes()
+}
+
+// SetTypeNotFound records that the lookup of key found nothing, so that
+// repeated lookups of a missing symbol fail fast. Such entries usually get
+// a shorter TTL than found types, see ExpiresAfter.
+func (c *Cache) SetTypeNotFound(key TypeCacheKey, opts ...EntryOption) {
+	c.SetType(key, nil, opts...)
+}
+
+// GetPackages retrieves loaded packages from the cache. They are shared, so
+// callers must not modify them.
+func (c *Cache) GetPackages(key PackageCacheKey) ([]*packages.Package, bool) {
+	if c == nil || c.ttl <= 0 {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.packages.get(key, c.now())
+}
+
+// SetPackages stores loaded packages in the cache
+func (c *Cache) SetPackages(key PackageCacheKey, pkgs []*packages.Package, opts ...EntryOption) {
+	if c == nil || c.ttl <= 0 {
+		return
+	}
+	o := entrySettings(opts)
+	stamps := stampFiles(o.files)
+	size := packagesSize(pkgs)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sweep()
+	c.packages.set(key, pkgs, c.expiry(o), stamps, size)
+	c.trimBytes()
+}
+
+// loadPackages returns the packages cached under key, calling load on a
+// miss and caching the packages as depending on the files it returns.
+// Concurrent misses for the same key share a single load. The load is not
+// cancelled with the context of the caller that started it, since others
+// may be waiting for it, but every caller stops waiting when its own
+// context is done.
+func (c *Cache) loadPackages(ctx context.Context, key PackageCacheKey, load func(ctx context.Context) ([]*packages.Package, []string, error)) ([]*packages.Package, error) {
+	if c == nil {
+		pkgs, _, err := load(ctx)
+		return pkgs, err
+	}
+	if pkgs, ok := c.GetPackages(key); ok {
+		return pkgs, nil
+	}
+
+	done := c.loads.DoChan(key.Dir+"\x00"+key.Pattern, func() (interface{}, error) {
+		pkgs, files, err := load(context.WithoutCancel(ctx))
+		if err != nil {
+			return nil, err
+		}
+		c.SetPackages(key, pkgs, DependsOn(files...))
+		return pkgs, nil
+	})
+	select {
+	case res := <-done:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.([]*packages.Package), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// GetAnalysis retrieves a copy of an analysis result from the cache
+func (c *Cache) GetAnalysis(key AnalysisCacheKey) (*AnalysisResult, bool) {
+	if c == nil || c.ttl <= 0 {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if result, ok := c.analyses.get(key, c.now()); ok {
+		return result.clone(), true
+	}
+	return nil, false
+}
+
+// SetAnalysis stores a copy of an analysis result in the cache. Results of
+// cancelled analyses are not stored.
+func (c *Cache) SetAnalysis(key AnalysisCacheKey, result *AnalysisResult, opts ...EntryOption) {
+	if c == nil || c.ttl <= 0 || result.Cancelled {
+		return
+	}
+	o := entrySettings(opts)
+	stamps := stampFiles(o.files)
+	size := jsonSize(result)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sweep()
+	c.analyses.set(key, result.clone(), c.expiry(o), stamps, size)
+	c.trimBytes()
+}
+
+// GetValidation retrieves a copy of a validation result from the cache.
+// Keys without a hash are never cached.
+func (c *Cache) GetValidation(key ValidationCacheKey) (*ValidationResult, bool) {
+	if c == nil || c.ttl <= 0 || key.Hash == "" {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if result, ok := c.results.get(key, c.now()); ok {
+		return result.clone(), true
+	}
+	return nil, false
+}
+
+// SetValidation stores a copy of a validation result in the cache. Results
+// of cancelled validations are not stored.
+func (c *Cache) SetValidation(key ValidationCacheKey, result *ValidationResult, opts ...EntryOption) {
+	if c == nil || c.ttl <= 0 || key.Hash == "" || result.Cancelled {
+		return
+	}
+	o := entrySettings(opts)
+	stamps := stampFiles(o.files)
+	size := jsonSize(result)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sweep()
+	c.results.set(key, result.clone(), c.expiry(o), stamps, size)
+	c.trimBytes()
+}
+
+// expiry returns when an entry stored now with the settings o expires
+func (c *Cache) expiry(o entryOptions) time.Time {
+	if o.ttl > 0 {
+		return c.now().Add(o.ttl)
+	}
+	return c.now().Add(c.ttl)
+}
+
+// sweep drops the expired entries, at most once per TTL so that stores stay
+// cheap. The caller must hold c.mu.
+func (c *Cache) sweep() {
+	now := c.now()
+	if now.Sub(c.lastSweep) < c.ttl {
+		return
+	}
+	c.lastSweep = now
+	c.types.expire(now)
+	c.packages.expire(now)
+	c.analyses.expire(now)
+	c.results.expire(now)
+}
+
+// InvalidatePackage drops the entries derived from the package pkgPath: type
+// lookups in it or resolved into it, loads that include it, analyses that
+// cover it and the validation results of the files, packages and projects
+// that include it. pkgPath is an import path, or the pattern the package
+// was looked up with. Validation results restored by Import only match
+// the pattern. It returns the number of entries
+// dropped. Entries depending on changed files are dropped anyway once they
+// are looked up; this is for callers, such as file watchers, that know
+// what changed and want the memory back at once.
+func (c *Cache) InvalidatePackage(pkgPath string) int {
+	if c == nil {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := c.types.removeIf(func(key TypeCacheKey, info *TypeInfo) bool {
+		return key.Package == pkgPath || (info != nil && info.Package == pkgPath)
+	})
+	n += c.packages.removeIf(func(key PackageCacheKey, pkgs []*packages.Package) bool {
+		found := key.Pattern == pkgPath
+		packages.Visit(pkgs, func(pkg *packages.Package) bool {
+			found = found || pkg.PkgPath == pkgPath
+			return !found
+		}, nil)
+		return found
+	})
+	n += c.analyses.removeIf(func(key AnalysisCacheKey, result *AnalysisResult) bool {
+		return key.Path == pkgPath || result.covers(pkgPath)
+	})
+	n += c.results.removeIf(func(key ValidationCacheKey, result *ValidationResult) bool {
+		return key.Scope == "package:"+pkgPath || result.covers(pkgPath)
+	})
+	return n
+}
+
+// Clear drops all entries. Statistics are kept.
+func (c *Cache) Clear() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.types.clear()
+	c.packages.clear()
+	c.analyses.clear()
+	c.results.clear()
+}
+
+// clearValidations removes all cached validation results
+func (c *Cache) clearValidations() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.results.clear()
+}
+
+// CacheStats reports the use of a cache. The embedded counters are totals
+// over all kinds of entries; each kind is also reported on its own.
+type CacheStats struct {
+	Enabled        bool `json:"enabled" yaml:"enabled"` // Whether the cache stores anything
+	CacheKindStats `yaml:",inline"`
+
+	Types       CacheKindStats `json:"types" yaml:"types"`             // Type lookups
+	Packages    CacheKindStats `json:"packages" yaml:"packages"`       // Loaded packages
+	Analyses    CacheKindStats `json:"analyses" yaml:"analyses"`       // Analysis results
+	Validations CacheKindStats `json:"validations" yaml:"validations"` // Validation results
+}
+
+// CacheKindStats reports the use of a cache by one kind of entries
+type CacheKindStats struct {
+	Hits        int64   `json:"hits" yaml:"hits"`
+	Misses      int64   `json:"misses" yaml:"misses"`
+	HitRatio    float64 `json:"hit_ratio" yaml:"hit_ratio"` // Share of lookups that hit, 0 without lookups
+	Entries     int64   `json:"entries" yaml:"entries"`
+	BytesUsed   int64   `json:"bytes_used" yaml:"bytes_used"`   // Approximate size of the entries
+	Evictions   int64   `json:"evictions" yaml:"evictions"`     // Entries removed to make room
+	Expirations int64   `json:"expirations" yaml:"expirations"` // Entries removed because they expired or went stale
 }
 
 // Stats returns cache statistics
-func (c *Cache) Stats() map[string]interface{} {
+func (c *Cache) Stats() CacheStats {
+	var stats CacheStats
 	if c == nil {
-		return map[string]interface{}{
-			"hits":    int64(0),
-			"entries": int64(0),
+		return stats
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats.Enabled = c.ttl > 0
+	stats.Types = c.types.stats()
+	stats.Packages = c.packages.stats()
+	stats.Analyses = c.analyses.stats()
+	stats.Validations = c.results.stats()
+	for _, kind := range []CacheKindStats{stats.Types, stats.Packages, stats.Analyses, stats.Validations} {
+		stats.Hits += kind.Hits
+		stats.Misses += kind.Misses
+		stats.Entries += kind.Entries
+		stats.BytesUsed += kind.BytesUsed
+		stats.Evictions += kind.Evictions
+		stats.Expirations += kind.Expirations
+	}
+	stats.HitRatio = hitRatio(stats.Hits, stats.Misses)
+	return stats
+}
+
+// lru is a map of expiring entries that holds at most max of them, evicting
+// the least recently used one to make room. It is not safe for concurrent
+// use.
+type lru[K comparable, V any] struct {
+	max   int // 0 means unlimited
+	order *list.List
+	items map[K]*list.Element
+	lruStats
+}
+
+// lruStats counts the lookups and removals of an lru
+type lruStats struct {
+	hits        int64
+	misses      int64
+	bytes       int64 // Approximate size of the entries
+	evictions   int64 // Entries removed to make room
+	expirations int64 // Entries removed because they expired or went stale
+}
+
+// hitRatio returns the share of lookups that were hits, or 0 without any
+func hitRatio(hits, misses int64) float64 {
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
+
+// lruEntry is the value of the elements of lru.order
+type lruEntry[K comparable, V any] struct {
+	key     K
+	value   V
+	expires time.Time
+	stamps  []fileStamp // Files the value was derived from
+	size    int64       // Approximate size in bytes
+}
+
+// sizedLRU is an lru of any kind, as seen when enforcing the size limit
+type sizedLRU interface {
+	size() int64
+	evictOldest()
+}
+
+// newLRU creates an empty, unlimited lru
+func newLRU[K comparable, V any]() *lru[K, V] {
+	return &lru[K, V]{order: list.New(), items: make(map[K]*list.Element)}
+}
+
+// get returns the value of key unless it expired by now or one of its files
+// changed, and marks it as recently used. A stale entry is removed.
+func (l *lru[K, V]) get(key K, now time.Time) (V, bool) {
+	var zero V
+	elem, ok := l.items[key]
+	if !ok {
+		l.misses++
+		return zero, false
+	}
+	entry := elem.Value.(*lruEntry[K, V])
+	if !now.Before(entry.expires) || !unchanged(entry.stamps) {
+		l.remove(elem)
+		l.expirations++
+		l.misses++
+		return zero, false
+	}
+	l.order.MoveToFront(elem)
+	l.hits++
+	return entry.value, true
+}
+
+// set stores value of the given size, derived from the stamped files,
+// under key until expires, evicting entries beyond the limit
+func (l *lru[K, V]) set(key K, value V, expires time.Time, stamps []fileStamp, size int64) {
+	if elem, ok := l.items[key]; ok {
+		entry := elem.Value.(*lruEntry[K, V])
+		l.bytes += size - entry.size
+		entry.value, entry.expires, entry.stamps, entry.size = value, expires, stamps, size
+		l.order.MoveToFront(elem)
+		return
+	}
+	l.items[key] = l.order.PushFront(&lruEntry[K, V]{key: key, value: value, expires: expires, stamps: stamps, size: size})
+	l.bytes += size
+	l.trim()
+}
+
+// expire removes the entries that expired by now
+func (l *lru[K, V]) expire(now time.Time) {
+	for elem := l.order.Front(); elem != nil; {
+		next := elem.Next()
+		if !now.Before(elem.Value.(*lruEntry[K, V]).expires) {
+			l.remove(elem)
+			l.expirations++
 		}
+		elem = next
+	}
+}
+
+// resize changes the limit, evicting entries beyond it
+func (l *lru[K, V]) resize(max int) {
+	if max < 0 {
+		max = 0
+	}
+	l.max = max
+	l.trim()
+}
+
+// trim evicts the least recently used entries beyond the limit
+func (l *lru[K, V]) trim() {
+	for l.max > 0 && l.order.Len() > l.max {
+		l.evictOldest()
+	}
+}
+
+// evictOldest evicts the least recently used entry, if any
+func (l *lru[K, V]) evictOldest() {
+	if elem := l.order.Back(); elem != nil {
+		l.remove(elem)
+		l.evictions++
 	}
+}
+
+// remove deletes the entry of elem
+func (l *lru[K, V]) remove(elem *list.Element) {
+	entry := elem.Value.(*lruEntry[K, V])
+	l.order.Remove(elem)
+	delete(l.items, entry.key)
+	l.bytes -= entry.size
+}
 
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+// removeIf removes the entries for which drop returns true and returns
+// their number
+func (l *lru[K, V]) removeIf(drop func(key K, value V) bool) int {
+	n := 0
+	for elem := l.order.Front(); elem != nil; {
+		next := elem.Next()
+		if entry := elem.Value.(*lruEntry[K, V]); drop(entry.key, entry.value) {
+			l.remove(elem)
+			n++
+		}
+		elem = next
+	}
+	return n
+}
 
-	return map[string]interface{}{
-		"hits":    c.hits,
-		"entries": int64(len(c.types)),
+// clear removes all entries
+func (l *lru[K, V]) clear() {
+	l.order.Init()
+	l.items = make(map[K]*list.Element)
+	l.bytes = 0
+}
+
+// size returns the approximate size of the entries in bytes
+func (l *lru[K, V]) size() int64 {
+	return l.bytes
+}
+
+// len returns the number of entries
+func (l *lru[K, V]) len() int {
+	return l.order.Len()
+}
+
+// stats returns the counters of l
+func (l *lru[K, V]) stats() CacheKindStats {
+	return CacheKindStats{
+		Hits:        l.hits,
+		Misses:      l.misses,
+		HitRatio:    hitRatio(l.hits, l.misses),
+		Entries:     int64(l.len()),
+		BytesUsed:   l.bytes,
+		Evictions:   l.evictions,
+		Expirations: l.expirations,
+	}
+}
+
+// packageOverhead is the approximate size of a loaded package besides its
+// syntax trees, and syntaxBytesPerSourceByte the approximate size of syntax
+// trees and type information relative to the source they were built from
+const (
+	packageOverhead          = 4 << 10
+	syntaxBytesPerSourceByte = 10
+)
+
+// packagesSize estimates the memory held by pkgs and their dependencies
+func packagesSize(pkgs []*packages.Package) int64 {
+	var size int64
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		size += packageOverhead
+		for _, file := range pkg.Syntax {
+			if tf := pkg.Fset.File(file.Pos()); tf != nil {
+				size += int64(tf.Size()) * syntaxBytesPerSourceByte
+			}
+		}
+	})
+	return size
+}
+
+// jsonSize estimates the memory held by v as the length of its JSON
+// encoding
+func jsonSize(v interface{}) int64 {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return int64(len(data))
+}
+
+// fileStamp records the state of a file when a cache entry was stored
+type fileStamp struct {
+	path    string
+	size    int64 // -1 if the file did not exist
+	modTime time.Time
+}
+
+// stampFiles records the current state of files
+func stampFiles(files []string) []fileStamp {
+	if len(files) == 0 {
+		return nil
+	}
+	stamps := make([]fileStamp, len(files))
+	for i, file := range files {
+		stamps[i] = stampFile(file)
+	}
+	return stamps
+}
+
+// stampFile records the current state of file
+func stampFile(file string) fileStamp {
+	info, err := os.Stat(file)
+	if err != nil {
+		return fileStamp{path: file, size: -1}
+	}
+	return fileStamp{path: file, size: info.Size(), modTime: info.ModTime()}
+}
+
+// unchanged reports whether the stamped files are still in the same state
+func unchanged(stamps []fileStamp) bool {
+	for _, stamp := range stamps {
+		if current := stampFile(stamp.path); current.size != stamp.size || !current.modTime.Equal(stamp.modTime) {
+			return false
+		}
 	}
+	return true
 }
@@ -0,0 +1,431 @@
+package readgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestCacheEviction(t *testing.T) {
+	cache := NewCache(time.Minute).WithMaxEntries(2)
+	key := func(name string) TypeCacheKey {
+		return TypeCacheKey{Package: "p", TypeName: name, Kind: "type"}
+	}
+
+	cache.SetType(key("A"), &TypeInfo{Name: "A"})
+	cache.SetType(key("B"), &TypeInfo{Name: "B"})
+	if _, ok := cache.GetType(key("A")); !ok {
+		t.Fatal("GetType(A) missed before the cache was full")
+	}
+	cache.SetType(key("C"), &TypeInfo{Name: "C"})
+
+	// B was the least recently used entry
+	for name, want := range map[string]bool{"A": true, "B": false, "C": true} {
+		if _, ok := cache.GetType(key(name)); ok != want {
+			t.Errorf("GetType(%s) found = %v, want %v", name, ok, want)
+		}
+	}
+	stats := cache.Stats()
+	if stats.Types.Entries != 2 || stats.Types.Evictions != 1 {
+		t.Errorf("Stats() = %v, want 2 entries and 1 eviction", stats)
+	}
+
+	cache.WithMaxEntries(1)
+	if stats := cache.Stats(); stats.Types.Entries != 1 || stats.Types.Evictions != 2 {
+		t.Errorf("Stats() after shrinking = %v, want 1 entry and 2 evictions", stats)
+	}
+	cache.WithMaxEntries(0)
+	cache.SetType(key("D"), &TypeInfo{Name: "D"})
+	cache.SetType(key("E"), &TypeInfo{Name: "E"})
+	if stats := cache.Stats(); stats.Types.Entries != 3 {
+		t.Errorf("Stats() without a limit = %v, want 3 entries", stats)
+	}
+}
+
+func TestCacheValidationEviction(t *testing.T) {
+	cache := NewCache(time.Minute).WithMaxEntries(1)
+	first := ValidationCacheKey{Scope: "project", Level: ValidationLevelBasic, Hash: "1"}
+	second := ValidationCacheKey{Scope: "project", Level: ValidationLevelBasic, Hash: "2"}
+
+	cache.SetValidation(first, &ValidationResult{Name: "first"})
+	cache.SetValidation(second, &ValidationResult{Name: "second"})
+	if _, ok := cache.GetValidation(first); ok {
+		t.Error("GetValidation(first) hit after it was evicted")
+	}
+	if result, ok := cache.GetValidation(second); !ok || result.Name != "second" {
+		t.Errorf("GetValidation(second) = %v, %v, want the second result", result, ok)
+	}
+	if stats := cache.Stats(); stats.Validations.Evictions != 1 {
+		t.Errorf("Stats() = %v, want 1 validation eviction", stats)
+	}
+}
+
+func TestCacheExpiry(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewCache(time.Minute)
+	cache.now = func() time.Time { return now }
+	key := func(name string) TypeCacheKey {
+		return TypeCacheKey{Package: "p", TypeName: name, Kind: "type"}
+	}
+
+	cache.SetType(key("A"), &TypeInfo{Name: "A"})
+	now = now.Add(30 * time.Second)
+	cache.SetType(key("B"), &TypeInfo{Name: "B"})
+	if _, ok := cache.GetType(key("A")); !ok {
+		t.Fatal("GetType(A) missed before it expired")
+	}
+
+	now = now.Add(30 * time.Second)
+	if _, ok := cache.GetType(key("A")); ok {
+		t.Error("GetType(A) hit after it expired")
+	}
+	if _, ok := cache.GetType(key("B")); !ok {
+		t.Error("GetType(B) missed before it expired")
+	}
+
+	// Storing after a TTL has passed sweeps the entries nobody asked for
+	now = now.Add(time.Minute)
+	cache.SetType(key("C"), &TypeInfo{Name: "C"})
+	if stats := cache.Stats(); stats.Types.Entries != 1 || stats.Types.Expirations != 2 {
+		t.Errorf("Stats() = %v, want only the fresh entry and 2 expirations", stats)
+	}
+}
+
+func TestCacheStats(t *testing.T) {
+	cache := NewCache(time.Minute)
+	key := func(name string) TypeCacheKey {
+		return TypeCacheKey{Package: "p", TypeName: name, Kind: "type"}
+	}
+	if stats := cache.Stats(); stats.Types.HitRatio != 0 {
+		t.Errorf("Stats() without lookups = %v, want a hit ratio of 0", stats)
+	}
+
+	// Lookups from many goroutines must be safe, see go test -race
+	cache.SetType(key("A"), &TypeInfo{Name: "A"})
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cache.GetType(key("A"))
+			cache.GetType(key("B"))
+			_ = cache.Stats()
+		}()
+	}
+	wg.Wait()
+
+	stats := cache.Stats()
+	if stats.Types.Hits != 8 || stats.Types.Misses != 8 || stats.Types.HitRatio != 0.5 {
+		t.Errorf("Stats() = %v, want 8 hits, 8 misses and a hit ratio of 0.5", stats)
+	}
+	if stats.Packages.Misses != 0 || stats.Validations.HitRatio != 0 {
+		t.Errorf("Stats() = %v, want no package or validation lookups", stats)
+	}
+
+	// Totals cover every kind
+	cache.SetValidation(ValidationCacheKey{Scope: "project", Hash: "abc"}, &ValidationResult{Name: "p"})
+	stats = cache.Stats()
+	if !stats.Enabled || stats.Entries != 2 || stats.Hits != 8 || stats.BytesUsed != stats.Types.BytesUsed+stats.Validations.BytesUsed {
+		t.Errorf("Stats() = %+v, want totals over types and validations", stats)
+	}
+	data, err := json.Marshal(stats)
+	if err != nil {
+		t.Fatalf("Failed to marshal stats: %v", err)
+	}
+	for _, want := range []string{`"enabled":true`, `"hits":8`, `"hit_ratio":0.5`, `"validations":{`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("json.Marshal(Stats()) = %s, want it to contain %s", data, want)
+		}
+	}
+}
+
+func TestCacheDependsOn(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "p.go")
+	if err := os.WriteFile(file, []byte("package p\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	missing := filepath.Join(filepath.Dir(file), "new.go")
+
+	cache := NewCache(time.Minute)
+	key := TypeCacheKey{Package: "p", TypeName: "T"}
+	cache.SetType(key, &TypeInfo{Name: "T"}, DependsOn(file, missing))
+	if _, ok := cache.GetType(key); !ok {
+		t.Fatal("GetType() missed with unchanged files")
+	}
+
+	if err := os.WriteFile(missing, []byte("package p\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	if _, ok := cache.GetType(key); ok {
+		t.Error("GetType() hit after a file it depends on was created")
+	}
+
+	cache.SetType(key, &TypeInfo{Name: "T"}, DependsOn(file))
+	if err := os.WriteFile(file, []byte("package p\n\ntype T int\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	if _, ok := cache.GetType(key); ok {
+		t.Error("GetType() hit after a file it depends on was modified")
+	}
+}
+
+func TestFindTypeAfterEdit(t *testing.T) {
+	tmpDir := t.TempDir()
+	file := filepath.Join(tmpDir, "p.go")
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example.com/p\n\ngo 1.22\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	write("package p\n\ntype ID int\n")
+
+	ctx := context.Background()
+	analyzer := NewAnalyzer(WithWorkDir(tmpDir), WithCacheTTL(time.Hour))
+	if info, err := analyzer.FindType(ctx, ".", "ID"); err != nil || info.Type != "int" {
+		t.Fatalf("FindType() = %+v, %v, want int", info, err)
+	}
+	write("package p\n\ntype ID string\n")
+	if info, err := analyzer.FindType(ctx, ".", "ID"); err != nil || info.Type != "string" {
+		t.Errorf("FindType() after an edit = %+v, %v, want string", info, err)
+	}
+}
+
+func TestCacheExportImport(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "p.go")
+	if err := os.WriteFile(file, []byte("package p\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	cache := NewCache(time.Minute)
+	typeKey := TypeCacheKey{Package: "p", TypeName: "T"}
+	cache.SetType(typeKey, &TypeInfo{Name: "T", Package: "p"}, DependsOn(file))
+	unstamped := TypeCacheKey{Package: "p", TypeName: "U"}
+	cache.SetType(unstamped, &TypeInfo{Name: "U", Package: "p"})
+	analysisKey := AnalysisCacheKey{Kind: "package", Dir: "/p", Path: "."}
+	cache.SetAnalysis(analysisKey, &AnalysisResult{Name: "p", Imports: []string{"fmt"}}, DependsOn(file))
+	extended := AnalysisCacheKey{Kind: "project", Dir: "/p", Path: "."}
+	cache.SetAnalysis(extended, &AnalysisResult{Name: "p", Extensions: map[string][]Entity{"x": {{Name: "e"}}}}, DependsOn(file))
+	validationKey := ValidationCacheKey{Scope: "package:p", Level: ValidationLevelStandard, Hash: "abc"}
+	cache.SetValidation(validationKey, &ValidationResult{Name: "p", Valid: true})
+
+	var buf bytes.Buffer
+	if err := cache.Export(&buf); err != nil {
+		t.Fatalf("Failed to export cache: %v", err)
+	}
+	exported := buf.Bytes()
+
+	// A fresh checkout changes modification times but not content
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(file, later, later); err != nil {
+		t.Fatalf("Failed to touch test file: %v", err)
+	}
+	restored := NewCache(time.Minute)
+	if err := restored.Import(bytes.NewReader(exported)); err != nil {
+		t.Fatalf("Failed to import cache: %v", err)
+	}
+	if info, ok := restored.GetType(typeKey); !ok || info.Name != "T" {
+		t.Errorf("GetType() = %v, %v after import, want T", info, ok)
+	}
+	if _, ok := restored.GetType(unstamped); ok {
+		t.Error("GetType() hit a type that does not record its files")
+	}
+	if result, ok := restored.GetAnalysis(analysisKey); !ok || !reflect.DeepEqual(result.Imports, []string{"fmt"}) {
+		t.Errorf("GetAnalysis() = %+v, %v after import, want the exported result", result, ok)
+	}
+	if _, ok := restored.GetAnalysis(extended); ok {
+		t.Error("GetAnalysis() hit a result holding extractor entities")
+	}
+	if result, ok := restored.GetValidation(validationKey); !ok || !result.Valid {
+		t.Errorf("GetValidation() = %+v, %v after import, want the exported result", result, ok)
+	}
+
+	// Entries whose files changed since the export are skipped
+	if err := os.WriteFile(file, []byte("package p\n\ntype T int\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	restored = NewCache(time.Minute)
+	if err := restored.Import(bytes.NewReader(exported)); err != nil {
+		t.Fatalf("Failed to import cache: %v", err)
+	}
+	if _, ok := restored.GetType(typeKey); ok {
+		t.Error("GetType() hit after its file changed")
+	}
+	if _, ok := restored.GetValidation(validationKey); !ok {
+		t.Error("GetValidation() missed, want it keyed by its own hash")
+	}
+
+	for _, input := range []string{"not json", `{"version":"other"}`} {
+		if err := NewCache(time.Minute).Import(strings.NewReader(input)); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("Import(%q) = %v, want ErrInvalidInput", input, err)
+		}
+	}
+}
+
+func TestCacheMaxBytes(t *testing.T) {
+	key := func(name string) TypeCacheKey {
+		return TypeCacheKey{Package: "p", TypeName: name, Kind: "type"}
+	}
+	size := jsonSize(&TypeInfo{Name: "A"})
+	cache := NewCache(time.Minute).WithMaxBytes(2*size + 1)
+
+	for _, name := range []string{"A", "B", "C"} {
+		cache.SetType(key(name), &TypeInfo{Name: name})
+	}
+	if _, ok := cache.GetType(key("A")); ok {
+		t.Error("GetType(A) hit, want it evicted to stay within the size limit")
+	}
+	stats := cache.Stats()
+	if stats.Types.BytesUsed != 2*size || stats.Types.Evictions != 1 {
+		t.Errorf("Stats() = %v, want %d bytes and 1 eviction", stats, 2*size)
+	}
+
+	cache.WithMaxBytes(size)
+	if stats := cache.Stats(); stats.Types.Entries != 1 || stats.Types.BytesUsed != size {
+		t.Errorf("Stats() after shrinking = %v, want 1 entry of %d bytes", stats, size)
+	}
+}
+
+func TestAnalyzerMaxCacheBytes(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example.com/p\n\ngo 1.22\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "p.go"), []byte("package p\n\ntype ID int\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	// Loaded packages are far larger than a type lookup, so only the
+	// lookup fits
+	analyzer := NewAnalyzer(WithWorkDir(tmpDir), WithCacheTTL(time.Minute), WithMaxCacheBytes(1<<10))
+	if _, err := analyzer.FindType(context.Background(), ".", "ID"); err != nil {
+		t.Fatalf("Failed to find type: %v", err)
+	}
+	stats := analyzer.GetCacheStats()
+	if stats.Packages.Entries != 0 || stats.Packages.Evictions != 1 || stats.Types.Entries != 1 {
+		t.Errorf("GetCacheStats() = %v, want the package evicted and the type kept", stats)
+	}
+}
+
+func TestCacheExpiresAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewCache(time.Minute)
+	cache.now = func() time.Time { return now }
+	key := func(name string) TypeCacheKey {
+		return TypeCacheKey{Package: "p", TypeName: name, Kind: "type"}
+	}
+
+	cache.SetType(key("Long"), &TypeInfo{Name: "Long"}, ExpiresAfter(time.Hour))
+	cache.SetType(key("Short"), &TypeInfo{Name: "Short"}, ExpiresAfter(time.Second))
+	cache.SetType(key("Default"), &TypeInfo{Name: "Default"})
+
+	now = now.Add(2 * time.Second)
+	if _, ok := cache.GetType(key("Short")); ok {
+		t.Error("GetType(Short) hit after its own TTL")
+	}
+	if _, ok := cache.GetType(key("Default")); !ok {
+		t.Error("GetType(Default) missed before the cache TTL")
+	}
+
+	// Sweeping keeps entries whose own TTL has not passed
+	now = now.Add(2 * time.Minute)
+	cache.SetType(key("Other"), &TypeInfo{Name: "Other"})
+	if _, ok := cache.GetType(key("Default")); ok {
+		t.Error("GetType(Default) hit after the cache TTL")
+	}
+	if _, ok := cache.GetType(key("Long")); !ok {
+		t.Error("GetType(Long) missed before its own TTL")
+	}
+}
+
+func TestStdlibCacheTTL(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example.com/p\n\ngo 1.22\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "p.go"), []byte("package p\n\ntype ID int\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	ctx := context.Background()
+	analyzer := NewAnalyzer(WithWorkDir(tmpDir), WithCacheTTL(time.Minute), WithStdlibCacheTTL(time.Hour))
+	now := time.Now()
+	analyzer.cache.now = func() time.Time { return now }
+	if _, err := analyzer.FindType(ctx, "fmt", "Stringer"); err != nil {
+		t.Fatalf("Failed to find type: %v", err)
+	}
+	if _, err := analyzer.FindType(ctx, ".", "ID"); err != nil {
+		t.Fatalf("Failed to find type: %v", err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok := analyzer.cache.GetType(analyzer.typeKey("fmt", "Stringer", "")); !ok {
+		t.Error("GetType(fmt.Stringer) missed before the stdlib TTL")
+	}
+	if _, ok := analyzer.cache.GetType(analyzer.typeKey(".", "ID", "")); ok {
+		t.Error("GetType(ID) hit after the cache TTL")
+	}
+}
+
+func TestCacheInvalidatePackage(t *testing.T) {
+	cache := NewCache(time.Minute)
+	cache.SetType(TypeCacheKey{Package: "p1", TypeName: "A"}, &TypeInfo{Name: "A", Package: "p1"})
+	cache.SetType(TypeCacheKey{Package: "p2", TypeName: "A"}, &TypeInfo{Name: "A", Package: "p1"}) // Found among p2's imports
+	cache.SetType(TypeCacheKey{Package: "p2", TypeName: "B"}, &TypeInfo{Name: "B", Package: "p2"})
+	p1 := &packages.Package{PkgPath: "p1"}
+	cache.SetPackages(PackageCacheKey{Dir: "/m", Pattern: "./..."}, []*packages.Package{
+		{PkgPath: "p2", Imports: map[string]*packages.Package{"p1": p1}},
+	})
+	cache.SetPackages(PackageCacheKey{Dir: "/m", Pattern: "p3"}, []*packages.Package{{PkgPath: "p3"}})
+	cache.SetAnalysis(AnalysisCacheKey{Kind: "project", Dir: "/m", Path: "."}, &AnalysisResult{
+		Types: []TypeInfo{{Name: "A", Package: "p1"}},
+	})
+	cache.SetAnalysis(AnalysisCacheKey{Kind: "package", Dir: "/m", Path: "p2"}, &AnalysisResult{
+		Types:   []TypeInfo{{Name: "B", Package: "p2"}},
+		Imports: []string{"p1"},
+	})
+	cache.SetValidation(ValidationCacheKey{Scope: "package:p1", Hash: "abc"}, &ValidationResult{Name: "p1"})
+	cache.SetValidation(ValidationCacheKey{Scope: "package:p2", Hash: "abc"}, &ValidationResult{Name: "p2"})
+
+	if n := cache.InvalidatePackage("p1"); n != 5 {
+		t.Errorf("InvalidatePackage() = %d, want 5 entries dropped", n)
+	}
+	stats := cache.Stats()
+	if stats.Types.Entries != 1 || stats.Packages.Entries != 1 || stats.Analyses.Entries != 1 || stats.Validations.Entries != 1 {
+		t.Errorf("Stats() = %+v, want one entry of each kind left", stats)
+	}
+	if _, ok := cache.GetType(TypeCacheKey{Package: "p2", TypeName: "B"}); !ok {
+		t.Error("GetType(p2.B) missed, want entries of other packages kept")
+	}
+}
+
+func TestAnalyzerInvalidateAll(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTestFiles(t, tmpDir)
+	ctx := context.Background()
+	analyzer := NewAnalyzer(WithWorkDir(tmpDir), WithCacheTTL(time.Minute))
+	if _, err := analyzer.FindType(ctx, "./testdata/basic", "User"); err != nil {
+		t.Fatalf("Failed to find type: %v", err)
+	}
+	if _, err := analyzer.AnalyzePackage(ctx, "./testdata/basic"); err != nil {
+		t.Fatalf("Failed to analyze package: %v", err)
+	}
+
+	analyzer.InvalidateAll()
+	if stats := analyzer.GetCacheStats(); stats.Entries != 0 || stats.BytesUsed != 0 {
+		t.Errorf("GetCacheStats() = %+v, want no entries after InvalidateAll", stats)
+	}
+}
@@ -0,0 +1,175 @@
+package readgo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// cacheExportVersion changes whenever the export format does, so that
+// exports written by other versions are rejected
+const cacheExportVersion = "readgo-cache-export-1"
+
+// cacheExport is the serialized form of a Cache. Loaded packages cannot be
+// serialized and are left out.
+type cacheExport struct {
+	Version     string                                                 `json:"version"`
+	Types       []exportedEntry[TypeCacheKey, *TypeInfo]               `json:"types,omitempty"`
+	Analyses    []exportedEntry[AnalysisCacheKey, *AnalysisResult]     `json:"analyses,omitempty"`
+	Validations []exportedEntry[ValidationCacheKey, *ValidationResult] `json:"validations,omitempty"`
+}
+
+// exportedEntry is a cache entry along with the content hashes of the files
+// it depends on. Modification times do not survive a fresh checkout, so
+// files are compared by content when the entry is imported.
+type exportedEntry[K comparable, V any] struct {
+	Key   K                 `json:"key"`
+	Value V                 `json:"value"`
+	Files map[string]string `json:"files,omitempty"` // Path to content hash, "" if missing
+}
+
+// Export writes the cached type information, analysis results and
+// validation results to w, so that a later process, such as the next CI
+// run, can restore them with Import. Stale entries are left out, as are
+// types and analyses that do not record the files they depend on and
+// analyses holding extractor entities, whose values may not survive JSON.
+func (c *Cache) Export(w io.Writer) error {
+	export := cacheExport{Version: cacheExportVersion}
+	if c != nil {
+		c.mu.Lock()
+		now := c.now()
+		export.Types = exportEntries(c.types, now, true)
+		for _, entry := range exportEntries(c.analyses, now, true) {
+			if len(entry.Value.Extensions) == 0 {
+				export.Analyses = append(export.Analyses, entry)
+			}
+		}
+		export.Validations = exportEntries(c.results, now, false)
+		c.mu.Unlock()
+	}
+
+	if err := json.NewEncoder(w).Encode(export); err != nil {
+		return fmt.Errorf("failed to export cache: %w", err)
+	}
+	return nil
+}
+
+// Import restores entries written by Export. Entries whose files changed
+// since they were exported are skipped; the others are stored as if they
+// had just been computed, so they expire one TTL from now. Import does
+// nothing if caching is disabled.
+func (c *Cache) Import(r io.Reader) error {
+	var export cacheExport
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return fmt.Errorf("%w: failed to decode cache export: %v", ErrInvalidInput, err)
+	}
+	if export.Version != cacheExportVersion {
+		return fmt.Errorf("%w: unsupported cache export version %q", ErrInvalidInput, export.Version)
+	}
+	if c == nil || c.ttl <= 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires := c.now().Add(c.ttl)
+	importEntries(c.types, export.Types, expires)
+	importEntries(c.analyses, export.Analyses, expires)
+	importEntries(c.results, export.Validations, expires)
+	c.trimBytes()
+	return nil
+}
+
+// exportEntries returns the live entries of l, most recently used first.
+// With needFiles, entries that do not record their files are left out.
+func exportEntries[K comparable, V any](l *lru[K, V], now time.Time, needFiles bool) []exportedEntry[K, V] {
+	var entries []exportedEntry[K, V]
+	for elem := l.order.Front(); elem != nil; elem = elem.Next() {
+		entry := elem.Value.(*lruEntry[K, V])
+		if !now.Before(entry.expires) || !unchanged(entry.stamps) || (needFiles && len(entry.stamps) == 0) {
+			continue
+		}
+		files, ok := digestFiles(entry.stamps)
+		if !ok {
+			continue
+		}
+		entries = append(entries, exportedEntry[K, V]{Key: entry.key, Value: entry.value, Files: files})
+	}
+	return entries
+}
+
+// digestFiles returns the content hashes of the stamped files
+func digestFiles(stamps []fileStamp) (map[string]string, bool) {
+	if len(stamps) == 0 {
+		return nil, true
+	}
+	files := make(map[string]string, len(stamps))
+	for _, stamp := range stamps {
+		digest, ok := fileDigest(stamp.path)
+		if !ok {
+			return nil, false
+		}
+		files[stamp.path] = digest
+	}
+	return files, true
+}
+
+// importEntries stores the entries whose files are unchanged in l, keeping
+// their order of use
+func importEntries[K comparable, V any](l *lru[K, V], entries []exportedEntry[K, V], expires time.Time) {
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if files, ok := unchangedContent(entry.Files); ok {
+			l.set(entry.Key, entry.Value, expires, stampFiles(files), jsonSize(entry.Value))
+		}
+	}
+}
+
+// unchangedContent returns the paths of files if they all still have the
+// given content hashes
+func unchangedContent(files map[string]string) ([]string, bool) {
+	paths := make([]string, 0, len(files))
+	for path, digest := range files {
+		if current, ok := fileDigest(path); !ok || current != digest {
+			return nil, false
+		}
+		paths = append(paths, path)
+	}
+	return paths, true
+}
+
+// fileDigest returns a hash of the content of path: of the file's bytes,
+// or of the entry names of a directory, and "" if path is missing. It
+// reports false if path cannot be read.
+func fileDigest(path string) (string, bool) {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return "", true
+	}
+	if err != nil {
+		return "", false
+	}
+
+	h := sha256.New()
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return "", false
+		}
+		for _, entry := range entries {
+			fmt.Fprintln(h, entry.Name())
+		}
+	} else {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", false
+		}
+		h.Write(data)
+	}
+	return hex.EncodeToString(h.Sum(nil)), true
+}
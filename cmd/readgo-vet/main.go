@@ -0,0 +1,47 @@
+// Command readgo-vet runs the validator's rules as a go vet tool:
+//
+//	go install github.com/iamlongalong/readgo/cmd/readgo-vet@latest
+//	go vet -vettool=$(which readgo-vet) ./...
+//
+// The rules read their settings from the nearest .readgo.yaml above the
+// package being checked, up to the root of its module.
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/iamlongalong/readgo"
+	"golang.org/x/tools/go/analysis/unitchecker"
+)
+
+func main() {
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "readgo-vet: %v\n", err)
+		os.Exit(1)
+	}
+	unitchecker.Main(readgo.RuleAnalyzers(cfg)...)
+}
+
+// loadConfig reads the nearest validator configuration above the working
+// directory, where go vet runs the tool for each package. It stops at the
+// directory holding go.mod and yields nil if there is none.
+func loadConfig() (*readgo.ValidatorConfig, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		path := filepath.Join(dir, readgo.ValidatorConfigFile)
+		if _, err := os.Stat(path); err == nil {
+			return readgo.LoadValidatorConfig(path)
+		}
+		parent := filepath.Dir(dir)
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil || parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
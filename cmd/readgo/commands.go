@@ -0,0 +1,402 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/iamlongalong/readgo"
+	"github.com/iamlongalong/readgo/lsp"
+)
+
+// runAnalyze analyzes a project directory, a file or a package. Directories
+// are analyzed with every package below them, .go files on their own and
+// anything else is loaded as a package pattern.
+func runAnalyze(ctx context.Context, e *env, args []string) int {
+	out := e.outputFlags()
+	exported := e.flags.Bool("exported", false, "keep only exported types and functions")
+	pkgGlob := e.flags.String("package", "", "keep only the packages whose import path matches the glob")
+	var kinds listFlag
+	e.flags.Var(&kinds, "kind", "keep only types of the given kinds, e.g. struct,interface")
+	rest, code, ok := e.parse(args, 0, 1)
+	if !ok {
+		return code
+	}
+	target := "."
+	if len(rest) == 1 {
+		target = rest[0]
+	}
+
+	analyzer := readgo.NewAnalyzer()
+	var result *readgo.AnalysisResult
+	var err error
+	if info, statErr := os.Stat(target); statErr == nil && info.IsDir() {
+		result, err = readgo.NewAnalyzer(readgo.WithWorkDir(target)).AnalyzeProject(ctx, ".")
+	} else if strings.HasSuffix(target, ".go") {
+		result, err = analyzer.AnalyzeFile(ctx, target)
+	} else {
+		result, err = analyzer.AnalyzePackage(ctx, target)
+	}
+	if err != nil {
+		return e.fail(err)
+	}
+
+	if *exported {
+		result = result.ExportedOnly()
+	}
+	if *pkgGlob != "" {
+		result = result.FilterPackage(*pkgGlob)
+	}
+	if len(kinds) > 0 {
+		typeKinds := make([]readgo.TypeKind, len(kinds))
+		for i, kind := range kinds {
+			typeKinds[i] = readgo.TypeKind(kind)
+		}
+		result = result.FilterKind(typeKinds...)
+	}
+	return e.write(out, result, func(w io.Writer) error { return writeAnalysis(w, result) })
+}
+
+// writeAnalysis writes the types and functions of r, grouped by package
+// when r holds a project
+func writeAnalysis(w io.Writer, r *readgo.AnalysisResult) error {
+	pkgs := r.Packages
+	if pkgs == nil {
+		pkgs = []readgo.PackageAnalysis{{Name: r.Name, Path: r.Path, Types: r.Types, Functions: r.Functions}}
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for i, pkg := range pkgs {
+		if i > 0 {
+			fmt.Fprintln(tw)
+		}
+		fmt.Fprintf(tw, "package %s\n", pkg.Path)
+		for _, t := range pkg.Types {
+			fmt.Fprintf(tw, "  type %s\t%s\n", t.Name, t.Kind)
+		}
+		for _, f := range pkg.Functions {
+			signature := f.Signature
+			if signature == "" {
+				signature = "func " + f.Name
+			}
+			fmt.Fprintf(tw, "  %s\t%s\n", signature, position(f.File, f.StartLine))
+		}
+	}
+	return tw.Flush()
+}
+
+// position formats a file and line, leaving out what is unknown
+func position(file string, line int) string {
+	if file == "" || line == 0 {
+		return file
+	}
+	return fmt.Sprintf("%s:%d", file, line)
+}
+
+// runFind looks up a type, interface or function in a package
+func runFind(ctx context.Context, e *env, args []string) int {
+	out := e.outputFlags()
+	kind := e.flags.String("kind", "type", "what to look up: type, interface or func")
+	rest, code, ok := e.parse(args, 2, 2)
+	if !ok {
+		return code
+	}
+
+	analyzer := readgo.NewAnalyzer()
+	var info *readgo.TypeInfo
+	var err error
+	switch *kind {
+	case "type":
+		info, err = analyzer.FindType(ctx, rest[0], rest[1])
+	case "interface":
+		info, err = analyzer.FindInterface(ctx, rest[0], rest[1])
+	case "func", "function":
+		info, err = analyzer.FindFunction(ctx, rest[0], rest[1])
+	default:
+		err = fmt.Errorf("%w: unknown kind %q", readgo.ErrInvalidInput, *kind)
+	}
+	if err != nil {
+		return e.fail(err)
+	}
+	return e.write(out, info, func(w io.Writer) error {
+		_, err := fmt.Fprintf(w, "%s %s in %s\n%s\n", info.Kind, info.Name, info.Package, info.Type)
+		return err
+	})
+}
+
+// runTree prints the file tree of a directory
+func runTree(ctx context.Context, e *env, args []string) int {
+	out := e.outputFlags()
+	fileType := e.flags.String("type", string(readgo.FileTypeAll), "files to list: all, go, test, generated or go_package")
+	limit := e.flags.Int("limit", 0, "most nodes to list, 0 for no limit")
+	var include, exclude listFlag
+	e.flags.Var(&include, "include", "list only files matching the glob; may be repeated")
+	e.flags.Var(&exclude, "exclude", "leave out files and directories matching the glob; may be repeated")
+	rest, code, ok := e.parse(args, 0, 1)
+	if !ok {
+		return code
+	}
+	root := "."
+	if len(rest) == 1 {
+		root = rest[0]
+	}
+
+	tree, err := readgo.NewSourceReader(".").GetFileTree(ctx, root, readgo.TreeOptions{
+		FileTypes:       readgo.FileType(*fileType),
+		IncludePatterns: include,
+		ExcludePatterns: exclude,
+		Limit:           *limit,
+	})
+	if err != nil {
+		return e.fail(err)
+	}
+	return e.write(out, tree, func(w io.Writer) error { return writeTree(w, tree, "") })
+}
+
+// writeTree writes node and its children, one per line, indenting each
+// level by two spaces
+func writeTree(w io.Writer, node *readgo.FileTreeNode, indent string) error {
+	name := node.Name
+	if node.Type == "directory" {
+		name += "/"
+	}
+	if _, err := fmt.Fprintf(w, "%s%s\n", indent, name); err != nil {
+		return err
+	}
+	for _, child := range node.Children {
+		if err := writeTree(w, child, indent+"  "); err != nil {
+			return err
+		}
+	}
+	if node.Truncated {
+		_, err := fmt.Fprintf(w, "%s  ...\n", indent)
+		return err
+	}
+	return nil
+}
+
+// severityRanks orders the severities for -fail-on
+var severityRanks = map[readgo.Severity]int{
+	readgo.SeverityInfo:    1,
+	readgo.SeverityWarning: 2,
+	readgo.SeverityError:   3,
+}
+
+// runValidate validates a project, exiting with exitFindings when a finding
+// is at least as severe as -fail-on
+func runValidate(ctx context.Context, e *env, args []string) int {
+	out := e.outputFlags()
+	levelName := e.flags.String("level", "standard", "validation level: basic, standard or strict")
+	configPath := e.flags.String("config", "", "validator configuration, "+readgo.ValidatorConfigFile+" in the project by default")
+	failOn := e.flags.String("fail-on", "error", "lowest severity that fails validation: error, warning, info or none")
+	rest, code, ok := e.parse(args, 0, 1)
+	if !ok {
+		return code
+	}
+	dir := "."
+	if len(rest) == 1 {
+		dir = rest[0]
+	}
+
+	level, err := readgo.ParseValidationLevel(*levelName)
+	if err != nil {
+		return e.fail(err)
+	}
+	threshold, known := severityRanks[readgo.Severity(*failOn)]
+	if !known && *failOn != "none" {
+		return e.fail(fmt.Errorf("%w: unknown severity %q", readgo.ErrInvalidInput, *failOn))
+	}
+	if *configPath == "" {
+		*configPath = dir
+	}
+	cfg, err := readgo.LoadValidatorConfig(*configPath)
+	if err != nil {
+		return e.fail(err)
+	}
+
+	result, err := readgo.NewValidator(dir).WithConfig(cfg).ValidateProject(ctx, level)
+	if err != nil {
+		return e.fail(err)
+	}
+	if code := e.write(out, result, func(w io.Writer) error { return writeValidation(w, result) }); code != exitOK {
+		return code
+	}
+	return e.validationStatus(ctx, result, threshold)
+}
+
+// validationStatus returns the exit status for result: exitFailure when the
+// validation was cancelled or timed out, since its findings are incomplete,
+// and exitFindings when a finding is at least as severe as threshold
+func (e *env) validationStatus(ctx context.Context, result *readgo.ValidationResult, threshold int) int {
+	if result.Cancelled || ctx.Err() != nil {
+		cause := context.Cause(ctx)
+		if cause == nil {
+			cause = context.Canceled
+		}
+		return e.fail(fmt.Errorf("validation did not finish: %w", cause))
+	}
+	if threshold > 0 && severityRanks[worstSeverity(result)] >= threshold {
+		return exitFindings
+	}
+	return exitOK
+}
+
+// warningSeverity returns the severity of w, which is a warning if unset
+func warningSeverity(w readgo.ValidationWarning) readgo.Severity {
+	if w.Severity == "" {
+		return readgo.SeverityWarning
+	}
+	return w.Severity
+}
+
+// worstSeverity returns the highest severity of the findings of r, or ""
+// if it has none
+func worstSeverity(r *readgo.ValidationResult) readgo.Severity {
+	if len(r.Errors) > 0 {
+		return readgo.SeverityError
+	}
+	var worst readgo.Severity
+	for _, w := range r.Warnings {
+		if s := warningSeverity(w); severityRanks[s] > severityRanks[worst] {
+			worst = s
+		}
+	}
+	return worst
+}
+
+// writeValidation writes the findings of r, one per line, and a summary
+func writeValidation(w io.Writer, r *readgo.ValidationResult) error {
+	counts := make(map[readgo.Severity]int)
+	for _, msg := range r.Errors {
+		fmt.Fprintf(w, "error: %s\n", msg)
+		counts[readgo.SeverityError]++
+	}
+	for _, warning := range r.Warnings {
+		severity := warningSeverity(warning)
+		counts[severity]++
+		where := position(warning.File, warning.Line)
+		if where != "" && warning.Line > 0 && warning.Column > 0 {
+			where += fmt.Sprintf(":%d", warning.Column)
+		}
+		if where != "" {
+			where += ": "
+		}
+		fmt.Fprintf(w, "%s%s: %s (%s)\n", where, severity, warning.Message, warning.Type)
+	}
+	status := "valid"
+	if !r.Valid {
+		status = "invalid"
+	}
+	_, err := fmt.Fprintf(w, "%s: %d errors, %d warnings, %d info\n",
+		status, counts[readgo.SeverityError], counts[readgo.SeverityWarning], counts[readgo.SeverityInfo])
+	return err
+}
+
+// depsReport is the output of the deps command
+type depsReport struct {
+	Module       *readgo.ModuleInfo  `json:"module,omitempty" yaml:"module,omitempty"`
+	Dependencies []readgo.Dependency `json:"dependencies" yaml:"dependencies"`
+}
+
+// runDeps lists the packages imported by a project and the modules it
+// requires
+func runDeps(ctx context.Context, e *env, args []string) int {
+	out := e.outputFlags()
+	var kinds listFlag
+	e.flags.Var(&kinds, "kind", "list only imports of the given kinds: std, internal or external")
+	rest, code, ok := e.parse(args, 0, 1)
+	if !ok {
+		return code
+	}
+	dir := "."
+	if len(rest) == 1 {
+		dir = rest[0]
+	}
+
+	result, err := readgo.NewAnalyzer(readgo.WithWorkDir(dir)).AnalyzeProject(ctx, ".")
+	if err != nil {
+		return e.fail(err)
+	}
+	report := &depsReport{Module: result.Module, Dependencies: []readgo.Dependency{}}
+	for _, dep := range result.Dependencies {
+		if len(kinds) == 0 || slices.Contains(kinds, string(dep.Kind)) {
+			report.Dependencies = append(report.Dependencies, dep)
+		}
+	}
+	return e.write(out, report, func(w io.Writer) error { return writeDeps(w, report) })
+}
+
+// writeDeps writes the module, its imports and its requirements
+func writeDeps(w io.Writer, r *depsReport) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if r.Module != nil {
+		fmt.Fprintf(tw, "module %s", r.Module.Path)
+		if r.Module.GoVersion != "" {
+			fmt.Fprintf(tw, " (go %s)", r.Module.GoVersion)
+		}
+		fmt.Fprintln(tw)
+	}
+	fmt.Fprintln(tw, "\nimports:")
+	for _, dep := range r.Dependencies {
+		fmt.Fprintf(tw, "  %s\t%s\n", dep.Kind, dep.Path)
+	}
+	if r.Module != nil && len(r.Module.Requires) > 0 {
+		fmt.Fprintln(tw, "\nrequires:")
+		for _, req := range r.Module.Requires {
+			line := fmt.Sprintf("  %s\t%s", req.Path, req.Version)
+			if req.Replace != "" {
+				line += " => " + req.Replace
+			}
+			if req.Indirect {
+				line += " // indirect"
+			}
+			fmt.Fprintln(tw, line)
+		}
+	}
+	return tw.Flush()
+}
+
+// runGraph prints the import graph of a project
+func runGraph(ctx context.Context, e *env, args []string) int {
+	asJSON := e.flags.Bool("json", false, "print node-link JSON, same as -format json")
+	formatName := e.flags.String("format", string(readgo.GraphFormatDOT), "graph format: dot, graphml or json")
+	rest, code, ok := e.parse(args, 0, 1)
+	if !ok {
+		return code
+	}
+	dir := "."
+	if len(rest) == 1 {
+		dir = rest[0]
+	}
+	if *asJSON {
+		*formatName = string(readgo.GraphFormatJSON)
+	}
+	format, err := readgo.ParseGraphFormat(*formatName)
+	if err != nil {
+		return e.fail(err)
+	}
+
+	graph, err := readgo.NewValidator(dir).ImportGraph(ctx)
+	if err != nil {
+		return e.fail(err)
+	}
+	if err := graph.Encode(e.stdout, format); err != nil {
+		return e.fail(err)
+	}
+	return exitOK
+}
+
+// runLSP serves the Language Server Protocol over stdin and stdout until
+// the editor exits
+func runLSP(ctx context.Context, e *env, args []string) int {
+	if _, code, ok := e.parse(args, 0, 0); !ok {
+		return code
+	}
+	if err := lsp.NewServer().Serve(ctx, e.stdin, e.stdout); err != nil {
+		return e.fail(err)
+	}
+	return exitOK
+}
@@ -0,0 +1,198 @@
+// Command readgo analyzes, browses and validates Go projects from the
+// command line. It wraps the readgo library:
+//
+//	readgo analyze [flags] [dir | file.go | package]
+//	readgo find [flags] <package> <name>
+//	readgo tree [flags] [dir]
+//	readgo validate [flags] [dir]
+//	readgo deps [flags] [dir]
+//	readgo graph [flags] [dir]
+//	readgo lsp
+//
+// Every command but lsp prints text by default; -json or -format json, yaml
+// or toml print the result in the library's output format instead. readgo
+// lsp serves a read-only language server to an editor over stdin and stdout.
+// The exit status is 0 on success, 1 when validate reports findings at or
+// above the severity given by -fail-on, 2 on usage errors and 3 when a
+// command fails.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/iamlongalong/readgo"
+)
+
+// Exit statuses
+const (
+	exitOK       = 0
+	exitFindings = 1
+	exitUsage    = 2
+	exitFailure  = 3
+)
+
+// command is a subcommand of readgo
+type command struct {
+	name    string
+	args    string // Synopsis of the arguments, after the flags
+	summary string
+	run     func(ctx context.Context, env *env, args []string) int
+}
+
+// env is where a command reads its flags and writes its output
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	flags  *flag.FlagSet
+}
+
+// commands lists the subcommands in the order of the usage message
+var commands = []*command{
+	{"analyze", "[dir | file.go | package]", "list the types and functions of a project, file or package", runAnalyze},
+	{"find", "<package> <name>", "look up a type, interface or function of a package", runFind},
+	{"tree", "[dir]", "print the file tree of a directory", runTree},
+	{"validate", "[dir]", "validate a project and exit non-zero on findings", runValidate},
+	{"deps", "[dir]", "list the imports of a project and the modules it requires", runDeps},
+	{"graph", "[dir]", "print the import graph of a project", runGraph},
+	{"lsp", "", "serve symbols, definitions, references and diagnostics to an editor", runLSP},
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
+
+// run runs the command line args and returns the exit status
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitUsage
+	}
+	name := args[0]
+	if name == "help" || name == "-h" || name == "-help" || name == "--help" {
+		usage(stdout)
+		return exitOK
+	}
+	if name == "version" || name == "-version" || name == "--version" {
+		fmt.Fprintf(stdout, "readgo %s\n", readgo.Version())
+		return exitOK
+	}
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+		fs := flag.NewFlagSet("readgo "+cmd.name, flag.ContinueOnError)
+		fs.SetOutput(stderr)
+		fs.Usage = func() {
+			fmt.Fprintf(stderr, "usage: readgo %s [flags] %s\n\n%s.\n\nFlags:\n", cmd.name, cmd.args, capitalize(cmd.summary))
+			fs.PrintDefaults()
+		}
+		return cmd.run(ctx, &env{stdin: os.Stdin, stdout: stdout, stderr: stderr, flags: fs}, args[1:])
+	}
+	fmt.Fprintf(stderr, "readgo: unknown command %q\n\n", name)
+	usage(stderr)
+	return exitUsage
+}
+
+// usage writes the list of commands to w
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: readgo <command> [flags] [arguments]")
+	fmt.Fprintln(w, "\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-9s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w, "\nRun \"readgo <command> -h\" for the flags of a command.")
+}
+
+// capitalize returns s with its first letter in upper case
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// parse parses the flags of a command and checks that it got at most max
+// arguments, or exactly min if min == max
+func (e *env) parse(args []string, min, max int) ([]string, int, bool) {
+	if err := e.flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil, exitOK, false
+		}
+		return nil, exitUsage, false
+	}
+	rest := e.flags.Args()
+	if len(rest) < min || len(rest) > max {
+		e.flags.Usage()
+		return nil, exitUsage, false
+	}
+	return rest, exitOK, true
+}
+
+// fail reports err and returns the exit status of a failed command
+func (e *env) fail(err error) int {
+	fmt.Fprintf(e.stderr, "%s: %v\n", e.flags.Name(), err)
+	if errors.Is(err, readgo.ErrInvalidInput) {
+		return exitUsage
+	}
+	return exitFailure
+}
+
+// output holds the output flags shared by the commands
+type output struct {
+	json   bool
+	format string
+}
+
+// outputFlags registers -json and -format on the flags of e
+func (e *env) outputFlags() *output {
+	o := &output{}
+	e.flags.BoolVar(&o.json, "json", false, "print JSON, same as -format json")
+	e.flags.StringVar(&o.format, "format", "text", "output format: text, json, yaml or toml")
+	return o
+}
+
+// write prints v in the selected format, calling text for the text format
+func (e *env) write(o *output, v interface{}, text func(w io.Writer) error) int {
+	name := o.format
+	if o.json {
+		name = "json"
+	}
+	var err error
+	if name == "text" {
+		err = text(e.stdout)
+	} else {
+		var format readgo.Format
+		if format, err = readgo.ParseFormat(name); err == nil {
+			err = readgo.Encode(e.stdout, v, format)
+		}
+	}
+	if err != nil {
+		return e.fail(err)
+	}
+	return exitOK
+}
+
+// listFlag is a flag that may be repeated or hold comma-separated values
+type listFlag []string
+
+func (l *listFlag) String() string { return strings.Join(*l, ",") }
+
+func (l *listFlag) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*l = append(*l, v)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iamlongalong/readgo"
+)
+
+// writeProject writes a module with a clean package and one with a syntax
+// error into a temporary directory and returns it
+func writeProject(t *testing.T, broken bool) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":         "module example.com/cli\n\ngo 1.22\n",
+		"store/store.go": "// Package store keeps things\npackage store\n\n// Store holds things\ntype Store struct{}\n\n// Open opens a store\nfunc Open() *Store { return &Store{} }\n",
+	}
+	if broken {
+		files["bad/bad.go"] = "package bad\n\nfunc Broken( {\n"
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+	return dir
+}
+
+func TestRun(t *testing.T) {
+	clean := writeProject(t, false)
+	broken := writeProject(t, true)
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		wantOut  string // Substring of stdout
+	}{
+		{"no command", nil, exitUsage, ""},
+		{"unknown command", []string{"lint"}, exitUsage, ""},
+		{"help", []string{"help"}, exitOK, "Commands:"},
+		{"too many arguments", []string{"tree", "a", "b"}, exitUsage, ""},
+		{"analyze text", []string{"analyze", clean}, exitOK, "func Open() *Store  store/store.go:8"},
+		{"analyze json", []string{"analyze", "-json", clean}, exitOK, `"name": "Store"`},
+		{"analyze yaml", []string{"analyze", "-format", "yaml", "-kind", "interface", clean}, exitOK, "name: main"},
+		{"unknown format", []string{"analyze", "-format", "xml", clean}, exitUsage, ""},
+		{"tree", []string{"tree", "-type", "go", clean}, exitOK, "    store.go\n"},
+		{"validate clean", []string{"validate", clean}, exitOK, "valid: 0 errors"},
+		{"validate broken", []string{"validate", broken}, exitFindings, "invalid: "},
+		{"validate broken json", []string{"validate", "-json", broken}, exitFindings, `"valid": false`},
+		{"validate fail-on none", []string{"validate", "-fail-on", "none", broken}, exitOK, ""},
+		{"validate bad level", []string{"validate", "-level", "pedantic", clean}, exitUsage, ""},
+		{"deps", []string{"deps", clean}, exitOK, "module example.com/cli (go 1.22)"},
+		{"graph", []string{"graph", clean}, exitOK, `"example.com/cli/store";`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(context.Background(), tt.args, &stdout, &stderr)
+			if code != tt.wantCode {
+				t.Fatalf("run(%q) = %d, want %d\nstdout:\n%s\nstderr:\n%s", tt.args, code, tt.wantCode, stdout.String(), stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.wantOut) {
+				t.Errorf("run(%q) stdout lacks %q:\n%s", tt.args, tt.wantOut, stdout.String())
+			}
+		})
+	}
+}
+
+func TestValidationStatus(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	warning := []readgo.ValidationWarning{{Type: "unused_import", Message: "unused import"}}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		result    *readgo.ValidationResult
+		threshold int
+		want      int
+	}{
+		{"clean", context.Background(), &readgo.ValidationResult{Valid: true}, 3, exitOK},
+		{"findings", context.Background(), &readgo.ValidationResult{Warnings: warning}, 2, exitFindings},
+		{"below threshold", context.Background(), &readgo.ValidationResult{Warnings: warning}, 3, exitOK},
+		{"cancelled result", context.Background(), &readgo.ValidationResult{Cancelled: true}, 3, exitFailure},
+		{"cancelled context", cancelled, &readgo.ValidationResult{Valid: true}, 0, exitFailure},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stderr bytes.Buffer
+			e := &env{stderr: &stderr, flags: flag.NewFlagSet("readgo validate", flag.ContinueOnError)}
+			if got := e.validationStatus(tt.ctx, tt.result, tt.threshold); got != tt.want {
+				t.Errorf("validationStatus() = %d, want %d\nstderr:\n%s", got, tt.want, stderr.String())
+			}
+		})
+	}
+}
+
+func TestRunFind(t *testing.T) {
+	dir := writeProject(t, false)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to change directory: %v", err)
+	}
+	defer os.Chdir(wd)
+
+	var stdout, stderr bytes.Buffer
+	if code := run(context.Background(), []string{"find", "-json", "-kind", "func", "./store", "Open"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("find exited with %d: %s", code, stderr.String())
+	}
+	var info struct {
+		Name string `json:"name"`
+		Kind string `json:"kind"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &info); err != nil {
+		t.Fatalf("Failed to decode output: %v", err)
+	}
+	if info.Name != "Open" || info.Kind != "func" {
+		t.Errorf("find output = %+v, want func Open", info)
+	}
+
+	stdout.Reset()
+	if code := run(context.Background(), []string{"find", "./store", "Missing"}, &stdout, &stderr); code != exitFailure {
+		t.Errorf("find of a missing type exited with %d, want %d", code, exitFailure)
+	}
+}
@@ -1,10 +1,68 @@
 package readgo
 
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
 // Common constants for file operations
 const (
 	maxFileSize = 10 * 1024 * 1024 // 10MB
+
+	// binarySniffLen is the number of leading bytes inspected for binary detection
+	binarySniffLen = 8000
 )
 
+// defaultExcludedDirs lists directories skipped by tree walks unless
+// TreeOptions.NoDefaultExcludes is set
+var defaultExcludedDirs = map[string]bool{
+	"vendor":       true,
+	"node_modules": true,
+}
+
+// isHiddenDir reports whether a directory name denotes a hidden directory
+// such as .git or .idea
+func isHiddenDir(name string) bool {
+	return len(name) > 1 && strings.HasPrefix(name, ".") && name != ".."
+}
+
+// isDefaultExcludedDir reports whether a directory is excluded by default
+func isDefaultExcludedDir(name string) bool {
+	return defaultExcludedDirs[name] || isHiddenDir(name)
+}
+
+// goPackageExtensions lists the extensions of files the go tool treats as
+// part of a package (see go/build.Package)
+var goPackageExtensions = map[string]bool{
+	".go": true, ".s": true, ".S": true, ".sx": true,
+	".c": true, ".cc": true, ".cpp": true, ".cxx": true, ".m": true,
+	".h": true, ".hh": true, ".hpp": true, ".hxx": true,
+	".f": true, ".F": true, ".for": true, ".f90": true,
+	".swig": true, ".swigcxx": true, ".syso": true,
+}
+
+// matchesFileType reports whether a file named name matches ft. The
+// generated callback is only consulted for FileTypeGenerated.
+func matchesFileType(ft FileType, name string, generated func() bool) bool {
+	switch ft {
+	case FileTypeGo:
+		return filepath.Ext(name) == ".go"
+	case FileTypeTest:
+		return strings.HasSuffix(name, "_test.go")
+	case FileTypeGenerated:
+		return filepath.Ext(name) == ".go" && generated()
+	case FileTypeGoPackage:
+		return goPackageExtensions[filepath.Ext(name)]
+	default:
+		return true
+	}
+}
+
 // isAllowedExtension checks if the file extension is allowed
 func isAllowedExtension(ext string) bool {
 	allowedExts := map[string]bool{
@@ -14,3 +72,66 @@ func isAllowedExtension(ext string) bool {
 	}
 	return allowedExts[ext]
 }
+
+// binaryExtensions lists extensions of files that are always treated as binary
+var binaryExtensions = map[string]bool{
+	".a": true, ".o": true, ".so": true, ".dll": true, ".dylib": true, ".exe": true,
+	".zip": true, ".gz": true, ".tgz": true, ".tar": true, ".bz2": true, ".xz": true,
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".ico": true, ".webp": true,
+	".pdf": true, ".wasm": true, ".class": true, ".jar": true, ".pyc": true,
+}
+
+// isBinaryFile reports whether the file at path is binary, judging by its
+// extension first and then by sniffing its leading bytes
+func isBinaryFile(path string) (bool, error) {
+	if binaryExtensions[strings.ToLower(filepath.Ext(path))] {
+		return true, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	buf := make([]byte, binarySniffLen)
+	n, err := io.ReadFull(f, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return false, err
+	}
+	return isBinaryContent(buf[:n]), nil
+}
+
+// isBinaryContent reports whether content looks binary, using the same
+// null-byte heuristic as git
+func isBinaryContent(content []byte) bool {
+	if len(content) > binarySniffLen {
+		content = content[:binarySniffLen]
+	}
+	return bytes.IndexByte(content, 0) >= 0
+}
+
+// validatePath checks that path is located inside root using filepath.Rel
+// containment rather than string prefixes, so "/work-evil" is not treated as
+// inside "/work". Paths on different volumes (drive letters or UNC shares) are
+// never contained, and on Windows the comparison ignores case.
+func validatePath(root, path string) error {
+	if !filepath.IsAbs(root) || !filepath.IsAbs(path) {
+		return fmt.Errorf("%w: paths must be absolute: %s, %s", ErrInvalidInput, root, path)
+	}
+
+	root, path = filepath.Clean(root), filepath.Clean(path)
+	if runtime.GOOS == "windows" {
+		root, path = strings.ToLower(root), strings.ToLower(path)
+	}
+
+	if filepath.VolumeName(root) != filepath.VolumeName(path) {
+		return fmt.Errorf("%w: %s is outside %s", ErrPermission, path, root)
+	}
+
+	rel, err := filepath.Rel(root, path)
+	if err != nil || filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("%w: %s is outside %s", ErrPermission, path, root)
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package readgo
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ValidatorConfigFile is the name of the per-project validator configuration
+const ValidatorConfigFile = ".readgo.yaml"
+
+// ValidatorConfig configures which checks a validator runs
+type ValidatorConfig struct {
+	// Rules enables or disables checks by name, e.g. UNUSED_IMPORT: false.
+	// Names are matched case-insensitively; unlisted checks keep their
+	// default, which is off for the opt-in built-in rules.
+	Rules map[string]bool `yaml:"rules" json:"rules,omitempty"`
+
+	// Enable lists opt-in built-in rules to run, e.g. shadow or unused.
+	// Only unused_import and the rules that need settings of their own run
+	// without being enabled.
+	Enable []string `yaml:"enable" json:"enable,omitempty"`
+
+	// Severities overrides the severity of findings by check name, e.g.
+	// BLANK_IMPORT: info. Errors make the result invalid at every level.
+	Severities map[string]Severity `yaml:"severities" json:"severities,omitempty"`
+
+	// MaxComplexity is the highest cyclomatic complexity a function may have
+	// before the complexity check reports it; zero uses the default of 15
+	MaxComplexity int `yaml:"max_complexity" json:"max_complexity,omitempty"`
+
+	// MaxFunctionLines is the longest a function may be, from its signature
+	// to its closing brace; zero uses the default of 80
+	MaxFunctionLines int `yaml:"max_function_lines" json:"max_function_lines,omitempty"`
+
+	// MaxParams is the most parameters a function may take; zero uses the
+	// default of 6
+	MaxParams int `yaml:"max_params" json:"max_params,omitempty"`
+
+	// MaxResults is the most results a function may return; zero uses the
+	// default of 3
+	MaxResults int `yaml:"max_results" json:"max_results,omitempty"`
+
+	// MinPaddingWaste is the fewest bytes of padding a struct may waste,
+	// compared with its best field order, before the field alignment check
+	// reports it; zero uses the default of 8
+	MinPaddingWaste int `yaml:"min_padding_waste" json:"min_padding_waste,omitempty"`
+
+	// CheckBlankErrors makes the unchecked error check also report errors
+	// assigned to the blank identifier, as in _ = f.Close()
+	CheckBlankErrors bool `yaml:"check_blank_errors" json:"check_blank_errors,omitempty"`
+
+	// MaxImportDepth is the longest chain of project imports allowed from a
+	// main package down to any package it depends on; zero disables the
+	// import depth check
+	MaxImportDepth int `yaml:"max_import_depth" json:"max_import_depth,omitempty"`
+
+	// MinCoverage is the lowest statement coverage, in percent, a package
+	// may have before CheckCoverage reports it; zero only records coverage
+	MinCoverage float64 `yaml:"min_coverage" json:"min_coverage,omitempty"`
+
+	// DocPackages enables the doc comment check for the packages matching
+	// these import path patterns, e.g. example.com/mod/api/... or ... for
+	// every package
+	DocPackages []string `yaml:"doc_packages" json:"doc_packages,omitempty"`
+
+	// Header is a regular expression the first comment of every Go file
+	// must match, e.g. ^// Copyright \d{4} Example Inc; empty disables the
+	// header check
+	Header string `yaml:"header" json:"header,omitempty"`
+
+	// HeaderExclude lists file patterns exempt from the header check, in
+	// addition to generated files
+	HeaderExclude []string `yaml:"header_exclude" json:"header_exclude,omitempty"`
+
+	// ImportPolicies restrict the imports of parts of the project, e.g.
+	// that cmd/** may not import database/sql
+	ImportPolicies []ImportPolicy `yaml:"import_policies" json:"import_policies,omitempty"`
+}
+
+// Limits used when none are configured
+const (
+	defaultMaxComplexity    = 15
+	defaultMaxFunctionLines = 80
+	defaultMaxParams        = 6
+	defaultMaxResults       = 3
+	defaultMinPaddingWaste  = 8
+)
+
+// configurableRule is implemented by built-in rules that read their
+// settings from the validator configuration
+type configurableRule interface {
+	Rule
+	configure(cfg *ValidatorConfig)
+}
+
+// limit returns value, or def if value is not positive
+func limit(value, def int) int {
+	if value > 0 {
+		return value
+	}
+	return def
+}
+
+// LoadValidatorConfig reads a validator configuration from path. If path is a
+// directory its .readgo.yaml is read; a missing file yields an empty config.
+func LoadValidatorConfig(path string) (*ValidatorConfig, error) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, ValidatorConfigFile)
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &ValidatorConfig{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read validator config: %w", err)
+	}
+
+	cfg := &ValidatorConfig{}
+	if err := yaml.Unmarshal(content, cfg); err != nil {
+		return nil, fmt.Errorf("%w: parse %s: %v", ErrInvalidInput, path, err)
+	}
+	if _, err := regexp.Compile(cfg.Header); err != nil {
+		return nil, fmt.Errorf("%w: %s: invalid header pattern: %v", ErrInvalidInput, path, err)
+	}
+	if cfg.MinCoverage < 0 || cfg.MinCoverage > 100 {
+		return nil, fmt.Errorf("%w: %s: min_coverage %v is not a percentage", ErrInvalidInput, path, cfg.MinCoverage)
+	}
+	for _, policy := range cfg.ImportPolicies {
+		if err := policy.validate(); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidInput, path, err)
+		}
+	}
+	for name, severity := range cfg.Severities {
+		if !severity.valid() {
+			return nil, fmt.Errorf("%w: %s: unknown severity %q for %s", ErrInvalidInput, path, severity, name)
+		}
+	}
+	return cfg, nil
+}
+
+// ruleEnabled reports whether the check with the given name should run
+func (c *ValidatorConfig) ruleEnabled(name string) bool {
+	if c == nil {
+		return !optInRules[strings.ToLower(name)]
+	}
+	if enabled, ok := lookupFold(c.Rules, name); ok {
+		return enabled
+	}
+	for _, enabled := range c.Enable {
+		if strings.EqualFold(enabled, name) {
+			return true
+		}
+	}
+	return !optInRules[strings.ToLower(name)]
+}
+
+// severity returns the severity for findings of the named check, applying
+// any configured override to the reported severity
+func (c *ValidatorConfig) severity(name string, reported Severity) Severity {
+	if c != nil {
+		if severity, ok := lookupFold(c.Severities, name); ok {
+			return severity
+		}
+	}
+	if reported == "" {
+		return SeverityWarning
+	}
+	return reported
+}
+
+// lookupFold looks up name in m, matching keys case-insensitively
+func lookupFold[V any](m map[string]V, name string) (V, bool) {
+	if v, ok := m[name]; ok {
+		return v, true
+	}
+	for key, v := range m {
+		if strings.EqualFold(key, name) {
+			return v, true
+		}
+	}
+	var zero V
+	return zero, false
+}
@@ -0,0 +1,161 @@
+package readgo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadValidatorConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	cfg, err := LoadValidatorConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadValidatorConfig() without file error = %v", err)
+	}
+	if len(cfg.Rules) != 0 {
+		t.Errorf("LoadValidatorConfig() without file rules = %v, want none", cfg.Rules)
+	}
+
+	content := "rules:\n  UNUSED_IMPORT: false\n  todo_func: true\nenable: [Shadow]\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, ValidatorConfigFile), []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	cfg, err = LoadValidatorConfig(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadValidatorConfig() error = %v", err)
+	}
+	if cfg.ruleEnabled("unused_import") {
+		t.Error("ruleEnabled(unused_import) = true, want false")
+	}
+	if !cfg.ruleEnabled("todo_func") || !cfg.ruleEnabled("other") {
+		t.Error("ruleEnabled() = false for enabled rules")
+	}
+	if !cfg.ruleEnabled("shadow") || cfg.ruleEnabled("unused") {
+		t.Error("ruleEnabled() ignores the enable list")
+	}
+
+	bad := filepath.Join(tmpDir, "bad.yaml")
+	if err := os.WriteFile(bad, []byte("rules: [\n"), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	if _, err := LoadValidatorConfig(bad); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("LoadValidatorConfig() with invalid YAML error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestValidatorConfigDisablesRules(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := "package test\n\nimport _ \"embed\"\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "blank.go"), []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	validator := NewValidator(tmpDir).WithConfig(&ValidatorConfig{
+		Rules: map[string]bool{"UNUSED_IMPORT": false},
+	})
+	result, err := validator.ValidateFile(context.Background(), "blank.go", ValidationLevelStandard)
+	if err != nil {
+		t.Fatalf("ValidateFile() error = %v", err)
+	}
+	if len(result.Warnings) != 0 {
+		t.Errorf("ValidateFile() warnings = %v, want none", result.Warnings)
+	}
+}
+
+func TestValidatorConfigDefaultRules(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := "package test\n\nimport _ \"embed\"\n\nfunc helper(n int) { println() }\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "rules.go"), []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		config *ValidatorConfig
+		want   []string
+	}{
+		{"default", nil, []string{"blank_import"}},
+		{"enabled", &ValidatorConfig{Enable: []string{"unused"}}, []string{"blank_import", "unused_func", "unused_param"}},
+		{"enabled by rules", &ValidatorConfig{Rules: map[string]bool{"unused": true, "unused_func": false}}, []string{"blank_import", "unused_param"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewValidator(tmpDir).WithConfig(tt.config).ValidateFile(context.Background(), "rules.go", ValidationLevelStandard)
+			if err != nil {
+				t.Fatalf("ValidateFile() error = %v", err)
+			}
+			var got []string
+			for _, w := range result.Warnings {
+				got = append(got, w.Type)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateFile() warnings = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatorConfigSeverities(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := "package test\n\nimport _ \"embed\"\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "blank.go"), []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		severity     Severity
+		level        ValidationLevel
+		wantErrors   int
+		wantWarnings int
+		wantValid    bool
+	}{
+		{"default", "", ValidationLevelStandard, 0, 1, true},
+		{"promoted", SeverityError, ValidationLevelStandard, 1, 0, false},
+		{"demoted", SeverityInfo, ValidationLevelStandard, 0, 1, true},
+		{"demoted strict", SeverityInfo, ValidationLevelStrict, 0, 1, true},
+		{"promoted basic", SeverityError, ValidationLevelBasic, 1, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &ValidatorConfig{}
+			if tt.severity != "" {
+				cfg.Severities = map[string]Severity{"BLANK_IMPORT": tt.severity}
+			}
+			result, err := NewValidator(tmpDir).WithConfig(cfg).ValidateFile(context.Background(), "blank.go", tt.level)
+			if err != nil {
+				t.Fatalf("ValidateFile() error = %v", err)
+			}
+			if len(result.Errors) != tt.wantErrors || len(result.Warnings) != tt.wantWarnings {
+				t.Errorf("ValidateFile() errors = %v, warnings = %v, want %d and %d", result.Errors, result.Warnings, tt.wantErrors, tt.wantWarnings)
+			}
+			if result.Valid != tt.wantValid {
+				t.Errorf("ValidateFile() valid = %v, want %v", result.Valid, tt.wantValid)
+			}
+		})
+	}
+
+	bad := filepath.Join(tmpDir, "bad.yaml")
+	if err := os.WriteFile(bad, []byte("severities:\n  unused_import: fatal\n"), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	if _, err := LoadValidatorConfig(bad); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("LoadValidatorConfig() with unknown severity error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestLoadValidatorConfigInvalidHeader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ValidatorConfigFile)
+	if err := os.WriteFile(path, []byte("header: \"([\"\n"), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	if _, err := LoadValidatorConfig(path); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("LoadValidatorConfig() with invalid header error = %v, want ErrInvalidInput", err)
+	}
+}
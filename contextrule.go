@@ -0,0 +1,112 @@
+package readgo
+
+import (
+	"context"
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// contextRule reports contexts that break cancellation chains: calls to
+// context.TODO outside tests, and calls to context.Background in functions
+// that receive a context.Context they should derive from instead. Its
+// findings use the check names context_todo and context_background.
+type contextRule struct{}
+
+// Name returns the rule name
+func (contextRule) Name() string {
+	return "context"
+}
+
+// Check reports the context misuse in the package
+func (contextRule) Check(ctx context.Context, pkg *packages.Package) []Finding {
+	if pkg.TypesInfo == nil {
+		return nil
+	}
+	info := pkg.TypesInfo
+
+	var findings []Finding
+	reported := make(map[*ast.CallExpr]bool)
+	report := func(check string, call *ast.CallExpr, message string) {
+		if reported[call] {
+			return
+		}
+		reported[call] = true
+		pos := pkg.Fset.Position(call.Pos())
+		findings = append(findings, Finding{
+			Rule:    check,
+			Message: message,
+			File:    pos.Filename,
+			Line:    pos.Line,
+			Column:  pos.Column,
+		})
+	}
+
+	// background reports the context.Background calls in the body of a
+	// function receiving the context param
+	background := func(body *ast.BlockStmt, param string) {
+		ast.Inspect(body, func(n ast.Node) bool {
+			if call, ok := n.(*ast.CallExpr); ok && isPkgFunc(info, call.Fun, "context", "Background") {
+				report("context_background", call, "context.Background() in a function that receives "+param+"; derive from it to keep cancellation")
+			}
+			return true
+		})
+	}
+
+	for _, file := range pkg.Syntax {
+		if ctx.Err() != nil {
+			break
+		}
+		isTest := strings.HasSuffix(pkg.Fset.File(file.Pos()).Name(), "_test.go")
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncDecl:
+				if n.Body != nil {
+					if param := contextParam(info, n.Type); param != "" {
+						background(n.Body, param)
+					}
+				}
+			case *ast.FuncLit:
+				if param := contextParam(info, n.Type); param != "" {
+					background(n.Body, param)
+				}
+			case *ast.CallExpr:
+				if !isTest && isPkgFunc(info, n.Fun, "context", "TODO") {
+					report("context_todo", n, "context.TODO() outside tests; accept a context.Context from the caller")
+				}
+			}
+			return true
+		})
+	}
+	return findings
+}
+
+// contextParam returns the name of the first context.Context parameter of
+// fn, "a context" if it is unnamed, or "" if there is none
+func contextParam(info *types.Info, fn *ast.FuncType) string {
+	if fn.Params == nil {
+		return ""
+	}
+	for _, field := range fn.Params.List {
+		if !isContextType(info.TypeOf(field.Type)) {
+			continue
+		}
+		if len(field.Names) == 0 || field.Names[0].Name == "_" {
+			return "a context"
+		}
+		return field.Names[0].Name
+	}
+	return ""
+}
+
+// isContextType reports whether t is context.Context
+func isContextType(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "context" && obj.Name() == "Context"
+}
@@ -0,0 +1,119 @@
+package readgo
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// coverageCheck is the check name of the findings of CheckCoverage
+const coverageCheck = "coverage"
+
+// coveragePattern matches the coverage line go test prints for a package
+var coveragePattern = regexp.MustCompile(`coverage: (\d+(?:\.\d+)?)% of statements`)
+
+// CheckCoverage runs go test -cover over the project in the validator's base
+// directory, records the statement coverage of every package in
+// result.Stats.Coverage and reports each package below
+// ValidatorConfig.MinCoverage as an error. Packages whose tests fail to
+// build have no coverage and are left out. It requires the go binary in
+// PATH.
+func (v *DefaultValidator) CheckCoverage(ctx context.Context, result *ValidationResult) error {
+	if result == nil {
+		return fmt.Errorf("%w: nil result", ErrInvalidInput)
+	}
+	if !v.config.ruleEnabled(coverageCheck) {
+		return nil
+	}
+
+	cmd := exec.CommandContext(ctx, "go", "test", "-cover", "-json", "./...")
+	cmd.Dir = v.baseDir
+	cmd.Env = os.Environ()
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+
+	// go test exits with a non-zero status when tests fail
+	var exitErr *exec.ExitError
+	if err != nil && (!errors.As(err, &exitErr) || len(bytes.TrimSpace(out)) == 0) {
+		return &ValidationError{
+			Message: fmt.Sprintf("run go test: %s", strings.TrimSpace(stderr.String())),
+			Wrapped: err,
+		}
+	}
+
+	coverage, err := ParseCoverageJSON(out)
+	if err != nil {
+		return &ValidationError{Message: "parse go test output", Wrapped: err}
+	}
+
+	pkgs := make([]string, 0, len(coverage))
+	for pkg := range coverage {
+		pkgs = append(pkgs, pkg)
+	}
+	sort.Strings(pkgs)
+
+	var min float64
+	if v.config != nil {
+		min = v.config.MinCoverage
+	}
+	for _, pkg := range pkgs {
+		percent := coverage[pkg]
+		result.Stats.cover(pkg, percent)
+		if percent < min {
+			v.addFinding(result, Finding{
+				Rule:     coverageCheck,
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("package %s has %.1f%% test coverage, below the minimum of %.1f%%", pkg, percent, min),
+			})
+		}
+	}
+	finishResult(result, result.Level)
+	return nil
+}
+
+// ParseCoverageJSON parses the output of go test -cover -json and returns
+// the statement coverage percentage of every package that reported one
+func ParseCoverageJSON(output []byte) (map[string]float64, error) {
+	coverage := make(map[string]float64)
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxFileSize)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] != '{' {
+			continue // Build output interleaved with the events
+		}
+
+		var event struct {
+			Action  string `json:"Action"`
+			Package string `json:"Package"`
+			Test    string `json:"Test"`
+			Output  string `json:"Output"`
+		}
+		if err := json.Unmarshal(line, &event); err != nil {
+			return nil, err
+		}
+		if event.Action != "output" || event.Package == "" || event.Test != "" {
+			continue
+		}
+		m := coveragePattern.FindStringSubmatch(event.Output)
+		if m == nil {
+			continue
+		}
+		percent, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			return nil, err
+		}
+		coverage[event.Package] = percent
+	}
+	return coverage, scanner.Err()
+}
@@ -0,0 +1,97 @@
+package readgo
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseCoverageJSON(t *testing.T) {
+	output := `# example.com/p/broken
+broken/b.go:3:1: syntax error
+{"Action":"start","Package":"example.com/p"}
+{"Action":"output","Package":"example.com/p","Test":"TestHalf","Output":"=== RUN   TestHalf\n"}
+{"Action":"output","Package":"example.com/p","Output":"coverage: 50.0% of statements\n"}
+{"Action":"output","Package":"example.com/p","Output":"ok  \texample.com/p\t0.002s\tcoverage: 50.0% of statements\n"}
+{"Action":"pass","Package":"example.com/p"}
+{"Action":"output","Package":"example.com/p/none","Output":"\texample.com/p/none\t\tcoverage: 0.0% of statements\n"}
+{"Action":"output","Package":"example.com/p/broken","Output":"FAIL\texample.com/p/broken [build failed]\n"}
+`
+	got, err := ParseCoverageJSON([]byte(output))
+	if err != nil {
+		t.Fatalf("ParseCoverageJSON() error = %v", err)
+	}
+	want := map[string]float64{"example.com/p": 50, "example.com/p/none": 0}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseCoverageJSON() = %v, want %v", got, want)
+	}
+
+	if _, err := ParseCoverageJSON([]byte("{not json\n")); err == nil {
+		t.Error("ParseCoverageJSON() expected error for invalid output")
+	}
+}
+
+func TestCheckCoverage(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not available")
+	}
+
+	tmpDir := t.TempDir()
+	testFiles := map[string]string{
+		"go.mod": "module example.com/p\n\ngo 1.22\n",
+		"p.go":   "package p\n\nfunc Sign(n int) int {\n\tif n < 0 {\n\t\treturn -1\n\t}\n\treturn 1\n}\n",
+		"p_test.go": "package p\n\nimport \"testing\"\n\nfunc TestSign(t *testing.T) {\n" +
+			"\tif Sign(1) != 1 {\n\t\tt.Fatal(\"wrong sign\")\n\t}\n}\n",
+	}
+	for name, content := range testFiles {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		config     *ValidatorConfig
+		wantErrors []string
+		wantStats  map[string]float64
+	}{
+		{
+			name:      "record only",
+			wantStats: map[string]float64{"example.com/p": 66.7},
+		},
+		{
+			name:      "above minimum",
+			config:    &ValidatorConfig{MinCoverage: 60},
+			wantStats: map[string]float64{"example.com/p": 66.7},
+		},
+		{
+			name:       "below minimum",
+			config:     &ValidatorConfig{MinCoverage: 80},
+			wantErrors: []string{"package example.com/p has 66.7% test coverage, below the minimum of 80.0%"},
+			wantStats:  map[string]float64{"example.com/p": 66.7},
+		},
+		{
+			name:   "disabled",
+			config: &ValidatorConfig{MinCoverage: 80, Rules: map[string]bool{"coverage": false}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &ValidationResult{Level: ValidationLevelStandard}
+			err := NewValidator(tmpDir).WithConfig(tt.config).CheckCoverage(context.Background(), result)
+			if err != nil {
+				t.Fatalf("CheckCoverage() error = %v", err)
+			}
+			if !reflect.DeepEqual(result.Errors, tt.wantErrors) {
+				t.Errorf("CheckCoverage() errors = %q, want %q", result.Errors, tt.wantErrors)
+			}
+			if !reflect.DeepEqual(result.Stats.Coverage, tt.wantStats) {
+				t.Errorf("CheckCoverage() coverage = %v, want %v", result.Stats.Coverage, tt.wantStats)
+			}
+		})
+	}
+}
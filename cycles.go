@@ -0,0 +1,320 @@
+package readgo
+
+import (
+	"context"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// importGraph maps the import path of each project package to the project
+// packages it imports
+type importGraph map[string][]string
+
+// importEdge is an import of one project package by another
+type importEdge struct {
+	from, to string
+}
+
+// Cycle is an import cycle between packages
+type Cycle struct {
+	// Packages lists the import paths of the cycle in import order, starting
+	// at the smallest; the last package imports the first
+	Packages []string `json:"packages" yaml:"packages"`
+
+	// File, Line and Column give the position of the import that closes the
+	// cycle, the one of the first package in the last. File is relative to
+	// the validator's base directory.
+	File   string `json:"file,omitempty" yaml:"file,omitempty"`
+	Line   int    `json:"line,omitempty" yaml:"line,omitempty"`
+	Column int    `json:"column,omitempty" yaml:"column,omitempty"`
+}
+
+// String formats the cycle as "a -> b -> a"
+func (c Cycle) String() string {
+	if len(c.Packages) == 0 {
+		return ""
+	}
+	return strings.Join(append(c.Packages[:len(c.Packages):len(c.Packages)], c.Packages[0]), " -> ")
+}
+
+// CheckCircularDependencies reports the import cycles among the packages of
+// the project. Every strongly connected component of the import graph is
+// examined, so all cycles are found rather than the first one reached. With
+// a non-empty pkgPath only the cycles reachable from that package are
+// reported.
+func (v *DefaultValidator) CheckCircularDependencies(ctx context.Context, pkgPath string) (*ValidationResult, error) {
+	base, err := filepath.Abs(v.baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("dependency check error: %w", err)
+	}
+
+	result := &ValidationResult{
+		Name:          filepath.Base(v.baseDir),
+		Path:          v.baseDir,
+		StartTime:     time.Now().Format(time.RFC3339),
+		AnalyzedAt:    time.Now(),
+		SchemaVersion: SchemaVersion,
+		ToolVersion:   Version(),
+	}
+
+	var seedDir string
+	if pkgPath != "" {
+		seedDir = filepath.Join(base, pkgPath)
+		if _, err := os.Stat(seedDir); err != nil {
+			return nil, fmt.Errorf("package access error: %w", err)
+		}
+		result.Name = filepath.Base(pkgPath)
+		result.Path = pkgPath
+	}
+
+	graph, positions, dirs, err := v.importGraph(ctx, base)
+	if err != nil {
+		return nil, fmt.Errorf("dependency check error: %w", err)
+	}
+
+	var seed string
+	if seedDir != "" {
+		for path, dir := range dirs {
+			if dir == seedDir {
+				seed = path
+				break
+			}
+		}
+		if seed == "" {
+			return nil, fmt.Errorf("%w: no Go package in %s", ErrNotFound, pkgPath)
+		}
+	}
+
+	for _, pkgs := range graph.cycles(seed) {
+		cycle := Cycle{Packages: pkgs}
+		if pos, ok := positions[importEdge{from: pkgs[len(pkgs)-1], to: pkgs[0]}]; ok {
+			cycle.File = v.relPath(pos.Filename)
+			cycle.Line = pos.Line
+			cycle.Column = pos.Column
+		}
+		result.CircularDeps = append(result.CircularDeps, cycle)
+
+		message := "import cycle: " + cycle.String()
+		if cycle.File != "" {
+			message = fmt.Sprintf("%s:%d:%d: %s", cycle.File, cycle.Line, cycle.Column, message)
+		}
+		result.Errors = append(result.Errors, message)
+	}
+	result.Valid = len(result.Errors) == 0
+
+	return result, nil
+}
+
+// importGraph builds the import graph of the packages below dir, including
+// the imports of their tests, together with the position of the first import
+// of every edge and the directory of each package. Imports are read from the
+// source because go/packages breaks cycles.
+func (v *DefaultValidator) importGraph(ctx context.Context, dir string) (importGraph, map[importEdge]token.Position, map[string]string, error) {
+	pkgs, err := v.load(ctx, dir, true, packages.NeedName|packages.NeedFiles, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	files := make(map[string]map[string]bool)
+	dirs := make(map[string]string)
+	for _, pkg := range pkgs {
+		if pkg.PkgPath == "" || strings.HasSuffix(pkg.PkgPath, ".test") || len(pkg.GoFiles) == 0 {
+			continue
+		}
+		if files[pkg.PkgPath] == nil {
+			files[pkg.PkgPath] = make(map[string]bool)
+			dirs[pkg.PkgPath] = filepath.Dir(pkg.GoFiles[0])
+		}
+		for _, f := range pkg.GoFiles {
+			files[pkg.PkgPath][f] = true
+		}
+	}
+
+	graph := make(importGraph, len(files))
+	positions := make(map[importEdge]token.Position)
+	fset := token.NewFileSet()
+	for path, names := range files {
+		imports := make(map[string]bool)
+		for _, name := range sortedKeys(names) {
+			if err := ctx.Err(); err != nil {
+				return nil, nil, nil, err
+			}
+			file, err := parser.ParseFile(fset, name, nil, parser.ImportsOnly)
+			if err != nil {
+				continue
+			}
+			for _, imp := range file.Imports {
+				target, err := strconv.Unquote(imp.Path.Value)
+				if err != nil || target == path || files[target] == nil {
+					continue
+				}
+				imports[target] = true
+				edge := importEdge{from: path, to: target}
+				if _, ok := positions[edge]; !ok {
+					positions[edge] = fset.Position(imp.Pos())
+				}
+			}
+		}
+		graph[path] = sortedKeys(imports)
+	}
+	return graph, positions, dirs, nil
+}
+
+// cycles returns the import cycles of the graph, one shortest cycle through
+// every package that is part of one, without repetitions. Each cycle starts
+// at its smallest import path. If seed is not empty only the cycles
+// reachable from it are returned.
+func (g importGraph) cycles(seed string) [][]string {
+	reachable := func(string) bool { return true }
+	if seed != "" {
+		seen := map[string]bool{}
+		var visit func(string)
+		visit = func(p string) {
+			if seen[p] {
+				return
+			}
+			seen[p] = true
+			for _, q := range g[p] {
+				visit(q)
+			}
+		}
+		visit(seed)
+		reachable = func(p string) bool { return seen[p] }
+	}
+
+	var cycles [][]string
+	found := map[string]bool{}
+	for _, scc := range g.components() {
+		if len(scc) < 2 || !reachable(scc[0]) {
+			continue
+		}
+		members := make(map[string]bool, len(scc))
+		for _, p := range scc {
+			members[p] = true
+		}
+		for _, p := range scc {
+			cycle := g.shortestCycle(p, members)
+			key := strings.Join(cycle, " ")
+			if len(cycle) > 0 && !found[key] {
+				found[key] = true
+				cycles = append(cycles, cycle)
+			}
+		}
+	}
+	return cycles
+}
+
+// components returns the strongly connected components of the graph using
+// Tarjan's algorithm. Components and their members are sorted by import
+// path.
+func (g importGraph) components() [][]string {
+	index := make(map[string]int)
+	low := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var components [][]string
+
+	var connect func(string)
+	connect = func(p string) {
+		index[p] = len(index)
+		low[p] = index[p]
+		stack = append(stack, p)
+		onStack[p] = true
+
+		for _, q := range g[p] {
+			if _, ok := index[q]; !ok {
+				connect(q)
+				low[p] = min(low[p], low[q])
+			} else if onStack[q] {
+				low[p] = min(low[p], index[q])
+			}
+		}
+
+		if low[p] == index[p] {
+			var scc []string
+			for {
+				q := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[q] = false
+				scc = append(scc, q)
+				if q == p {
+					break
+				}
+			}
+			sort.Strings(scc)
+			components = append(components, scc)
+		}
+	}
+
+	nodes := make([]string, 0, len(g))
+	for p := range g {
+		nodes = append(nodes, p)
+	}
+	sort.Strings(nodes)
+	for _, p := range nodes {
+		if _, ok := index[p]; !ok {
+			connect(p)
+		}
+	}
+
+	sort.Slice(components, func(i, j int) bool { return components[i][0] < components[j][0] })
+	return components
+}
+
+// shortestCycle returns the shortest path from start back to itself that
+// stays within members, rotated to begin at its smallest import path
+func (g importGraph) shortestCycle(start string, members map[string]bool) []string {
+	prev := map[string]string{}
+	queue := []string{start}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		for _, q := range g[p] {
+			if !members[q] {
+				continue
+			}
+			if q == start {
+				cycle := []string{p}
+				for cycle[0] != start {
+					cycle = append([]string{prev[cycle[0]]}, cycle...)
+				}
+				return rotateCycle(cycle)
+			}
+			if _, ok := prev[q]; !ok {
+				prev[q] = p
+				queue = append(queue, q)
+			}
+		}
+	}
+	return nil
+}
+
+// rotateCycle rotates cycle to begin at its smallest element
+func rotateCycle(cycle []string) []string {
+	first := 0
+	for i, p := range cycle {
+		if p < cycle[first] {
+			first = i
+		}
+	}
+	return append(cycle[first:len(cycle):len(cycle)], cycle[:first]...)
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
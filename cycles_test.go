@@ -0,0 +1,126 @@
+package readgo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCheckCircularDependencies(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFiles := map[string]string{
+		"go.mod":      "module example.com/cyc\n\ngo 1.20\n",
+		"a/a.go":      "package a\n\nimport _ \"example.com/cyc/b\"\n",
+		"b/b.go":      "package b\n\nimport (\n\t_ \"example.com/cyc/a\"\n\t_ \"example.com/cyc/c\"\n)\n",
+		"c/c.go":      "package c\n\nimport _ \"example.com/cyc/b\"\n",
+		"d/d.go":      "package d\n\nimport _ \"example.com/cyc/e\"\n",
+		"e/e.go":      "package e\n\nimport \"fmt\"\n\nvar _ = fmt.Sprint\n",
+		"e/e_test.go": "package e\n\nimport _ \"example.com/cyc/d\"\n",
+		"f/f.go":      "package f\n\nimport _ \"example.com/cyc/a\"\n",
+		"g/g.go":      "package g\n\nimport _ \"fmt\"\n",
+	}
+	for name, content := range testFiles {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		pkgPath string
+		want    []Cycle
+	}{
+		{
+			name: "project",
+			want: []Cycle{
+				{Packages: []string{"example.com/cyc/a", "example.com/cyc/b"}, File: filepath.Join("b", "b.go"), Line: 4, Column: 2},
+				{Packages: []string{"example.com/cyc/b", "example.com/cyc/c"}, File: filepath.Join("c", "c.go"), Line: 3, Column: 8},
+				{Packages: []string{"example.com/cyc/d", "example.com/cyc/e"}, File: filepath.Join("e", "e_test.go"), Line: 3, Column: 8},
+			},
+		},
+		{
+			name:    "from package",
+			pkgPath: "f",
+			want: []Cycle{
+				{Packages: []string{"example.com/cyc/a", "example.com/cyc/b"}, File: filepath.Join("b", "b.go"), Line: 4, Column: 2},
+				{Packages: []string{"example.com/cyc/b", "example.com/cyc/c"}, File: filepath.Join("c", "c.go"), Line: 3, Column: 8},
+			},
+		},
+		{
+			name:    "test imports",
+			pkgPath: "e",
+			want: []Cycle{
+				{Packages: []string{"example.com/cyc/d", "example.com/cyc/e"}, File: filepath.Join("e", "e_test.go"), Line: 3, Column: 8},
+			},
+		},
+		{
+			name:    "no cycles",
+			pkgPath: "g",
+		},
+	}
+
+	validator := NewValidator(tmpDir)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := validator.CheckCircularDependencies(context.Background(), tt.pkgPath)
+			if err != nil {
+				t.Fatalf("CheckCircularDependencies() error = %v", err)
+			}
+			if !reflect.DeepEqual(result.CircularDeps, tt.want) {
+				t.Errorf("CheckCircularDependencies() cycles = %+v, want %+v", result.CircularDeps, tt.want)
+			}
+			if result.Valid != (len(tt.want) == 0) || len(result.Errors) != len(tt.want) {
+				t.Errorf("CheckCircularDependencies() valid = %v, errors = %q", result.Valid, result.Errors)
+			}
+		})
+	}
+
+	result, err := validator.CheckCircularDependencies(context.Background(), "")
+	if err != nil {
+		t.Fatalf("CheckCircularDependencies() error = %v", err)
+	}
+	wantError := filepath.Join("b", "b.go") + ":4:2: import cycle: example.com/cyc/a -> example.com/cyc/b -> example.com/cyc/a"
+	if result.Errors[0] != wantError {
+		t.Errorf("CheckCircularDependencies() error = %q, want %q", result.Errors[0], wantError)
+	}
+
+	if _, err := validator.CheckCircularDependencies(context.Background(), "missing"); err == nil {
+		t.Error("CheckCircularDependencies(missing) error = nil")
+	}
+	if err := os.MkdirAll(filepath.Join(tmpDir, "empty"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if _, err := validator.CheckCircularDependencies(context.Background(), "empty"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("CheckCircularDependencies(empty) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestImportGraphCycles(t *testing.T) {
+	// Two cycles share the component {a, b, c}; d is acyclic
+	g := importGraph{
+		"a": {"b"},
+		"b": {"c", "a"},
+		"c": {"a"},
+		"d": {"a"},
+	}
+	want := [][]string{{"a", "b"}, {"a", "b", "c"}}
+	if got := g.cycles(""); !reflect.DeepEqual(got, want) {
+		t.Errorf("cycles() = %v, want %v", got, want)
+	}
+	if got := g.cycles("d"); !reflect.DeepEqual(got, want) {
+		t.Errorf("cycles(d) = %v, want %v", got, want)
+	}
+
+	g["e"] = []string{"e2"}
+	g["e2"] = []string{"e"}
+	if got := g.cycles("a"); !reflect.DeepEqual(got, want) {
+		t.Errorf("cycles(a) = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,231 @@
+package readgo
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/tools/go/packages"
+)
+
+// DependencyKind tells where an imported package comes from
+type DependencyKind string
+
+const (
+	// DependencyStandard is a package of the standard library
+	DependencyStandard DependencyKind = "std"
+	// DependencyInternal is a package of the module being analyzed
+	DependencyInternal DependencyKind = "internal"
+	// DependencyExternal is a package of another module
+	DependencyExternal DependencyKind = "external"
+)
+
+// Dependency is a package imported by the analyzed code
+type Dependency struct {
+	Path string         `json:"path" yaml:"path"`
+	Kind DependencyKind `json:"kind" yaml:"kind"`
+}
+
+var (
+	stdOnce     sync.Once
+	stdPackages map[string]bool
+)
+
+// ClassifyImport tells whether importPath belongs to the module modulePath,
+// to the standard library or to another module. Standard library packages
+// are listed by the go command; if it is unavailable, paths without a dot in
+// their first element are taken to be standard. An empty modulePath makes
+// every non-standard package external.
+func ClassifyImport(importPath, modulePath string) DependencyKind {
+	switch {
+	case modulePath != "" && (importPath == modulePath || strings.HasPrefix(importPath, modulePath+"/")):
+		return DependencyInternal
+	case isStandardImport(importPath):
+		return DependencyStandard
+	default:
+		return DependencyExternal
+	}
+}
+
+// isStandardImport reports whether importPath belongs to the standard library
+func isStandardImport(importPath string) bool {
+	stdOnce.Do(func() {
+		pkgs, err := packages.Load(&packages.Config{Mode: packages.NeedName}, "std")
+		if err != nil || len(pkgs) == 0 {
+			return
+		}
+		stdPackages = make(map[string]bool, len(pkgs))
+		for _, pkg := range pkgs {
+			stdPackages[pkg.PkgPath] = true
+		}
+	})
+	if stdPackages != nil {
+		if stdPackages[importPath] {
+			return true
+		}
+		// "C" and vendored copies are not listed by the go command
+		if importPath == "C" || strings.HasPrefix(importPath, "vendor/") {
+			return true
+		}
+		return false
+	}
+	first, _, _ := strings.Cut(importPath, "/")
+	return !strings.Contains(first, ".")
+}
+
+// ModuleInfo describes the module an analysis was run on, as declared by its
+// go.mod and go.sum files
+type ModuleInfo struct {
+	Path      string `json:"path" yaml:"path"`
+	GoVersion string `json:"go_version,omitempty" yaml:"go_version,omitempty"`
+	// Requires lists the required modules sorted by path
+	Requires []ModuleRequirement `json:"requires,omitempty" yaml:"requires,omitempty"`
+}
+
+// ModuleRequirement is a module version required by the analyzed module
+type ModuleRequirement struct {
+	Path     string `json:"path" yaml:"path"`
+	Version  string `json:"version" yaml:"version"`
+	Indirect bool   `json:"indirect,omitempty" yaml:"indirect,omitempty"`
+	// Replace is the replacement of the module, as path@version or as a
+	// local directory
+	Replace string `json:"replace,omitempty" yaml:"replace,omitempty"`
+	// Sum is the go.sum checksum of the content of the module version in
+	// use, empty if go.sum lacks it or the module is replaced by a directory
+	Sum string `json:"sum,omitempty" yaml:"sum,omitempty"`
+}
+
+// findGoMod returns the path of the nearest go.mod at or above dir, or ""
+// outside modules
+func findGoMod(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		gomod := filepath.Join(dir, "go.mod")
+		if info, err := os.Stat(gomod); err == nil && !info.IsDir() {
+			return gomod
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// modulePath returns the path of the module dir belongs to, read from the
+// nearest go.mod, or "" outside modules
+func modulePath(dir string) string {
+	gomod := findGoMod(dir)
+	if gomod == "" {
+		return ""
+	}
+	data, err := os.ReadFile(gomod)
+	if err != nil {
+		return ""
+	}
+	return modfile.ModulePath(data)
+}
+
+// packageGoMod returns the go.mod file of the module of pkg, falling back to
+// the go.mod above its files when the module was not loaded
+func packageGoMod(pkg *packages.Package) string {
+	if pkg.Module != nil && pkg.Module.GoMod != "" {
+		return pkg.Module.GoMod
+	}
+	if len(pkg.GoFiles) > 0 {
+		return findGoMod(filepath.Dir(pkg.GoFiles[0]))
+	}
+	return ""
+}
+
+// readModuleInfo reads the module declared by the go.mod file at gomod and
+// the checksums of its requirements from the go.sum file next to it. It
+// returns nil if there is no go.mod or it cannot be parsed; a missing go.sum
+// only leaves the checksums empty.
+func readModuleInfo(gomod string) *ModuleInfo {
+	if gomod == "" {
+		return nil
+	}
+	data, err := os.ReadFile(gomod)
+	if err != nil {
+		return nil
+	}
+	mf, err := modfile.Parse(gomod, data, nil)
+	if err != nil || mf.Module == nil {
+		return nil
+	}
+	info := &ModuleInfo{Path: mf.Module.Mod.Path}
+	if mf.Go != nil {
+		info.GoVersion = mf.Go.Version
+	}
+
+	sums := make(map[module.Version]string)
+	if data, err := os.ReadFile(filepath.Join(filepath.Dir(gomod), "go.sum")); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			if fields := strings.Fields(line); len(fields) == 3 && !strings.HasSuffix(fields[1], "/go.mod") {
+				sums[module.Version{Path: fields[0], Version: fields[1]}] = fields[2]
+			}
+		}
+	}
+
+	// A replacement without a version applies to every version of the module
+	replaces := make(map[module.Version]module.Version, len(mf.Replace))
+	for _, r := range mf.Replace {
+		replaces[r.Old] = r.New
+	}
+	for _, req := range mf.Require {
+		r := ModuleRequirement{Path: req.Mod.Path, Version: req.Mod.Version, Indirect: req.Indirect}
+		used := req.Mod
+		repl, ok := replaces[req.Mod]
+		if !ok {
+			repl, ok = replaces[module.Version{Path: req.Mod.Path}]
+		}
+		switch {
+		case ok && repl.Version == "":
+			r.Replace = repl.Path
+			used = module.Version{}
+		case ok:
+			r.Replace = repl.Path + "@" + repl.Version
+			used = repl
+		}
+		r.Sum = sums[used]
+		info.Requires = append(info.Requires, r)
+	}
+	sort.Slice(info.Requires, func(i, j int) bool { return info.Requires[i].Path < info.Requires[j].Path })
+	return info
+}
+
+// packageModulePath returns the module path of pkg, falling back to the
+// go.mod above its files when the module was not loaded
+func packageModulePath(pkg *packages.Package) string {
+	if pkg.Module != nil {
+		return pkg.Module.Path
+	}
+	if len(pkg.GoFiles) > 0 {
+		return modulePath(filepath.Dir(pkg.GoFiles[0]))
+	}
+	return ""
+}
+
+// dependencies classifies the import paths against modulePath, returning
+// them sorted and without duplicates
+func dependencies(importPaths []string, modulePath string) []Dependency {
+	seen := make(map[string]bool, len(importPaths))
+	var deps []Dependency
+	for _, p := range importPaths {
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+		deps = append(deps, Dependency{Path: p, Kind: ClassifyImport(p, modulePath)})
+	}
+	sort.Slice(deps, func(i, j int) bool { return deps[i].Path < deps[j].Path })
+	return deps
+}
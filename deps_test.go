@@ -0,0 +1,197 @@
+package readgo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestClassifyImport(t *testing.T) {
+	tests := []struct {
+		name       string
+		importPath string
+		modulePath string
+		want       DependencyKind
+	}{
+		{"standard library", "fmt", "example.com/app", DependencyStandard},
+		{"nested standard library", "net/http/httptest", "example.com/app", DependencyStandard},
+		{"cgo", "C", "example.com/app", DependencyStandard},
+		{"module root", "example.com/app", "example.com/app", DependencyInternal},
+		{"module package", "example.com/app/internal/store", "example.com/app", DependencyInternal},
+		{"module path prefix", "example.com/application", "example.com/app", DependencyExternal},
+		{"github module", "github.com/user/lib", "example.com/app", DependencyExternal},
+		{"own github module", "github.com/iamlongalong/readgo/report", "github.com/iamlongalong/readgo", DependencyInternal},
+		{"gitlab module", "gitlab.com/group/lib", "example.com/app", DependencyExternal},
+		{"gopkg.in module", "gopkg.in/yaml.v3", "example.com/app", DependencyExternal},
+		{"custom domain", "go.uber.org/zap", "example.com/app", DependencyExternal},
+		{"x repository", "golang.org/x/tools/go/packages", "example.com/app", DependencyExternal},
+		{"dotless module", "myapp/store", "myapp", DependencyInternal},
+		{"dotless outside module", "otherapp/store", "myapp", DependencyExternal},
+		{"no module", "example.com/app/store", "", DependencyExternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyImport(tt.importPath, tt.modulePath); got != tt.want {
+				t.Errorf("ClassifyImport(%q, %q) = %q, want %q", tt.importPath, tt.modulePath, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeFileDependencies(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.22\n",
+		"cmd/app/main.go": `package main
+
+import (
+	"fmt"
+	"os"
+
+	"example.com/app/store"
+	"example.com/application/client"
+	"gitlab.com/group/lib"
+	yaml "gopkg.in/yaml.v3"
+)
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	analyzer := NewAnalyzer(WithWorkDir(tmpDir))
+	result, err := analyzer.AnalyzeFile(context.Background(), "cmd/app/main.go")
+	if err != nil {
+		t.Fatalf("Failed to analyze file: %v", err)
+	}
+
+	want := []Dependency{
+		{Path: "example.com/app/store", Kind: DependencyInternal},
+		{Path: "example.com/application/client", Kind: DependencyExternal},
+		{Path: "fmt", Kind: DependencyStandard},
+		{Path: "gitlab.com/group/lib", Kind: DependencyExternal},
+		{Path: "gopkg.in/yaml.v3", Kind: DependencyExternal},
+		{Path: "os", Kind: DependencyStandard},
+	}
+	if !reflect.DeepEqual(result.Dependencies, want) {
+		t.Errorf("Dependencies = %v, want %v", result.Dependencies, want)
+	}
+}
+
+func TestAnalyzePackageDependencies(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"go.mod":         "module example.com/app\n\ngo 1.22\n",
+		"store/store.go": "package store\n\n// Open opens the store\nfunc Open() {}\n",
+		"api/api.go": `package api
+
+import (
+	"strings"
+
+	"example.com/app/store"
+)
+
+// Serve starts the API
+func Serve() {
+	store.Open()
+	_ = strings.TrimSpace("")
+}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	analyzer := NewAnalyzer(WithWorkDir(tmpDir))
+	result, err := analyzer.AnalyzePackage(context.Background(), "./api")
+	if err != nil {
+		t.Fatalf("Failed to analyze package: %v", err)
+	}
+
+	want := []Dependency{
+		{Path: "example.com/app/store", Kind: DependencyInternal},
+		{Path: "strings", Kind: DependencyStandard},
+	}
+	if !reflect.DeepEqual(result.Dependencies, want) {
+		t.Errorf("Dependencies = %v, want %v", result.Dependencies, want)
+	}
+}
+
+func TestAnalyzeFileModule(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"go.mod": `module example.com/app
+
+go 1.22.3
+
+require (
+	example.com/forked v1.0.0
+	example.com/local v0.1.0
+	gopkg.in/yaml.v3 v3.0.1
+	golang.org/x/mod v0.20.0 // indirect
+)
+
+replace example.com/forked => example.com/fork v1.1.0
+
+replace example.com/local => ../local
+`,
+		"go.sum": `example.com/fork v1.1.0 h1:fork=
+example.com/fork v1.1.0/go.mod h1:forkmod=
+example.com/forked v1.0.0 h1:forked=
+gopkg.in/yaml.v3 v3.0.1 h1:yaml=
+gopkg.in/yaml.v3 v3.0.1/go.mod h1:yamlmod=
+`,
+		"main.go": "package main\n\nfunc main() {}\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	analyzer := NewAnalyzer(WithWorkDir(tmpDir))
+	result, err := analyzer.AnalyzeFile(context.Background(), "main.go")
+	if err != nil {
+		t.Fatalf("Failed to analyze file: %v", err)
+	}
+
+	want := &ModuleInfo{
+		Path:      "example.com/app",
+		GoVersion: "1.22.3",
+		Requires: []ModuleRequirement{
+			{Path: "example.com/forked", Version: "v1.0.0", Replace: "example.com/fork@v1.1.0", Sum: "h1:fork="},
+			{Path: "example.com/local", Version: "v0.1.0", Replace: "../local"},
+			{Path: "golang.org/x/mod", Version: "v0.20.0", Indirect: true},
+			{Path: "gopkg.in/yaml.v3", Version: "v3.0.1", Sum: "h1:yaml="},
+		},
+	}
+	if !reflect.DeepEqual(result.Module, want) {
+		t.Errorf("Module = %+v, want %+v", result.Module, want)
+	}
+
+	if err := os.Remove(filepath.Join(tmpDir, "go.mod")); err != nil {
+		t.Fatalf("Failed to remove go.mod: %v", err)
+	}
+	result, err = NewAnalyzer(WithWorkDir(tmpDir)).AnalyzeFile(context.Background(), "main.go")
+	if err != nil {
+		t.Fatalf("Failed to analyze file: %v", err)
+	}
+	if result.Module != nil {
+		t.Errorf("Module = %+v outside a module, want nil", result.Module)
+	}
+}
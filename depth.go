@@ -0,0 +1,99 @@
+package readgo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// importDepthRule reports project packages that sit more than
+// ValidatorConfig.MaxImportDepth imports below an entrypoint, measured along
+// the longest chain of project imports from a main package. It is opt-in.
+type importDepthRule struct {
+	max int
+}
+
+// Name returns the rule name
+func (r *importDepthRule) Name() string {
+	return "import_depth"
+}
+
+// configure reads the depth limit
+func (r *importDepthRule) configure(cfg *ValidatorConfig) {
+	r.max = 0
+	if cfg != nil {
+		r.max = cfg.MaxImportDepth
+	}
+}
+
+// Check reports the packages buried too deep below the package, if it is an
+// entrypoint
+func (r *importDepthRule) Check(ctx context.Context, pkg *packages.Package) []Finding {
+	if r.max <= 0 || pkg.Name != "main" || strings.HasSuffix(pkg.PkgPath, ".test") {
+		return nil
+	}
+
+	// Order the project packages reachable from the entrypoint so that every
+	// package comes after those importing it
+	var order []*packages.Package
+	visited := map[*packages.Package]bool{}
+	var visit func(*packages.Package)
+	visit = func(p *packages.Package) {
+		visited[p] = true
+		for _, imp := range p.Imports {
+			if !visited[imp] && sameProject(pkg, imp) {
+				visit(imp)
+			}
+		}
+		order = append(order, p)
+	}
+	visit(pkg)
+
+	// Longest chain to every package
+	depth := map[*packages.Package]int{pkg: 0}
+	via := map[*packages.Package]*packages.Package{}
+	for i := len(order) - 1; i >= 0; i-- {
+		p := order[i]
+		for _, imp := range p.Imports {
+			if visited[imp] && depth[p]+1 > depth[imp] {
+				depth[imp] = depth[p] + 1
+				via[imp] = p
+			}
+		}
+	}
+
+	var findings []Finding
+	for i := len(order) - 1; i >= 0; i-- {
+		p := order[i]
+		if depth[p] <= r.max || len(p.Syntax) == 0 {
+			continue
+		}
+
+		chain := []string{p.PkgPath}
+		for q := via[p]; q != nil; q = via[q] {
+			chain = append([]string{q.PkgPath}, chain...)
+		}
+		pos := p.Fset.Position(p.Syntax[0].Name.Pos())
+		findings = append(findings, Finding{
+			Message: fmt.Sprintf("package %s is %d imports deep from entrypoint %s (limit %d): %s",
+				p.PkgPath, depth[p], pkg.PkgPath, r.max, strings.Join(chain, " -> ")),
+			File:   pos.Filename,
+			Line:   pos.Line,
+			Column: pos.Column,
+		})
+	}
+	sort.Slice(findings, func(i, j int) bool { return findings[i].Message < findings[j].Message })
+	return findings
+}
+
+// sameProject reports whether imp belongs to the module of pkg. Outside
+// modules every package that is not part of the standard library counts.
+func sameProject(pkg, imp *packages.Package) bool {
+	if pkg.Module != nil {
+		return imp.Module != nil && imp.Module.Path == pkg.Module.Path
+	}
+	return !isStandardImport(imp.PkgPath)
+}
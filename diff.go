@@ -0,0 +1,167 @@
+package readgo
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// lineRange is an inclusive range of line numbers
+type lineRange struct {
+	start, end int
+}
+
+// changedLines maps absolute file paths to the line ranges changed in them
+type changedLines map[string][]lineRange
+
+// contains reports whether line of file was changed. Line 0 stands for the
+// file as a whole and matches every changed file.
+func (c changedLines) contains(file string, line int) bool {
+	ranges, ok := c[file]
+	if !ok {
+		return false
+	}
+	if line == 0 {
+		return true
+	}
+	for _, r := range ranges {
+		if line >= r.start && line <= r.end {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateDiff validates the project at the given level but only reports the
+// findings on lines added or modified relative to the git ref baseRef (a
+// branch, tag or commit). The working tree is compared, so uncommitted and
+// untracked files are included. It requires the git binary in PATH.
+func (v *DefaultValidator) ValidateDiff(ctx context.Context, baseRef string, level ValidationLevel) (*ValidationResult, error) {
+	if baseRef == "" {
+		return nil, fmt.Errorf("%w: empty base ref", ErrInvalidInput)
+	}
+	if !level.valid() {
+		return nil, fmt.Errorf("%w: unknown validation level %d", ErrInvalidInput, int(level))
+	}
+
+	absPath, err := filepath.Abs(v.baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("diff validation error: %w", err)
+	}
+
+	result := &ValidationResult{
+		Name:          filepath.Base(v.baseDir),
+		Path:          v.baseDir,
+		StartTime:     time.Now().Format(time.RFC3339),
+		AnalyzedAt:    time.Now(),
+		SchemaVersion: SchemaVersion,
+		ToolVersion:   Version(),
+		Level:         level,
+	}
+
+	changed, err := gitChangedLines(ctx, absPath, baseRef)
+	if err != nil {
+		return nil, fmt.Errorf("diff validation error: %w", err)
+	}
+	if len(changed) == 0 {
+		finishResult(result, level)
+		return result, nil
+	}
+
+	pkgs, err := v.loadPackages(ctx, absPath, true, nil)
+	if err != nil {
+		return nil, fmt.Errorf("diff validation error: %w", err)
+	}
+	v.checkPackages(ctx, pkgs, changed.contains, result)
+	finishResult(result, level)
+
+	return result, nil
+}
+
+// gitChangedLines returns the lines of the Go files below dir that differ
+// between the working tree and baseRef. Untracked files count as entirely
+// added.
+func gitChangedLines(ctx context.Context, dir, baseRef string) (changedLines, error) {
+	if _, err := runGit(ctx, dir, "rev-parse", "--verify", "--end-of-options", baseRef+"^{commit}"); err != nil {
+		return nil, fmt.Errorf("%w: unknown revision %s: %v", ErrNotFound, baseRef, err)
+	}
+
+	out, err := runGit(ctx, dir, "-c", "core.quotePath=false", "diff", "--no-color", "--no-ext-diff",
+		"--src-prefix=a/", "--dst-prefix=b/", "--unified=0", "--relative", "--diff-filter=AMR", baseRef, "--", "*.go")
+	if err != nil {
+		return nil, err
+	}
+	changed, err := parseUnifiedDiff(dir, out)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err = runGit(ctx, dir, "-c", "core.quotePath=false", "ls-files", "--others", "--exclude-standard", "--", "*.go")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range strings.Split(string(out), "\n") {
+		if name != "" {
+			changed[filepath.Join(dir, filepath.FromSlash(name))] = []lineRange{{start: 1, end: math.MaxInt}}
+		}
+	}
+
+	return changed, nil
+}
+
+// parseUnifiedDiff extracts the added and modified lines of the new files in
+// a unified diff produced with --unified=0. File names are resolved against
+// dir.
+func parseUnifiedDiff(dir string, diff []byte) (changedLines, error) {
+	changed := changedLines{}
+	var file, prev string
+
+	scanner := bufio.NewScanner(bytes.NewReader(diff))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxFileSize)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "+++ ") && strings.HasPrefix(prev, "--- "):
+			file = ""
+			name := strings.TrimPrefix(line, "+++ ")
+			if unquoted, err := strconv.Unquote(name); err == nil {
+				name = unquoted
+			}
+			if name != "/dev/null" {
+				file = filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(name, "b/")))
+			}
+		case strings.HasPrefix(line, "@@ ") && file != "":
+			// Format: @@ -<start>[,<count>] +<start>[,<count>] @@
+			fields := strings.Fields(line)
+			if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+				return nil, fmt.Errorf("malformed hunk header: %q", line)
+			}
+			startText, countText, hasCount := strings.Cut(fields[2][1:], ",")
+			start, err := strconv.Atoi(startText)
+			if err != nil {
+				return nil, fmt.Errorf("malformed hunk header: %q", line)
+			}
+			count := 1
+			if hasCount {
+				if count, err = strconv.Atoi(countText); err != nil {
+					return nil, fmt.Errorf("malformed hunk header: %q", line)
+				}
+			}
+			if count > 0 {
+				changed[file] = append(changed[file], lineRange{start: start, end: start + count - 1})
+			}
+		}
+		prev = line
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return changed, nil
+}
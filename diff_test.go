@@ -0,0 +1,117 @@
+package readgo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestValidateDiff(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write file %s: %v", name, err)
+		}
+	}
+
+	git("init", "-q")
+	git("config", "diff.mnemonicPrefix", "true") // Would print w/a.go instead of b/a.go
+	write("go.mod", "module example.com/diff\n\ngo 1.20\n")
+	write("a.go", "package diff\n\nfunc old() {}\n")
+	write("b.go", "package diff\n\nfunc other() {}\n")
+	git("add", "-A")
+	git("commit", "-q", "-m", "base")
+
+	write("a.go", "package diff\n\nfunc old() {}\n\nfunc added() {}\n")
+	write("c.go", "package diff\n\nfunc fresh() {}\n")
+
+	result, err := NewValidator(dir).WithConfig(&ValidatorConfig{Enable: []string{"unused"}}).ValidateDiff(context.Background(), "HEAD", ValidationLevelStandard)
+	if err != nil {
+		t.Fatalf("ValidateDiff() error = %v", err)
+	}
+
+	var got []string
+	for _, w := range result.Warnings {
+		if w.Type == "unused_func" {
+			got = append(got, w.File+":"+w.Message)
+		}
+	}
+	sort.Strings(got)
+	want := []string{
+		"a.go:function added is never used",
+		"c.go:function fresh is never used",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateDiff() warnings = %q, want %q", got, want)
+	}
+
+	if _, err := NewValidator(dir).ValidateDiff(context.Background(), "missing", ValidationLevelStandard); !errors.Is(err, ErrNotFound) {
+		t.Errorf("ValidateDiff(missing) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestParseUnifiedDiff(t *testing.T) {
+	diff := "diff --git a/x.go b/x.go\n" +
+		"--- a/x.go\n" +
+		"+++ b/x.go\n" +
+		"@@ -3 +3,2 @@\n" +
+		"-old\n" +
+		"+++ new\n" +
+		"+more\n" +
+		"@@ -10,2 +11,0 @@\n" +
+		"-gone\n" +
+		"-gone\n" +
+		"@@ -20 +19 @@\n" +
+		"-a\n" +
+		"+b\n" +
+		"diff --git a/y.go b/y.go\n" +
+		"deleted file mode 100644\n" +
+		"--- a/y.go\n" +
+		"+++ /dev/null\n" +
+		"@@ -1 +0,0 @@\n" +
+		"-package y\n"
+
+	changed, err := parseUnifiedDiff("/repo", []byte(diff))
+	if err != nil {
+		t.Fatalf("Failed to parse diff: %v", err)
+	}
+	want := changedLines{
+		filepath.Join("/repo", "x.go"): {{start: 3, end: 4}, {start: 19, end: 19}},
+	}
+	if !reflect.DeepEqual(changed, want) {
+		t.Errorf("parseUnifiedDiff() = %v, want %v", changed, want)
+	}
+
+	file := filepath.Join("/repo", "x.go")
+	for _, tt := range []struct {
+		line int
+		want bool
+	}{
+		{0, true}, {3, true}, {4, true}, {5, false}, {19, true}, {20, false},
+	} {
+		if got := changed.contains(file, tt.line); got != tt.want {
+			t.Errorf("contains(x.go, %d) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+	if changed.contains(filepath.Join("/repo", "y.go"), 0) {
+		t.Error("contains(y.go) = true, want false")
+	}
+}
@@ -0,0 +1,142 @@
+package readgo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// diskCacheVersion changes whenever the format of cached entries does, so
+// that entries written by older versions are ignored
+const diskCacheVersion = "readgo-disk-cache-1"
+
+// diskCache persists type lookups and analysis results as JSON files so
+// that they survive between process runs. Entries are keyed by a hash of
+// everything they depend on, so they never go stale and are not expired;
+// the directory may be deleted at any time. Failing to read or write an
+// entry only makes it a miss.
+type diskCache struct {
+	dir    string
+	logger *slog.Logger
+}
+
+// diskTypeEntry is a type lookup as kept on disk, with the files it depends
+// on so that it is invalidated by their edits once back in memory
+type diskTypeEntry struct {
+	Info    *TypeInfo `json:"info"`
+	Sources []string  `json:"sources"`
+}
+
+// newDiskCache returns a disk cache in dir logging failed writes to logger,
+// or nil if dir is empty
+func newDiskCache(dir string, logger *slog.Logger) *diskCache {
+	if dir == "" {
+		return nil
+	}
+	return &diskCache{dir: dir, logger: loggerOrDiscard(logger)}
+}
+
+// get decodes the entry stored under key into v
+func (d *diskCache) get(key string, v interface{}) bool {
+	if d == nil || key == "" {
+		return false
+	}
+	data, err := os.ReadFile(d.path(key))
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
+
+// set stores v under key, logging rather than returning failures
+func (d *diskCache) set(key string, v interface{}) {
+	if d == nil || key == "" {
+		return
+	}
+	if err := d.write(key, v); err != nil {
+		d.logger.Debug("disk cache write failed", "key", key, "error", err)
+	}
+}
+
+// write stores v under key. The entry is written to a temporary file first
+// so that concurrent readers never see a partial entry.
+func (d *diskCache) write(key string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(d.dir, 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(d.dir, "entry-*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), d.path(key))
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+	return err
+}
+
+// path returns the file of the entry stored under key
+func (d *diskCache) path(key string) string {
+	return filepath.Join(d.dir, key+".json")
+}
+
+// diskKey returns the disk cache key of looking up name of the given kind
+// in pkgPath from dir. It covers the Go version, which fixes the standard
+// library, and the sources and module files of the module containing dir,
+// which fix the project and the versions of its dependencies. It returns ""
+// if the disk cache is disabled or the sources cannot be read.
+func (a *DefaultAnalyzer) diskKey(dir, kind, pkgPath, name string) string {
+	if a.disk == nil {
+		return ""
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n%s\n%s\n%s\n", diskCacheVersion, runtime.Version(), kind, pkgPath, name)
+	roots := []string{moduleRoot(dir)}
+	if roots[0] != dir {
+		// dir may lie in a directory the module hash skips, e.g. testdata
+		roots = append(roots, dir)
+	}
+	for _, root := range roots {
+		sum, err := sourceHash(root, true, nil)
+		if err != nil {
+			return ""
+		}
+		fmt.Fprintf(h, "%s %s\n", root, sum)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// moduleRoot returns the directory of the go.mod governing dir, or dir
+// itself outside a module
+func moduleRoot(dir string) string {
+	for d := dir; ; {
+		if _, err := os.Stat(filepath.Join(d, "go.mod")); err == nil {
+			return d
+		}
+		parent := filepath.Dir(d)
+		if parent == d {
+			return dir
+		}
+		d = parent
+	}
+}
@@ -0,0 +1,87 @@
+package readgo
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDiskCache(t *testing.T) {
+	tmpDir := t.TempDir()
+	cacheDir := filepath.Join(t.TempDir(), "cache")
+	testFiles := map[string]string{
+		"go.mod": "module example.com/p\n\ngo 1.22\n",
+		"p.go":   "package p\n\ntype Point struct{ X, Y int }\n\nfunc Origin() Point { return Point{} }\n",
+	}
+	for name, content := range testFiles {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	ctx := context.Background()
+	newAnalyzer := func() *DefaultAnalyzer {
+		return NewAnalyzer(WithWorkDir(tmpDir), WithCacheTTL(time.Minute), WithDiskCache(cacheDir))
+	}
+
+	first, err := newAnalyzer().FindType(ctx, ".", "Point")
+	if err != nil {
+		t.Fatalf("Failed to find type: %v", err)
+	}
+	if _, err := newAnalyzer().AnalyzePackage(ctx, "."); err != nil {
+		t.Fatalf("Failed to analyze package: %v", err)
+	}
+	entries, err := filepath.Glob(filepath.Join(cacheDir, "*.json"))
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("disk cache entries = %v, %v, want 2", entries, err)
+	}
+
+	// Tamper with the entries to tell disk hits from fresh lookups
+	tamper := func(match, replace string) {
+		t.Helper()
+		for _, entry := range entries {
+			data, err := os.ReadFile(entry)
+			if err != nil {
+				t.Fatalf("Failed to read cache entry: %v", err)
+			}
+			data = bytes.ReplaceAll(data, []byte(match), []byte(replace))
+			if err := os.WriteFile(entry, data, 0644); err != nil {
+				t.Fatalf("Failed to write cache entry: %v", err)
+			}
+		}
+	}
+	tamper(first.Type, "struct{cached int}")
+	tamper(`"Origin"`, `"Cached"`)
+
+	promoted := newAnalyzer()
+	info, err := promoted.FindType(ctx, ".", "Point")
+	if err != nil || info.Type != "struct{cached int}" {
+		t.Errorf("FindType() = %+v, %v, want the disk cache entry", info, err)
+	}
+	result, err := newAnalyzer().AnalyzePackage(ctx, ".")
+	if err != nil || len(result.Functions) != 1 || result.Functions[0].Name != "Cached" {
+		t.Errorf("AnalyzePackage() = %+v, %v, want the disk cache entry", result, err)
+	}
+
+	// Editing a source file changes the keys
+	content := testFiles["p.go"] + "\nfunc Unit() Point { return Point{1, 1} }\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "p.go"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	info, err = newAnalyzer().FindType(ctx, ".", "Point")
+	if err != nil || info.Type != first.Type {
+		t.Errorf("FindType() after an edit = %+v, %v, want %q", info, err, first.Type)
+	}
+	// The disk hit kept in memory depends on the edited file too
+	info, err = promoted.FindType(ctx, ".", "Point")
+	if err != nil || info.Type != first.Type {
+		t.Errorf("FindType() of a disk hit after an edit = %+v, %v, want %q", info, err, first.Type)
+	}
+	result, err = newAnalyzer().AnalyzePackage(ctx, ".")
+	if err != nil || len(result.Functions) != 2 {
+		t.Errorf("AnalyzePackage() after an edit = %+v, %v, want 2 functions", result, err)
+	}
+}
@@ -0,0 +1,158 @@
+package readgo
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// docCommentRule reports exported functions, methods and types, and
+// packages, whose doc comments are missing or do not begin with the name of
+// what they document. It is opt-in: only packages selected by
+// ValidatorConfig.DocPackages are checked.
+type docCommentRule struct {
+	packages []string
+}
+
+// Name returns the rule name
+func (r *docCommentRule) Name() string {
+	return "doc_comment"
+}
+
+// configure reads the package patterns to check
+func (r *docCommentRule) configure(cfg *ValidatorConfig) {
+	r.packages = nil
+	if cfg != nil {
+		r.packages = cfg.DocPackages
+	}
+}
+
+// Check reports the missing and malformed doc comments in the package
+func (r *docCommentRule) Check(ctx context.Context, pkg *packages.Package) []Finding {
+	if !matchAnyPackage(r.packages, pkg.PkgPath) {
+		return nil
+	}
+
+	var findings []Finding
+	report := func(node ast.Node, format string, args ...interface{}) {
+		pos := pkg.Fset.Position(node.Pos())
+		findings = append(findings, Finding{
+			Message: fmt.Sprintf(format, args...),
+			File:    pos.Filename,
+			Line:    pos.Line,
+			Column:  pos.Column,
+		})
+	}
+
+	var files []*ast.File
+	for _, file := range pkg.Syntax {
+		if ctx.Err() != nil {
+			break
+		}
+		if !strings.HasSuffix(pkg.Fset.File(file.Pos()).Name(), "_test.go") {
+			files = append(files, file)
+		}
+	}
+	if len(files) == 0 {
+		return nil
+	}
+
+	// Package comment, needed in one file of the package
+	name := files[0].Name.Name
+	var packageDoc *ast.File
+	for _, file := range files {
+		if file.Doc != nil {
+			packageDoc = file
+			break
+		}
+	}
+	switch {
+	case name == "main":
+	case packageDoc == nil:
+		report(files[0].Name, "package %s should have a package comment", name)
+	case !strings.HasPrefix(packageDoc.Doc.Text(), "Package "+name):
+		report(packageDoc.Doc, "package comment should be of the form \"Package %s ...\"", name)
+	}
+
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				if !d.Name.IsExported() || !exportedReceiver(d) {
+					continue
+				}
+				kind := "function"
+				if d.Recv != nil {
+					kind = "method"
+				}
+				if problem := docProblem(d.Doc, d.Name.Name, false); problem != "" {
+					report(d.Name, "exported %s %s %s", kind, funcDisplayName(d), problem)
+				}
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					ts, ok := spec.(*ast.TypeSpec)
+					if !ok || !ts.Name.IsExported() {
+						continue
+					}
+					doc := ts.Doc
+					if doc == nil && !d.Lparen.IsValid() {
+						doc = d.Doc
+					}
+					if problem := docProblem(doc, ts.Name.Name, true); problem != "" {
+						report(ts.Name, "exported type %s %s", ts.Name.Name, problem)
+					}
+				}
+			}
+		}
+	}
+	return findings
+}
+
+// exportedReceiver reports whether fn is a function or a method on an
+// exported type
+func exportedReceiver(fn *ast.FuncDecl) bool {
+	if fn.Recv == nil {
+		return true
+	}
+	typeName := strings.TrimSuffix(funcDisplayName(fn), "."+fn.Name.Name)
+	return ast.IsExported(typeName)
+}
+
+// docProblem describes what is wrong with the doc comment of name, if
+// anything. Type comments may start with an article.
+func docProblem(doc *ast.CommentGroup, name string, allowArticle bool) string {
+	if doc == nil {
+		return "should have a comment"
+	}
+	text := doc.Text()
+	if allowArticle {
+		for _, article := range []string{"A ", "An ", "The "} {
+			text = strings.TrimPrefix(text, article)
+		}
+	}
+	if text == name || strings.HasPrefix(text, name+" ") || strings.HasPrefix(text, name+"\n") {
+		return ""
+	}
+	return fmt.Sprintf("should have a comment of the form \"%s ...\"", name)
+}
+
+// matchAnyPackage reports whether pkgPath matches one of the patterns. A
+// pattern ending in "/..." also matches every package below it, and "..."
+// alone matches everything.
+func matchAnyPackage(patterns []string, pkgPath string) bool {
+	for _, pattern := range patterns {
+		switch {
+		case pattern == "..." || pattern == pkgPath:
+			return true
+		case strings.HasSuffix(pattern, "/..."):
+			prefix := strings.TrimSuffix(pattern, "/...")
+			if pkgPath == prefix || strings.HasPrefix(pkgPath, prefix+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
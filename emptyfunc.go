@@ -0,0 +1,52 @@
+package readgo
+
+import (
+	"context"
+	"go/ast"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// emptyFuncRule reports functions whose body is empty. A comment inside the
+// body marks it as empty on purpose, and methods are skipped because empty
+// ones often only exist to satisfy an interface.
+type emptyFuncRule struct{}
+
+// Name returns the rule name
+func (emptyFuncRule) Name() string {
+	return "empty_func"
+}
+
+// Check reports the empty functions in the package
+func (emptyFuncRule) Check(ctx context.Context, pkg *packages.Package) []Finding {
+	var findings []Finding
+	for _, file := range pkg.Syntax {
+		if ctx.Err() != nil {
+			break
+		}
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || fn.Body == nil || len(fn.Body.List) > 0 || hasComment(file, fn.Body) {
+				continue
+			}
+			pos := pkg.Fset.Position(fn.Name.Pos())
+			findings = append(findings, Finding{
+				Message: "function " + fn.Name.Name + " has an empty body",
+				File:    pos.Filename,
+				Line:    pos.Line,
+				Column:  pos.Column,
+			})
+		}
+	}
+	return findings
+}
+
+// hasComment reports whether file has a comment inside node
+func hasComment(file *ast.File, node ast.Node) bool {
+	for _, group := range file.Comments {
+		if group.Pos() > node.Pos() && group.End() < node.End() {
+			return true
+		}
+	}
+	return false
+}
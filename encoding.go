@@ -0,0 +1,126 @@
+package readgo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Encoding identifies the character encoding of a text file
+type Encoding string
+
+const (
+	// EncodingUTF8 is UTF-8, with or without a byte order mark
+	EncodingUTF8 Encoding = "utf-8"
+	// EncodingUTF16LE is little-endian UTF-16
+	EncodingUTF16LE Encoding = "utf-16le"
+	// EncodingUTF16BE is big-endian UTF-16
+	EncodingUTF16BE Encoding = "utf-16be"
+	// EncodingLatin1 is ISO-8859-1, assumed for text that is not valid UTF-8
+	EncodingLatin1 Encoding = "latin-1"
+)
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// DetectEncoding guesses the encoding of content from its byte order mark,
+// falling back to the distribution of zero bytes for UTF-16 without a BOM
+// and to Latin-1 for content that is not valid UTF-8
+func DetectEncoding(content []byte) Encoding {
+	switch {
+	case bytes.HasPrefix(content, bomUTF8):
+		return EncodingUTF8
+	case bytes.HasPrefix(content, bomUTF16LE):
+		return EncodingUTF16LE
+	case bytes.HasPrefix(content, bomUTF16BE):
+		return EncodingUTF16BE
+	}
+
+	if enc, ok := sniffUTF16(content); ok {
+		return enc
+	}
+	if utf8.Valid(content) {
+		return EncodingUTF8
+	}
+	return EncodingLatin1
+}
+
+// sniffUTF16 detects BOM-less UTF-16 holding mostly ASCII text, where every
+// other byte is zero
+func sniffUTF16(content []byte) (Encoding, bool) {
+	if len(content) > binarySniffLen {
+		content = content[:binarySniffLen]
+	}
+	if len(content) < 4 || len(content)%2 != 0 {
+		return "", false
+	}
+
+	var evenZeros, oddZeros int
+	for i := 0; i < len(content); i += 2 {
+		if content[i] == 0 {
+			evenZeros++
+		}
+		if content[i+1] == 0 {
+			oddZeros++
+		}
+	}
+
+	// Require nearly all high bytes to be zero and no low bytes
+	pairs := len(content) / 2
+	switch {
+	case oddZeros*10 >= pairs*9 && evenZeros == 0:
+		return EncodingUTF16LE, true
+	case evenZeros*10 >= pairs*9 && oddZeros == 0:
+		return EncodingUTF16BE, true
+	}
+	return "", false
+}
+
+// transcodeToUTF8 converts content in the given encoding to UTF-8 and
+// removes any byte order mark
+func transcodeToUTF8(content []byte, enc Encoding) []byte {
+	switch enc {
+	case EncodingUTF16LE, EncodingUTF16BE:
+		var order binary.ByteOrder = binary.LittleEndian
+		bom := bomUTF16LE
+		if enc == EncodingUTF16BE {
+			order, bom = binary.BigEndian, bomUTF16BE
+		}
+		content = bytes.TrimPrefix(content, bom)
+
+		units := make([]uint16, len(content)/2)
+		for i := range units {
+			units[i] = order.Uint16(content[2*i:])
+		}
+		var buf bytes.Buffer
+		for _, r := range utf16.Decode(units) {
+			buf.WriteRune(r)
+		}
+		return buf.Bytes()
+	case EncodingLatin1:
+		var buf bytes.Buffer
+		for _, b := range content {
+			buf.WriteRune(rune(b))
+		}
+		return buf.Bytes()
+	default:
+		return bytes.TrimPrefix(content, bomUTF8)
+	}
+}
+
+// decodeContent prepares raw file content for reading: it transcodes it to
+// UTF-8 if requested and rejects binary content
+func decodeContent(path string, content []byte, opts ReadOptions) ([]byte, error) {
+	if opts.Transcode {
+		content = transcodeToUTF8(content, DetectEncoding(content))
+	}
+	if isBinaryContent(content) {
+		return nil, fmt.Errorf("%w: %s", ErrBinaryFile, path)
+	}
+	return content, nil
+}
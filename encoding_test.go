@@ -0,0 +1,98 @@
+package readgo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// utf16Bytes encodes ASCII text as UTF-16 with the given byte order
+func utf16Bytes(text string, bigEndian, bom bool) []byte {
+	var out []byte
+	if bom {
+		if bigEndian {
+			out = append(out, 0xFE, 0xFF)
+		} else {
+			out = append(out, 0xFF, 0xFE)
+		}
+	}
+	for _, c := range []byte(text) {
+		if bigEndian {
+			out = append(out, 0, c)
+		} else {
+			out = append(out, c, 0)
+		}
+	}
+	return out
+}
+
+func TestDetectEncoding(t *testing.T) {
+	tests := []struct {
+		name    string
+		content []byte
+		want    Encoding
+	}{
+		{"ASCII", []byte("package main\n"), EncodingUTF8},
+		{"UTF-8 with BOM", append([]byte{0xEF, 0xBB, 0xBF}, "package main"...), EncodingUTF8},
+		{"UTF-8 multibyte", []byte("// héllo 世界\n"), EncodingUTF8},
+		{"UTF-16LE with BOM", utf16Bytes("package main\n", false, true), EncodingUTF16LE},
+		{"UTF-16BE with BOM", utf16Bytes("package main\n", true, true), EncodingUTF16BE},
+		{"UTF-16LE without BOM", utf16Bytes("package main\n", false, false), EncodingUTF16LE},
+		{"Latin-1", []byte("// caf\xe9\n"), EncodingLatin1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectEncoding(tt.content); got != tt.want {
+				t.Errorf("DetectEncoding() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadTranscoded(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string][]byte{
+		"utf16.go":  utf16Bytes("package main\n", false, true),
+		"latin1.go": []byte("package main\n\n// caf\xe9\n"),
+		"bom.go":    append([]byte{0xEF, 0xBB, 0xBF}, "package main\n"...),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), content, 0600); err != nil {
+			t.Fatalf("Failed to write file %s: %v", name, err)
+		}
+	}
+
+	reader := NewDefaultReader().WithWorkDir(tmpDir)
+	ctx := context.Background()
+
+	if _, err := reader.ReadSourceFile(ctx, "utf16.go", ReadOptions{}); !errors.Is(err, ErrBinaryFile) {
+		t.Errorf("ReadSourceFile() without Transcode error = %v, want %v", err, ErrBinaryFile)
+	}
+
+	want := map[string]string{
+		"utf16.go":  "package main\n",
+		"latin1.go": "package main\n\n// café\n",
+		"bom.go":    "package main\n",
+	}
+	for name, text := range want {
+		content, err := reader.ReadSourceFile(ctx, name, ReadOptions{Transcode: true})
+		if err != nil {
+			t.Errorf("ReadSourceFile(%s) error = %v", name, err)
+			continue
+		}
+		if string(content) != text {
+			t.Errorf("ReadSourceFile(%s) = %q, want %q", name, content, text)
+		}
+	}
+
+	lines, err := ReadLines(ctx, reader, "utf16.go", ReadOptions{Transcode: true})
+	if err != nil {
+		t.Fatalf("Failed to read lines: %v", err)
+	}
+	if len(lines) != 1 || lines[0] != (SourceLine{Number: 1, Text: "package main"}) {
+		t.Errorf("ReadLines(utf16.go) = %v, want [{1 package main}]", lines)
+	}
+}
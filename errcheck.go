@@ -0,0 +1,211 @@
+package readgo
+
+import (
+	"context"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// uncheckedErrorRule reports calls whose error result is discarded by using
+// the call as a statement, and with ValidatorConfig.CheckBlankErrors also
+// those assigning it to the blank identifier. Deferred calls and calls
+// started with go are not reported, nor are writes that cannot fail, such
+// as printing to standard output, into a bytes.Buffer or into a hash.
+type uncheckedErrorRule struct {
+	blank bool
+}
+
+// Name returns the rule name
+func (r *uncheckedErrorRule) Name() string {
+	return "unchecked_error"
+}
+
+// configure reads whether blank assignments are reported
+func (r *uncheckedErrorRule) configure(cfg *ValidatorConfig) {
+	r.blank = cfg != nil && cfg.CheckBlankErrors
+}
+
+// Check reports the discarded errors in the package
+func (r *uncheckedErrorRule) Check(ctx context.Context, pkg *packages.Package) []Finding {
+	if pkg.TypesInfo == nil {
+		return nil
+	}
+	info := pkg.TypesInfo
+
+	var findings []Finding
+	report := func(call *ast.CallExpr, message string) {
+		pos := pkg.Fset.Position(call.Pos())
+		findings = append(findings, Finding{
+			Message: message,
+			File:    pos.Filename,
+			Line:    pos.Line,
+			Column:  pos.Column,
+		})
+	}
+
+	for _, file := range pkg.Syntax {
+		if ctx.Err() != nil {
+			break
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.ExprStmt:
+				call, ok := ast.Unparen(n.X).(*ast.CallExpr)
+				if ok && len(errorResults(info, call)) > 0 && !cannotFail(info, call) {
+					report(call, "error returned by "+types.ExprString(call.Fun)+" is not checked")
+				}
+			case *ast.AssignStmt:
+				if !r.blank {
+					return true
+				}
+				for _, call := range blankErrors(info, n) {
+					report(call, "error returned by "+types.ExprString(call.Fun)+" is assigned to _")
+				}
+			}
+			return true
+		})
+	}
+	return findings
+}
+
+// blankErrors returns the calls of assign whose error results are all
+// assigned to the blank identifier
+func blankErrors(info *types.Info, assign *ast.AssignStmt) []*ast.CallExpr {
+	isBlank := func(expr ast.Expr) bool {
+		id, ok := expr.(*ast.Ident)
+		return ok && id.Name == "_"
+	}
+
+	var calls []*ast.CallExpr
+	if len(assign.Rhs) == 1 && len(assign.Lhs) > 1 {
+		// v, _ := f()
+		call, ok := ast.Unparen(assign.Rhs[0]).(*ast.CallExpr)
+		if !ok || cannotFail(info, call) {
+			return nil
+		}
+		results := errorResults(info, call)
+		for _, i := range results {
+			if !isBlank(assign.Lhs[i]) {
+				return nil
+			}
+		}
+		if len(results) > 0 {
+			calls = append(calls, call)
+		}
+		return calls
+	}
+
+	// _ = f(), possibly among other assignments
+	for i, rhs := range assign.Rhs {
+		call, ok := ast.Unparen(rhs).(*ast.CallExpr)
+		if !ok || i >= len(assign.Lhs) || !isBlank(assign.Lhs[i]) || cannotFail(info, call) {
+			continue
+		}
+		if len(errorResults(info, call)) > 0 {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// errorResults returns the indices of the results of call that have type
+// error
+func errorResults(info *types.Info, call *ast.CallExpr) []int {
+	if tv, ok := info.Types[call.Fun]; !ok || tv.IsType() || tv.IsBuiltin() {
+		return nil
+	}
+	sig, ok := info.TypeOf(call.Fun).Underlying().(*types.Signature)
+	if !ok {
+		return nil
+	}
+
+	var results []int
+	for i := 0; i < sig.Results().Len(); i++ {
+		if types.Identical(sig.Results().At(i).Type(), types.Universe.Lookup("error").Type()) {
+			results = append(results, i)
+		}
+	}
+	return results
+}
+
+// cannotFail reports whether call only returns an error to satisfy an
+// interface: printing to standard output, and writing into a bytes.Buffer,
+// a strings.Builder or a hash.Hash. Other methods of those types, such as
+// ReadFrom or WriteTo, can still fail.
+func cannotFail(info *types.Info, call *ast.CallExpr) bool {
+	for _, name := range []string{"Print", "Printf", "Println"} {
+		if isPkgFunc(info, call.Fun, "fmt", name) {
+			return true
+		}
+	}
+	for _, name := range []string{"Fprint", "Fprintf", "Fprintln"} {
+		if isPkgFunc(info, call.Fun, "fmt", name) && len(call.Args) > 0 && isInfallibleWriter(info.TypeOf(call.Args[0])) {
+			return true
+		}
+	}
+
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	fn, ok := info.Uses[sel.Sel].(*types.Func)
+	if !ok {
+		return false
+	}
+	switch fn.Name() {
+	case "Write", "WriteString", "WriteByte", "WriteRune":
+	default:
+		return false
+	}
+	// Methods promoted from an embedded io.Writer have it as receiver, so
+	// the operand type is checked too
+	recv := fn.Type().(*types.Signature).Recv()
+	return recv != nil && (isInfallibleWriter(recv.Type()) || isInfallibleWriter(info.TypeOf(sel.X)))
+}
+
+// hashInterface has the method set of hash.Hash, whose Write never returns
+// an error, so that hashes are recognized without importing the package
+var hashInterface = func() *types.Interface {
+	bytesType := types.NewSlice(types.Typ[types.Byte])
+	method := func(name string, params, results []*types.Var) *types.Func {
+		sig := types.NewSignatureType(nil, nil, nil, types.NewTuple(params...), types.NewTuple(results...), false)
+		return types.NewFunc(token.NoPos, nil, name, sig)
+	}
+	v := func(t types.Type) *types.Var {
+		return types.NewParam(token.NoPos, nil, "", t)
+	}
+	iface := types.NewInterfaceType([]*types.Func{
+		method("Write", []*types.Var{v(bytesType)}, []*types.Var{v(types.Typ[types.Int]), v(types.Universe.Lookup("error").Type())}),
+		method("Sum", []*types.Var{v(bytesType)}, []*types.Var{v(bytesType)}),
+		method("Reset", nil, nil),
+		method("Size", nil, []*types.Var{v(types.Typ[types.Int])}),
+		method("BlockSize", nil, []*types.Var{v(types.Typ[types.Int])}),
+	}, nil)
+	return iface.Complete()
+}()
+
+// isInfallibleWriter reports whether t is a bytes.Buffer or strings.Builder,
+// or a pointer to one, or implements hash.Hash
+func isInfallibleWriter(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	if types.Implements(t, hashInterface) {
+		return true
+	}
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	switch named.Obj().Pkg().Path() + "." + named.Obj().Name() {
+	case "bytes.Buffer", "strings.Builder":
+		return true
+	}
+	return false
+}
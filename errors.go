@@ -14,6 +14,9 @@ var (
 
 	// ErrPermission indicates permission related errors
 	ErrPermission = fmt.Errorf("permission denied")
+
+	// ErrBinaryFile indicates that a file expected to hold text is binary
+	ErrBinaryFile = fmt.Errorf("binary file")
 )
 
 // AnalysisError represents an error that occurred during code analysis
@@ -0,0 +1,255 @@
+package readgo
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// errorType is the predeclared error interface
+var errorType = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
+// errorWrapRule reports fmt.Errorf calls that format an error without %w
+// and errors compared with == or != instead of errors.Is. Its findings use
+// the check names error_wrap and error_compare.
+type errorWrapRule struct{}
+
+// Name returns the rule name
+func (errorWrapRule) Name() string {
+	return "error_handling"
+}
+
+// Check reports the error handling problems in the package
+func (errorWrapRule) Check(ctx context.Context, pkg *packages.Package) []Finding {
+	if pkg.TypesInfo == nil {
+		return nil
+	}
+
+	var findings []Finding
+	report := func(check string, node ast.Node, format string, args ...interface{}) {
+		pos := pkg.Fset.Position(node.Pos())
+		findings = append(findings, Finding{
+			Rule:    check,
+			Message: fmt.Sprintf(format, args...),
+			File:    pos.Filename,
+			Line:    pos.Line,
+			Column:  pos.Column,
+		})
+	}
+
+	isError := func(expr ast.Expr) bool {
+		tv, ok := pkg.TypesInfo.Types[expr]
+		return ok && !tv.IsNil() && tv.Type != nil && types.Implements(tv.Type, errorType)
+	}
+
+	for _, file := range pkg.Syntax {
+		if ctx.Err() != nil {
+			break
+		}
+		for _, decl := range file.Decls {
+			fn, isFunc := decl.(*ast.FuncDecl)
+			// Is methods implement errors.Is and compare targets directly
+			inIsMethod := isFunc && fn.Recv != nil && fn.Name.Name == "Is"
+
+			ast.Inspect(decl, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.CallExpr:
+					if !isPkgFunc(pkg.TypesInfo, n.Fun, "fmt", "Errorf") || len(n.Args) < 2 {
+						return true
+					}
+					format, ok := constantString(pkg.TypesInfo, n.Args[0])
+					if !ok {
+						return true
+					}
+					for _, d := range formatVerbs(format) {
+						if d.arg+1 >= len(n.Args) || d.verb == 'w' {
+							continue
+						}
+						if arg := n.Args[d.arg+1]; isError(arg) {
+							report("error_wrap", arg, "fmt.Errorf formats error %s with %%%c; use %%w to wrap it", types.ExprString(arg), d.verb)
+							findings[len(findings)-1].SuggestedFixes = wrapVerbFix(pkg.Fset, n.Args[0], d.arg)
+						}
+					}
+				case *ast.BinaryExpr:
+					if inIsMethod || (n.Op != token.EQL && n.Op != token.NEQ) {
+						return true
+					}
+					if isError(n.X) && isError(n.Y) {
+						report("error_compare", n, "comparing errors with %s; use errors.Is", n.Op)
+					}
+				}
+				return true
+			})
+		}
+	}
+	return findings
+}
+
+// isPkgFunc reports whether expr refers to the function name in the package
+// with the given path
+func isPkgFunc(info *types.Info, expr ast.Expr, path, name string) bool {
+	sel, ok := ast.Unparen(expr).(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	fn, ok := info.Uses[sel.Sel].(*types.Func)
+	return ok && fn.Pkg() != nil && fn.Pkg().Path() == path && fn.Name() == name
+}
+
+// constantString returns the value of a constant string expression
+func constantString(info *types.Info, expr ast.Expr) (string, bool) {
+	tv, ok := info.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+// formatDirective is a verb of a printf format string
+type formatDirective struct {
+	arg   int  // Index of the operand it formats
+	verb  rune // Verb, or '*' for an operand consumed by a * width
+	start int  // Byte offset of the '%'
+	end   int  // Byte offset just past the verb
+}
+
+// formatVerbs returns the directive formatting each operand of a printf
+// format string, in operand order. An operand formatted more than once gets
+// its last directive. Operands consumed by * widths have the verb '*'.
+func formatVerbs(format string) []formatDirective {
+	byArg := make(map[int]formatDirective)
+	for _, d := range parseFormat(format) {
+		byArg[d.arg] = d
+	}
+	verbs := make([]formatDirective, 0, len(byArg))
+	for _, d := range byArg {
+		verbs = append(verbs, d)
+	}
+	sort.Slice(verbs, func(i, j int) bool { return verbs[i].arg < verbs[j].arg })
+	return verbs
+}
+
+// parseFormat returns the directives of a printf format string
+func parseFormat(format string) []formatDirective {
+	var directives []formatDirective
+	arg := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		start := i
+		i++
+		for ; i < len(format); i++ {
+			c := format[i]
+			switch {
+			case c == '+' || c == '-' || c == '#' || c == ' ' || c == '0' || c == '.' || (c >= '1' && c <= '9'):
+				continue
+			case c == '*':
+				directives = append(directives, formatDirective{arg: arg, verb: '*', start: i, end: i + 1})
+				arg++
+				continue
+			case c == '[':
+				end := i + 1
+				for end < len(format) && format[end] != ']' {
+					end++
+				}
+				if n, err := strconv.Atoi(format[i+1 : min(end, len(format))]); err == nil {
+					arg = n - 1
+				}
+				i = end
+				continue
+			}
+			break
+		}
+		if i >= len(format) {
+			break
+		}
+		if format[i] == '%' {
+			continue
+		}
+		directives = append(directives, formatDirective{arg: arg, verb: rune(format[i]), start: start, end: i + 1})
+		arg++
+	}
+	return directives
+}
+
+// wrapVerbFix returns a fix replacing the plain %v or %s directive for
+// operand arg of a format string literal with %w. No fix is offered when
+// the directive is spelled with escape sequences, as in "\x25v".
+func wrapVerbFix(fset *token.FileSet, format ast.Expr, arg int) []SuggestedFix {
+	lit, ok := format.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return nil
+	}
+	value, offsets, ok := literalOffsets(lit.Value)
+	if !ok {
+		return nil
+	}
+	for _, d := range parseFormat(value) {
+		if d.arg != arg || d.end-d.start != 2 || (d.verb != 'v' && d.verb != 's') {
+			continue
+		}
+		start := offsets[d.start]
+		if lit.Value[start] != '%' || offsets[d.start+1] != start+1 || lit.Value[start+1] != byte(d.verb) {
+			return nil
+		}
+		pos := lit.Pos() + token.Pos(start)
+		return []SuggestedFix{{
+			Message: "Wrap the error with %w",
+			Edits:   []TextEdit{newTextEdit(fset, pos, pos+2, "%w")},
+		}}
+	}
+	return nil
+}
+
+// literalOffsets returns the value of the string literal lit and, for every
+// byte of the value, the offset in lit of the character or escape sequence
+// it comes from
+func literalOffsets(lit string) (string, []int, bool) {
+	if len(lit) < 2 {
+		return "", nil, false
+	}
+	if lit[0] == '`' {
+		// Carriage returns are dropped from raw strings
+		value := lit[1 : len(lit)-1]
+		if strings.Contains(value, "\r") {
+			return "", nil, false
+		}
+		offsets := make([]int, len(value))
+		for i := range offsets {
+			offsets[i] = i + 1
+		}
+		return value, offsets, true
+	}
+
+	var value []byte
+	var offsets []int
+	s, pos := lit[1:len(lit)-1], 1
+	for len(s) > 0 {
+		r, multibyte, tail, err := strconv.UnquoteChar(s, '"')
+		if err != nil {
+			return "", nil, false
+		}
+		n := len(value)
+		if multibyte {
+			value = utf8.AppendRune(value, r)
+		} else {
+			value = append(value, byte(r))
+		}
+		for range value[n:] {
+			offsets = append(offsets, pos)
+		}
+		pos += len(s) - len(tail)
+		s = tail
+	}
+	return string(value), offsets, true
+}
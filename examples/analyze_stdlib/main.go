@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"strings"
 	"time"
 
 	"github.com/iamlongalong/readgo"
@@ -31,10 +30,8 @@ func main() {
 
 	// Print interfaces
 	fmt.Println("Interfaces:")
-	for _, t := range result.Types {
-		if strings.Contains(t.Type, "interface") {
-			fmt.Printf("  - %s: %s\n", t.Name, t.Type)
-		}
+	for _, t := range result.FilterKind(readgo.TypeKindInterface).Types {
+		fmt.Printf("  - %s: %s\n", t.Name, t.Type)
 	}
 	fmt.Println()
 
@@ -47,13 +44,14 @@ func main() {
 	fmt.Printf("  Name: %s\n", reader.Name)
 	fmt.Printf("  Package: %s\n", reader.Package)
 	fmt.Printf("  Type: %s\n", reader.Type)
+	fmt.Printf("  Kind: %s\n", reader.Kind)
 	fmt.Printf("  Exported: %v\n\n", reader.IsExported)
 
 	// Print cache statistics
 	fmt.Println("Cache Statistics:")
 	fmt.Println("----------------")
 	stats := analyzer.GetCacheStats()
-	for key, value := range stats {
-		fmt.Printf("%s: %v\n", key, value)
-	}
+	fmt.Printf("Enabled: %v\n", stats.Enabled)
+	fmt.Printf("Hits: %d, Misses: %d, Hit ratio: %.2f\n", stats.Hits, stats.Misses, stats.HitRatio)
+	fmt.Printf("Entries: %d, Bytes used: %d, Evictions: %d\n", stats.Entries, stats.BytesUsed, stats.Evictions)
 }
@@ -26,9 +26,9 @@ func main() {
 	fmt.Println("\nCache Statistics:")
 	fmt.Println(strings.Repeat("-", 80))
 	stats := analyzer.GetCacheStats()
-	for key, value := range stats {
-		fmt.Printf("%s: %v\n", key, value)
-	}
+	fmt.Printf("Enabled: %v\n", stats.Enabled)
+	fmt.Printf("Hits: %d, Misses: %d, Hit ratio: %.2f\n", stats.Hits, stats.Misses, stats.HitRatio)
+	fmt.Printf("Entries: %d, Bytes used: %d, Evictions: %d\n", stats.Entries, stats.BytesUsed, stats.Evictions)
 }
 
 func analyzePackage(analyzer *readgo.DefaultAnalyzer, pkgPath string) {
@@ -46,20 +46,17 @@ func analyzePackage(analyzer *readgo.DefaultAnalyzer, pkgPath string) {
 	fmt.Printf("Package: %s\n", result.Name)
 	fmt.Printf("Path: %s\n", result.Path)
 
-	// Print types
+	// Print exported types
+	exported := result.ExportedOnly()
 	fmt.Println("\nTypes:")
-	for _, t := range result.Types {
-		if t.IsExported {
-			fmt.Printf("  - %s.%s: %s\n", t.Package, t.Name, t.Type)
-		}
+	for _, t := range exported.Types {
+		fmt.Printf("  - %s.%s: %s\n", t.Package, t.Name, t.Type)
 	}
 
-	// Print functions
+	// Print exported functions
 	fmt.Println("\nFunctions:")
-	for _, f := range result.Functions {
-		if f.IsExported {
-			fmt.Printf("  - %s.%s\n", f.Package, f.Name)
-		}
+	for _, f := range exported.Functions {
+		fmt.Printf("  - %s.%s\n", f.Package, f.Name)
 	}
 
 	// Print imports
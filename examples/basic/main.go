@@ -51,7 +51,7 @@ func main() {
 	fmt.Println("\nCache Statistics:")
 	fmt.Println("----------------")
 	stats := analyzer.GetCacheStats()
-	for key, value := range stats {
-		fmt.Printf("%s: %v\n", key, value)
-	}
+	fmt.Printf("Enabled: %v\n", stats.Enabled)
+	fmt.Printf("Hits: %d, Misses: %d, Hit ratio: %.2f\n", stats.Hits, stats.Misses, stats.HitRatio)
+	fmt.Printf("Entries: %d, Bytes used: %d, Evictions: %d\n", stats.Entries, stats.BytesUsed, stats.Evictions)
 }
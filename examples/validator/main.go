@@ -53,7 +53,7 @@ func InvalidFunction() {
 	fmt.Println("Validating individual files:")
 	for name := range files {
 		fmt.Printf("\nValidating %s:\n", name)
-		result, err := validator.ValidateFile(context.Background(), name)
+		result, err := validator.ValidateFile(context.Background(), name, readgo.ValidationLevelStandard)
 		if err != nil {
 			log.Printf("Error validating %s: %v", name, err)
 			continue
@@ -78,7 +78,7 @@ func InvalidFunction() {
 
 	// Validate the entire project
 	fmt.Println("\nValidating entire project:")
-	result, err := validator.ValidateProject(context.Background())
+	result, err := validator.ValidateProject(context.Background(), readgo.ValidationLevelStandard)
 	if err != nil {
 		log.Fatalf("Failed to validate project: %v", err)
 	}
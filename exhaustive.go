@@ -0,0 +1,116 @@
+package readgo
+
+import (
+	"context"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// exhaustiveRule reports switch statements over an enum that neither cover
+// every member nor have a default case. An enum is a named type with a
+// basic underlying type and at least two constants of that type declared in
+// its package; members of another package count only if they are exported.
+type exhaustiveRule struct{}
+
+// Name returns the rule name
+func (exhaustiveRule) Name() string {
+	return "exhaustive"
+}
+
+// Check reports the non-exhaustive enum switches in the package
+func (exhaustiveRule) Check(ctx context.Context, pkg *packages.Package) []Finding {
+	if pkg.TypesInfo == nil {
+		return nil
+	}
+	info := pkg.TypesInfo
+
+	var findings []Finding
+	for _, file := range pkg.Syntax {
+		if ctx.Err() != nil {
+			break
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			sw, ok := n.(*ast.SwitchStmt)
+			if !ok || sw.Tag == nil {
+				return true
+			}
+			named, ok := types.Unalias(info.TypeOf(sw.Tag)).(*types.Named)
+			if !ok {
+				return true
+			}
+			members := enumMembers(named, pkg.Types)
+			if len(members) < 2 {
+				return true
+			}
+
+			covered := make(map[string]bool)
+			for _, stmt := range sw.Body.List {
+				clause := stmt.(*ast.CaseClause)
+				if clause.List == nil {
+					return true // default
+				}
+				for _, expr := range clause.List {
+					tv, ok := info.Types[expr]
+					if !ok || tv.Value == nil {
+						return true // Not decidable
+					}
+					covered[tv.Value.ExactString()] = true
+				}
+			}
+
+			var missing []string
+			for _, m := range members {
+				if !covered[m.Val().ExactString()] {
+					missing = append(missing, m.Name())
+				}
+			}
+			if len(missing) == 0 {
+				return true
+			}
+			pos := pkg.Fset.Position(sw.Pos())
+			findings = append(findings, Finding{
+				Message: "switch on " + named.Obj().Name() + " does not handle " + strings.Join(missing, ", ") + " and has no default",
+				File:    pos.Filename,
+				Line:    pos.Line,
+				Column:  pos.Column,
+			})
+			return true
+		})
+	}
+	return findings
+}
+
+// enumMembers returns the constants of type named declared in its package,
+// in declaration order, leaving out unexported ones when from is another
+// package. It returns nil unless named has a basic underlying type.
+func enumMembers(named *types.Named, from *types.Package) []*types.Const {
+	obj := named.Obj()
+	if obj.Pkg() == nil {
+		return nil
+	}
+	if _, ok := named.Underlying().(*types.Basic); !ok {
+		return nil
+	}
+
+	var members []*types.Const
+	scope := obj.Pkg().Scope()
+	for _, name := range scope.Names() {
+		c, ok := scope.Lookup(name).(*types.Const)
+		if !ok || c.Name() == "_" || c.Val().Kind() == constant.Unknown || !types.Identical(c.Type(), named) {
+			continue
+		}
+		if obj.Pkg() != from && !c.Exported() {
+			continue
+		}
+		members = append(members, c)
+	}
+	sort.SliceStable(members, func(i, j int) bool {
+		return members[i].Pos() < members[j].Pos()
+	})
+	return members
+}
@@ -0,0 +1,89 @@
+package readgo
+
+// ExportedOnly returns a copy of r holding only its exported types and
+// functions
+func (r *AnalysisResult) ExportedOnly() *AnalysisResult {
+	return r.filter(
+		func(t TypeInfo) bool { return t.IsExported },
+		func(f FunctionInfo) bool { return f.IsExported },
+		nil,
+	)
+}
+
+// FilterPackage returns a copy of r holding only the types, functions and
+// extractor entities of the packages whose import path matches glob. A
+// glob without a slash is matched against the last path element, so
+// "models" selects every package named models; one with a slash is matched
+// against the whole import path, where "**" matches any number of elements,
+// e.g. "github.com/org/**/internal/*". The Packages of other packages are
+// dropped; the flat Imports and Dependencies are kept.
+func (r *AnalysisResult) FilterPackage(glob string) *AnalysisResult {
+	match := func(pkgPath string) bool {
+		return matchPattern(glob, pkgPath)
+	}
+	c := r.filter(
+		func(t TypeInfo) bool { return match(t.Package) },
+		func(f FunctionInfo) bool { return match(f.Package) },
+		func(e Entity) bool { return match(e.Package) },
+	)
+	if c.Packages != nil {
+		c.Packages = keep(c.Packages, func(p PackageAnalysis) bool { return match(p.Path) })
+	}
+	return c
+}
+
+// FilterKind returns a copy of r holding only the types of the given kinds.
+// Functions are kept.
+func (r *AnalysisResult) FilterKind(kinds ...TypeKind) *AnalysisResult {
+	return r.filter(
+		func(t TypeInfo) bool {
+			for _, kind := range kinds {
+				if t.Kind == kind {
+					return true
+				}
+			}
+			return false
+		},
+		nil,
+		nil,
+	)
+}
+
+// filter returns a copy of r holding the types, functions and entities
+// accepted by the given functions; a nil function accepts everything
+func (r *AnalysisResult) filter(keepType func(TypeInfo) bool, keepFunc func(FunctionInfo) bool, keepEntity func(Entity) bool) *AnalysisResult {
+	c := r.clone()
+	if keepType != nil {
+		c.Types = keep(c.Types, keepType)
+		for i := range c.Packages {
+			c.Packages[i].Types = keep(c.Packages[i].Types, keepType)
+		}
+	}
+	if keepFunc != nil {
+		c.Functions = keep(c.Functions, keepFunc)
+		for i := range c.Packages {
+			c.Packages[i].Functions = keep(c.Packages[i].Functions, keepFunc)
+		}
+	}
+	if keepEntity != nil && c.Extensions != nil {
+		for name, entities := range c.Extensions {
+			if entities = keep(entities, keepEntity); len(entities) > 0 {
+				c.Extensions[name] = entities
+			} else {
+				delete(c.Extensions, name)
+			}
+		}
+	}
+	return c
+}
+
+// keep returns the elements of s accepted by ok, reusing its array
+func keep[T any](s []T, ok func(T) bool) []T {
+	kept := s[:0]
+	for _, v := range s {
+		if ok(v) {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,97 @@
+package readgo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnalysisResultFilters(t *testing.T) {
+	result := &AnalysisResult{
+		Name: "project",
+		Types: []TypeInfo{
+			{Name: "User", Package: "example.com/app/models", Kind: TypeKindStruct, IsExported: true},
+			{Name: "Store", Package: "example.com/app/models", Kind: TypeKindInterface, IsExported: true},
+			{Name: "handler", Package: "example.com/app/internal/http", Kind: TypeKindFunc},
+			{Name: "ID", Package: "example.com/app", Kind: TypeKindBasic, IsExported: true},
+		},
+		Functions: []FunctionInfo{
+			{Name: "NewUser", Package: "example.com/app/models", IsExported: true},
+			{Name: "serve", Package: "example.com/app/internal/http"},
+		},
+		Imports: []string{"fmt"},
+		Extensions: map[string][]Entity{
+			"routes": {{Name: "GET /", Package: "example.com/app/internal/http"}},
+		},
+	}
+	original := result.clone()
+
+	tests := []struct {
+		name      string
+		filtered  *AnalysisResult
+		types     []string
+		functions []string
+		entities  int
+	}{
+		{
+			name:      "exported only",
+			filtered:  result.ExportedOnly(),
+			types:     []string{"User", "Store", "ID"},
+			functions: []string{"NewUser"},
+			entities:  1,
+		},
+		{
+			name:      "package name",
+			filtered:  result.FilterPackage("models"),
+			types:     []string{"User", "Store"},
+			functions: []string{"NewUser"},
+		},
+		{
+			name:      "import path glob",
+			filtered:  result.FilterPackage("example.com/app/**/http"),
+			types:     []string{"handler"},
+			functions: []string{"serve"},
+			entities:  1,
+		},
+		{
+			name:      "kinds",
+			filtered:  result.FilterKind(TypeKindStruct, TypeKindInterface),
+			types:     []string{"User", "Store"},
+			functions: []string{"NewUser", "serve"},
+			entities:  1,
+		},
+		{
+			name:      "chained",
+			filtered:  result.FilterPackage("example.com/app/*").FilterKind(TypeKindInterface),
+			types:     []string{"Store"},
+			functions: []string{"NewUser"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var types, functions []string
+			for _, typ := range tt.filtered.Types {
+				types = append(types, typ.Name)
+			}
+			for _, f := range tt.filtered.Functions {
+				functions = append(functions, f.Name)
+			}
+			if !reflect.DeepEqual(types, tt.types) {
+				t.Errorf("types = %v, want %v", types, tt.types)
+			}
+			if !reflect.DeepEqual(functions, tt.functions) {
+				t.Errorf("functions = %v, want %v", functions, tt.functions)
+			}
+			if got := len(tt.filtered.Extensions["routes"]); got != tt.entities {
+				t.Errorf("routes = %d, want %d", got, tt.entities)
+			}
+			if !reflect.DeepEqual(tt.filtered.Imports, result.Imports) {
+				t.Errorf("imports = %v, want them kept", tt.filtered.Imports)
+			}
+		})
+	}
+
+	if !reflect.DeepEqual(result, original) {
+		t.Errorf("filtering modified the result: %+v", result)
+	}
+}
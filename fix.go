@@ -0,0 +1,160 @@
+package readgo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// safeFixChecks are the checks whose suggested fixes Fix applies; their
+// fixes never change program behavior
+var safeFixChecks = map[string]bool{
+	"unused_import": true,
+}
+
+// FixReport describes the changes made by Fix
+type FixReport struct {
+	Files []FileFix `json:"files,omitempty" yaml:"files,omitempty"`
+}
+
+// FileFix lists the fixes applied to one file
+type FileFix struct {
+	Path    string   `json:"path" yaml:"path"`       // File path relative to the validator's base directory
+	Applied []string `json:"applied" yaml:"applied"` // Description of every applied fix
+}
+
+// Fix applies the safe subset of fixes to the project: it removes unused
+// imports, formats files with gofmt and adds missing newlines at the end of
+// files. At ValidationLevelBasic, where warnings are not reported, only
+// formatting is applied. Generated files are left untouched.
+func (v *DefaultValidator) Fix(ctx context.Context, level ValidationLevel) (*FixReport, error) {
+	if !level.valid() {
+		return nil, fmt.Errorf("%w: unknown validation level %d", ErrInvalidInput, int(level))
+	}
+
+	base, err := filepath.Abs(v.baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("fix project: %w", err)
+	}
+
+	// Collect the safe fixes by file
+	fixes := make(map[string][]SuggestedFix)
+	if level != ValidationLevelBasic {
+		pkgs, err := v.loadPackages(ctx, base, true, nil)
+		if err != nil {
+			return nil, fmt.Errorf("fix project: %w", err)
+		}
+		for _, f := range v.checkPackages(ctx, pkgs, nil, &ValidationResult{}) {
+			if safeFixChecks[f.Rule] && len(f.SuggestedFixes) > 0 {
+				fixes[f.File] = append(fixes[f.File], f.SuggestedFixes[0])
+			}
+		}
+	}
+
+	report := &FixReport{}
+	err = filepath.WalkDir(base, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != base && (isHiddenDir(d.Name()) || isDefaultExcludedDir(d.Name()) || d.Name() == "testdata") {
+				return filepath.SkipDir
+			}
+			// Nested modules are projects of their own
+			if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil && path != base {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".go" {
+			return nil
+		}
+
+		applied, err := fixFile(path, fixes[path])
+		if err != nil || len(applied) == 0 {
+			return err
+		}
+		rel, _ := filepath.Rel(base, path)
+		report.Files = append(report.Files, FileFix{Path: rel, Applied: applied})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fix project: %w", err)
+	}
+
+	return report, nil
+}
+
+// fixFile applies fixes, gofmt and a final newline to the file at path and
+// returns a description of every change made
+func fixFile(path string, fixes []SuggestedFix) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if isGeneratedFile(content) {
+		return nil, nil
+	}
+
+	var applied []string
+	fixed := content
+
+	// Apply fixes in order, skipping any that conflict with earlier ones
+	var edits []TextEdit
+	sort.SliceStable(fixes, func(i, j int) bool {
+		return fixes[i].Edits[0].Start < fixes[j].Edits[0].Start
+	})
+	for _, fix := range fixes {
+		candidate := append(append([]TextEdit(nil), edits...), fix.Edits...)
+		if _, err := applyEdits(content, candidate); err != nil {
+			continue
+		}
+		edits = candidate
+		applied = append(applied, fix.Message)
+	}
+	if len(edits) > 0 {
+		if fixed, err = applyEdits(content, edits); err != nil {
+			return nil, err
+		}
+	}
+
+	// Source that does not parse is left unformatted
+	if formatted, err := format.Source(fixed); err == nil && !bytes.Equal(formatted, fixed) {
+		fixed = formatted
+		applied = append(applied, "Format with gofmt")
+	}
+	if len(fixed) > 0 && !bytes.HasSuffix(fixed, []byte("\n")) {
+		fixed = append(fixed, '\n')
+		applied = append(applied, "Add newline at end of file")
+	}
+
+	if bytes.Equal(fixed, content) {
+		return nil, nil
+	}
+	if err := os.WriteFile(path, fixed, info.Mode().Perm()); err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+// String summarizes the report, one file per line
+func (r *FixReport) String() string {
+	var b strings.Builder
+	for _, f := range r.Files {
+		fmt.Fprintf(&b, "%s: %s\n", f.Path, strings.Join(f.Applied, ", "))
+	}
+	return b.String()
+}
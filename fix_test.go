@@ -0,0 +1,85 @@
+package readgo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFix(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"imports.go":   "package test\n\nimport (\n\t\"fmt\"\n\t\"os\"\n)\n\nfunc F() { fmt.Println() }\n",
+		"format.go":    "package test\n\nfunc  G( ) {\n}",
+		"clean.go":     "package test\n\nfunc H() {}\n",
+		"generated.go": "// Code generated by tool. DO NOT EDIT.\n\npackage test\nfunc  I() {}",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	report, err := NewValidator(tmpDir).Fix(context.Background(), ValidationLevelStandard)
+	if err != nil {
+		t.Fatalf("Fix() error = %v", err)
+	}
+
+	want := []FileFix{
+		{Path: "format.go", Applied: []string{"Format with gofmt"}},
+		{Path: "imports.go", Applied: []string{`Remove import "os"`}},
+	}
+	if !reflect.DeepEqual(report.Files, want) {
+		t.Errorf("Fix() report = %+v, want %+v", report.Files, want)
+	}
+
+	wantContent := map[string]string{
+		"imports.go":   "package test\n\nimport (\n\t\"fmt\"\n)\n\nfunc F() { fmt.Println() }\n",
+		"format.go":    "package test\n\nfunc G() {\n}\n",
+		"clean.go":     files["clean.go"],
+		"generated.go": files["generated.go"],
+	}
+	for name, want := range wantContent {
+		got, err := os.ReadFile(filepath.Join(tmpDir, name))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", name, err)
+		}
+		if string(got) != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+
+	// Nested modules are left alone
+	nested := filepath.Join(tmpDir, "plugin")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	nestedFiles := map[string]string{
+		"go.mod":    "module example.com/plugin\n\ngo 1.22\n",
+		"plugin.go": "package plugin\nfunc  K() {}",
+	}
+	for name, content := range nestedFiles {
+		if err := os.WriteFile(filepath.Join(nested, name), []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	// Only formatting at the basic level
+	content := "package test\n\nimport \"os\"\n\nfunc J() {}"
+	if err := os.WriteFile(filepath.Join(tmpDir, "basic.go"), []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	report, err = NewValidator(tmpDir).Fix(context.Background(), ValidationLevelBasic)
+	if err != nil {
+		t.Fatalf("Fix() error = %v", err)
+	}
+	want = []FileFix{{Path: "basic.go", Applied: []string{"Format with gofmt"}}}
+	if !reflect.DeepEqual(report.Files, want) {
+		t.Errorf("Fix() basic report = %+v, want %+v", report.Files, want)
+	}
+	if got, err := os.ReadFile(filepath.Join(nested, "plugin.go")); err != nil || string(got) != nestedFiles["plugin.go"] {
+		t.Errorf("Fix() changed the nested module: %q, %v", got, err)
+	}
+}
@@ -0,0 +1,56 @@
+package readgo
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"sort"
+)
+
+// newTextEdit creates an edit replacing the source between start and end
+func newTextEdit(fset *token.FileSet, start, end token.Pos, newText string) TextEdit {
+	startPos, endPos := fset.Position(start), fset.Position(end)
+	return TextEdit{
+		File:        startPos.Filename,
+		Start:       startPos.Offset,
+		End:         endPos.Offset,
+		StartLine:   startPos.Line,
+		StartColumn: startPos.Column,
+		EndLine:     endPos.Line,
+		EndColumn:   endPos.Column,
+		NewText:     newText,
+	}
+}
+
+// deleteLinesEdit creates an edit deleting the whole lines spanned by the
+// source between start and end, including the final newline
+func deleteLinesEdit(fset *token.FileSet, start, end token.Pos) TextEdit {
+	file := fset.File(start)
+	from := file.LineStart(file.Line(start))
+	to := token.Pos(file.Base() + file.Size())
+	if line := file.Line(end); line < file.LineCount() {
+		to = file.LineStart(line + 1)
+	}
+	return newTextEdit(fset, from, to, "")
+}
+
+// applyEdits applies non-overlapping edits to content
+func applyEdits(content []byte, edits []TextEdit) ([]byte, error) {
+	sorted := append([]TextEdit(nil), edits...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Start < sorted[j].Start
+	})
+
+	var out bytes.Buffer
+	last := 0
+	for _, e := range sorted {
+		if e.Start < last || e.End < e.Start || e.End > len(content) {
+			return nil, fmt.Errorf("%w: edit %d-%d overlaps or is out of range", ErrInvalidInput, e.Start, e.End)
+		}
+		out.Write(content[last:e.Start])
+		out.WriteString(e.NewText)
+		last = e.End
+	}
+	out.Write(content[last:])
+	return out.Bytes(), nil
+}
@@ -0,0 +1,82 @@
+package readgo
+
+import (
+	"testing"
+)
+
+func TestSuggestedFixes(t *testing.T) {
+	tests := []struct {
+		name  string
+		check string
+		src   string
+		want  string
+	}{
+		{
+			name:  "unused import in group",
+			check: "unused_import",
+			src:   "package test\n\nimport (\n\t\"fmt\"\n\t\"os\"\n)\n\nfunc F() { fmt.Println() }\n",
+			want:  "package test\n\nimport (\n\t\"fmt\"\n)\n\nfunc F() { fmt.Println() }\n",
+		},
+		{
+			name:  "single unused import",
+			check: "unused_import",
+			src:   "package test\n\nimport \"os\"\n\nfunc F() {}\n",
+			want:  "package test\n\n\nfunc F() {}\n",
+		},
+		{
+			name:  "missing %w",
+			check: "error_wrap",
+			src:   "package test\n\nimport \"fmt\"\n\nfunc F(err error) error {\n\treturn fmt.Errorf(\"load\\t%d: %v\", 1, err)\n}\n",
+			want:  "package test\n\nimport \"fmt\"\n\nfunc F(err error) error {\n\treturn fmt.Errorf(\"load\\t%d: %w\", 1, err)\n}\n",
+		},
+		{
+			name:  "missing %w after escapes",
+			check: "error_wrap",
+			src:   "package test\n\nimport \"fmt\"\n\nfunc F(err error) error {\n\treturn fmt.Errorf(\"\\x25d \\u00e9: %v\", 1, err)\n}\n",
+			want:  "package test\n\nimport \"fmt\"\n\nfunc F(err error) error {\n\treturn fmt.Errorf(\"\\x25d \\u00e9: %w\", 1, err)\n}\n",
+		},
+		{
+			name:  "unkeyed fields",
+			check: "unkeyed_fields",
+			src:   "package test\n\nimport \"image\"\n\nvar P = image.Point{1, 2}\n",
+			want:  "package test\n\nimport \"image\"\n\nvar P = image.Point{X: 1, Y: 2}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings := ruleWarnings(t, tt.src, nil, tt.check)
+			if len(warnings) != 1 || len(warnings[0].SuggestedFixes) != 1 {
+				t.Fatalf("%s warnings = %+v, want one with a fix", tt.check, warnings)
+			}
+			fix := warnings[0].SuggestedFixes[0]
+			if fix.Edits[0].File != "fixture.go" {
+				t.Errorf("edit file = %q, want fixture.go", fix.Edits[0].File)
+			}
+			got, err := applyEdits([]byte(tt.src), fix.Edits)
+			if err != nil {
+				t.Fatalf("applyEdits() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("fixed source = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapVerbFixEscapedDirective(t *testing.T) {
+	for _, format := range []string{`"load: \x25v"`, `"load: %\x76"`, `"load: \u0025v"`, `"load: \045s"`} {
+		src := "package test\n\nimport \"fmt\"\n\nfunc F(err error) error {\n\treturn fmt.Errorf(" + format + ", err)\n}\n"
+		warnings := ruleWarnings(t, src, nil, "error_wrap")
+		if len(warnings) != 1 || len(warnings[0].SuggestedFixes) != 0 {
+			t.Errorf("error_wrap warnings for %s = %+v, want one without a fix", format, warnings)
+		}
+	}
+}
+
+func TestApplyEditsOverlap(t *testing.T) {
+	edits := []TextEdit{{Start: 0, End: 4}, {Start: 2, End: 6}}
+	if _, err := applyEdits([]byte("package"), edits); err == nil {
+		t.Error("applyEdits() expected error for overlapping edits")
+	}
+}
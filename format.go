@@ -0,0 +1,294 @@
+package readgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format identifies a serialization format of results
+type Format string
+
+const (
+	// FormatJSON is indented JSON, as described by the shipped schemas
+	FormatJSON Format = "json"
+	// FormatYAML is YAML, with the same field names as JSON
+	FormatYAML Format = "yaml"
+	// FormatTOML is TOML, with the same field names as JSON
+	FormatTOML Format = "toml"
+)
+
+// ParseFormat parses a format name, accepting "yml" for YAML
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "json":
+		return FormatJSON, nil
+	case "yaml", "yml":
+		return FormatYAML, nil
+	case "toml":
+		return FormatTOML, nil
+	default:
+		return "", fmt.Errorf("%w: unknown format %q", ErrInvalidInput, name)
+	}
+}
+
+// Encode writes the analysis result to w in the given format
+func (r *AnalysisResult) Encode(w io.Writer, format Format) error {
+	return Encode(w, r, format)
+}
+
+// Encode writes the validation result to w in the given format
+func (r *ValidationResult) Encode(w io.Writer, format Format) error {
+	return Encode(w, r, format)
+}
+
+// Encode writes v, one of the output types, to w in the given format. All
+// formats use the JSON field names, so the shipped schemas describe each of
+// them. TOML has no null, so null fields are left out, and its top level
+// must be a table, so v must encode as a JSON object.
+func Encode(w io.Writer, v interface{}, format Format) error {
+	var buf bytes.Buffer
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(&buf)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(v); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+	case FormatYAML:
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return fmt.Errorf("failed to encode YAML: %w", err)
+		}
+		if err := enc.Close(); err != nil {
+			return fmt.Errorf("failed to encode YAML: %w", err)
+		}
+	case FormatTOML:
+		if err := encodeTOML(&buf, v); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: unknown format %q", ErrInvalidInput, format)
+	}
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write %s: %w", format, err)
+	}
+	return nil
+}
+
+// tomlField is a field of a TOML table, in the order of the JSON encoding
+type tomlField struct {
+	key   string
+	value interface{} // json.Number, string, bool, nil, []interface{} or []tomlField
+}
+
+// encodeTOML writes v as a TOML document. It goes through JSON so that the
+// field names, omitempty and text marshalers match the JSON output.
+func encodeTOML(buf *bytes.Buffer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode TOML: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	value, err := decodeOrdered(dec)
+	if err != nil {
+		return fmt.Errorf("failed to encode TOML: %w", err)
+	}
+	table, ok := value.([]tomlField)
+	if !ok {
+		return fmt.Errorf("%w: TOML of %T, want a value encoding as an object", ErrInvalidInput, v)
+	}
+	writeTOMLTable(buf, nil, table)
+	return nil
+}
+
+// decodeOrdered decodes the next JSON value from dec, keeping the order of
+// object fields. Fields of maps are sorted, as encoding/json writes them.
+func decodeOrdered(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		table := []tomlField{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			table = append(table, tomlField{key: key.(string), value: value})
+		}
+		_, err := dec.Token()
+		return table, err
+	case json.Delim('['):
+		array := []interface{}{}
+		for dec.More() {
+			value, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		_, err := dec.Token()
+		return array, err
+	default:
+		return tok, nil
+	}
+}
+
+// writeTOMLTable writes the fields of the table at path. Plain values come
+// first, as TOML requires, then sub-tables, then arrays of tables.
+func writeTOMLTable(buf *bytes.Buffer, path []string, table []tomlField) {
+	var tables, arrays []tomlField
+	for _, f := range table {
+		if f.value == nil {
+			continue
+		}
+		if _, ok := f.value.([]tomlField); ok {
+			tables = append(tables, f)
+			continue
+		}
+		if array, ok := f.value.([]interface{}); ok && isTableArray(array) {
+			arrays = append(arrays, f)
+			continue
+		}
+		fmt.Fprintf(buf, "%s = ", tomlKey(f.key))
+		writeTOMLValue(buf, f.value)
+		buf.WriteByte('\n')
+	}
+
+	for _, f := range tables {
+		sub := appendPath(path, f.key)
+		fmt.Fprintf(buf, "\n[%s]\n", tomlPath(sub))
+		writeTOMLTable(buf, sub, f.value.([]tomlField))
+	}
+	for _, f := range arrays {
+		sub := appendPath(path, f.key)
+		for _, elem := range f.value.([]interface{}) {
+			fmt.Fprintf(buf, "\n[[%s]]\n", tomlPath(sub))
+			writeTOMLTable(buf, sub, elem.([]tomlField))
+		}
+	}
+}
+
+// isTableArray reports whether array is a non-empty array of objects, which
+// is written as an array of tables
+func isTableArray(array []interface{}) bool {
+	for _, elem := range array {
+		if _, ok := elem.([]tomlField); !ok {
+			return false
+		}
+	}
+	return len(array) > 0
+}
+
+// writeTOMLValue writes value inline
+func writeTOMLValue(buf *bytes.Buffer, value interface{}) {
+	switch value := value.(type) {
+	case json.Number:
+		buf.WriteString(value.String())
+	case bool:
+		fmt.Fprint(buf, value)
+	case string:
+		buf.WriteString(tomlString(value))
+	case []interface{}:
+		buf.WriteByte('[')
+		first := true
+		for _, elem := range value {
+			if elem == nil {
+				continue
+			}
+			if !first {
+				buf.WriteString(", ")
+			}
+			first = false
+			writeTOMLValue(buf, elem)
+		}
+		buf.WriteByte(']')
+	case []tomlField:
+		buf.WriteByte('{')
+		first := true
+		for _, f := range value {
+			if f.value == nil {
+				continue
+			}
+			if !first {
+				buf.WriteString(", ")
+			}
+			first = false
+			fmt.Fprintf(buf, "%s = ", tomlKey(f.key))
+			writeTOMLValue(buf, f.value)
+		}
+		buf.WriteByte('}')
+	}
+}
+
+// bareKey matches the keys TOML accepts unquoted
+var bareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// tomlKey returns key, quoted unless it is a bare key
+func tomlKey(key string) string {
+	if bareKey.MatchString(key) {
+		return key
+	}
+	return tomlString(key)
+}
+
+// tomlPath returns the dotted header of the table at path
+func tomlPath(path []string) string {
+	keys := make([]string, len(path))
+	for i, key := range path {
+		keys[i] = tomlKey(key)
+	}
+	return strings.Join(keys, ".")
+}
+
+// appendPath returns path extended by key, without sharing its array
+func appendPath(path []string, key string) []string {
+	return append(path[:len(path):len(path)], key)
+}
+
+// tomlString returns s as a TOML basic string
+func tomlString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
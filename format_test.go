@@ -0,0 +1,120 @@
+package readgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestEncode(t *testing.T) {
+	analyzedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	result := &ValidationResult{
+		Name:       "p",
+		StartTime:  analyzedAt.Format(time.RFC3339),
+		AnalyzedAt: analyzedAt,
+		Level:      ValidationLevelStrict,
+		Errors:     []string{"say \"hi\"\n"},
+		Warnings: []ValidationWarning{
+			{Type: "naming", Severity: SeverityError, Message: "bad name", Line: 3},
+			{Type: "todo", Severity: SeverityInfo, Message: "TODO"},
+		},
+		Stats:        ValidationStats{Suppressed: 3, SuppressedByRule: map[string]int{"naming": 1, "a.b": 2}},
+		CircularDeps: []Cycle{{Packages: []string{"a", "b"}}},
+	}
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := result.Encode(&buf, FormatJSON); err != nil {
+			t.Fatalf("Failed to encode: %v", err)
+		}
+		var decoded ValidationResult
+		if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("Failed to decode: %v", err)
+		}
+		if !reflect.DeepEqual(&decoded, result) {
+			t.Errorf("decoded = %+v, want %+v", decoded, *result)
+		}
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := result.Encode(&buf, FormatYAML); err != nil {
+			t.Fatalf("Failed to encode: %v", err)
+		}
+		if !strings.Contains(buf.String(), "level: strict\n") || !strings.Contains(buf.String(), "circular_deps:\n") {
+			t.Errorf("YAML does not use the JSON field names:\n%s", buf.String())
+		}
+		var decoded ValidationResult
+		if err := yaml.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("Failed to decode: %v", err)
+		}
+		if !reflect.DeepEqual(&decoded, result) {
+			t.Errorf("decoded = %+v, want %+v", decoded, *result)
+		}
+	})
+
+	t.Run("toml", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := result.Encode(&buf, FormatTOML); err != nil {
+			t.Fatalf("Failed to encode: %v", err)
+		}
+		for _, want := range []string{
+			"name = \"p\"\n",
+			"level = \"strict\"\n",
+			`errors = ["say \"hi\"\n"]` + "\n",
+			"\n[stats]\nsuppressed = 3\n",
+			"\n[stats.suppressed_by_rule]\n\"a.b\" = 2\nnaming = 1\n",
+			"\n[[warnings]]\ntype = \"naming\"\n",
+			"\n[[circular_deps]]\npackages = [\"a\", \"b\"]\n",
+		} {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("TOML lacks %q:\n%s", want, buf.String())
+			}
+		}
+		// Plain values of the root table come before any header
+		header := strings.Index(buf.String(), "\n[")
+		if valid := strings.Index(buf.String(), "valid = "); valid < 0 || valid > header {
+			t.Errorf("valid is not in the root table:\n%s", buf.String())
+		}
+	})
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, []string{"a"}, FormatTOML); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("Encode of a slice as TOML error = %v, want ErrInvalidInput", err)
+	}
+	if err := Encode(&buf, result, Format("xml")); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("Encode as xml error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Format
+		wantErr bool
+	}{
+		{name: "json", want: FormatJSON},
+		{name: "YAML", want: FormatYAML},
+		{name: "yml", want: FormatYAML},
+		{name: "toml", want: FormatTOML},
+		{name: "xml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFormat(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFormat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package readgo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GitReader implements SourceReader over the tree of a git commit, reading
+// blobs straight from the object database so that historical revisions can
+// be analyzed without checking them out.
+type GitReader struct {
+	virtualReader
+	repoPath string
+	commit   string
+}
+
+// NewGitReader creates a reader for the given ref (branch, tag or commit)
+// of the repository at repoPath. It requires the git binary in PATH.
+func NewGitReader(repoPath, ref string) (*GitReader, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+	ctx := context.Background()
+
+	out, err := runGit(ctx, repoPath, "rev-parse", "--verify", "--end-of-options", ref+"^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown revision %s: %v", ErrNotFound, ref, err)
+	}
+	commit := strings.TrimSpace(string(out))
+
+	out, err = runGit(ctx, repoPath, "show", "-s", "--format=%ct", commit)
+	if err != nil {
+		return nil, err
+	}
+	seconds, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parse commit time: %w", err)
+	}
+	commitTime := time.Unix(seconds, 0)
+
+	out, err = runGit(ctx, repoPath, "ls-tree", "-r", "-l", "-z", commit)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*virtualFile
+	for _, entry := range bytes.Split(out, []byte{0}) {
+		if len(entry) == 0 {
+			continue
+		}
+		// Format: <mode> SP <type> SP <object> SP+ <size> TAB <path>
+		meta, name, ok := strings.Cut(string(entry), "\t")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(meta)
+		if len(fields) != 4 || fields[1] != "blob" || fields[0] == "120000" {
+			continue
+		}
+		size, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		object := fields[2]
+		files = append(files, &virtualFile{
+			path:    name,
+			size:    size,
+			modTime: commitTime,
+			read: func() ([]byte, error) {
+				return runGit(context.Background(), repoPath, "cat-file", "blob", object)
+			},
+		})
+	}
+
+	return &GitReader{
+		virtualReader: newVirtualReader(files),
+		repoPath:      repoPath,
+		commit:        commit,
+	}, nil
+}
+
+// Commit returns the full hash of the commit being read
+func (r *GitReader) Commit() string {
+	return r.commit
+}
+
+// runGit runs a git command in dir and returns its standard output
+func runGit(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
@@ -0,0 +1,96 @@
+package readgo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// setupGitRepo creates a repository with two commits tagged v1 and v2
+func setupGitRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write file %s: %v", name, err)
+		}
+	}
+
+	git("init", "-q")
+	write("main.go", "package main\n\nfunc main() {}\n")
+	write("pkg/old.go", "package pkg\n")
+	git("add", "-A")
+	git("commit", "-q", "-m", "first")
+	git("tag", "v1")
+
+	write("main.go", "package main\n\nfunc main() { println() }\n")
+	git("rm", "-q", "pkg/old.go")
+	write("pkg/new.go", "package pkg\n")
+	git("add", "-A")
+	git("commit", "-q", "-m", "second")
+	git("tag", "v2")
+
+	return dir
+}
+
+func TestGitReader(t *testing.T) {
+	repo := setupGitRepo(t)
+	ctx := context.Background()
+
+	var reader SourceReader
+	reader, err := NewGitReader(repo, "v1")
+	if err != nil {
+		t.Fatalf("NewGitReader() error = %v", err)
+	}
+
+	content, err := reader.ReadSourceFile(ctx, "main.go", ReadOptions{})
+	if err != nil {
+		t.Fatalf("ReadSourceFile() error = %v", err)
+	}
+	if string(content) != "package main\n\nfunc main() {}\n" {
+		t.Errorf("ReadSourceFile() returned content from wrong revision: %q", content)
+	}
+
+	files, err := reader.GetPackageFiles(ctx, "pkg", TreeOptions{})
+	if err != nil {
+		t.Fatalf("GetPackageFiles() error = %v", err)
+	}
+	if len(files) != 1 || files[0].Name != "old.go" {
+		t.Errorf("GetPackageFiles() = %v, want [old.go]", files)
+	}
+
+	newer, err := NewGitReader(repo, "v2")
+	if err != nil {
+		t.Fatalf("NewGitReader() error = %v", err)
+	}
+	oldTree, _ := reader.GetFileTree(ctx, ".", TreeOptions{ComputeHashes: true})
+	newTree, _ := newer.GetFileTree(ctx, ".", TreeOptions{ComputeHashes: true})
+	diff := DiffTrees(oldTree, newTree)
+	if len(diff.Added) != 1 || len(diff.Removed) != 1 || len(diff.Modified) != 1 {
+		t.Errorf("DiffTrees() = %+v", diff)
+	}
+
+	if _, err := NewGitReader(repo, "no-such-ref"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("NewGitReader() error = %v, want %v", err, ErrNotFound)
+	}
+}
@@ -0,0 +1,77 @@
+package readgo
+
+import (
+	"path"
+	"strings"
+)
+
+// matchAnyPattern reports whether relPath matches any of the patterns.
+// relPath is slash-separated and relative to the root of the walk.
+func matchAnyPattern(patterns []string, relPath string) bool {
+	for _, pattern := range patterns {
+		if matchPattern(pattern, relPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPattern matches a tree pattern against a slash-separated relative path.
+// Patterns without a slash are matched against the base name only, as with
+// filepath.Match. Patterns containing a slash are matched against the whole
+// relative path, where a "**" segment matches zero or more directories
+// (e.g. "internal/**/mock_*.go").
+func matchPattern(pattern, relPath string) bool {
+	pattern = strings.ReplaceAll(pattern, "\\", "/")
+	if !strings.Contains(pattern, "/") {
+		matched, _ := path.Match(pattern, path.Base(relPath))
+		return matched
+	}
+
+	pattern = strings.TrimPrefix(pattern, "./")
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(relPath, "/"))
+}
+
+// matchSegments matches pattern segments against path segments
+func matchSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// Collapse consecutive "**" segments
+			for len(pattern) > 0 && pattern[0] == "**" {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 0 {
+				return true
+			}
+			for i := 0; i <= len(segments); i++ {
+				if matchSegments(pattern, segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(segments) == 0 {
+			return false
+		}
+		if matched, err := path.Match(pattern[0], segments[0]); err != nil || !matched {
+			return false
+		}
+		pattern = pattern[1:]
+		segments = segments[1:]
+	}
+	return len(segments) == 0
+}
+
+// matchAnyPathSuffix reports whether any trailing part of a slash-separated
+// path matches one of the patterns. It matches project-relative patterns
+// against absolute paths when the project root is not known.
+func matchAnyPathSuffix(patterns []string, slashPath string) bool {
+	segments := strings.Split(strings.TrimPrefix(slashPath, "/"), "/")
+	for i := range segments {
+		if matchAnyPattern(patterns, strings.Join(segments[i:], "/")) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+// Command golangci is a golangci-lint plugin that runs the validator's rules
+// alongside the other linters. Build it with the Go and golang.org/x/tools
+// versions of the golangci-lint binary that loads it:
+//
+//	go build -buildmode=plugin -o readgo.so github.com/iamlongalong/readgo/golangci
+//
+// and register it in .golangci.yml:
+//
+//	linters-settings:
+//	  custom:
+//	    readgo:
+//	      path: readgo.so
+//	      description: readgo validation rules
+//	      settings:
+//	        config: .readgo.yaml
+package main
+
+import (
+	"fmt"
+
+	"github.com/iamlongalong/readgo"
+	"golang.org/x/tools/go/analysis"
+)
+
+// New returns the analyzers of the plugin. The optional config setting names
+// the validator configuration the rules read their settings from.
+func New(conf any) ([]*analysis.Analyzer, error) {
+	var cfg *readgo.ValidatorConfig
+	if settings, ok := conf.(map[string]any); ok {
+		if path, ok := settings["config"].(string); ok && path != "" {
+			c, err := readgo.LoadValidatorConfig(path)
+			if err != nil {
+				return nil, fmt.Errorf("readgo plugin: %w", err)
+			}
+			cfg = c
+		}
+	}
+	return readgo.RuleAnalyzers(cfg), nil
+}
+
+// main is required of the main package; golangci-lint only calls New
+func main() {}
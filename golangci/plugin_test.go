@@ -0,0 +1,46 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	dir := t.TempDir()
+	config := filepath.Join(dir, ".readgo.yaml")
+	if err := os.WriteFile(config, []byte("enable: [naming]\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	invalid := filepath.Join(dir, "invalid.yaml")
+	if err := os.WriteFile(invalid, []byte("rules: [\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		conf       any
+		wantNaming bool
+		wantErr    bool
+	}{
+		{name: "no settings", conf: nil},
+		{name: "config", conf: map[string]any{"config": config}, wantNaming: true},
+		{name: "missing config", conf: map[string]any{"config": filepath.Join(dir, "missing.yaml")}},
+		{name: "invalid config", conf: map[string]any{"config": invalid}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analyzers, err := New(tt.conf)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			naming := false
+			for _, a := range analyzers {
+				naming = naming || a.Name == "naming"
+			}
+			if naming != tt.wantNaming {
+				t.Errorf("New() includes naming = %v, want %v", naming, tt.wantNaming)
+			}
+		})
+	}
+}
@@ -0,0 +1,311 @@
+package readgo
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/token"
+	"go/types"
+	"go/version"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// goVersionRule reports language features and standard library APIs that
+// are newer than the go directive of the module. API versions are read from
+// the api directory of the Go installation; without it only language
+// features are checked.
+type goVersionRule struct{}
+
+// Name returns the rule name
+func (goVersionRule) Name() string {
+	return "go_version"
+}
+
+// versionUse is a feature that requires a Go version
+type versionUse struct {
+	feature string
+	version string
+	pos     token.Pos
+}
+
+// Check reports the first use of every feature that needs a newer Go
+// version than the module declares
+func (goVersionRule) Check(ctx context.Context, pkg *packages.Package) []Finding {
+	if pkg.Module == nil || pkg.Module.GoVersion == "" || pkg.TypesInfo == nil {
+		return nil
+	}
+	declared := version.Lang("go" + pkg.Module.GoVersion)
+	if declared == "" {
+		return nil
+	}
+
+	first := map[string]versionUse{}
+	record := func(feature, v string, pos token.Pos) {
+		if version.Compare(v, declared) <= 0 {
+			return
+		}
+		if u, ok := first[feature]; !ok || pos < u.pos {
+			first[feature] = versionUse{feature: feature, version: v, pos: pos}
+		}
+	}
+
+	api := stdlibAPI()
+	for _, file := range pkg.Syntax {
+		if ctx.Err() != nil {
+			break
+		}
+		if v := version.Lang(file.GoVersion); v != "" && version.Compare(v, declared) > 0 {
+			// A //go:build go1.N constraint raises the version of the file
+			continue
+		}
+		for _, imp := range file.Imports {
+			path, err := strconv.Unquote(imp.Path.Value)
+			if err != nil {
+				continue
+			}
+			if v, ok := api[path][""]; ok {
+				record("package "+path, v, imp.Pos())
+			}
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			for _, u := range languageFeatures(pkg.TypesInfo, n) {
+				record(u.feature, u.version, u.pos)
+			}
+			if feature, v := apiVersion(api, pkg.TypesInfo, n); v != "" {
+				record(feature, v, n.Pos())
+			}
+			return true
+		})
+	}
+
+	uses := make([]versionUse, 0, len(first))
+	for _, u := range first {
+		uses = append(uses, u)
+	}
+	sort.Slice(uses, func(i, j int) bool { return uses[i].pos < uses[j].pos })
+
+	findings := make([]Finding, 0, len(uses))
+	for _, u := range uses {
+		pos := pkg.Fset.Position(u.pos)
+		findings = append(findings, Finding{
+			Message: fmt.Sprintf("%s requires %s but go.mod declares go %s",
+				u.feature, u.version, strings.TrimPrefix(declared, "go")),
+			File:   pos.Filename,
+			Line:   pos.Line,
+			Column: pos.Column,
+		})
+	}
+	return findings
+}
+
+// languageFeatures returns the version-dependent language features used
+// directly by node n
+func languageFeatures(info *types.Info, n ast.Node) []versionUse {
+	switch n := n.(type) {
+	case *ast.TypeSpec:
+		if n.TypeParams != nil {
+			return []versionUse{{"type parameters", "go1.18", n.TypeParams.Pos()}}
+		}
+	case *ast.FuncType:
+		if n.TypeParams != nil {
+			return []versionUse{{"type parameters", "go1.18", n.TypeParams.Pos()}}
+		}
+	case *ast.Ident:
+		// Identifiers predeclared after the language version of the package
+		// are left unresolved
+		obj := info.Uses[n]
+		if obj == nil && info.Defs[n] == nil {
+			obj = types.Universe.Lookup(n.Name)
+		}
+		if obj == nil || obj.Parent() != types.Universe {
+			return nil
+		}
+		switch obj.Name() {
+		case "any", "comparable":
+			return []versionUse{{"predeclared " + obj.Name(), "go1.18", n.Pos()}}
+		case "min", "max", "clear":
+			return []versionUse{{"builtin " + obj.Name(), "go1.21", n.Pos()}}
+		}
+	case *ast.BasicLit:
+		if n.Kind != token.INT && n.Kind != token.FLOAT && n.Kind != token.IMAG {
+			return nil
+		}
+		lit := strings.ToLower(n.Value)
+		if strings.Contains(lit, "_") || strings.HasPrefix(lit, "0b") || strings.HasPrefix(lit, "0o") {
+			return []versionUse{{"binary, octal 0o and underscored number literals", "go1.13", n.Pos()}}
+		}
+	case *ast.RangeStmt:
+		tv, ok := info.Types[n.X]
+		if !ok || tv.Type == nil {
+			return nil
+		}
+		switch t := tv.Type.Underlying().(type) {
+		case *types.Basic:
+			if t.Info()&types.IsInteger != 0 {
+				return []versionUse{{"range over int", "go1.22", n.For}}
+			}
+		case *types.Signature:
+			return []versionUse{{"range over function", "go1.23", n.For}}
+		}
+	}
+	return nil
+}
+
+// apiVersion returns the standard library symbol n refers to and the Go
+// version that added it, or "" if it is not a versioned standard library
+// symbol. Package-level symbols are found through identifiers, fields and
+// methods through selector expressions.
+func apiVersion(api map[string]map[string]string, info *types.Info, n ast.Node) (string, string) {
+	var obj types.Object
+	var name string
+	switch n := n.(type) {
+	case *ast.Ident:
+		obj = info.Uses[n]
+		if obj == nil || obj.Pkg() == nil || obj.Parent() != obj.Pkg().Scope() {
+			return "", ""
+		}
+		name = obj.Name()
+	case *ast.SelectorExpr:
+		sel, ok := info.Selections[n]
+		if !ok {
+			return "", ""
+		}
+		obj = sel.Obj()
+		recv := sel.Recv()
+		if fn, ok := obj.(*types.Func); ok {
+			// The declaring type, not the one the method was promoted to
+			recv = fn.Type().(*types.Signature).Recv().Type()
+		}
+		if p, ok := recv.(*types.Pointer); ok {
+			recv = p.Elem()
+		}
+		named, ok := recv.(*types.Named)
+		if !ok || obj.Pkg() == nil || named.Obj().Pkg() != obj.Pkg() {
+			return "", ""
+		}
+		name = named.Obj().Name() + "." + obj.Name()
+	default:
+		return "", ""
+	}
+
+	v, ok := api[obj.Pkg().Path()][name]
+	if !ok {
+		return "", ""
+	}
+	return obj.Pkg().Path() + "." + name, v
+}
+
+var (
+	apiOnce sync.Once
+	apiData map[string]map[string]string
+)
+
+// stdlibAPI returns the Go version that added each standard library symbol,
+// keyed by package path and then by symbol name, with methods and fields as
+// "Type.Name". The empty name holds the version that added the package.
+func stdlibAPI() map[string]map[string]string {
+	apiOnce.Do(func() {
+		files, _ := filepath.Glob(filepath.Join(build.Default.GOROOT, "api", "go1*.txt"))
+		apiData = make(map[string]map[string]string)
+		for _, file := range files {
+			v := strings.TrimSuffix(filepath.Base(file), ".txt")
+			if !version.IsValid(v) {
+				continue
+			}
+			readAPIFile(file, v, apiData)
+		}
+	})
+	return apiData
+}
+
+// readAPIFile adds the symbols listed in an api file of version v to api
+func readAPIFile(file, v string, api map[string]map[string]string) {
+	f, err := os.Open(file)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.Contains(line, "//deprecated") {
+			continue
+		}
+		pkgPath, name, ok := parseAPILine(line)
+		if !ok {
+			continue
+		}
+		symbols := api[pkgPath]
+		if symbols == nil {
+			symbols = make(map[string]string)
+			api[pkgPath] = symbols
+		}
+		for _, key := range []string{name, ""} {
+			if old, ok := symbols[key]; !ok || version.Compare(v, old) < 0 {
+				symbols[key] = v
+			}
+		}
+	}
+}
+
+// parseAPILine returns the package and symbol of an api file line such as
+// "pkg slices, func Contains[...](...) bool #57433"
+func parseAPILine(line string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(line, "pkg ")
+	if !ok {
+		return "", "", false
+	}
+	pkgPath, decl, ok := strings.Cut(rest, ", ")
+	if !ok {
+		return "", "", false
+	}
+	pkgPath, _, _ = strings.Cut(pkgPath, " ") // drop "(linux-amd64)"
+
+	kind, decl, _ := strings.Cut(decl, " ")
+	switch kind {
+	case "func", "const", "var":
+		return pkgPath, identPrefix(decl), true
+	case "method":
+		// method (*T[$0]) Name(...)
+		recv, name, ok := strings.Cut(strings.TrimPrefix(decl, "("), ") ")
+		if !ok {
+			return "", "", false
+		}
+		return pkgPath, identPrefix(strings.TrimPrefix(recv, "*")) + "." + identPrefix(name), true
+	case "type":
+		// type T struct, Field / type T interface, Method(...) / type T int /
+		// type T interface { M, N } / type T struct, embedded pkg.U
+		typeName := identPrefix(decl)
+		head, member, ok := strings.Cut(decl, ", ")
+		if !ok || strings.Contains(head, "{") {
+			return pkgPath, typeName, true
+		}
+		if embedded, ok := strings.CutPrefix(member, "embedded "); ok {
+			embedded = strings.TrimPrefix(embedded, "*")
+			member = embedded[strings.LastIndex(embedded, ".")+1:]
+		}
+		return pkgPath, typeName + "." + identPrefix(member), true
+	}
+	return "", "", false
+}
+
+// identPrefix returns the identifier s starts with
+func identPrefix(s string) string {
+	end := strings.IndexFunc(s, func(r rune) bool {
+		return !(r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r > 127)
+	})
+	if end < 0 {
+		return s
+	}
+	return s[:end]
+}
@@ -0,0 +1,240 @@
+package readgo
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ImportGraph is the import graph of the packages of a project, as returned
+// by DefaultValidator.ImportGraph
+type ImportGraph struct {
+	Nodes []GraphNode `json:"nodes" yaml:"nodes"` // Sorted by import path
+	Edges []GraphEdge `json:"links" yaml:"links"` // Sorted by source, then target
+}
+
+// GraphNode is a package of an import graph
+type GraphNode struct {
+	ID  string `json:"id" yaml:"id"`                       // Import path
+	Dir string `json:"dir,omitempty" yaml:"dir,omitempty"` // Directory relative to the validator's base directory
+}
+
+// GraphEdge is an import of one package by another
+type GraphEdge struct {
+	Source  string `json:"source" yaml:"source"`                         // Import path of the importing package
+	Target  string `json:"target" yaml:"target"`                         // Import path of the imported package
+	InCycle bool   `json:"in_cycle,omitempty" yaml:"in_cycle,omitempty"` // Set if the import is part of an import cycle
+}
+
+// GraphFormat identifies a serialization format of import graphs
+type GraphFormat string
+
+const (
+	// GraphFormatDOT is the Graphviz DOT language
+	GraphFormatDOT GraphFormat = "dot"
+	// GraphFormatGraphML is GraphML, read by Gephi, Cytoscape and yEd
+	GraphFormatGraphML GraphFormat = "graphml"
+	// GraphFormatJSON is node-link JSON as used by D3 and NetworkX
+	GraphFormatJSON GraphFormat = "json"
+)
+
+// ParseGraphFormat parses a graph format name
+func ParseGraphFormat(name string) (GraphFormat, error) {
+	switch strings.ToLower(name) {
+	case "dot", "gv":
+		return GraphFormatDOT, nil
+	case "graphml":
+		return GraphFormatGraphML, nil
+	case "json":
+		return GraphFormatJSON, nil
+	default:
+		return "", fmt.Errorf("%w: unknown graph format %q", ErrInvalidInput, name)
+	}
+}
+
+// ImportGraph returns the import graph of the packages of the project,
+// including the imports of their tests. Imports of packages outside the
+// project are left out. Imports that are part of a cycle are marked, as
+// reported by CheckCircularDependencies.
+func (v *DefaultValidator) ImportGraph(ctx context.Context) (*ImportGraph, error) {
+	base, err := filepath.Abs(v.baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("import graph error: %w", err)
+	}
+	graph, _, dirs, err := v.importGraph(ctx, base)
+	if err != nil {
+		return nil, fmt.Errorf("import graph error: %w", err)
+	}
+
+	component := make(map[string]int)
+	for i, scc := range graph.components() {
+		if len(scc) > 1 {
+			for _, p := range scc {
+				component[p] = i + 1
+			}
+		}
+	}
+
+	g := &ImportGraph{Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+	paths := make([]string, 0, len(graph))
+	for path := range graph {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		g.Nodes = append(g.Nodes, GraphNode{ID: path, Dir: filepath.ToSlash(v.relPath(dirs[path]))})
+		for _, target := range graph[path] {
+			g.Edges = append(g.Edges, GraphEdge{
+				Source:  path,
+				Target:  target,
+				InCycle: component[path] != 0 && component[path] == component[target],
+			})
+		}
+	}
+	return g, nil
+}
+
+// Encode writes the graph to w in the given format
+func (g *ImportGraph) Encode(w io.Writer, format GraphFormat) error {
+	b := bufio.NewWriter(w)
+	var err error
+	switch format {
+	case GraphFormatDOT:
+		g.writeDOT(b)
+	case GraphFormatGraphML:
+		err = g.writeGraphML(b)
+	case GraphFormatJSON:
+		err = g.writeNodeLink(b)
+	default:
+		return fmt.Errorf("%w: unknown graph format %q", ErrInvalidInput, format)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode %s graph: %w", format, err)
+	}
+	if err := b.Flush(); err != nil {
+		return fmt.Errorf("failed to write %s graph: %w", format, err)
+	}
+	return nil
+}
+
+// writeDOT writes the graph as a Graphviz digraph, drawing imports that are
+// part of a cycle in red
+func (g *ImportGraph) writeDOT(b *bufio.Writer) {
+	b.WriteString("digraph imports {\n\tnode [shape=box];\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(b, "\t%s;\n", strconv.Quote(n.ID))
+	}
+	for _, e := range g.Edges {
+		attrs := ""
+		if e.InCycle {
+			attrs = " [color=red]"
+		}
+		fmt.Fprintf(b, "\t%s -> %s%s;\n", strconv.Quote(e.Source), strconv.Quote(e.Target), attrs)
+	}
+	b.WriteString("}\n")
+}
+
+// graphML is the root element of a GraphML document
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+// graphMLKey declares an attribute of nodes or edges
+type graphMLKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+// graphMLGraph holds the nodes and edges of a GraphML document
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+// graphMLNode is a node with its attribute values
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+// graphMLEdge is an edge with its attribute values
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+// graphMLData is the value of an attribute
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// writeGraphML writes the graph as a GraphML document. Nodes carry their
+// import path as label, since Gephi and Cytoscape do not display ids.
+func (g *ImportGraph) writeGraphML(b *bufio.Writer) error {
+	doc := graphML{
+		Xmlns: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "label", For: "node", Name: "label", Type: "string"},
+			{ID: "dir", For: "node", Name: "dir", Type: "string"},
+			{ID: "in_cycle", For: "edge", Name: "in_cycle", Type: "boolean"},
+		},
+		Graph: graphMLGraph{ID: "imports", EdgeDefault: "directed"},
+	}
+	for _, n := range g.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID:   n.ID,
+			Data: []graphMLData{{Key: "label", Value: n.ID}, {Key: "dir", Value: n.Dir}},
+		})
+	}
+	for i, e := range g.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			ID:     fmt.Sprintf("e%d", i),
+			Source: e.Source,
+			Target: e.Target,
+			Data:   []graphMLData{{Key: "in_cycle", Value: strconv.FormatBool(e.InCycle)}},
+		})
+	}
+
+	b.WriteString(xml.Header)
+	enc := xml.NewEncoder(b)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	b.WriteString("\n")
+	return nil
+}
+
+// writeNodeLink writes the graph as node-link JSON, the layout of D3 force
+// graphs and of NetworkX's node_link_data
+func (g *ImportGraph) writeNodeLink(b *bufio.Writer) error {
+	enc := json.NewEncoder(b)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Directed   bool                   `json:"directed"`
+		Multigraph bool                   `json:"multigraph"`
+		Graph      map[string]interface{} `json:"graph"`
+		*ImportGraph
+	}{
+		Directed:    true,
+		Graph:       map[string]interface{}{"name": "imports"},
+		ImportGraph: g,
+	})
+}
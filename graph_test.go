@@ -0,0 +1,115 @@
+package readgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestImportGraph(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFiles := map[string]string{
+		"go.mod": "module example.com/g\n\ngo 1.20\n",
+		"a/a.go": "package a\n\nimport _ \"example.com/g/b\"\n",
+		"b/b.go": "package b\n\nimport (\n\t_ \"example.com/g/a\"\n\t_ \"fmt\"\n)\n",
+		"c/c.go": "package c\n\nimport _ \"example.com/g/a\"\n",
+		"d/d.go": "package d\n",
+	}
+	for name, content := range testFiles {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	graph, err := NewValidator(tmpDir).ImportGraph(context.Background())
+	if err != nil {
+		t.Fatalf("ImportGraph() error = %v", err)
+	}
+	want := &ImportGraph{
+		Nodes: []GraphNode{
+			{ID: "example.com/g/a", Dir: "a"},
+			{ID: "example.com/g/b", Dir: "b"},
+			{ID: "example.com/g/c", Dir: "c"},
+			{ID: "example.com/g/d", Dir: "d"},
+		},
+		Edges: []GraphEdge{
+			{Source: "example.com/g/a", Target: "example.com/g/b", InCycle: true},
+			{Source: "example.com/g/b", Target: "example.com/g/a", InCycle: true},
+			{Source: "example.com/g/c", Target: "example.com/g/a"},
+		},
+	}
+	if !reflect.DeepEqual(graph, want) {
+		t.Fatalf("ImportGraph() = %+v, want %+v", graph, want)
+	}
+
+	t.Run("dot", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := graph.Encode(&buf, GraphFormatDOT); err != nil {
+			t.Fatalf("Failed to encode graph: %v", err)
+		}
+		for _, line := range []string{
+			`digraph imports {`,
+			`	"example.com/g/d";`,
+			`	"example.com/g/a" -> "example.com/g/b" [color=red];`,
+			`	"example.com/g/c" -> "example.com/g/a";`,
+		} {
+			if !strings.Contains(buf.String(), line+"\n") {
+				t.Errorf("DOT lacks %q:\n%s", line, buf.String())
+			}
+		}
+	})
+
+	t.Run("graphml", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := graph.Encode(&buf, GraphFormatGraphML); err != nil {
+			t.Fatalf("Failed to encode graph: %v", err)
+		}
+		var doc graphML
+		if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+			t.Fatalf("Failed to decode GraphML: %v", err)
+		}
+		if doc.Graph.EdgeDefault != "directed" || len(doc.Graph.Nodes) != 4 || len(doc.Graph.Edges) != 3 {
+			t.Fatalf("GraphML graph = %+v", doc.Graph)
+		}
+		edge := doc.Graph.Edges[2]
+		if edge.Source != "example.com/g/c" || edge.Target != "example.com/g/a" || edge.Data[0].Value != "false" {
+			t.Errorf("GraphML edge = %+v", edge)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := graph.Encode(&buf, GraphFormatJSON); err != nil {
+			t.Fatalf("Failed to encode graph: %v", err)
+		}
+		var doc struct {
+			Directed bool        `json:"directed"`
+			Nodes    []GraphNode `json:"nodes"`
+			Links    []GraphEdge `json:"links"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+			t.Fatalf("Failed to decode JSON: %v", err)
+		}
+		if !doc.Directed || !reflect.DeepEqual(doc.Nodes, want.Nodes) || !reflect.DeepEqual(doc.Links, want.Edges) {
+			t.Errorf("node-link JSON = %s", buf.String())
+		}
+	})
+
+	if err := graph.Encode(&bytes.Buffer{}, GraphFormat("gexf")); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("Encode as gexf error = %v, want ErrInvalidInput", err)
+	}
+	if _, err := ParseGraphFormat("gexf"); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("ParseGraphFormat(gexf) error = %v, want ErrInvalidInput", err)
+	}
+}
@@ -0,0 +1,84 @@
+package readgo
+
+import (
+	"context"
+	"go/ast"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// headerRule reports Go files whose leading comment, such as a license
+// header, is missing or does not match ValidatorConfig.Header. It is
+// opt-in and skips generated files and files matching HeaderExclude.
+type headerRule struct {
+	pattern *regexp.Regexp
+	exclude []string
+}
+
+// Name returns the rule name
+func (r *headerRule) Name() string {
+	return "file_header"
+}
+
+// configure compiles the header pattern. An invalid pattern disables the
+// rule; LoadValidatorConfig rejects such configurations up front.
+func (r *headerRule) configure(cfg *ValidatorConfig) {
+	r.pattern, r.exclude = nil, nil
+	if cfg == nil || cfg.Header == "" {
+		return
+	}
+	r.pattern, _ = regexp.Compile(cfg.Header)
+	r.exclude = cfg.HeaderExclude
+}
+
+// Check reports the files with a missing or malformed header
+func (r *headerRule) Check(ctx context.Context, pkg *packages.Package) []Finding {
+	if r.pattern == nil {
+		return nil
+	}
+
+	var findings []Finding
+	for _, file := range pkg.Syntax {
+		if ctx.Err() != nil {
+			break
+		}
+		name := pkg.Fset.File(file.Pos()).Name()
+		if ast.IsGenerated(file) || matchAnyPathSuffix(r.exclude, filepath.ToSlash(name)) {
+			continue
+		}
+
+		header := fileHeader(file)
+		var message string
+		switch {
+		case header == "":
+			message = "file is missing the required header"
+		case !r.pattern.MatchString(header):
+			message = "file header does not match the required pattern"
+		default:
+			continue
+		}
+		findings = append(findings, Finding{
+			Message: message,
+			File:    name,
+			Line:    1,
+			Column:  1,
+		})
+	}
+	return findings
+}
+
+// fileHeader returns the source text of the first comment group of file
+// when it precedes the package clause
+func fileHeader(file *ast.File) string {
+	if len(file.Comments) == 0 || file.Comments[0].Pos() > file.Package {
+		return ""
+	}
+	var lines []string
+	for _, c := range file.Comments[0].List {
+		lines = append(lines, c.Text)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,143 @@
+package readgo
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// ImportPolicy restricts what the packages of a part of the project may
+// import
+type ImportPolicy struct {
+	// Packages lists the packages the policy applies to, as glob patterns
+	// matched against the trailing segments of their import paths, e.g.
+	// cmd/** or internal/*/handler
+	Packages []string `yaml:"packages" json:"packages"`
+
+	// Allow, when not empty, lists the only import paths the packages may
+	// use. Each entry matches the path and everything below it; "std"
+	// matches the whole standard library.
+	Allow []string `yaml:"allow" json:"allow,omitempty"`
+
+	// Deny lists import paths the packages may not use, matched like Allow.
+	// Deny takes precedence over Allow.
+	Deny []string `yaml:"deny" json:"deny,omitempty"`
+
+	// Message is added to every violation, e.g. to point at the package to
+	// use instead
+	Message string `yaml:"message" json:"message,omitempty"`
+}
+
+// validate checks that the policy selects packages and restricts something
+func (p ImportPolicy) validate() error {
+	if len(p.Packages) == 0 {
+		return fmt.Errorf("import policy without packages")
+	}
+	if len(p.Allow) == 0 && len(p.Deny) == 0 {
+		return fmt.Errorf("import policy for %s allows everything", strings.Join(p.Packages, ", "))
+	}
+	for _, pattern := range p.Packages {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid package pattern %q: %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+// violation returns why importing importPath breaks the policy, or "" if it
+// is permitted
+func (p ImportPolicy) violation(importPath string) string {
+	var reason string
+	switch {
+	case matchAnyImport(p.Deny, importPath):
+		reason = "is banned"
+	case len(p.Allow) > 0 && !matchAnyImport(p.Allow, importPath):
+		reason = "is not allowed"
+	default:
+		return ""
+	}
+	if p.Message != "" {
+		reason += ": " + p.Message
+	}
+	return reason
+}
+
+// matchAnyImport reports whether importPath equals or lies below any of the
+// prefixes. The prefix "std" matches standard library packages.
+func matchAnyImport(prefixes []string, importPath string) bool {
+	for _, prefix := range prefixes {
+		prefix = strings.TrimSuffix(prefix, "/")
+		switch {
+		case prefix == "std" && isStandardImport(importPath):
+			return true
+		case importPath == prefix || strings.HasPrefix(importPath, prefix+"/"):
+			return true
+		}
+	}
+	return false
+}
+
+// importPolicyRule reports imports that break the configured import
+// policies. It is opt-in through ValidatorConfig.ImportPolicies.
+type importPolicyRule struct {
+	policies []ImportPolicy
+}
+
+// Name returns the rule name
+func (r *importPolicyRule) Name() string {
+	return "import_policy"
+}
+
+// configure reads the import policies
+func (r *importPolicyRule) configure(cfg *ValidatorConfig) {
+	r.policies = nil
+	if cfg != nil {
+		r.policies = cfg.ImportPolicies
+	}
+}
+
+// Check reports every import of the package that a matching policy forbids
+func (r *importPolicyRule) Check(ctx context.Context, pkg *packages.Package) []Finding {
+	var policies []ImportPolicy
+	pkgPath := strings.TrimSuffix(pkg.PkgPath, "_test")
+	for _, p := range r.policies {
+		if matchAnyPathSuffix(p.Packages, pkgPath) {
+			policies = append(policies, p)
+		}
+	}
+	if len(policies) == 0 {
+		return nil
+	}
+
+	var findings []Finding
+	for _, file := range pkg.Syntax {
+		if ctx.Err() != nil {
+			break
+		}
+		for _, imp := range file.Imports {
+			importPath, err := strconv.Unquote(imp.Path.Value)
+			if err != nil {
+				continue
+			}
+			for _, p := range policies {
+				reason := p.violation(importPath)
+				if reason == "" {
+					continue
+				}
+				pos := pkg.Fset.Position(imp.Pos())
+				findings = append(findings, Finding{
+					Message: fmt.Sprintf("import of %s in %s %s", importPath, pkg.PkgPath, reason),
+					File:    pos.Filename,
+					Line:    pos.Line,
+					Column:  pos.Column,
+				})
+				break
+			}
+		}
+	}
+	return findings
+}
@@ -1,17 +1,21 @@
 package readgo
 
-import "context"
+import (
+	"context"
+
+	"golang.org/x/tools/go/packages"
+)
 
 // Validator defines the interface for validating Go code
 type Validator interface {
-	// ValidateFile validates a specific Go source file
-	ValidateFile(ctx context.Context, filePath string) (*ValidationResult, error)
+	// ValidateFile validates a specific Go source file at the given level
+	ValidateFile(ctx context.Context, filePath string, level ValidationLevel) (*ValidationResult, error)
 
-	// ValidatePackage validates a Go package
-	ValidatePackage(ctx context.Context, pkgPath string) (*ValidationResult, error)
+	// ValidatePackage validates a Go package at the given level
+	ValidatePackage(ctx context.Context, pkgPath string, level ValidationLevel) (*ValidationResult, error)
 
-	// ValidateProject validates the entire project
-	ValidateProject(ctx context.Context) (*ValidationResult, error)
+	// ValidateProject validates the entire project at the given level
+	ValidateProject(ctx context.Context, level ValidationLevel) (*ValidationResult, error)
 }
 
 // SourceReader defines the interface for reading Go source code
@@ -49,3 +53,25 @@ type CodeAnalyzer interface {
 	// AnalyzeProject analyzes a Go project at the specified path
 	AnalyzeProject(ctx context.Context, projectPath string) (*AnalysisResult, error)
 }
+
+// Extractor defines the interface for custom entity extractors. Extractors
+// run over every package loaded by AnalyzePackage and AnalyzeProject and
+// their results are stored in AnalysisResult.Extensions under Name().
+type Extractor interface {
+	// Name returns the key used for the extracted entities
+	Name() string
+
+	// Extract returns the domain-specific entities found in the package
+	Extract(ctx context.Context, pkg *packages.Package) ([]Entity, error)
+}
+
+// Rule defines the interface for custom validation checks. Rules run over
+// every package loaded during validation, in the same pass as the built-in
+// checks, and their findings are reported as warnings.
+type Rule interface {
+	// Name returns the rule name, used as the warning type
+	Name() string
+
+	// Check returns the issues found in the package
+	Check(ctx context.Context, pkg *packages.Package) []Finding
+}
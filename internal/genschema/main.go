@@ -0,0 +1,39 @@
+// Command genschema writes the JSON Schemas of readgo's output types into
+// a directory. It is run by go generate.
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/iamlongalong/readgo"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: genschema <dir>")
+		os.Exit(2)
+	}
+	if err := generate(os.Args[1]); err != nil {
+		fmt.Fprintf(os.Stderr, "genschema: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// generate writes every schema into dir
+func generate(dir string) error {
+	schemas, err := readgo.Schemas()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for name, schema := range schemas {
+		if err := os.WriteFile(filepath.Join(dir, name), schema, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package readgo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/format"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ReadLines reads a file through r and returns its lines together with
+// their original line numbers. With opts.StripSpaces, leading and trailing
+// blank lines are dropped but the remaining lines keep their numbers.
+func ReadLines(ctx context.Context, r SourceReader, path string, opts ReadOptions) ([]SourceLine, error) {
+	readOpts := opts
+	readOpts.WithLineNumbers = false
+	readOpts.StripSpaces = false
+	content, err := r.ReadSourceFile(ctx, path, readOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := splitLines(content)
+	if opts.StripSpaces {
+		lines = trimBlankLines(lines)
+	}
+	return lines, nil
+}
+
+// applyReadOptions applies the content transformations requested by opts
+// to the content of the file at path
+func applyReadOptions(path string, content []byte, opts ReadOptions) ([]byte, error) {
+	if opts.Format && filepath.Ext(path) == ".go" {
+		formatted, err := format.Source(content)
+		if err != nil {
+			return nil, &AnalysisError{Op: "format file", Path: path, Wrapped: err}
+		}
+		content = formatted
+	}
+
+	if opts.WithLineNumbers {
+		lines := splitLines(content)
+		if opts.StripSpaces {
+			lines = trimBlankLines(lines)
+		}
+		return formatNumberedLines(lines), nil
+	}
+
+	if opts.StripSpaces {
+		content = bytes.TrimSpace(content)
+	}
+	return content, nil
+}
+
+// splitLines splits content into numbered lines. A trailing newline does
+// not produce an extra empty line.
+func splitLines(content []byte) []SourceLine {
+	text := strings.TrimSuffix(string(content), "\n")
+	if text == "" {
+		return nil
+	}
+
+	parts := strings.Split(text, "\n")
+	lines := make([]SourceLine, len(parts))
+	for i, part := range parts {
+		lines[i] = SourceLine{Number: i + 1, Text: strings.TrimSuffix(part, "\r")}
+	}
+	return lines
+}
+
+// trimBlankLines drops blank lines at the start and end
+func trimBlankLines(lines []SourceLine) []SourceLine {
+	for len(lines) > 0 && strings.TrimSpace(lines[0].Text) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1].Text) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// formatNumberedLines renders lines as "<number>\t<text>", right-aligning
+// the numbers so the prefix width is the same on every line
+func formatNumberedLines(lines []SourceLine) []byte {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	width := len(strconv.Itoa(lines[len(lines)-1].Number))
+	var buf bytes.Buffer
+	for _, line := range lines {
+		fmt.Fprintf(&buf, "%*d\t%s\n", width, line.Number, line.Text)
+	}
+	return buf.Bytes()
+}
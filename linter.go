@@ -0,0 +1,217 @@
+package readgo
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ExternalLinter describes an external linter binary whose findings are
+// merged into validation results
+type ExternalLinter struct {
+	Name    string                                 // Check name used for findings without one
+	Command string                                 // Linter binary
+	Args    []string                               // Arguments selecting JSON output
+	Env     []string                               // Extra environment variables
+	Parse   func(output []byte) ([]Finding, error) // Output parser
+}
+
+// GolangciLint returns an ExternalLinter running golangci-lint on the whole
+// project. Version 2 replaced --out-format json with --output.json.path, so
+// the installed golangci-lint is asked for its version; v2 is assumed when
+// it cannot be run.
+func GolangciLint() *ExternalLinter {
+	return &ExternalLinter{
+		Name:    "golangci-lint",
+		Command: "golangci-lint",
+		Args:    golangciLintArgs(golangciLintVersion("golangci-lint")),
+		Parse:   ParseGolangciLintJSON,
+	}
+}
+
+// golangciVersionPattern matches the version printed by golangci-lint
+// --version, e.g. "golangci-lint has version v2.1.6 built with go1.24.2"
+var golangciVersionPattern = regexp.MustCompile(`version v?(\d+)\.`)
+
+// golangciLintVersion returns the major version of the golangci-lint binary
+// command, or 0 when it cannot be run or its version is not recognized
+func golangciLintVersion(command string) int {
+	out, err := exec.Command(command, "--version").Output()
+	if err != nil {
+		return 0
+	}
+	return parseGolangciLintVersion(string(out))
+}
+
+// parseGolangciLintVersion returns the major version in the output of
+// golangci-lint --version, or 0 if there is none
+func parseGolangciLintVersion(output string) int {
+	m := golangciVersionPattern.FindStringSubmatch(output)
+	if m == nil {
+		return 0
+	}
+	major, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0
+	}
+	return major
+}
+
+// golangciLintArgs returns the arguments running golangci-lint of the given
+// major version with JSON output on stdout
+func golangciLintArgs(major int) []string {
+	if major == 1 {
+		return []string{"run", "--out-format", "json", "./..."}
+	}
+	return []string{"run", "--output.json.path", "stdout", "--show-stats=false", "./..."}
+}
+
+// Staticcheck returns an ExternalLinter running staticcheck on the whole
+// project
+func Staticcheck() *ExternalLinter {
+	return &ExternalLinter{
+		Name:    "staticcheck",
+		Command: "staticcheck",
+		Args:    []string{"-f", "json", "./..."},
+		Parse:   ParseStaticcheckJSON,
+	}
+}
+
+// RunLinter runs an external linter in the validator's base directory and
+// merges its findings into result, applying the validator configuration and
+// the result's validation level
+func (v *DefaultValidator) RunLinter(ctx context.Context, l *ExternalLinter, result *ValidationResult) error {
+	if l == nil || l.Command == "" || l.Parse == nil || result == nil {
+		return fmt.Errorf("%w: incomplete linter", ErrInvalidInput)
+	}
+
+	cmd := exec.CommandContext(ctx, l.Command, l.Args...)
+	cmd.Dir = v.baseDir
+	cmd.Env = append(os.Environ(), l.Env...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+
+	// Linters exit with a non-zero status when they report issues
+	var exitErr *exec.ExitError
+	if err != nil && (!errors.As(err, &exitErr) || len(bytes.TrimSpace(out)) == 0) {
+		return &ValidationError{
+			Message: fmt.Sprintf("run %s: %s", l.Command, strings.TrimSpace(stderr.String())),
+			Wrapped: err,
+		}
+	}
+
+	findings, err := l.Parse(out)
+	if err != nil {
+		return &ValidationError{
+			Message: fmt.Sprintf("parse %s output", l.Command),
+			Wrapped: err,
+		}
+	}
+
+	for _, f := range findings {
+		if f.Rule == "" {
+			f.Rule = l.Name
+		}
+		if !v.config.ruleEnabled(l.Name) || !v.config.ruleEnabled(f.Rule) {
+			continue
+		}
+		v.addFinding(result, f)
+	}
+	finishResult(result, result.Level)
+	return nil
+}
+
+// ParseGolangciLintJSON parses the JSON report of golangci-lint, written by
+// --output.json.path stdout in v2 and --out-format json in v1. Anything
+// after the report, such as the summary of older v2 releases, is ignored.
+func ParseGolangciLintJSON(output []byte) ([]Finding, error) {
+	var report struct {
+		Issues []struct {
+			FromLinter string `json:"FromLinter"`
+			Text       string `json:"Text"`
+			Severity   string `json:"Severity"`
+			Pos        struct {
+				Filename string `json:"Filename"`
+				Line     int    `json:"Line"`
+				Column   int    `json:"Column"`
+			} `json:"Pos"`
+		} `json:"Issues"`
+	}
+	if err := json.NewDecoder(bytes.NewReader(output)).Decode(&report); err != nil {
+		return nil, err
+	}
+
+	findings := make([]Finding, 0, len(report.Issues))
+	for _, issue := range report.Issues {
+		findings = append(findings, Finding{
+			Rule:     issue.FromLinter,
+			Severity: linterSeverity(issue.Severity),
+			Message:  issue.Text,
+			File:     issue.Pos.Filename,
+			Line:     issue.Pos.Line,
+			Column:   issue.Pos.Column,
+		})
+	}
+	return findings, nil
+}
+
+// ParseStaticcheckJSON parses the output of staticcheck -f json, which is
+// one JSON object per line
+func ParseStaticcheckJSON(output []byte) ([]Finding, error) {
+	var findings []Finding
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxFileSize)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var issue struct {
+			Code     string `json:"code"`
+			Severity string `json:"severity"`
+			Message  string `json:"message"`
+			Location struct {
+				File   string `json:"file"`
+				Line   int    `json:"line"`
+				Column int    `json:"column"`
+			} `json:"location"`
+		}
+		if err := json.Unmarshal(line, &issue); err != nil {
+			return nil, err
+		}
+		findings = append(findings, Finding{
+			Rule:     issue.Code,
+			Severity: linterSeverity(issue.Severity),
+			Message:  issue.Message,
+			File:     issue.Location.File,
+			Line:     issue.Location.Line,
+			Column:   issue.Location.Column,
+		})
+	}
+	return findings, scanner.Err()
+}
+
+// linterSeverity maps a linter's severity name onto Severity, leaving
+// unknown names to the default
+func linterSeverity(name string) Severity {
+	switch strings.ToLower(name) {
+	case "error":
+		return SeverityError
+	case "warning":
+		return SeverityWarning
+	case "info", "ignored":
+		return SeverityInfo
+	default:
+		return ""
+	}
+}
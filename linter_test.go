@@ -0,0 +1,128 @@
+package readgo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+const golangciOutput = `{"Issues":[
+	{"FromLinter":"errcheck","Text":"Error return value is not checked","Severity":"","Pos":{"Filename":"main.go","Line":7,"Column":2}},
+	{"FromLinter":"gosimple","Text":"should use strings.Contains","Severity":"error","Pos":{"Filename":"util/util.go","Line":3,"Column":9}}
+]}`
+
+// TestLinterHelperProcess is not a real test; it stands in for an external
+// linter binary in TestRunLinter
+func TestLinterHelperProcess(t *testing.T) {
+	if os.Getenv("READGO_LINTER_HELPER") != "1" {
+		return
+	}
+	fmt.Print(golangciOutput)
+	os.Exit(1)
+}
+
+func TestGolangciLintArgs(t *testing.T) {
+	tests := []struct {
+		output string
+		want   []string
+	}{
+		{"golangci-lint has version 1.64.8 built with go1.24.1 from 8b37f141 on 2025-03-17T20:41:53Z\n", []string{"run", "--out-format", "json", "./..."}},
+		{"golangci-lint has version 2.1.6 built with go1.24.2 from eabc2638 on 2025-05-04T15:41:19Z\n", []string{"run", "--output.json.path", "stdout", "--show-stats=false", "./..."}},
+		{"golangci-lint has version v2.0.0 built from (unknown, modified: ?, mod sum: \"h1:\")\n", []string{"run", "--output.json.path", "stdout", "--show-stats=false", "./..."}},
+		{"", []string{"run", "--output.json.path", "stdout", "--show-stats=false", "./..."}},
+	}
+	for _, tt := range tests {
+		if got := golangciLintArgs(parseGolangciLintVersion(tt.output)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("golangciLintArgs() for %q = %q, want %q", tt.output, got, tt.want)
+		}
+	}
+
+	findings, err := ParseGolangciLintJSON([]byte(golangciOutput + "\n2 issues:\n* errcheck: 1\n"))
+	if err != nil || len(findings) != 2 {
+		t.Errorf("ParseGolangciLintJSON() with a trailing summary = %v, %v, want 2 findings", findings, err)
+	}
+}
+
+func TestParseStaticcheckJSON(t *testing.T) {
+	output := []byte(`{"code":"SA4006","severity":"warning","location":{"file":"/p/a.go","line":4,"column":2},"message":"value never used"}
+{"code":"ST1003","severity":"ignored","location":{"file":"/p/b.go","line":1,"column":9},"message":"bad name"}
+`)
+	findings, err := ParseStaticcheckJSON(output)
+	if err != nil {
+		t.Fatalf("ParseStaticcheckJSON() error = %v", err)
+	}
+	want := []Finding{
+		{Rule: "SA4006", Severity: SeverityWarning, Message: "value never used", File: "/p/a.go", Line: 4, Column: 2},
+		{Rule: "ST1003", Severity: SeverityInfo, Message: "bad name", File: "/p/b.go", Line: 1, Column: 9},
+	}
+	if !reflect.DeepEqual(findings, want) {
+		t.Errorf("ParseStaticcheckJSON() = %+v, want %+v", findings, want)
+	}
+
+	if _, err := ParseStaticcheckJSON([]byte("not json\n")); err == nil {
+		t.Error("ParseStaticcheckJSON() expected error for invalid output")
+	}
+}
+
+func TestRunLinter(t *testing.T) {
+	linter := &ExternalLinter{
+		Name:    "golangci-lint",
+		Command: os.Args[0],
+		Args:    []string{"-test.run=TestLinterHelperProcess"},
+		Env:     []string{"READGO_LINTER_HELPER=1"},
+		Parse:   ParseGolangciLintJSON,
+	}
+
+	tests := []struct {
+		name         string
+		config       *ValidatorConfig
+		wantErrors   int
+		wantWarnings []string
+	}{
+		{
+			name:         "all findings",
+			wantErrors:   1,
+			wantWarnings: []string{"errcheck"},
+		},
+		{
+			name:         "disabled linter",
+			config:       &ValidatorConfig{Rules: map[string]bool{"gosimple": false}},
+			wantWarnings: []string{"errcheck"},
+		},
+		{
+			name:       "promoted severity",
+			config:     &ValidatorConfig{Severities: map[string]Severity{"errcheck": SeverityError}},
+			wantErrors: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &ValidationResult{Level: ValidationLevelStandard}
+			err := NewValidator(t.TempDir()).WithConfig(tt.config).RunLinter(context.Background(), linter, result)
+			if err != nil {
+				t.Fatalf("RunLinter() error = %v", err)
+			}
+			if len(result.Errors) != tt.wantErrors {
+				t.Errorf("RunLinter() errors = %v, want %d", result.Errors, tt.wantErrors)
+			}
+			var types []string
+			for _, w := range result.Warnings {
+				types = append(types, w.Type)
+			}
+			if !reflect.DeepEqual(types, tt.wantWarnings) {
+				t.Errorf("RunLinter() warnings = %v, want %v", types, tt.wantWarnings)
+			}
+			if result.Valid != (tt.wantErrors == 0) {
+				t.Errorf("RunLinter() valid = %v, want %v", result.Valid, tt.wantErrors == 0)
+			}
+		})
+	}
+
+	missing := &ExternalLinter{Name: "missing", Command: "readgo-no-such-linter", Parse: ParseGolangciLintJSON}
+	if err := NewValidator(t.TempDir()).RunLinter(context.Background(), missing, &ValidationResult{}); err == nil {
+		t.Error("RunLinter() expected error for missing binary")
+	}
+}
@@ -0,0 +1,29 @@
+package readgo
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LevelTrace is the level of the most detailed log messages, such as cache
+// hits and single file reads, below slog.LevelDebug
+const LevelTrace = slog.LevelDebug - 4
+
+// discardHandler drops every record
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+// discardLogger is the logger of analyzers, readers and validators given none
+var discardLogger = slog.New(discardHandler{})
+
+// loggerOrDiscard returns l, or a logger that drops everything if l is nil
+func loggerOrDiscard(l *slog.Logger) *slog.Logger {
+	if l == nil {
+		return discardLogger
+	}
+	return l
+}
@@ -0,0 +1,53 @@
+package readgo
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithLogger(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTestFiles(t, tmpDir)
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: LevelTrace}))
+	analyzer := NewAnalyzer(WithWorkDir(tmpDir), WithCacheTTL(time.Minute), WithLogger(logger))
+	for i := 0; i < 2; i++ {
+		if _, err := analyzer.AnalyzePackage(ctx, "./testdata/basic"); err != nil {
+			t.Fatalf("Failed to analyze package: %v", err)
+		}
+	}
+	if _, err := analyzer.AnalyzeFile(ctx, "testdata/basic/main.go"); err != nil {
+		t.Fatalf("Failed to analyze file: %v", err)
+	}
+	validator := NewValidator(tmpDir, WithLogger(logger))
+	if _, err := validator.ValidatePackage(ctx, "testdata/basic", ValidationLevelBasic); err != nil {
+		t.Fatalf("Failed to validate package: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		`level=DEBUG msg="loading packages"`,
+		`msg="loaded packages"`,
+		`level=DEBUG-4 msg="analysis cache hit" kind=package path=./testdata/basic`,
+		`level=DEBUG-4 msg="read file"`,
+		`level=DEBUG-4 msg="checked package"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log does not contain %q:\n%s", want, out)
+		}
+	}
+	if n := strings.Count(out, `msg="loaded packages"`); n != 2 {
+		t.Errorf("log has %d package loads, want one by the analyzer and one by the validator", n)
+	}
+
+	// Without a logger nothing is logged, and nothing breaks
+	if _, err := NewAnalyzer(WithWorkDir(tmpDir)).AnalyzePackage(ctx, "./testdata/basic"); err != nil {
+		t.Fatalf("Failed to analyze package: %v", err)
+	}
+}
@@ -0,0 +1,637 @@
+// Package lsp serves a read-only subset of the Language Server Protocol
+// backed by readgo, so lightweight editors can navigate Go code without
+// gopls. It answers textDocument/documentSymbol, textDocument/definition and
+// textDocument/references from the analyzer, and publishes the validator's
+// findings as diagnostics when a document is opened or saved.
+//
+//	srv := lsp.NewServer()
+//	err := srv.Serve(ctx, os.Stdin, os.Stdout)
+//
+// Navigation works on the files as saved; diagnostics use the editor's
+// buffer. The workspace is the first workspace folder, or the root URI, the
+// client sends in its initialize request.
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/iamlongalong/readgo"
+)
+
+// Server is a language server for one workspace. A Server serves a single
+// connection.
+type Server struct {
+	opts []readgo.Option
+
+	writeMu sync.Mutex
+	out     io.Writer
+
+	mu        sync.Mutex
+	root      string
+	level     readgo.ValidationLevel
+	analyzer  *readgo.DefaultAnalyzer
+	validator *readgo.DefaultValidator
+	docs      map[string]*document          // Open documents by path
+	pending   map[string]context.CancelFunc // Running requests by ID
+	shutdown  bool
+	wg        sync.WaitGroup
+}
+
+// document is a document open in the editor
+type document struct {
+	text    string
+	version int // Bumped on every change, to drop stale diagnostics
+}
+
+// NewServer creates a language server. The options configure the analyzer
+// and validator as for readgo.NewAnalyzer; the working directory is set to
+// the workspace on initialization.
+func NewServer(opts ...readgo.Option) *Server {
+	return &Server{
+		opts:    opts,
+		level:   readgo.ValidationLevelStandard,
+		docs:    make(map[string]*document),
+		pending: make(map[string]context.CancelFunc),
+	}
+}
+
+// Serve reads messages from in and writes replies to out until the client
+// sends exit or closes in. Requests run concurrently and are cancelled when
+// ctx is done or the client cancels them.
+func (s *Server) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	s.out = out
+	ctx, cancel := context.WithCancel(ctx)
+	defer func() {
+		cancel()
+		s.wg.Wait()
+	}()
+
+	r := textproto.NewReader(bufio.NewReader(in))
+	for {
+		body, err := readMessage(r)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var msg message
+		if err := json.Unmarshal(body, &msg); err != nil {
+			s.reply(nil, nil, &responseError{Code: codeParseError, Message: err.Error()})
+			continue
+		}
+		if msg.Method == "exit" {
+			return nil
+		}
+		s.handle(ctx, &msg)
+	}
+}
+
+// readMessage reads the body of the next message, framed by a
+// Content-Length header
+func readMessage(r *textproto.Reader) ([]byte, error) {
+	header, err := r.ReadMIMEHeader()
+	if err != nil {
+		if errors.Is(err, io.EOF) && len(header) == 0 {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	n, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("invalid Content-Length %q", header.Get("Content-Length"))
+	}
+	body := make([]byte, n)
+	if _, err := io.ReadFull(r.R, body); err != nil {
+		return nil, fmt.Errorf("failed to read message: %w", err)
+	}
+	return body, nil
+}
+
+// handle dispatches a message. Notifications and lifecycle requests are
+// handled in order; the other requests run in their own goroutine.
+func (s *Server) handle(ctx context.Context, msg *message) {
+	if msg.ID == nil {
+		s.notify(ctx, msg)
+		return
+	}
+	if msg.Method == "" {
+		return // A response; the server sends no requests
+	}
+
+	s.mu.Lock()
+	initialized, shutdown := s.analyzer != nil, s.shutdown
+	s.mu.Unlock()
+	switch {
+	case msg.Method == "initialize":
+		result, err := s.initialize(msg.Params)
+		s.reply(msg.ID, result, err)
+		return
+	case !initialized:
+		s.reply(msg.ID, nil, &responseError{Code: codeServerNotInitialized, Message: "server not initialized"})
+		return
+	case msg.Method == "shutdown":
+		s.mu.Lock()
+		s.shutdown = true
+		s.mu.Unlock()
+		s.reply(msg.ID, nil, nil)
+		return
+	case shutdown:
+		s.reply(msg.ID, nil, &responseError{Code: codeInvalidRequest, Message: "server is shutting down"})
+		return
+	}
+
+	var run func(context.Context, json.RawMessage) (any, error)
+	switch msg.Method {
+	case "textDocument/documentSymbol":
+		run = s.documentSymbol
+	case "textDocument/definition":
+		run = s.definition
+	case "textDocument/references":
+		run = s.references
+	default:
+		s.reply(msg.ID, nil, &responseError{Code: codeMethodNotFound, Message: "method not supported: " + msg.Method})
+		return
+	}
+
+	id := string(*msg.ID)
+	ctx, cancel := context.WithCancel(ctx)
+	s.mu.Lock()
+	s.pending[id] = cancel
+	s.mu.Unlock()
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		result, err := run(ctx, msg.Params)
+		s.mu.Lock()
+		delete(s.pending, id)
+		s.mu.Unlock()
+		cancel()
+		s.reply(msg.ID, result, err)
+	}()
+}
+
+// notify handles a notification; unknown ones are ignored
+func (s *Server) notify(ctx context.Context, msg *message) {
+	switch msg.Method {
+	case "$/cancelRequest":
+		var p cancelParams
+		if json.Unmarshal(msg.Params, &p) == nil {
+			s.mu.Lock()
+			if cancel, ok := s.pending[string(p.ID)]; ok {
+				cancel()
+			}
+			s.mu.Unlock()
+		}
+	case "textDocument/didOpen":
+		var p didOpenParams
+		if json.Unmarshal(msg.Params, &p) == nil {
+			s.update(ctx, p.TextDocument.URI, &p.TextDocument.Text, true)
+		}
+	case "textDocument/didChange":
+		var p didChangeParams
+		if json.Unmarshal(msg.Params, &p) == nil && len(p.ContentChanges) > 0 {
+			s.update(ctx, p.TextDocument.URI, &p.ContentChanges[len(p.ContentChanges)-1].Text, false)
+		}
+	case "textDocument/didSave":
+		var p didSaveParams
+		if json.Unmarshal(msg.Params, &p) == nil {
+			s.update(ctx, p.TextDocument.URI, p.Text, true)
+		}
+	case "textDocument/didClose":
+		var p didCloseParams
+		if json.Unmarshal(msg.Params, &p) == nil {
+			if path, err := uriToPath(p.TextDocument.URI); err == nil {
+				s.mu.Lock()
+				delete(s.docs, path)
+				s.mu.Unlock()
+			}
+			s.send("textDocument/publishDiagnostics", publishDiagnosticsParams{URI: p.TextDocument.URI, Diagnostics: []Diagnostic{}})
+		}
+	}
+}
+
+// initialize sets up the analyzer and validator for the client's workspace
+func (s *Server) initialize(params json.RawMessage) (any, error) {
+	var p initializeParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &responseError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	root := p.RootPath
+	if uri := p.RootURI; len(p.WorkspaceFolders) > 0 || uri != "" {
+		if len(p.WorkspaceFolders) > 0 {
+			uri = p.WorkspaceFolders[0].URI
+		}
+		path, err := uriToPath(uri)
+		if err != nil {
+			return nil, &responseError{Code: codeInvalidParams, Message: err.Error()}
+		}
+		root = path
+	}
+	if root == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		root = wd
+	}
+	level := readgo.ValidationLevelStandard
+	if o := p.InitializationOptions; o != nil && o.ValidationLevel != "" {
+		l, err := readgo.ParseValidationLevel(o.ValidationLevel)
+		if err != nil {
+			return nil, &responseError{Code: codeInvalidParams, Message: err.Error()}
+		}
+		level = l
+	}
+
+	opts := append(append([]readgo.Option{}, s.opts...), readgo.WithWorkDir(root))
+	s.mu.Lock()
+	s.root = root
+	s.level = level
+	s.analyzer = readgo.NewAnalyzer(opts...)
+	s.validator = readgo.NewValidator(root, s.opts...)
+	s.mu.Unlock()
+
+	return initializeResult{
+		Capabilities: serverCapabilities{
+			TextDocumentSync:       textDocumentSyncOptions{OpenClose: true, Change: 1, Save: saveOptions{IncludeText: true}},
+			DocumentSymbolProvider: true,
+			DefinitionProvider:     true,
+			ReferencesProvider:     true,
+		},
+		ServerInfo: serverInfo{Name: "readgo", Version: readgo.Version()},
+	}, nil
+}
+
+// documentSymbol returns the outline of a document
+func (s *Server) documentSymbol(ctx context.Context, params json.RawMessage) (any, error) {
+	var p documentSymbolParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &responseError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	path, err := uriToPath(p.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	symbols, err := s.analyzer.FileSymbols(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	lines := s.lines(path)
+	var convert func([]readgo.Symbol) []DocumentSymbol
+	convert = func(symbols []readgo.Symbol) []DocumentSymbol {
+		out := make([]DocumentSymbol, 0, len(symbols))
+		for _, sym := range symbols {
+			out = append(out, DocumentSymbol{
+				Name:           sym.Name,
+				Detail:         sym.Detail,
+				Kind:           symbolKind(sym.Kind),
+				Range:          toRange(lines, sym.Location),
+				SelectionRange: toRange(lines, sym.Selection),
+				Children:       convert(sym.Children),
+			})
+		}
+		return out
+	}
+	return convert(symbols), nil
+}
+
+// definition returns where the identifier at a position is declared, or
+// null if it has no declaration in source
+func (s *Server) definition(ctx context.Context, params json.RawMessage) (any, error) {
+	var p textDocumentPositionParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &responseError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	path, line, column, err := s.position(p)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := s.analyzer.Definition(ctx, path, line, column)
+	if errors.Is(err, readgo.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.location(*loc), nil
+}
+
+// references returns the references to the identifier at a position
+func (s *Server) references(ctx context.Context, params json.RawMessage) (any, error) {
+	var p referenceParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &responseError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	path, line, column, err := s.position(p.textDocumentPositionParams)
+	if err != nil {
+		return nil, err
+	}
+	refs, err := s.analyzer.References(ctx, path, line, column, p.Context.IncludeDeclaration)
+	if errors.Is(err, readgo.ErrNotFound) {
+		return []Location{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	locations := make([]Location, 0, len(refs))
+	for _, ref := range refs {
+		locations = append(locations, s.location(ref))
+	}
+	return locations, nil
+}
+
+// update records the text of a document, when given, and validates it if
+// requested
+func (s *Server) update(ctx context.Context, uri string, text *string, validate bool) {
+	path, err := uriToPath(uri)
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	doc, ok := s.docs[path]
+	if !ok && text == nil {
+		s.mu.Unlock()
+		return // Only open documents are tracked
+	}
+	if !ok {
+		doc = &document{}
+		s.docs[path] = doc
+	}
+	if text != nil {
+		doc.text = *text
+	}
+	doc.version++
+	version, content := doc.version, doc.text
+	s.mu.Unlock()
+	if !validate || s.validator == nil {
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		diagnostics := s.diagnostics(ctx, path, content)
+		s.mu.Lock()
+		current := s.docs[path] == doc && doc.version == version
+		s.mu.Unlock()
+		if current && diagnostics != nil {
+			s.send("textDocument/publishDiagnostics", publishDiagnosticsParams{URI: uri, Diagnostics: diagnostics})
+		}
+	}()
+}
+
+// errorPosition matches the "file:line[:col]" position inside the error
+// strings of the validator
+var errorPosition = regexp.MustCompile(`(\S+\.go):(\d+)(?::(\d+))?(?::\s*|$)`)
+
+// diagnostics validates content as the file at path. It returns nil if the
+// file is outside the workspace or could not be validated.
+func (s *Server) diagnostics(ctx context.Context, path, content string) []Diagnostic {
+	rel, err := filepath.Rel(s.root, path)
+	if err != nil || !filepath.IsLocal(rel) {
+		return nil
+	}
+	result, err := s.validator.ValidateFileContent(ctx, rel, []byte(content), s.level)
+	if err != nil {
+		return nil
+	}
+
+	lines := strings.Split(content, "\n")
+	diagnostics := make([]Diagnostic, 0, len(result.Errors)+len(result.Warnings))
+	for _, e := range result.Errors {
+		line, column, msg := 0, 0, e
+		if m := errorPosition.FindStringSubmatchIndex(e); m != nil {
+			line, _ = strconv.Atoi(e[m[4]:m[5]])
+			if m[6] >= 0 {
+				column, _ = strconv.Atoi(e[m[6]:m[7]])
+			}
+			if m[0] == 0 && m[1] < len(e) {
+				msg = e[m[1]:] // Drop the position from "file:line:col: message"
+			}
+		}
+		diagnostics = append(diagnostics, diagnostic(lines, line, column, severityError, "", msg))
+	}
+	for _, w := range result.Warnings {
+		severity := severityWarning
+		switch w.Severity {
+		case readgo.SeverityError:
+			severity = severityError
+		case readgo.SeverityInfo:
+			severity = severityInformation
+		}
+		diagnostics = append(diagnostics, diagnostic(lines, w.Line, w.Column, severity, w.Type, w.Message))
+	}
+	return diagnostics
+}
+
+// diagnostic returns a diagnostic from the 1-based line to the end of the
+// line, starting at the 1-based byte column or the first non-blank
+// character if column is 0. A line of 0 marks the start of the document.
+func diagnostic(lines []string, line, column, severity int, code, msg string) Diagnostic {
+	d := Diagnostic{Severity: severity, Code: code, Source: "readgo", Message: msg}
+	if line < 1 || line > len(lines) {
+		return d
+	}
+	text := strings.TrimSuffix(lines[line-1], "\r")
+	if column < 1 {
+		column = len(text) - len(strings.TrimLeft(text, " \t")) + 1
+	}
+	d.Range = Range{
+		Start: Position{Line: line - 1, Character: utf16Column(text, column)},
+		End:   Position{Line: line - 1, Character: utf16Column(text, len(text)+1)},
+	}
+	return d
+}
+
+// position converts the position of a request to a file path and 1-based
+// line and byte column
+func (s *Server) position(p textDocumentPositionParams) (string, int, int, error) {
+	path, err := uriToPath(p.TextDocument.URI)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	lines := s.lines(path)
+	if p.Position.Line < 0 || p.Position.Line >= len(lines) {
+		return "", 0, 0, &responseError{Code: codeInvalidParams, Message: fmt.Sprintf("line %d is outside the document", p.Position.Line)}
+	}
+	return path, p.Position.Line + 1, byteColumn(lines[p.Position.Line], p.Position.Character), nil
+}
+
+// location converts a location of the analyzer to an LSP location
+func (s *Server) location(loc readgo.Location) Location {
+	path := loc.File
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(s.root, path)
+	}
+	return Location{URI: pathToURI(path), Range: toRange(s.lines(path), loc)}
+}
+
+// lines returns the lines of a file as saved, or nil if it cannot be read
+func (s *Server) lines(path string) []string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	lines := strings.Split(string(content), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines
+}
+
+// toRange converts a location of the analyzer to an LSP range, measuring
+// columns in the given lines of its file
+func toRange(lines []string, loc readgo.Location) Range {
+	pos := func(line, column int) Position {
+		p := Position{Line: line - 1, Character: column - 1}
+		if line >= 1 && line <= len(lines) {
+			p.Character = utf16Column(lines[line-1], column)
+		}
+		return p
+	}
+	return Range{Start: pos(loc.Line, loc.Column), End: pos(loc.EndLine, loc.EndColumn)}
+}
+
+// utf16Column returns the UTF-16 offset of the 1-based byte column of line
+func utf16Column(line string, column int) int {
+	n := 0
+	for i, r := range line {
+		if i >= column-1 {
+			break
+		}
+		n += utf16Len(r)
+	}
+	return n
+}
+
+// byteColumn returns the 1-based byte column of the UTF-16 offset of line
+func byteColumn(line string, offset int) int {
+	n := 0
+	for i, r := range line {
+		if n >= offset {
+			return i + 1
+		}
+		n += utf16Len(r)
+	}
+	return len(line) + 1
+}
+
+// utf16Len returns the number of UTF-16 code units encoding r
+func utf16Len(r rune) int {
+	if r >= 0x10000 {
+		return 2
+	}
+	return 1
+}
+
+// symbolKind returns the LSP symbol kind of a readgo symbol kind
+func symbolKind(kind readgo.SymbolKind) int {
+	switch kind {
+	case readgo.SymbolKindStruct:
+		return symbolKindStruct
+	case readgo.SymbolKindInterface:
+		return symbolKindInterface
+	case readgo.SymbolKindFunc:
+		return symbolKindFunction
+	case readgo.SymbolKindMethod:
+		return symbolKindMethod
+	case readgo.SymbolKindField:
+		return symbolKindField
+	case readgo.SymbolKindVar:
+		return symbolKindVariable
+	case readgo.SymbolKindConst:
+		return symbolKindConstant
+	default:
+		return symbolKindClass
+	}
+}
+
+// uriToPath returns the path of a file URI
+func uriToPath(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return "", &responseError{Code: codeInvalidParams, Message: fmt.Sprintf("not a file URI: %q", uri)}
+	}
+	path := u.Path
+	if len(path) >= 3 && path[0] == '/' && path[2] == ':' {
+		path = path[1:] // Windows drive letter, as in file:///C:/dir
+	}
+	return filepath.Clean(filepath.FromSlash(path)), nil
+}
+
+// pathToURI returns the file URI of an absolute path
+func pathToURI(path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
+// reply sends the response to a request. A nil error with a nil result
+// replies null.
+func (s *Server) reply(id *json.RawMessage, result any, err error) {
+	resp := response{JSONRPC: "2.0", ID: id}
+	if err != nil {
+		resp.Error = toResponseError(err)
+	} else {
+		data, merr := json.Marshal(result)
+		if merr != nil {
+			resp.Error = &responseError{Code: codeInternalError, Message: merr.Error()}
+		} else {
+			resp.Result = data
+		}
+	}
+	if id == nil {
+		null := json.RawMessage("null")
+		resp.ID = &null
+	}
+	s.write(resp)
+}
+
+// toResponseError converts an error to the error of a response
+func toResponseError(err error) *responseError {
+	var re *responseError
+	switch {
+	case errors.As(err, &re):
+		return re
+	case errors.Is(err, context.Canceled):
+		return &responseError{Code: codeRequestCancelled, Message: err.Error()}
+	case errors.Is(err, readgo.ErrInvalidInput):
+		return &responseError{Code: codeInvalidParams, Message: err.Error()}
+	default:
+		return &responseError{Code: codeRequestFailed, Message: err.Error()}
+	}
+}
+
+// send sends a notification to the client
+func (s *Server) send(method string, params any) {
+	s.write(notification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+// write writes a message framed by its Content-Length header. Write errors
+// surface as the client closing its end, so they are dropped here.
+func (s *Server) write(v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n", len(data))
+	s.out.Write(data)
+}
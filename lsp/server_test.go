@@ -0,0 +1,225 @@
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// client drives a server over pipes
+type client struct {
+	t      *testing.T
+	in     io.Writer
+	out    *textproto.Reader
+	nextID int
+	notes  []notification // Notifications received while waiting for responses
+	done   chan error
+}
+
+// newClient serves a server over pipes and returns a client of it
+func newClient(t *testing.T) *client {
+	t.Helper()
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	c := &client{t: t, in: inW, out: textproto.NewReader(bufio.NewReader(outR)), done: make(chan error, 1)}
+	go func() {
+		c.done <- NewServer().Serve(context.Background(), inR, outW)
+		outW.Close()
+	}()
+	t.Cleanup(func() { inW.Close() })
+	return c
+}
+
+// write sends a message
+func (c *client) write(v any) {
+	c.t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		c.t.Fatalf("Failed to encode message: %v", err)
+	}
+	if _, err := fmt.Fprintf(c.in, "Content-Length: %d\r\n\r\n%s", len(data), data); err != nil {
+		c.t.Fatalf("Failed to send message: %v", err)
+	}
+}
+
+// call sends a request and decodes the result of its response into result
+func (c *client) call(method string, params, result any) *responseError {
+	c.t.Helper()
+	c.nextID++
+	c.write(map[string]any{"jsonrpc": "2.0", "id": c.nextID, "method": method, "params": params})
+	for {
+		var resp struct {
+			ID     *int            `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+			Result json.RawMessage `json:"result"`
+			Error  *responseError  `json:"error"`
+		}
+		c.read(&resp)
+		if resp.ID == nil {
+			c.notes = append(c.notes, notification{Method: resp.Method, Params: resp.Params})
+			continue
+		}
+		if *resp.ID != c.nextID {
+			c.t.Fatalf("Got response %d, want %d", *resp.ID, c.nextID)
+		}
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if err := json.Unmarshal(resp.Result, result); err != nil {
+			c.t.Fatalf("Failed to decode %s result %s: %v", method, resp.Result, err)
+		}
+		return nil
+	}
+}
+
+// read reads the next message into v
+func (c *client) read(v any) {
+	c.t.Helper()
+	body, err := readMessage(c.out)
+	if err != nil {
+		c.t.Fatalf("Failed to read message: %v", err)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		c.t.Fatalf("Failed to decode message %s: %v", body, err)
+	}
+}
+
+// diagnostics waits for the next diagnostics published for uri
+func (c *client) diagnostics(uri string) []Diagnostic {
+	c.t.Helper()
+	for {
+		var n struct {
+			Method string                   `json:"method"`
+			Params publishDiagnosticsParams `json:"params"`
+		}
+		if len(c.notes) > 0 {
+			data, _ := json.Marshal(c.notes[0])
+			c.notes = c.notes[1:]
+			json.Unmarshal(data, &n)
+		} else {
+			c.read(&n)
+		}
+		if n.Method == "textDocument/publishDiagnostics" && n.Params.URI == uri {
+			return n.Params.Diagnostics
+		}
+	}
+}
+
+func TestServer(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":         "module example.com/lsp\n\ngo 1.22\n",
+		"store/store.go": "package store\n\n// Store keeps things\ntype Store struct {\n\tName string\n}\n\n// Open opens a store\nfunc Open() *Store { return &Store{} }\n",
+		"api/api.go":     "package api\n\nimport \"example.com/lsp/store\"\n\n// Ünïcode comes first so columns differ from bytes\nvar ü, s = 1, store.Open()\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+	storeURI := pathToURI(filepath.Join(dir, "store", "store.go"))
+	apiURI := pathToURI(filepath.Join(dir, "api", "api.go"))
+
+	c := newClient(t)
+	var caps initializeResult
+	if err := c.call("textDocument/documentSymbol", map[string]any{"textDocument": map[string]string{"uri": storeURI}}, &caps); err == nil || err.Code != codeServerNotInitialized {
+		t.Fatalf("documentSymbol before initialize error = %v, want not initialized", err)
+	}
+	if err := c.call("initialize", map[string]any{"rootUri": pathToURI(dir)}, &caps); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	if !caps.Capabilities.DefinitionProvider || !caps.Capabilities.ReferencesProvider || !caps.Capabilities.DocumentSymbolProvider {
+		t.Errorf("initialize() capabilities = %+v, want symbols, definition and references", caps.Capabilities)
+	}
+	c.write(map[string]any{"jsonrpc": "2.0", "method": "initialized", "params": map[string]any{}})
+
+	t.Run("documentSymbol", func(t *testing.T) {
+		var symbols []DocumentSymbol
+		if err := c.call("textDocument/documentSymbol", map[string]any{"textDocument": map[string]string{"uri": storeURI}}, &symbols); err != nil {
+			t.Fatalf("documentSymbol() error = %v", err)
+		}
+		if len(symbols) != 2 || symbols[0].Name != "Store" || symbols[0].Kind != symbolKindStruct || symbols[1].Name != "Open" {
+			t.Fatalf("documentSymbol() = %+v, want Store and Open", symbols)
+		}
+		want := Range{Start: Position{Line: 8, Character: 5}, End: Position{Line: 8, Character: 9}}
+		if symbols[1].SelectionRange != want || len(symbols[0].Children) != 1 {
+			t.Errorf("documentSymbol() Open at %+v with %d fields in Store, want %+v and 1", symbols[1].SelectionRange, len(symbols[0].Children), want)
+		}
+	})
+
+	t.Run("definition", func(t *testing.T) {
+		var loc *Location
+		// "Open" on "var ü, s = 1, store.Open()", where ü takes one UTF-16 unit
+		params := map[string]any{"textDocument": map[string]string{"uri": apiURI}, "position": Position{Line: 5, Character: 20}}
+		if err := c.call("textDocument/definition", params, &loc); err != nil {
+			t.Fatalf("definition() error = %v", err)
+		}
+		want := Location{URI: storeURI, Range: Range{Start: Position{Line: 8, Character: 5}, End: Position{Line: 8, Character: 9}}}
+		if loc == nil || *loc != want {
+			t.Errorf("definition() = %+v, want %+v", loc, want)
+		}
+
+		params["position"] = Position{Line: 1, Character: 0}
+		loc = &Location{}
+		if err := c.call("textDocument/definition", params, &loc); err != nil || loc != nil {
+			t.Errorf("definition() on a blank line = %+v, %v, want null", loc, err)
+		}
+	})
+
+	t.Run("references", func(t *testing.T) {
+		var locs []Location
+		params := map[string]any{
+			"textDocument": map[string]string{"uri": storeURI},
+			"position":     Position{Line: 8, Character: 6},
+			"context":      map[string]bool{"includeDeclaration": true},
+		}
+		if err := c.call("textDocument/references", params, &locs); err != nil {
+			t.Fatalf("references() error = %v", err)
+		}
+		if len(locs) != 2 || locs[0].URI != apiURI || locs[0].Range.Start != (Position{Line: 5, Character: 20}) || locs[1].URI != storeURI {
+			t.Errorf("references() = %+v, want the use in api.go and the declaration", locs)
+		}
+	})
+
+	t.Run("diagnostics", func(t *testing.T) {
+		text := "package store\n\nfunc Broken() {\n\tx := \n}\n"
+		c.write(map[string]any{"jsonrpc": "2.0", "method": "textDocument/didOpen", "params": map[string]any{
+			"textDocument": map[string]any{"uri": storeURI, "languageId": "go", "version": 1, "text": text},
+		}})
+		diags := c.diagnostics(storeURI)
+		if len(diags) == 0 || diags[0].Severity != severityError || diags[0].Range.Start.Line != 4 {
+			t.Fatalf("diagnostics = %+v, want an error on line 5", diags)
+		}
+		if !strings.Contains(diags[0].Message, "expected") {
+			t.Errorf("diagnostic message = %q, want the syntax error", diags[0].Message)
+		}
+
+		c.write(map[string]any{"jsonrpc": "2.0", "method": "textDocument/didClose", "params": map[string]any{
+			"textDocument": map[string]string{"uri": storeURI},
+		}})
+		if diags := c.diagnostics(storeURI); len(diags) != 0 {
+			t.Errorf("diagnostics after close = %+v, want none", diags)
+		}
+	})
+
+	var null any
+	if err := c.call("shutdown", nil, &null); err != nil {
+		t.Fatalf("Failed to shut down: %v", err)
+	}
+	c.write(map[string]any{"jsonrpc": "2.0", "method": "exit"})
+	if err := <-c.done; err != nil {
+		t.Errorf("Serve() error = %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+// Command readgo-mcp serves readgo's tools for one project to an MCP client
+// over stdin and stdout. Register it with a coding agent as:
+//
+//	readgo-mcp -root /path/to/project
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/iamlongalong/readgo/mcp"
+	gomcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func main() {
+	root := flag.String("root", ".", "directory of the project to serve")
+	flag.Parse()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := serve(ctx, *root); err != nil {
+		fmt.Fprintf(os.Stderr, "readgo-mcp: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// serve serves the project in root over stdio until the client disconnects
+func serve(ctx context.Context, root string) error {
+	srv, err := mcp.NewServer(root)
+	if err != nil {
+		return err
+	}
+	return srv.Run(ctx, &gomcp.StdioTransport{})
+}
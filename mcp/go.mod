@@ -0,0 +1,22 @@
+module github.com/iamlongalong/readgo/mcp
+
+go 1.23.0
+
+require (
+	github.com/iamlongalong/readgo v0.2.0
+	github.com/modelcontextprotocol/go-sdk v1.2.0
+)
+
+require (
+	github.com/google/jsonschema-go v0.3.0 // indirect
+	github.com/yosida95/uritemplate/v3 v3.0.2 // indirect
+	go.opentelemetry.io/otel v1.34.0 // indirect
+	go.opentelemetry.io/otel/trace v1.34.0 // indirect
+	golang.org/x/mod v0.25.0 // indirect
+	golang.org/x/oauth2 v0.30.0 // indirect
+	golang.org/x/sync v0.15.0 // indirect
+	golang.org/x/tools v0.34.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/iamlongalong/readgo => ../
@@ -0,0 +1,311 @@
+// Package mcp serves readgo as a Model Context Protocol server, so coding
+// agents can read, search, analyze and validate a Go project through it.
+//
+//	srv, err := mcp.NewServer("/path/to/project")
+//	if err != nil {
+//		return err
+//	}
+//	return srv.Run(ctx, &gomcp.StdioTransport{})
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/iamlongalong/readgo"
+	gomcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultLimit is the most nodes get_file_tree and search_files return
+// unless asked for another limit, to keep results within a model's context
+const defaultLimit = 1000
+
+// tools serves the tools of one project
+type tools struct {
+	reader    *readgo.DefaultReader
+	analyzer  *readgo.DefaultAnalyzer
+	validator *readgo.DefaultValidator
+}
+
+// NewServer creates an MCP server with readgo's tools for the project in
+// root. The options configure the analyzer and validator as for
+// readgo.NewAnalyzer; a working directory among them is overridden by root.
+// Files are read through a jailed reader, and paths that leave root are
+// rejected.
+func NewServer(root string, opts ...readgo.Option) (*gomcp.Server, error) {
+	reader, err := readgo.NewJailedReader(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open project: %w", err)
+	}
+	opts = append(opts, readgo.WithWorkDir(root))
+	t := &tools{
+		reader:    reader,
+		analyzer:  readgo.NewAnalyzer(opts...),
+		validator: readgo.NewValidator(root, opts...),
+	}
+
+	s := gomcp.NewServer(&gomcp.Implementation{Name: "readgo", Version: readgo.Version()}, &gomcp.ServerOptions{
+		Instructions: "Tools to read, search, analyze and validate the Go project in " + root +
+			". Paths are relative to the project root; packages are import paths or patterns such as ./internal/store.",
+	})
+	gomcp.AddTool(s, &gomcp.Tool{
+		Name:        "read_file",
+		Description: "Read a file of the project, optionally only a range of lines and with line numbers",
+	}, t.readFile)
+	gomcp.AddTool(s, &gomcp.Tool{
+		Name:        "get_file_tree",
+		Description: "List the files and directories below a directory of the project as a tree",
+	}, t.getFileTree)
+	gomcp.AddTool(s, &gomcp.Tool{
+		Name:        "search_files",
+		Description: "Find the files of the project whose name contains a pattern such as _test.go",
+	}, t.searchFiles)
+	gomcp.AddTool(s, &gomcp.Tool{
+		Name:        "find_type",
+		Description: "Look up a type, interface or function of a package by name",
+	}, t.findType)
+	gomcp.AddTool(s, &gomcp.Tool{
+		Name:        "analyze_file",
+		Description: "List the imports, types and functions of a Go source file",
+	}, t.analyzeFile)
+	gomcp.AddTool(s, &gomcp.Tool{
+		Name:        "analyze_package",
+		Description: "List the imports, types and functions, with signatures and doc comments, of a Go package",
+	}, t.analyzePackage)
+	gomcp.AddTool(s, &gomcp.Tool{
+		Name:        "validate_file",
+		Description: "Check a Go source file for syntax errors, type errors and lint warnings",
+	}, t.validateFile)
+	gomcp.AddTool(s, &gomcp.Tool{
+		Name:        "validate_project",
+		Description: "Check the whole project for syntax errors, type errors and lint warnings",
+	}, t.validateProject)
+	return s, nil
+}
+
+// readFileInput holds the arguments of read_file
+type readFileInput struct {
+	Path            string `json:"path" jsonschema:"path of the file, relative to the project root"`
+	StartLine       int    `json:"start_line,omitempty" jsonschema:"first line to read, from 1; the start of the file if unset"`
+	EndLine         int    `json:"end_line,omitempty" jsonschema:"last line to read; the end of the file if unset"`
+	WithLineNumbers bool   `json:"with_line_numbers,omitempty" jsonschema:"prefix each line with its line number"`
+}
+
+// readFile returns the text of a file, or of a range of its lines
+func (t *tools) readFile(ctx context.Context, _ *gomcp.CallToolRequest, in readFileInput) (*gomcp.CallToolResult, any, error) {
+	if in.StartLine == 0 && in.EndLine == 0 {
+		content, err := t.reader.ReadSourceFile(ctx, in.Path, readgo.ReadOptions{
+			IncludeComments: true,
+			WithLineNumbers: in.WithLineNumbers,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		return textResult(string(content)), nil, nil
+	}
+
+	lines, err := readgo.ReadLines(ctx, t.reader, in.Path, readgo.ReadOptions{IncludeComments: true})
+	if err != nil {
+		return nil, nil, err
+	}
+	start, end := max(in.StartLine, 1), in.EndLine
+	if end == 0 || end > len(lines) {
+		end = len(lines)
+	}
+	if start > end {
+		return nil, nil, fmt.Errorf("%w: line range %d-%d is outside the file's %d lines", readgo.ErrInvalidInput, in.StartLine, in.EndLine, len(lines))
+	}
+	var b strings.Builder
+	for _, line := range lines[start-1 : end] {
+		if in.WithLineNumbers {
+			fmt.Fprintf(&b, "%6d\t", line.Number)
+		}
+		b.WriteString(line.Text)
+		b.WriteByte('\n')
+	}
+	return textResult(b.String()), nil, nil
+}
+
+// treeInput holds the arguments of get_file_tree
+type treeInput struct {
+	Root      string `json:"root,omitempty" jsonschema:"directory at the root of the tree, relative to the project root; the project root if unset"`
+	FileTypes string `json:"file_types,omitempty" jsonschema:"files to list: all (the default), go, test, generated or go_package"`
+	Limit     int    `json:"limit,omitempty" jsonschema:"most nodes to list, 1000 if unset"`
+}
+
+// getFileTree returns the file tree below a directory
+func (t *tools) getFileTree(ctx context.Context, _ *gomcp.CallToolRequest, in treeInput) (*gomcp.CallToolResult, any, error) {
+	tree, err := t.reader.GetFileTree(ctx, in.Root, treeOptions(in.FileTypes, in.Limit))
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, tree, nil
+}
+
+// searchInput holds the arguments of search_files
+type searchInput struct {
+	Pattern   string `json:"pattern" jsonschema:"text the file names must contain, such as _test.go"`
+	FileTypes string `json:"file_types,omitempty" jsonschema:"files to search: all (the default), go, test, generated or go_package"`
+	Limit     int    `json:"limit,omitempty" jsonschema:"most files to return, 1000 if unset"`
+}
+
+// searchResult is the output of search_files; tool output must be an object
+type searchResult struct {
+	Files []*readgo.FileTreeNode `json:"files"`
+}
+
+// searchFiles returns the files whose name contains a pattern
+func (t *tools) searchFiles(ctx context.Context, _ *gomcp.CallToolRequest, in searchInput) (*gomcp.CallToolResult, any, error) {
+	files, err := t.reader.SearchFiles(ctx, in.Pattern, treeOptions(in.FileTypes, in.Limit))
+	if err != nil {
+		return nil, nil, err
+	}
+	if files == nil {
+		files = []*readgo.FileTreeNode{}
+	}
+	return nil, searchResult{Files: files}, nil
+}
+
+// findTypeInput holds the arguments of find_type
+type findTypeInput struct {
+	Package string `json:"package" jsonschema:"import path or pattern of the package, such as ./internal/store"`
+	Name    string `json:"name" jsonschema:"name of the type, interface or function"`
+	Kind    string `json:"kind,omitempty" jsonschema:"what to look up: type (the default), interface or func"`
+}
+
+// findType looks up a type, interface or function of a package
+func (t *tools) findType(ctx context.Context, _ *gomcp.CallToolRequest, in findTypeInput) (*gomcp.CallToolResult, any, error) {
+	if err := checkPath(in.Package); err != nil {
+		return nil, nil, err
+	}
+	var info *readgo.TypeInfo
+	var err error
+	switch in.Kind {
+	case "", "type":
+		info, err = t.analyzer.FindType(ctx, in.Package, in.Name)
+	case "interface":
+		info, err = t.analyzer.FindInterface(ctx, in.Package, in.Name)
+	case "func", "function":
+		info, err = t.analyzer.FindFunction(ctx, in.Package, in.Name)
+	default:
+		err = fmt.Errorf("%w: unknown kind %q", readgo.ErrInvalidInput, in.Kind)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, info, nil
+}
+
+// analyzeFileInput holds the arguments of analyze_file
+type analyzeFileInput struct {
+	Path string `json:"path" jsonschema:"path of the Go source file, relative to the project root"`
+}
+
+// analyzeFile analyzes a single Go source file
+func (t *tools) analyzeFile(ctx context.Context, _ *gomcp.CallToolRequest, in analyzeFileInput) (*gomcp.CallToolResult, any, error) {
+	if err := checkPath(in.Path); err != nil {
+		return nil, nil, err
+	}
+	result, err := t.analyzer.AnalyzeFile(ctx, in.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, result, nil
+}
+
+// analyzePackageInput holds the arguments of analyze_package
+type analyzePackageInput struct {
+	Package      string `json:"package" jsonschema:"import path or pattern of the package, such as ./internal/store"`
+	ExportedOnly bool   `json:"exported_only,omitempty" jsonschema:"leave out unexported types and functions"`
+}
+
+// analyzePackage analyzes a Go package
+func (t *tools) analyzePackage(ctx context.Context, _ *gomcp.CallToolRequest, in analyzePackageInput) (*gomcp.CallToolResult, any, error) {
+	if err := checkPath(in.Package); err != nil {
+		return nil, nil, err
+	}
+	result, err := t.analyzer.AnalyzePackage(ctx, in.Package)
+	if err != nil {
+		return nil, nil, err
+	}
+	if in.ExportedOnly {
+		result = result.ExportedOnly()
+	}
+	return nil, result, nil
+}
+
+// validateFileInput holds the arguments of validate_file
+type validateFileInput struct {
+	Path  string `json:"path" jsonschema:"path of the Go source file, relative to the project root"`
+	Level string `json:"level,omitempty" jsonschema:"validation level: basic, standard (the default) or strict"`
+}
+
+// validateFile validates a single Go source file
+func (t *tools) validateFile(ctx context.Context, _ *gomcp.CallToolRequest, in validateFileInput) (*gomcp.CallToolResult, any, error) {
+	if err := checkPath(in.Path); err != nil {
+		return nil, nil, err
+	}
+	level, err := validationLevel(in.Level)
+	if err != nil {
+		return nil, nil, err
+	}
+	result, err := t.validator.ValidateFile(ctx, in.Path, level)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, result, nil
+}
+
+// validateProjectInput holds the arguments of validate_project
+type validateProjectInput struct {
+	Level string `json:"level,omitempty" jsonschema:"validation level: basic, standard (the default) or strict"`
+}
+
+// validateProject validates the whole project
+func (t *tools) validateProject(ctx context.Context, _ *gomcp.CallToolRequest, in validateProjectInput) (*gomcp.CallToolResult, any, error) {
+	level, err := validationLevel(in.Level)
+	if err != nil {
+		return nil, nil, err
+	}
+	result, err := t.validator.ValidateProject(ctx, level)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, result, nil
+}
+
+// textResult returns a tool result holding text
+func textResult(text string) *gomcp.CallToolResult {
+	return &gomcp.CallToolResult{Content: []gomcp.Content{&gomcp.TextContent{Text: text}}}
+}
+
+// treeOptions returns the options of a tree or search listing fileTypes
+// and at most limit nodes
+func treeOptions(fileTypes string, limit int) readgo.TreeOptions {
+	if fileTypes == "" {
+		fileTypes = string(readgo.FileTypeAll)
+	}
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	return readgo.TreeOptions{FileTypes: readgo.FileType(fileTypes), Limit: limit}
+}
+
+// validationLevel parses a validation level, defaulting to the standard level
+func validationLevel(name string) (readgo.ValidationLevel, error) {
+	if name == "" {
+		return readgo.ValidationLevelStandard, nil
+	}
+	return readgo.ParseValidationLevel(name)
+}
+
+// checkPath rejects file paths and package patterns that are absolute or
+// climb out of the project; import paths pass
+func checkPath(path string) error {
+	if path == "" || filepath.IsLocal(path) {
+		return nil
+	}
+	return fmt.Errorf("%w: path %q is outside the project", readgo.ErrInvalidInput, path)
+}
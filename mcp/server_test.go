@@ -0,0 +1,174 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gomcp "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// newSession serves an MCP server for dir over in-memory transports and
+// returns a client session of it
+func newSession(t *testing.T, dir string) *gomcp.ClientSession {
+	t.Helper()
+	srv, err := NewServer(dir)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	ctx := context.Background()
+	st, ct := gomcp.NewInMemoryTransports()
+	ss, err := srv.Connect(ctx, st, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect server: %v", err)
+	}
+	t.Cleanup(func() { ss.Close() })
+	cs, err := gomcp.NewClient(&gomcp.Implementation{Name: "test"}, nil).Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect client: %v", err)
+	}
+	t.Cleanup(func() { cs.Close() })
+	return cs
+}
+
+// callTool calls a tool and returns its text content
+func callTool(t *testing.T, cs *gomcp.ClientSession, name string, args map[string]any) (string, bool) {
+	t.Helper()
+	res, err := cs.CallTool(context.Background(), &gomcp.CallToolParams{Name: name, Arguments: args})
+	if err != nil {
+		t.Fatalf("Failed to call %s: %v", name, err)
+	}
+	var b strings.Builder
+	for _, c := range res.Content {
+		if text, ok := c.(*gomcp.TextContent); ok {
+			b.WriteString(text.Text)
+		}
+	}
+	return b.String(), res.IsError
+}
+
+func TestServer(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":         "module example.com/mcp\n\ngo 1.22\n",
+		"store/store.go": "package store\n\n// Store keeps things\ntype Store interface {\n\tGet(key string) string\n}\n\n// Open opens a store\nfunc Open() Store { return nil }\n\nfunc reset() {}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+	cs := newSession(t, dir)
+
+	t.Run("tools", func(t *testing.T) {
+		res, err := cs.ListTools(context.Background(), nil)
+		if err != nil {
+			t.Fatalf("Failed to list tools: %v", err)
+		}
+		var got []string
+		for _, tool := range res.Tools {
+			got = append(got, tool.Name)
+		}
+		want := "analyze_file analyze_package find_type get_file_tree read_file search_files validate_file validate_project"
+		if strings.Join(got, " ") != want {
+			t.Errorf("ListTools() = %v, want %s", got, want)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		tool    string
+		args    map[string]any
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "read lines",
+			tool: "read_file",
+			args: map[string]any{"path": "store/store.go", "start_line": 3, "end_line": 4, "with_line_numbers": true},
+			want: "     3\t// Store keeps things\n     4\ttype Store interface {\n",
+		},
+		{
+			name: "file tree",
+			tool: "get_file_tree",
+			args: map[string]any{"file_types": "go"},
+			want: `"name":"store.go"`,
+		},
+		{
+			name: "search files",
+			tool: "search_files",
+			args: map[string]any{"pattern": ".mod"},
+			want: `"name":"go.mod"`,
+		},
+		{
+			name: "find interface",
+			tool: "find_type",
+			args: map[string]any{"package": "./store", "name": "Store", "kind": "interface"},
+			want: `"kind":"interface"`,
+		},
+		{
+			name:    "find missing type",
+			tool:    "find_type",
+			args:    map[string]any{"package": "./store", "name": "Missing"},
+			want:    "not found",
+			wantErr: true,
+		},
+		{
+			name: "analyze exported",
+			tool: "analyze_package",
+			args: map[string]any{"package": "./store", "exported_only": true},
+			want: `"signature":"func Open() Store"`,
+		},
+		{
+			name: "validate project",
+			tool: "validate_project",
+			want: `"valid":true`,
+		},
+		{
+			name:    "path outside the project",
+			tool:    "analyze_file",
+			args:    map[string]any{"path": "../outside.go"},
+			want:    "outside the project",
+			wantErr: true,
+		},
+		{
+			name:    "read outside the project",
+			tool:    "read_file",
+			args:    map[string]any{"path": "/etc/passwd"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, isErr := callTool(t, cs, tt.tool, tt.args)
+			if isErr != tt.wantErr {
+				t.Fatalf("%s() error = %v, want %v: %s", tt.tool, isErr, tt.wantErr, got)
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("%s() = %s, want it to contain %s", tt.tool, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("unexported left out", func(t *testing.T) {
+		got, _ := callTool(t, cs, "analyze_package", map[string]any{"package": "./store", "exported_only": true})
+		var result struct {
+			Functions []struct {
+				Name string `json:"name"`
+			} `json:"functions"`
+		}
+		if err := json.Unmarshal([]byte(got), &result); err != nil {
+			t.Fatalf("Failed to decode result: %v", err)
+		}
+		if len(result.Functions) != 1 || result.Functions[0].Name != "Open" {
+			t.Errorf("analyze_package(exported_only) functions = %v, want Open", result.Functions)
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package readgo
+
+import "strings"
+
+// MergeResults combines validation results, such as those of ValidateFile
+// for several files, into one. Name, Path, Level and the times are taken
+// from the first result. Errors, warnings, TODO comments and cycles found
+// by more than one result are kept once, and warnings are grouped by file
+// in the order the files first appear. Stats of a result whose Path was
+// already merged are not added again, so validating the same file twice
+// does not inflate the counts. Nil results are skipped. The merged result
+// carries the schema and tool version of this release.
+func MergeResults(results ...*ValidationResult) *ValidationResult {
+	merged := &ValidationResult{SchemaVersion: SchemaVersion, ToolVersion: Version()}
+	first := true
+	paths := make(map[string]bool)
+	errors := make(map[string]bool)
+	warnings := make(map[warningKey]bool)
+	todos := make(map[TodoComment]bool)
+	cycles := make(map[string]bool)
+	var files []string
+	byFile := make(map[string][]ValidationWarning)
+
+	for _, r := range results {
+		if r == nil {
+			continue
+		}
+		if first {
+			merged.Name, merged.Path = r.Name, r.Path
+			merged.StartTime, merged.AnalyzedAt = r.StartTime, r.AnalyzedAt
+			merged.Level = r.Level
+			first = false
+		}
+		if !paths[r.Path] {
+			paths[r.Path] = true
+			merged.Stats.merge(r.Stats)
+		}
+		merged.Cancelled = merged.Cancelled || r.Cancelled
+
+		for _, e := range r.Errors {
+			if !errors[e] {
+				errors[e] = true
+				merged.Errors = append(merged.Errors, e)
+			}
+		}
+		for _, w := range r.Warnings {
+			key := keyOf(w)
+			if warnings[key] {
+				continue
+			}
+			warnings[key] = true
+			if _, ok := byFile[w.File]; !ok {
+				files = append(files, w.File)
+			}
+			w.SuggestedFixes = append([]SuggestedFix(nil), w.SuggestedFixes...)
+			byFile[w.File] = append(byFile[w.File], w)
+		}
+		for _, todo := range r.Todos {
+			if !todos[todo] {
+				todos[todo] = true
+				merged.Todos = append(merged.Todos, todo)
+			}
+		}
+		for _, c := range r.CircularDeps {
+			key := strings.Join(c.Packages, " ")
+			if !cycles[key] {
+				cycles[key] = true
+				c.Packages = append([]string(nil), c.Packages...)
+				merged.CircularDeps = append(merged.CircularDeps, c)
+			}
+		}
+	}
+
+	for _, file := range files {
+		merged.Warnings = append(merged.Warnings, byFile[file]...)
+	}
+	merged.Valid = len(merged.Errors) == 0 && !merged.Cancelled
+	return merged
+}
+
+// warningKey identifies a warning regardless of its suggested fixes
+type warningKey struct {
+	check, file, message string
+	line, column         int
+	severity             Severity
+}
+
+// keyOf returns the identity of w
+func keyOf(w ValidationWarning) warningKey {
+	return warningKey{
+		check:    w.Type,
+		file:     w.File,
+		message:  w.Message,
+		line:     w.Line,
+		column:   w.Column,
+		severity: w.Severity,
+	}
+}
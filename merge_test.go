@@ -0,0 +1,109 @@
+package readgo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestMergeResults(t *testing.T) {
+	a := &ValidationResult{
+		Name:     "a.go",
+		Path:     "a.go",
+		Level:    ValidationLevelStandard,
+		Valid:    true,
+		Warnings: []ValidationWarning{{Type: "naming", File: "a.go", Line: 3, Message: "bad name"}},
+		Stats:    ValidationStats{Suppressed: 1, SuppressedByRule: map[string]int{"naming": 1}},
+		Todos:    []TodoComment{{Tag: "TODO", Text: "a", File: "a.go", Line: 1, Column: 4}},
+	}
+	b := &ValidationResult{
+		Name:   "b.go",
+		Path:   "b.go",
+		Level:  ValidationLevelStandard,
+		Errors: []string{"parse error: b.go:1:1: expected 'package'"},
+		Warnings: []ValidationWarning{
+			{Type: "unused_func", File: "b.go", Line: 5, Message: "function f is never used"},
+			{Type: "naming", File: "a.go", Line: 7, Message: "other name"},
+		},
+		Stats: ValidationStats{Suppressed: 2, SuppressedByRule: map[string]int{"naming": 1, "unused_func": 1}},
+	}
+
+	got := MergeResults(a, nil, b, a)
+	want := &ValidationResult{
+		Name:   "a.go",
+		Path:   "a.go",
+		Level:  ValidationLevelStandard,
+		Errors: []string{"parse error: b.go:1:1: expected 'package'"},
+		Warnings: []ValidationWarning{
+			{Type: "naming", File: "a.go", Line: 3, Message: "bad name"},
+			{Type: "naming", File: "a.go", Line: 7, Message: "other name"},
+			{Type: "unused_func", File: "b.go", Line: 5, Message: "function f is never used"},
+		},
+		Stats:         ValidationStats{Suppressed: 3, SuppressedByRule: map[string]int{"naming": 2, "unused_func": 1}},
+		Todos:         []TodoComment{{Tag: "TODO", Text: "a", File: "a.go", Line: 1, Column: 4}},
+		SchemaVersion: SchemaVersion,
+		ToolVersion:   Version(),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeResults() = %+v, want %+v", got, want)
+	}
+
+	if got := MergeResults(a, &ValidationResult{Path: "c.go", Cancelled: true}); got.Valid || !got.Cancelled {
+		t.Errorf("MergeResults() Valid = %v, Cancelled = %v, want false, true", got.Valid, got.Cancelled)
+	}
+	if got := MergeResults(); !got.Valid || len(got.Warnings) != 0 {
+		t.Errorf("MergeResults() = %+v, want an empty valid result", got)
+	}
+}
+
+func TestMergeFileResults(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFiles := map[string]string{
+		"go.mod": "module example.com/p\n\ngo 1.22\n",
+		"a.go":   "package p\n\nfunc unusedA() {}\n\n//nolint:unused_func\nfunc quiet() {}\n",
+		"b.go":   "package p\n\nfunc unusedB() {}\n",
+	}
+	for name, content := range testFiles {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	ctx := context.Background()
+	v := NewValidator(tmpDir).WithConfig(&ValidatorConfig{Enable: []string{"unused"}})
+	var files []*ValidationResult
+	for _, name := range []string{"a.go", "b.go", "a.go"} {
+		result, err := v.ValidateFile(ctx, name, ValidationLevelStandard)
+		if err != nil {
+			t.Fatalf("Failed to validate file: %v", err)
+		}
+		files = append(files, result)
+	}
+	pkg, err := v.ValidatePackage(ctx, ".", ValidationLevelStandard)
+	if err != nil {
+		t.Fatalf("Failed to validate package: %v", err)
+	}
+
+	if pkg.Stats.Suppressed != 1 {
+		t.Errorf("ValidatePackage() suppressed = %d, want 1", pkg.Stats.Suppressed)
+	}
+
+	merged := MergeResults(files...)
+	messages := func(r *ValidationResult) []string {
+		var got []string
+		for _, w := range r.Warnings {
+			got = append(got, warningError(w))
+		}
+		sort.Strings(got)
+		return got
+	}
+	if got, want := messages(merged), messages(pkg); !reflect.DeepEqual(got, want) {
+		t.Errorf("merged warnings = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(merged.Stats, pkg.Stats) {
+		t.Errorf("merged stats = %+v, want %+v", merged.Stats, pkg.Stats)
+	}
+}
@@ -0,0 +1,194 @@
+// Package metrics exports the work of readgo analyzers and validators to
+// Prometheus, so long-running services built on them can be monitored. A
+// Collector observes analyzers and validators and reads the statistics of
+// their caches when scraped.
+//
+//	c := metrics.NewCollector()
+//	prometheus.MustRegister(c)
+//	analyzer := readgo.NewAnalyzer(readgo.WithObserver(c))
+//	c.WatchCache("analyzer", analyzer)
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/iamlongalong/readgo"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// namespace prefixes the names of all metrics
+const namespace = "readgo"
+
+// CacheStatsSource is anything reporting cache statistics, such as
+// readgo.DefaultAnalyzer and readgo.DefaultValidator
+type CacheStatsSource interface {
+	GetCacheStats() readgo.CacheStats
+}
+
+// Collector is a prometheus.Collector of the analyses run, package load
+// durations, findings by severity and cache use of readgo. It implements
+// readgo.Observer; pass it to readgo.WithObserver.
+type Collector struct {
+	loads              *prometheus.HistogramVec
+	analyses           *prometheus.CounterVec
+	analysisDurations  *prometheus.HistogramVec
+	validations        *prometheus.CounterVec
+	validationDuration prometheus.Histogram
+	findings           *prometheus.CounterVec
+
+	cacheHits    *prometheus.Desc
+	cacheMisses  *prometheus.Desc
+	cacheRatio   *prometheus.Desc
+	cacheEntries *prometheus.Desc
+
+	mu     sync.Mutex
+	caches map[string]CacheStatsSource
+}
+
+var _ readgo.Observer = (*Collector)(nil)
+
+// NewCollector creates a collector with no caches watched
+func NewCollector() *Collector {
+	cacheLabels := []string{"cache", "kind"}
+	return &Collector{
+		loads: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "package_load_duration_seconds",
+			Help:      "Duration of package loads, by result.",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+		}, []string{"result"}),
+		analyses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "analyses_total",
+			Help:      "Analyses run, not counting cache hits, by kind and result.",
+		}, []string{"kind", "result"}),
+		analysisDurations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "analysis_duration_seconds",
+			Help:      "Duration of analyses, by kind.",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+		}, []string{"kind"}),
+		validations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "validations_total",
+			Help:      "Validations run, not counting cache hits, by level and validity.",
+		}, []string{"level", "valid"}),
+		validationDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "validation_duration_seconds",
+			Help:      "Duration of validations.",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+		}),
+		findings: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "findings_total",
+			Help:      "Findings reported by validations, by severity.",
+		}, []string{"severity"}),
+		cacheHits: prometheus.NewDesc(prometheus.BuildFQName(namespace, "cache", "hits_total"),
+			"Cache lookups that hit, by cache and kind of entry.", cacheLabels, nil),
+		cacheMisses: prometheus.NewDesc(prometheus.BuildFQName(namespace, "cache", "misses_total"),
+			"Cache lookups that missed, by cache and kind of entry.", cacheLabels, nil),
+		cacheRatio: prometheus.NewDesc(prometheus.BuildFQName(namespace, "cache", "hit_ratio"),
+			"Share of cache lookups that hit, 0 without lookups, by cache and kind of entry.", cacheLabels, nil),
+		cacheEntries: prometheus.NewDesc(prometheus.BuildFQName(namespace, "cache", "entries"),
+			"Entries in the cache, by cache and kind of entry.", cacheLabels, nil),
+		caches: make(map[string]CacheStatsSource),
+	}
+}
+
+// WatchCache reports the cache statistics of source under the cache label
+// name on every scrape, replacing any source watched under the same name.
+// Analyzers and validators sharing a cache need to be watched only once.
+func (c *Collector) WatchCache(name string, source CacheStatsSource) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.caches[name] = source
+}
+
+// PackagesLoaded implements readgo.Observer
+func (c *Collector) PackagesLoaded(d time.Duration, err error) {
+	c.loads.WithLabelValues(result(err)).Observe(d.Seconds())
+}
+
+// AnalysisDone implements readgo.Observer
+func (c *Collector) AnalysisDone(kind string, d time.Duration, err error) {
+	c.analyses.WithLabelValues(kind, result(err)).Inc()
+	c.analysisDurations.WithLabelValues(kind).Observe(d.Seconds())
+}
+
+// ValidationDone implements readgo.Observer. Errors count as findings of
+// severity error, warnings by their own severity.
+func (c *Collector) ValidationDone(r *readgo.ValidationResult, d time.Duration) {
+	valid := "false"
+	if r.Valid {
+		valid = "true"
+	}
+	c.validations.WithLabelValues(r.Level.String(), valid).Inc()
+	c.validationDuration.Observe(d.Seconds())
+
+	if len(r.Errors) > 0 {
+		c.findings.WithLabelValues(string(readgo.SeverityError)).Add(float64(len(r.Errors)))
+	}
+	for _, w := range r.Warnings {
+		severity := w.Severity
+		if severity == "" {
+			severity = readgo.SeverityWarning
+		}
+		c.findings.WithLabelValues(string(severity)).Inc()
+	}
+}
+
+// result is the result label of an operation that failed with err
+func result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.loads.Describe(ch)
+	c.analyses.Describe(ch)
+	c.analysisDurations.Describe(ch)
+	c.validations.Describe(ch)
+	c.validationDuration.Describe(ch)
+	c.findings.Describe(ch)
+	ch <- c.cacheHits
+	ch <- c.cacheMisses
+	ch <- c.cacheRatio
+	ch <- c.cacheEntries
+}
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.loads.Collect(ch)
+	c.analyses.Collect(ch)
+	c.analysisDurations.Collect(ch)
+	c.validations.Collect(ch)
+	c.validationDuration.Collect(ch)
+	c.findings.Collect(ch)
+
+	c.mu.Lock()
+	caches := make(map[string]CacheStatsSource, len(c.caches))
+	for name, source := range c.caches {
+		caches[name] = source
+	}
+	c.mu.Unlock()
+
+	for name, source := range caches {
+		stats := source.GetCacheStats()
+		for kind, s := range map[string]readgo.CacheKindStats{
+			"types":       stats.Types,
+			"packages":    stats.Packages,
+			"analyses":    stats.Analyses,
+			"validations": stats.Validations,
+		} {
+			ch <- prometheus.MustNewConstMetric(c.cacheHits, prometheus.CounterValue, float64(s.Hits), name, kind)
+			ch <- prometheus.MustNewConstMetric(c.cacheMisses, prometheus.CounterValue, float64(s.Misses), name, kind)
+			ch <- prometheus.MustNewConstMetric(c.cacheRatio, prometheus.GaugeValue, s.HitRatio, name, kind)
+			ch <- prometheus.MustNewConstMetric(c.cacheEntries, prometheus.GaugeValue, float64(s.Entries), name, kind)
+		}
+	}
+}
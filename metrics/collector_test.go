@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iamlongalong/readgo"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollector(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":      "module example.com/metrics\n\ngo 1.22\n",
+		"app/app.go":  "package app\n\n// Run runs\nfunc Run() {}\n",
+		"bad/bad.go":  "package bad\n\nfunc Broken() {\n\tx := \n}\n",
+		"good/doc.go": "// Package good is fine\npackage good\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	c := NewCollector()
+	registry := prometheus.NewPedanticRegistry()
+	if err := registry.Register(c); err != nil {
+		t.Fatalf("Failed to register collector: %v", err)
+	}
+	ctx := context.Background()
+	analyzer := readgo.NewAnalyzer(readgo.WithWorkDir(dir), readgo.WithCacheTTL(time.Minute), readgo.WithObserver(c))
+	c.WatchCache("analyzer", analyzer)
+	for i := 0; i < 2; i++ {
+		if _, err := analyzer.AnalyzePackage(ctx, "./app"); err != nil {
+			t.Fatalf("Failed to analyze package: %v", err)
+		}
+	}
+	validator := readgo.NewValidator(dir, readgo.WithObserver(c))
+	validation, err := validator.ValidatePackage(ctx, "bad", readgo.ValidationLevelStandard)
+	if err != nil {
+		t.Fatalf("Failed to validate package: %v", err)
+	}
+	if len(validation.Errors) == 0 {
+		t.Fatalf("ValidatePackage() found no errors in a broken package")
+	}
+
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"package analyses", testutil.ToFloat64(c.analyses.WithLabelValues("package", "ok")), 1},
+		{"validations", testutil.ToFloat64(c.validations.WithLabelValues("standard", "false")), 1},
+		{"errors", testutil.ToFloat64(c.findings.WithLabelValues("error")), float64(len(validation.Errors))},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+	if n := testutil.CollectAndCount(c, "readgo_package_load_duration_seconds"); n != 1 {
+		t.Errorf("got %d package load histograms, want 1", n)
+	}
+
+	want := `
+# HELP readgo_cache_hit_ratio Share of cache lookups that hit, 0 without lookups, by cache and kind of entry.
+# TYPE readgo_cache_hit_ratio gauge
+readgo_cache_hit_ratio{cache="analyzer",kind="analyses"} 0.5
+readgo_cache_hit_ratio{cache="analyzer",kind="packages"} 0
+readgo_cache_hit_ratio{cache="analyzer",kind="types"} 0
+readgo_cache_hit_ratio{cache="analyzer",kind="validations"} 0
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(want), "readgo_cache_hit_ratio"); err != nil {
+		t.Errorf("Unexpected cache metrics: %v", err)
+	}
+	if problems, err := testutil.GatherAndLint(registry); err != nil || len(problems) > 0 {
+		t.Errorf("GatherAndLint() = %v, %v, want no problems", problems, err)
+	}
+}
@@ -0,0 +1,228 @@
+package readgo
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+const (
+	// goSumRule is the check name of go.sum findings
+	goSumRule = "go_sum"
+	// modDirectiveRule is the check name of replace and exclude directives
+	modDirectiveRule = "mod_directive"
+)
+
+// CheckModule checks the go.mod file in the validator's base directory and
+// the go.sum file next to it. It reports requirements whose checksums are
+// missing, which keep the module from building offline, checksums of module
+// versions go.mod no longer requires, and replace and exclude directives,
+// which apply only when building the module itself.
+func (v *DefaultValidator) CheckModule(ctx context.Context) (*ValidationResult, error) {
+	base, err := filepath.Abs(v.baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("module check error: %w", err)
+	}
+	gomod := filepath.Join(base, "go.mod")
+	data, err := os.ReadFile(gomod)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: no go.mod in %s", ErrNotFound, v.baseDir)
+		}
+		return nil, fmt.Errorf("module check error: %w", err)
+	}
+	mf, err := modfile.Parse(gomod, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &ValidationResult{
+		Name:          filepath.Base(v.baseDir),
+		Path:          v.baseDir,
+		StartTime:     time.Now().Format(time.RFC3339),
+		AnalyzedAt:    time.Now(),
+		SchemaVersion: SchemaVersion,
+		ToolVersion:   Version(),
+	}
+	if mf.Module != nil {
+		result.Name = mf.Module.Mod.Path
+	}
+
+	var findings []Finding
+	if v.config.ruleEnabled(goSumRule) {
+		gosum := filepath.Join(base, "go.sum")
+		sums, err := os.ReadFile(gosum)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("module check error: %w", err)
+		}
+		findings = append(findings, checkGoSum(mf, gosum, sums)...)
+	}
+	if v.config.ruleEnabled(modDirectiveRule) {
+		findings = append(findings, checkModDirectives(mf, base)...)
+	}
+	for _, f := range findings {
+		v.addFinding(result, f)
+	}
+	result.Valid = len(result.Errors) == 0
+
+	return result, nil
+}
+
+// goSumEntry is a line of a go.sum file
+type goSumEntry struct {
+	line  int
+	gomod bool
+}
+
+// checkGoSum compares the requirements of mf with the go.sum file at path
+// holding sums. Every required module version needs the checksum of its
+// go.mod file, and direct requirements also the checksum of their content.
+// Modules replaced by local directories need neither. Checksums of versions
+// that are not required are not reported: go mod tidy keeps those needed by
+// the pruned module graph and by the tests of dependencies.
+func checkGoSum(mf *modfile.File, path string, sums []byte) []Finding {
+	var findings []Finding
+	entries := make(map[module.Version][]goSumEntry)
+	scanner := bufio.NewScanner(bytes.NewReader(sums))
+	for n := 1; scanner.Scan(); n++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 || !strings.HasPrefix(fields[2], "h1:") {
+			findings = append(findings, Finding{
+				Rule:     goSumRule,
+				Severity: SeverityError,
+				Message:  "malformed go.sum line",
+				File:     path,
+				Line:     n,
+			})
+			continue
+		}
+		version, gomod := strings.CutSuffix(fields[1], "/go.mod")
+		mod := module.Version{Path: fields[0], Version: version}
+		entries[mod] = append(entries[mod], goSumEntry{line: n, gomod: gomod})
+	}
+
+	has := func(mod module.Version, gomod bool) bool {
+		for _, e := range entries[mod] {
+			if e.gomod == gomod {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, req := range mf.Require {
+		mod := replacement(mf, req.Mod)
+		if mod.Version == "" {
+			continue
+		}
+
+		var missing []string
+		if !has(mod, true) {
+			missing = append(missing, "go.mod checksum")
+		}
+		if !req.Indirect && !has(mod, false) {
+			missing = append(missing, "checksum")
+		}
+		if len(missing) == 0 {
+			continue
+		}
+		findings = append(findings, Finding{
+			Rule:     goSumRule,
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("missing go.sum %s for %s", strings.Join(missing, " and "), mod),
+			File:     mf.Syntax.Name,
+			Line:     req.Syntax.Start.Line,
+			Column:   req.Syntax.Start.LineRune,
+		})
+	}
+
+	return findings
+}
+
+// checkModDirectives reports the replace and exclude directives of mf, whose
+// directory is dir. Replacements by local directories are warnings, or
+// errors if the directory does not exist, since they break the build
+// everywhere but on the machine that added them; the other directives are
+// informational.
+func checkModDirectives(mf *modfile.File, dir string) []Finding {
+	var findings []Finding
+	for _, r := range mf.Replace {
+		f := Finding{
+			Rule:     modDirectiveRule,
+			Severity: SeverityInfo,
+			Message:  fmt.Sprintf("replace %s => %s", formatVersion(r.Old), formatVersion(r.New)),
+			File:     mf.Syntax.Name,
+			Line:     r.Syntax.Start.Line,
+			Column:   r.Syntax.Start.LineRune,
+		}
+		if r.New.Version == "" {
+			f.Severity = SeverityWarning
+			f.Message += " uses a local directory"
+			target := r.New.Path
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(dir, target)
+			}
+			if _, err := os.Stat(target); err != nil {
+				f.Severity = SeverityError
+				f.Message += " that does not exist"
+			}
+		}
+		findings = append(findings, f)
+	}
+	for _, x := range mf.Exclude {
+		findings = append(findings, Finding{
+			Rule:     modDirectiveRule,
+			Severity: SeverityInfo,
+			Message:  "exclude " + formatVersion(x.Mod),
+			File:     mf.Syntax.Name,
+			Line:     x.Syntax.Start.Line,
+			Column:   x.Syntax.Start.LineRune,
+		})
+	}
+	return findings
+}
+
+// formatVersion formats mod the way go.mod spells it, "path version" or
+// just the path
+func formatVersion(mod module.Version) string {
+	if mod.Version == "" {
+		return mod.Path
+	}
+	return mod.Path + " " + mod.Version
+}
+
+// replacement returns the module version that replaces mod in mf, mod
+// itself if it is not replaced, or a version without Version for a local
+// directory
+func replacement(mf *modfile.File, mod module.Version) module.Version {
+	var found *modfile.Replace
+	for _, r := range mf.Replace {
+		if r.Old.Path != mod.Path {
+			continue
+		}
+		// A replacement of the specific version wins over one of all versions
+		if r.Old.Version == mod.Version || (r.Old.Version == "" && found == nil) {
+			found = r
+		}
+	}
+	if found == nil {
+		return mod
+	}
+	return found.New
+}
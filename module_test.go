@@ -0,0 +1,213 @@
+package readgo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCheckModuleGoSum(t *testing.T) {
+	const gomod = `module example.com/app
+
+go 1.22
+
+require (
+	github.com/direct/lib v1.2.0
+	gitlab.com/group/indirect v0.3.0 // indirect
+)
+`
+	tests := []struct {
+		name      string
+		gomod     string
+		gosum     string
+		wantValid bool
+		want      []string
+	}{
+		{
+			name:  "complete",
+			gomod: gomod,
+			gosum: `github.com/direct/lib v1.2.0 h1:aaa=
+github.com/direct/lib v1.2.0/go.mod h1:bbb=
+github.com/other/graph v0.1.0/go.mod h1:ccc=
+gitlab.com/group/indirect v0.3.0/go.mod h1:ddd=
+`,
+			wantValid: true,
+		},
+		{
+			name:      "no go.sum",
+			gomod:     gomod,
+			wantValid: false,
+			want: []string{
+				"go.mod:6:2: missing go.sum go.mod checksum and checksum for github.com/direct/lib@v1.2.0",
+				"go.mod:7:2: missing go.sum go.mod checksum for gitlab.com/group/indirect@v0.3.0",
+			},
+		},
+		{
+			name:  "missing content checksum",
+			gomod: gomod,
+			gosum: `github.com/direct/lib v1.2.0/go.mod h1:bbb=
+gitlab.com/group/indirect v0.3.0 h1:eee=
+gitlab.com/group/indirect v0.3.0/go.mod h1:ddd=
+`,
+			wantValid: false,
+			want:      []string{"go.mod:6:2: missing go.sum checksum for github.com/direct/lib@v1.2.0"},
+		},
+		{
+			name:  "unrequired checksums",
+			gomod: gomod,
+			gosum: `github.com/direct/lib v1.1.0 h1:old=
+github.com/direct/lib v1.2.0 h1:aaa=
+github.com/direct/lib v1.2.0/go.mod h1:bbb=
+github.com/removed/lib v0.9.0 h1:fff=
+gitlab.com/group/indirect v0.3.0/go.mod h1:ddd=
+`,
+			wantValid: true,
+		},
+		{
+			name: "replacements",
+			gomod: `module example.com/app
+
+go 1.22
+
+require (
+	github.com/direct/lib v1.2.0
+	github.com/local/lib v0.0.0
+)
+
+replace github.com/direct/lib => github.com/fork/lib v1.2.1
+
+replace github.com/local/lib => ../lib
+`,
+			gosum: `github.com/fork/lib v1.2.1 h1:aaa=
+github.com/fork/lib v1.2.1/go.mod h1:bbb=
+`,
+			wantValid: true,
+		},
+		{
+			name:      "malformed line",
+			gomod:     "module example.com/app\n\ngo 1.22\n",
+			gosum:     "github.com/direct/lib v1.2.0\n",
+			wantValid: false,
+			want:      []string{"go.sum:1:0: malformed go.sum line"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte(tt.gomod), 0644); err != nil {
+				t.Fatalf("Failed to write go.mod: %v", err)
+			}
+			if tt.gosum != "" {
+				if err := os.WriteFile(filepath.Join(tmpDir, "go.sum"), []byte(tt.gosum), 0644); err != nil {
+					t.Fatalf("Failed to write go.sum: %v", err)
+				}
+			}
+
+			v := NewValidator(tmpDir).WithConfig(&ValidatorConfig{Rules: map[string]bool{"mod_directive": false}})
+			result, err := v.CheckModule(context.Background())
+			if err != nil {
+				t.Fatalf("Failed to check module: %v", err)
+			}
+			if result.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v", result.Valid, tt.wantValid)
+			}
+
+			var got []string
+			got = append(got, result.Errors...)
+			for _, w := range result.Warnings {
+				got = append(got, warningError(w))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findings = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckModuleTidiedGoSum(t *testing.T) {
+	// The go.mod and go.sum of this module, as left by go mod tidy, keep
+	// checksums of modules needed only by the pruned graph and by tests
+	tmpDir := t.TempDir()
+	for _, name := range []string{"go.mod", "go.sum"} {
+		content, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", name, err)
+		}
+		if err := os.WriteFile(filepath.Join(tmpDir, name), content, 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	v := NewValidator(tmpDir).WithConfig(&ValidatorConfig{Rules: map[string]bool{"mod_directive": false}})
+	result, err := v.CheckModule(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to check module: %v", err)
+	}
+	if !result.Valid || len(result.Errors) > 0 || len(result.Warnings) > 0 {
+		t.Errorf("CheckModule() = %q, %+v, want no findings", result.Errors, result.Warnings)
+	}
+}
+
+func TestCheckModuleDirectives(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, "lib"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	gomod := `module example.com/app
+
+go 1.22
+
+replace github.com/direct/lib v1.2.0 => github.com/fork/lib v1.2.1
+
+replace (
+	example.com/lib => ./lib
+	example.com/gone => ../gone
+)
+
+exclude github.com/broken/lib v0.4.0
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte(gomod), 0644); err != nil {
+		t.Fatalf("Failed to write go.mod: %v", err)
+	}
+
+	result, err := NewValidator(tmpDir).CheckModule(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to check module: %v", err)
+	}
+	if result.Valid {
+		t.Error("Expected the missing replacement directory to invalidate the module")
+	}
+
+	wantErrors := []string{"go.mod:9:2: replace example.com/gone => ../gone uses a local directory that does not exist"}
+	if !reflect.DeepEqual(result.Errors, wantErrors) {
+		t.Errorf("Errors = %q, want %q", result.Errors, wantErrors)
+	}
+
+	want := map[string]Severity{
+		"replace github.com/direct/lib v1.2.0 => github.com/fork/lib v1.2.1": SeverityInfo,
+		"replace example.com/lib => ./lib uses a local directory":            SeverityWarning,
+		"exclude github.com/broken/lib v0.4.0":                               SeverityInfo,
+	}
+	got := make(map[string]Severity)
+	for _, w := range result.Warnings {
+		if w.Type != "mod_directive" {
+			t.Errorf("Unexpected finding of %s: %s", w.Type, w.Message)
+			continue
+		}
+		got[w.Message] = w.Severity
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("warnings = %v, want %v", got, want)
+	}
+}
+
+func TestCheckModuleWithoutGoMod(t *testing.T) {
+	_, err := NewValidator(t.TempDir()).CheckModule(context.Background())
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("CheckModule() error = %v, want ErrNotFound", err)
+	}
+}
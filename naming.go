@@ -0,0 +1,188 @@
+package readgo
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// maxPackageNameLen is the longest package name the naming rule accepts
+const maxPackageNameLen = 15
+
+// commonInitialisms are the words Go names spell in a single case, as
+// listed by golint
+var commonInitialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true,
+	"DNS": true, "EOF": true, "GUID": true, "HTML": true, "HTTP": true,
+	"HTTPS": true, "ID": true, "IP": true, "JSON": true, "LHS": true,
+	"QPS": true, "RAM": true, "RHS": true, "RPC": true, "SLA": true,
+	"SMTP": true, "SQL": true, "SSH": true, "TCP": true, "TLS": true,
+	"TTL": true, "UDP": true, "UI": true, "UID": true, "UUID": true,
+	"URI": true, "URL": true, "UTF8": true, "VM": true, "XML": true,
+	"XMPP": true, "XSRF": true, "XSS": true,
+}
+
+// namingRule checks Go naming conventions. Its findings use the check names
+// underscore_name, initialism, package_name and receiver_name so each
+// convention can be turned off on its own.
+type namingRule struct{}
+
+// Name returns the rule name
+func (namingRule) Name() string {
+	return "naming"
+}
+
+// Check reports the naming problems in the package
+func (r namingRule) Check(ctx context.Context, pkg *packages.Package) []Finding {
+	var findings []Finding
+	report := func(check string, node ast.Node, format string, args ...interface{}) {
+		pos := pkg.Fset.Position(node.Pos())
+		findings = append(findings, Finding{
+			Rule:    check,
+			Message: fmt.Sprintf(format, args...),
+			File:    pos.Filename,
+			Line:    pos.Line,
+			Column:  pos.Column,
+		})
+	}
+
+	if len(pkg.Syntax) > 0 {
+		clause := pkg.Syntax[0].Name
+		if problem := packageNameProblem(clause.Name); problem != "" {
+			report("package_name", clause, "package name %s %s", clause.Name, problem)
+		}
+	}
+
+	for _, file := range pkg.Syntax {
+		if ctx.Err() != nil {
+			break
+		}
+		isTest := strings.HasSuffix(pkg.Fset.File(file.Pos()).Name(), "_test.go")
+		ast.Inspect(file, func(n ast.Node) bool {
+			id, ok := n.(*ast.Ident)
+			if !ok || id == file.Name || pkg.TypesInfo == nil || pkg.TypesInfo.Defs[id] == nil {
+				return true
+			}
+			if strings.Contains(strings.Trim(id.Name, "_"), "_") {
+				if !isTest || !isTestFuncName(id.Name) {
+					report("underscore_name", id, "name %s should not contain underscores", id.Name)
+				}
+				return true
+			}
+			if fixed := fixInitialisms(id.Name); fixed != id.Name {
+				report("initialism", id, "name %s should be %s", id.Name, fixed)
+			}
+			return true
+		})
+	}
+
+	checkReceiverNames(pkg, report)
+	return findings
+}
+
+// checkReceiverNames reports methods whose receiver is named differently
+// from the first method of the same type, and receivers named this or self
+func checkReceiverNames(pkg *packages.Package, report func(string, ast.Node, string, ...interface{})) {
+	var methods []*ast.FuncDecl
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv != nil && len(fn.Recv.List) > 0 && len(fn.Recv.List[0].Names) > 0 {
+				methods = append(methods, fn)
+			}
+		}
+	}
+	sort.SliceStable(methods, func(i, j int) bool {
+		pi, pj := pkg.Fset.Position(methods[i].Pos()), pkg.Fset.Position(methods[j].Pos())
+		if pi.Filename != pj.Filename {
+			return pi.Filename < pj.Filename
+		}
+		return pi.Offset < pj.Offset
+	})
+
+	first := make(map[string]string)
+	for _, fn := range methods {
+		recv := fn.Recv.List[0].Names[0]
+		if recv.Name == "_" {
+			continue
+		}
+		typeName := strings.TrimSuffix(funcDisplayName(fn), "."+fn.Name.Name)
+		if recv.Name == "this" || recv.Name == "self" {
+			report("receiver_name", recv, "receiver name %s should be a short name reflecting %s", recv.Name, typeName)
+			continue
+		}
+		if name, ok := first[typeName]; !ok {
+			first[typeName] = recv.Name
+		} else if name != recv.Name {
+			report("receiver_name", recv, "receiver name %s should be consistent with previous receiver name %s for %s", recv.Name, name, typeName)
+		}
+	}
+}
+
+// packageNameProblem describes what is wrong with a package name, if anything
+func packageNameProblem(name string) string {
+	name = strings.TrimSuffix(name, "_test")
+	switch {
+	case strings.ToLower(name) != name:
+		return "should be lowercase"
+	case strings.Contains(name, "_"):
+		return "should not contain underscores"
+	case len(name) > maxPackageNameLen:
+		return fmt.Sprintf("should be at most %d characters", maxPackageNameLen)
+	}
+	return ""
+}
+
+// isTestFuncName reports whether name is a test, benchmark, example or fuzz
+// function, whose names may use underscores
+func isTestFuncName(name string) bool {
+	for _, prefix := range []string{"Test", "Benchmark", "Example", "Fuzz"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// fixInitialisms returns name with the common initialisms among its words
+// spelled in a single case, e.g. userId becomes userID
+func fixInitialisms(name string) string {
+	words := splitCamel(name)
+	for i, w := range words {
+		upper := strings.ToUpper(w)
+		if !commonInitialisms[upper] || w == upper {
+			continue
+		}
+		// A leading lowercase word is fine in unexported names
+		if i == 0 && w == strings.ToLower(w) {
+			continue
+		}
+		words[i] = upper
+	}
+	return strings.Join(words, "")
+}
+
+// splitCamel splits a camel case identifier into words. A run of capitals
+// forms one word, except for the last capital when a lowercase letter
+// follows it: HTTPServer splits into HTTP and Server.
+func splitCamel(name string) []string {
+	runes := []rune(name)
+	var words []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+		boundary := unicode.IsUpper(cur) && !unicode.IsUpper(prev)
+		if unicode.IsUpper(prev) && unicode.IsUpper(cur) && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			boundary = true
+		}
+		if boundary {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	return append(words, string(runes[start:]))
+}
@@ -0,0 +1,281 @@
+package readgo
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Location is a range of a source file. Lines and columns are 1-based, and
+// columns count bytes, as in go/token.
+type Location struct {
+	File      string `json:"file" yaml:"file"` // Relative to the analyzer's working directory when inside it
+	Line      int    `json:"line" yaml:"line"`
+	Column    int    `json:"column" yaml:"column"`
+	EndLine   int    `json:"end_line" yaml:"end_line"`
+	EndColumn int    `json:"end_column" yaml:"end_column"`
+}
+
+// SymbolKind classifies a declaration of a file outline
+type SymbolKind string
+
+const (
+	// SymbolKindType is a type other than a struct or interface
+	SymbolKindType SymbolKind = "type"
+	// SymbolKindStruct is a struct type
+	SymbolKindStruct SymbolKind = "struct"
+	// SymbolKindInterface is an interface type
+	SymbolKindInterface SymbolKind = "interface"
+	// SymbolKindFunc is a function
+	SymbolKindFunc SymbolKind = "func"
+	// SymbolKindMethod is a method, declared or of an interface
+	SymbolKindMethod SymbolKind = "method"
+	// SymbolKindField is a struct field
+	SymbolKindField SymbolKind = "field"
+	// SymbolKindVar is a package-level variable
+	SymbolKindVar SymbolKind = "var"
+	// SymbolKindConst is a package-level constant
+	SymbolKindConst SymbolKind = "const"
+)
+
+// Symbol is a declaration in a file outline
+type Symbol struct {
+	Name      string     `json:"name" yaml:"name"`
+	Kind      SymbolKind `json:"kind" yaml:"kind"`
+	Detail    string     `json:"detail,omitempty" yaml:"detail,omitempty"` // Signature of functions, type of fields and variables
+	Location  Location   `json:"location" yaml:"location"`                 // The whole declaration
+	Selection Location   `json:"selection" yaml:"selection"`               // The declared name
+	Children  []Symbol   `json:"children,omitempty" yaml:"children,omitempty"`
+}
+
+// FileSymbols returns the outline of a Go source file: its types with their
+// fields and methods, functions, variables and constants, in source order.
+// Declared methods are listed at the top level with their receiver in Detail.
+func (a *DefaultAnalyzer) FileSymbols(ctx context.Context, filePath string) ([]Symbol, error) {
+	content, err := a.reader.ReadSourceFile(ctx, filePath, ReadOptions{IncludeComments: true})
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filePath, content, parser.SkipObjectResolution)
+	if err != nil && file == nil {
+		return nil, &AnalysisError{Op: "parse file", Path: filePath, Wrapped: err}
+	}
+
+	loc := func(n ast.Node) Location { return a.location(fset, n.Pos(), n.End()) }
+	symbols := make([]Symbol, 0)
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			s := Symbol{Name: d.Name.Name, Kind: SymbolKindFunc, Detail: funcSignature(fset, d), Location: loc(d), Selection: loc(d.Name)}
+			if d.Recv != nil {
+				s.Kind = SymbolKindMethod
+			}
+			symbols = append(symbols, s)
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch sp := spec.(type) {
+				case *ast.TypeSpec:
+					symbols = append(symbols, typeSymbol(sp, loc))
+				case *ast.ValueSpec:
+					kind := SymbolKindVar
+					if d.Tok == token.CONST {
+						kind = SymbolKindConst
+					}
+					var detail string
+					if sp.Type != nil {
+						detail = types.ExprString(sp.Type)
+					}
+					for _, name := range sp.Names {
+						if name.Name == "_" {
+							continue
+						}
+						symbols = append(symbols, Symbol{Name: name.Name, Kind: kind, Detail: detail, Location: loc(sp), Selection: loc(name)})
+					}
+				}
+			}
+		}
+	}
+	return symbols, nil
+}
+
+// typeSymbol returns the symbol of a type declaration, with the fields of
+// structs and the methods of interfaces as children
+func typeSymbol(spec *ast.TypeSpec, loc func(ast.Node) Location) Symbol {
+	s := Symbol{Name: spec.Name.Name, Kind: SymbolKindType, Detail: types.ExprString(spec.Type), Location: loc(spec), Selection: loc(spec.Name)}
+	var fields *ast.FieldList
+	childKind := SymbolKindField
+	switch t := spec.Type.(type) {
+	case *ast.StructType:
+		s.Kind, s.Detail, fields = SymbolKindStruct, "", t.Fields
+	case *ast.InterfaceType:
+		s.Kind, s.Detail, fields, childKind = SymbolKindInterface, "", t.Methods, SymbolKindMethod
+	}
+	if fields == nil {
+		return s
+	}
+	for _, f := range fields.List {
+		detail := types.ExprString(f.Type)
+		if len(f.Names) == 0 { // Embedded field or interface
+			s.Children = append(s.Children, Symbol{Name: detail, Kind: SymbolKindField, Location: loc(f), Selection: loc(f.Type)})
+			continue
+		}
+		for _, name := range f.Names {
+			s.Children = append(s.Children, Symbol{Name: name.Name, Kind: childKind, Detail: detail, Location: loc(f), Selection: loc(name)})
+		}
+	}
+	return s
+}
+
+// Definition returns where the identifier at a position of a Go source
+// file is declared. It fails with ErrNotFound if no identifier is there or
+// it has no declaration, as for the universe's types.
+func (a *DefaultAnalyzer) Definition(ctx context.Context, filePath string, line, column int) (*Location, error) {
+	path := a.absPath(filePath)
+	pkgs, err := a.load(ctx, filepath.Dir(path), ".")
+	if err != nil {
+		return nil, &AnalysisError{Op: "load package", Path: filePath, Wrapped: err}
+	}
+	pkg, obj, err := objectAt(pkgs, path, line, column)
+	if err != nil {
+		return nil, err
+	}
+	if !obj.Pos().IsValid() {
+		return nil, fmt.Errorf("%w: %s has no declaration", ErrNotFound, obj.Name())
+	}
+	l := a.location(pkg.Fset, obj.Pos(), obj.Pos()+token.Pos(len(obj.Name())))
+	return &l, nil
+}
+
+// References returns the uses, and the declaration if includeDeclaration is
+// set, of the identifier at a position of a Go source file across the
+// packages of the working directory, sorted by file and position
+func (a *DefaultAnalyzer) References(ctx context.Context, filePath string, line, column int, includeDeclaration bool) ([]Location, error) {
+	path := a.absPath(filePath)
+	pkgs, err := a.load(ctx, a.workDir, "./...")
+	if err != nil {
+		return nil, &AnalysisError{Op: "load packages", Path: a.workDir, Wrapped: err}
+	}
+	_, obj, err := objectAt(pkgs, path, line, column)
+	if err != nil {
+		return nil, err
+	}
+
+	locations := make([]Location, 0)
+	seen := make(map[Location]bool)
+	add := func(fset *token.FileSet, id *ast.Ident) {
+		l := a.location(fset, id.Pos(), id.End())
+		if !seen[l] {
+			seen[l] = true
+			locations = append(locations, l)
+		}
+	}
+	for _, pkg := range pkgs {
+		if pkg.TypesInfo == nil {
+			continue
+		}
+		for id, use := range pkg.TypesInfo.Uses {
+			if sameObject(use, obj) {
+				add(pkg.Fset, id)
+			}
+		}
+		if !includeDeclaration {
+			continue
+		}
+		for id, def := range pkg.TypesInfo.Defs {
+			if def != nil && sameObject(def, obj) {
+				add(pkg.Fset, id)
+			}
+		}
+	}
+	sort.Slice(locations, func(i, j int) bool {
+		li, lj := locations[i], locations[j]
+		if li.File != lj.File {
+			return li.File < lj.File
+		}
+		if li.Line != lj.Line {
+			return li.Line < lj.Line
+		}
+		return li.Column < lj.Column
+	})
+	return locations, nil
+}
+
+// objectAt returns the object denoted or declared by the identifier at a
+// position of the file at path, and the package holding the file
+func objectAt(pkgs []*packages.Package, path string, line, column int) (*packages.Package, types.Object, error) {
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			tf := pkg.Fset.File(file.Pos())
+			if tf == nil || filepath.Clean(tf.Name()) != path {
+				continue
+			}
+			if line < 1 || line > tf.LineCount() || column < 1 {
+				return nil, nil, fmt.Errorf("%w: position %d:%d is outside %s", ErrInvalidInput, line, column, path)
+			}
+			pos := tf.LineStart(line) + token.Pos(column-1)
+			var ident *ast.Ident
+			ast.Inspect(file, func(n ast.Node) bool {
+				if n == nil || ident != nil || pos < n.Pos() || pos >= n.End() {
+					return false
+				}
+				if id, ok := n.(*ast.Ident); ok {
+					ident = id
+				}
+				return true
+			})
+			if ident == nil || pkg.TypesInfo == nil {
+				return nil, nil, fmt.Errorf("%w: no identifier at %d:%d", ErrNotFound, line, column)
+			}
+			obj := pkg.TypesInfo.ObjectOf(ident)
+			if obj == nil {
+				return nil, nil, fmt.Errorf("%w: %s does not denote an object", ErrNotFound, ident.Name)
+			}
+			return pkg, obj, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("%w: %s is not in a package of the project", ErrNotFound, path)
+}
+
+// sameObject reports whether a and b are the same object. Packages loaded
+// together share their objects; matching by package, name and position as
+// well covers a package type-checked more than once.
+func sameObject(a, b types.Object) bool {
+	if a == b {
+		return true
+	}
+	if a.Pkg() == nil || b.Pkg() == nil {
+		return false
+	}
+	return a.Pkg().Path() == b.Pkg().Path() && a.Name() == b.Name() && a.Pos() == b.Pos()
+}
+
+// absPath returns path, cleaned, made absolute against the working directory
+func (a *DefaultAnalyzer) absPath(path string) string {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(a.workDir, path)
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
+
+// location returns the location of the range from pos to end
+func (a *DefaultAnalyzer) location(fset *token.FileSet, pos, end token.Pos) Location {
+	start, stop := fset.Position(pos), fset.Position(end)
+	return Location{
+		File:      a.relPath(start.Filename),
+		Line:      start.Line,
+		Column:    start.Column,
+		EndLine:   stop.Line,
+		EndColumn: stop.Column,
+	}
+}
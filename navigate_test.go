@@ -0,0 +1,145 @@
+package readgo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestNavigation(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/nav\n\ngo 1.22\n",
+		"store/store.go": `package store
+
+// Store keeps things
+type Store struct {
+	Name string
+	items map[string]string
+}
+
+// Getter gets things
+type Getter interface {
+	Get(key string) string
+}
+
+const Default = "default"
+
+// Open opens a store
+func Open() *Store { return &Store{Name: Default} }
+
+// Get returns the item at key
+func (s *Store) Get(key string) string { return s.items[key] }
+`,
+		"api/api.go": `package api
+
+import "example.com/nav/store"
+
+var s = store.Open()
+
+func Get() string { return s.Get(store.Default) }
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+	analyzer := NewAnalyzer(WithWorkDir(tmpDir))
+	ctx := context.Background()
+
+	t.Run("FileSymbols", func(t *testing.T) {
+		symbols, err := analyzer.FileSymbols(ctx, "store/store.go")
+		if err != nil {
+			t.Fatalf("Failed to list symbols: %v", err)
+		}
+		var got []string
+		for _, s := range symbols {
+			got = append(got, string(s.Kind)+" "+s.Name)
+			for _, c := range s.Children {
+				got = append(got, "  "+string(c.Kind)+" "+c.Name+" "+c.Detail)
+			}
+		}
+		want := []string{
+			"struct Store",
+			"  field Name string",
+			"  field items map[string]string",
+			"interface Getter",
+			"  method Get func(key string) string",
+			"const Default",
+			"func Open",
+			"method Get",
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("FileSymbols() = %q, want %q", got, want)
+		}
+		open := symbols[3]
+		wantLoc := Location{File: "store/store.go", Line: 17, Column: 6, EndLine: 17, EndColumn: 10}
+		if open.Detail != "func Open() *Store" || open.Selection != wantLoc {
+			t.Errorf("Open = %q at %+v, want func Open() *Store at %+v", open.Detail, open.Selection, wantLoc)
+		}
+	})
+
+	t.Run("Definition", func(t *testing.T) {
+		tests := []struct {
+			name         string
+			line, column int
+			want         Location
+			wantErr      error
+		}{
+			{name: "function of another package", line: 5, column: 15, want: Location{File: "store/store.go", Line: 17, Column: 6, EndLine: 17, EndColumn: 10}},
+			{name: "method", line: 7, column: 30, want: Location{File: "store/store.go", Line: 20, Column: 17, EndLine: 20, EndColumn: 20}},
+			{name: "package variable", line: 7, column: 28, want: Location{File: "api/api.go", Line: 5, Column: 5, EndLine: 5, EndColumn: 6}},
+			{name: "universe type", line: 7, column: 12, wantErr: ErrNotFound},
+			{name: "no identifier", line: 2, column: 1, wantErr: ErrNotFound},
+			{name: "outside the file", line: 99, column: 1, wantErr: ErrInvalidInput},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got, err := analyzer.Definition(ctx, "api/api.go", tt.line, tt.column)
+				if tt.wantErr != nil {
+					if !errors.Is(err, tt.wantErr) {
+						t.Errorf("Definition() error = %v, want %v", err, tt.wantErr)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("Failed to find definition: %v", err)
+				}
+				if *got != tt.want {
+					t.Errorf("Definition() = %+v, want %+v", *got, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("References", func(t *testing.T) {
+		got, err := analyzer.References(ctx, "store/store.go", 14, 7, true)
+		if err != nil {
+			t.Fatalf("Failed to find references: %v", err)
+		}
+		want := []Location{
+			{File: "api/api.go", Line: 7, Column: 40, EndLine: 7, EndColumn: 47},
+			{File: "store/store.go", Line: 14, Column: 7, EndLine: 14, EndColumn: 14},
+			{File: "store/store.go", Line: 17, Column: 42, EndLine: 17, EndColumn: 49},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("References() = %+v, want %+v", got, want)
+		}
+
+		got, err = analyzer.References(ctx, "store/store.go", 14, 7, false)
+		if err != nil {
+			t.Fatalf("Failed to find references: %v", err)
+		}
+		if len(got) != 2 {
+			t.Errorf("References() without declaration = %+v, want the 2 uses", got)
+		}
+	})
+}
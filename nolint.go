@@ -0,0 +1,117 @@
+package readgo
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// nolintDirective is a //nolint comment and the lines it covers
+type nolintDirective struct {
+	startLine int
+	endLine   int
+	codes     []string // empty suppresses every check
+}
+
+// nolintIndex holds the //nolint directives of a set of files by file name
+type nolintIndex map[string][]nolintDirective
+
+// addFile records the //nolint directives of a parsed file. A directive
+// covers its own line and, when it is part of a declaration's doc comment or
+// trails the declaration's first line, the whole declaration.
+func (idx nolintIndex) addFile(fset *token.FileSet, file *ast.File) {
+	name := fset.File(file.Pos()).Name()
+	for _, group := range file.Comments {
+		for _, c := range group.List {
+			codes, ok := parseNolint(c.Text)
+			if !ok {
+				continue
+			}
+			line := fset.Position(c.Pos()).Line
+			d := nolintDirective{startLine: line, endLine: line, codes: codes}
+			for _, node := range declNodes(file) {
+				start, end := fset.Position(node.Pos()).Line, fset.Position(node.End()).Line
+				if start == line || docOf(node) == group {
+					d.startLine, d.endLine = min(d.startLine, start), max(d.endLine, end)
+				}
+			}
+			idx[name] = append(idx[name], d)
+		}
+	}
+}
+
+// suppressed reports whether a finding of the named check at file:line is
+// suppressed by a directive
+func (idx nolintIndex) suppressed(file string, line int, check string) bool {
+	for _, d := range idx[file] {
+		if line < d.startLine || line > d.endLine {
+			continue
+		}
+		if len(d.codes) == 0 {
+			return true
+		}
+		for _, code := range d.codes {
+			if strings.EqualFold(code, check) || strings.EqualFold(code, "all") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseNolint parses a "//nolint" or "//nolint:a,b // reason" comment
+func parseNolint(text string) ([]string, bool) {
+	rest, ok := strings.CutPrefix(text, "//nolint")
+	if !ok {
+		return nil, false
+	}
+	if i := strings.Index(rest, "//"); i >= 0 {
+		rest = rest[:i]
+	}
+	rest = strings.TrimSpace(rest)
+	if rest == "" {
+		return nil, true
+	}
+	list, ok := strings.CutPrefix(rest, ":")
+	if !ok {
+		return nil, false
+	}
+
+	var codes []string
+	for _, code := range strings.Split(list, ",") {
+		if code = strings.TrimSpace(code); code != "" {
+			codes = append(codes, code)
+		}
+	}
+	return codes, true
+}
+
+// declNodes returns the top-level declarations of file and the specs of its
+// grouped declarations
+func declNodes(file *ast.File) []ast.Node {
+	var nodes []ast.Node
+	for _, decl := range file.Decls {
+		nodes = append(nodes, decl)
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Lparen.IsValid() {
+			for _, spec := range gen.Specs {
+				nodes = append(nodes, spec)
+			}
+		}
+	}
+	return nodes
+}
+
+// docOf returns the doc comment of a declaration node, if any
+func docOf(node ast.Node) *ast.CommentGroup {
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		return n.Doc
+	case *ast.GenDecl:
+		return n.Doc
+	case *ast.TypeSpec:
+		return n.Doc
+	case *ast.ValueSpec:
+		return n.Doc
+	}
+	return nil
+}
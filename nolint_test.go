@@ -0,0 +1,73 @@
+package readgo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseNolint(t *testing.T) {
+	tests := []struct {
+		text      string
+		wantCodes []string
+		wantOK    bool
+	}{
+		{"//nolint", nil, true},
+		{"//nolint:unused_import", []string{"unused_import"}, true},
+		{"//nolint:a, b // legacy code", []string{"a", "b"}, true},
+		{"// nolint", nil, false},
+		{"//nolintx", nil, false},
+		{"// regular comment", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			codes, ok := parseNolint(tt.text)
+			if ok != tt.wantOK || !reflect.DeepEqual(codes, tt.wantCodes) {
+				t.Errorf("parseNolint() = %v, %v, want %v, %v", codes, ok, tt.wantCodes, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestNolintSuppression(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := `package test
+
+import _ "embed" //nolint:blank_import
+
+//nolint:todo_func // tracked elsewhere
+func todo() {}
+
+func Other() {
+	todo()
+}
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "nolint.go"), []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	content = "package test\n\nimport _ \"unsafe\"\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "other.go"), []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	validator := NewValidator(tmpDir)
+	validator.RegisterRule(todoRule{})
+
+	result, err := validator.ValidatePackage(context.Background(), ".", ValidationLevelStandard)
+	if err != nil {
+		t.Fatalf("ValidatePackage() error = %v", err)
+	}
+	if len(result.Warnings) != 1 || result.Warnings[0].File != "other.go" {
+		t.Errorf("ValidatePackage() warnings = %+v, want only the other.go import", result.Warnings)
+	}
+	want := ValidationStats{
+		Suppressed:       2,
+		SuppressedByRule: map[string]int{"blank_import": 1, "todo_func": 1},
+	}
+	if !reflect.DeepEqual(result.Stats, want) {
+		t.Errorf("ValidatePackage() stats = %+v, want %+v", result.Stats, want)
+	}
+}
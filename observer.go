@@ -0,0 +1,20 @@
+package readgo
+
+import "time"
+
+// Observer is told about the work of analyzers and validators as it is
+// done, e.g. to export metrics. Its methods may be called concurrently and
+// should return quickly.
+type Observer interface {
+	// PackagesLoaded reports a call to packages.Load that took d
+	PackagesLoaded(d time.Duration, err error)
+
+	// AnalysisDone reports an analysis of a "project", "package" or "file"
+	// that took d. Results answered from the cache are not reported.
+	AnalysisDone(kind string, d time.Duration, err error)
+
+	// ValidationDone reports a completed validation that took d. Results
+	// answered from the cache are not reported, and result must not be
+	// modified.
+	ValidationDone(result *ValidationResult, d time.Duration)
+}
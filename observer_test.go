@@ -0,0 +1,70 @@
+package readgo
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingObserver records what it is told
+type recordingObserver struct {
+	mu          sync.Mutex
+	loads       int
+	analyses    []string
+	validations []*ValidationResult
+}
+
+func (o *recordingObserver) PackagesLoaded(time.Duration, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.loads++
+}
+
+func (o *recordingObserver) AnalysisDone(kind string, _ time.Duration, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if err != nil {
+		kind += " failed"
+	}
+	o.analyses = append(o.analyses, kind)
+}
+
+func (o *recordingObserver) ValidationDone(result *ValidationResult, _ time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.validations = append(o.validations, result)
+}
+
+func TestWithObserver(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTestFiles(t, tmpDir)
+	ctx := context.Background()
+	observer := &recordingObserver{}
+
+	analyzer := NewAnalyzer(WithWorkDir(tmpDir), WithCacheTTL(time.Minute), WithObserver(observer))
+	for i := 0; i < 2; i++ {
+		if _, err := analyzer.AnalyzePackage(ctx, "./testdata/basic"); err != nil {
+			t.Fatalf("Failed to analyze package: %v", err)
+		}
+	}
+	if _, err := analyzer.AnalyzeFile(ctx, "testdata/basic/missing.go"); err == nil {
+		t.Fatal("AnalyzeFile() of a missing file succeeded")
+	}
+	validator := NewValidator(tmpDir, WithObserver(observer))
+	for i := 0; i < 2; i++ {
+		if _, err := validator.ValidatePackage(ctx, "testdata/basic", ValidationLevelBasic); err != nil {
+			t.Fatalf("Failed to validate package: %v", err)
+		}
+	}
+
+	if observer.loads != 2 {
+		t.Errorf("observed %d package loads, want 2", observer.loads)
+	}
+	if len(observer.analyses) != 2 || observer.analyses[0] != "package" || observer.analyses[1] != "file failed" {
+		t.Errorf("observed analyses %v, want [package, file failed]", observer.analyses)
+	}
+	if len(observer.validations) != 1 || observer.validations[0].Level != ValidationLevelBasic {
+		t.Errorf("observed %d validations, want the first one only", len(observer.validations))
+	}
+}
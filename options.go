@@ -1,6 +1,11 @@
 package readgo
 
-import "time"
+import (
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
 
 // AnalyzerOptions configures the behavior of the analyzer
 type AnalyzerOptions struct {
@@ -11,10 +16,37 @@ type AnalyzerOptions struct {
 	// If zero, caching is disabled
 	CacheTTL time.Duration
 
-	// MaxCacheSize is the maximum number of entries in each cache type
+	// StdlibCacheTTL is the time-to-live for cached lookups of standard
+	// library types and functions, which only change with the Go installation
+	// If zero, CacheTTL applies
+	StdlibCacheTTL time.Duration
+
+	// NotFoundCacheTTL is the time-to-live for cached lookups that found
+	// nothing, kept short since the symbol may be about to be written
+	// If zero, such lookups are not cached
+	NotFoundCacheTTL time.Duration
+
+	// MaxCacheSize is the maximum number of entries in each cache type;
+	// the least recently used entries are evicted beyond it
 	// If zero, no limit is applied
 	MaxCacheSize int
 
+	// MaxCacheBytes is the approximate maximum size of all cached entries
+	// together; the least recently used entries are evicted beyond it
+	// If zero, no limit is applied
+	MaxCacheBytes int64
+
+	// Cache is a cache shared with other analyzers, so that they reuse each
+	// other's lookups and loads; CacheTTL, MaxCacheSize and MaxCacheBytes then
+	// come from it
+	// If nil, each analyzer creates its own
+	Cache *Cache
+
+	// DiskCacheDir is a directory where type lookups and analysis results
+	// are kept between process runs
+	// If empty, nothing is written to disk
+	DiskCacheDir string
+
 	// AnalysisTimeout is the timeout for analysis operations
 	// If zero, no timeout is applied
 	AnalysisTimeout time.Duration
@@ -25,13 +57,38 @@ type AnalyzerOptions struct {
 	// MaxConcurrentAnalysis is the maximum number of concurrent analyses
 	// If zero, defaults to runtime.NumCPU()
 	MaxConcurrentAnalysis int
+
+	// Extractors are custom extractors run over each analyzed package
+	Extractors []Extractor
+
+	// Progress is called by the validator as packages are checked
+	Progress ProgressFunc
+
+	// Logger receives debug messages about package loads and caching, and
+	// the most detailed ones at LevelTrace
+	// If nil, nothing is logged
+	Logger *slog.Logger
+
+	// TracerProvider provides the tracer of the spans started around
+	// package loads, cache lookups, tree walks and validation passes
+	// If nil, no spans are recorded
+	TracerProvider trace.TracerProvider
+
+	// Observer is told about package loads, analyses and validations
+	// If nil, nothing is reported
+	Observer Observer
 }
 
+// ProgressFunc reports that done of total packages have been checked,
+// currentPkg being the one just finished. Calls are never concurrent.
+type ProgressFunc func(done, total int, currentPkg string)
+
 // DefaultOptions returns the default analyzer options
 func DefaultOptions() *AnalyzerOptions {
 	return &AnalyzerOptions{
 		WorkDir:                  ".",
 		CacheTTL:                 5 * time.Minute,
+		NotFoundCacheTTL:         30 * time.Second,
 		MaxCacheSize:             1000,
 		AnalysisTimeout:          30 * time.Second,
 		EnableConcurrentAnalysis: true,
@@ -56,6 +113,20 @@ func WithCacheTTL(ttl time.Duration) Option {
 	}
 }
 
+// WithStdlibCacheTTL sets the cache TTL of standard library lookups
+func WithStdlibCacheTTL(ttl time.Duration) Option {
+	return func(o *AnalyzerOptions) {
+		o.StdlibCacheTTL = ttl
+	}
+}
+
+// WithNotFoundCacheTTL sets the cache TTL of lookups that found nothing
+func WithNotFoundCacheTTL(ttl time.Duration) Option {
+	return func(o *AnalyzerOptions) {
+		o.NotFoundCacheTTL = ttl
+	}
+}
+
 // WithMaxCacheSize sets the maximum cache size
 func WithMaxCacheSize(size int) Option {
 	return func(o *AnalyzerOptions) {
@@ -63,6 +134,28 @@ func WithMaxCacheSize(size int) Option {
 	}
 }
 
+// WithMaxCacheBytes sets the approximate maximum size of the cache in bytes
+func WithMaxCacheBytes(size int64) Option {
+	return func(o *AnalyzerOptions) {
+		o.MaxCacheBytes = size
+	}
+}
+
+// WithCache makes analyzers share cache, see NewCache
+func WithCache(cache *Cache) Option {
+	return func(o *AnalyzerOptions) {
+		o.Cache = cache
+	}
+}
+
+// WithDiskCache keeps type lookups and analysis results in dir, so that
+// later processes analyzing unchanged sources skip loading packages
+func WithDiskCache(dir string) Option {
+	return func(o *AnalyzerOptions) {
+		o.DiskCacheDir = dir
+	}
+}
+
 // WithAnalysisTimeout sets the analysis timeout
 func WithAnalysisTimeout(timeout time.Duration) Option {
 	return func(o *AnalyzerOptions) {
@@ -83,3 +176,40 @@ func WithMaxConcurrentAnalysis(max int) Option {
 		o.MaxConcurrentAnalysis = max
 	}
 }
+
+// WithExtractor registers a custom extractor
+func WithExtractor(e Extractor) Option {
+	return func(o *AnalyzerOptions) {
+		o.Extractors = append(o.Extractors, e)
+	}
+}
+
+// WithProgress sets the function the validator reports its progress to
+func WithProgress(fn ProgressFunc) Option {
+	return func(o *AnalyzerOptions) {
+		o.Progress = fn
+	}
+}
+
+// WithLogger sets the logger of the analyzer or validator and of the
+// reader the analyzer uses
+func WithLogger(l *slog.Logger) Option {
+	return func(o *AnalyzerOptions) {
+		o.Logger = l
+	}
+}
+
+// WithTracerProvider records OpenTelemetry spans of package loads, cache
+// lookups, tree walks and validation passes with tp
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(o *AnalyzerOptions) {
+		o.TracerProvider = tp
+	}
+}
+
+// WithObserver reports package loads, analyses and validations to o
+func WithObserver(o Observer) Option {
+	return func(opts *AnalyzerOptions) {
+		opts.Observer = o
+	}
+}
@@ -3,19 +3,35 @@ package readgo
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"go/ast"
 	"go/parser"
+	"go/printer"
 	"go/token"
+	"go/types"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/tools/go/packages"
 )
 
 // DefaultReader implements SourceReader
 type DefaultReader struct {
 	workDir string
+	root    string // if set, all paths must resolve inside this directory
+	logger  *slog.Logger
+	tp      trace.TracerProvider
 }
 
 // NewSourceReader creates a new DefaultReader instance
@@ -31,15 +47,11 @@ func (r *DefaultReader) ReadSourceFile(ctx context.Context, path string, opts Re
 		return nil, fmt.Errorf("empty path")
 	}
 
-	// Convert to absolute path if needed
-	absPath := path
-	if !filepath.IsAbs(path) {
-		absPath = filepath.Join(r.workDir, path)
+	absPath, err := r.resolvePath(path)
+	if err != nil {
+		return nil, err
 	}
 
-	// Clean the path
-	absPath = filepath.Clean(absPath)
-
 	// Verify file exists and get info
 	info, err := os.Stat(absPath)
 	if err != nil {
@@ -56,13 +68,13 @@ func (r *DefaultReader) ReadSourceFile(ctx context.Context, path string, opts Re
 	if err != nil {
 		return nil, err
 	}
-
-	// Apply options
-	if opts.StripSpaces {
-		content = bytes.TrimSpace(content)
+	r.log().Log(ctx, LevelTrace, "read file", "path", absPath, "bytes", len(content))
+	content, err = decodeContent(path, content, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	return content, nil
+	return applyReadOptions(path, content, opts)
 }
 
 // NewDefaultReader creates a new DefaultReader instance
@@ -70,13 +82,96 @@ func NewDefaultReader() *DefaultReader {
 	return &DefaultReader{}
 }
 
-// WithWorkDir sets the working directory for the reader
+// NewJailedReader creates a DefaultReader confined to root. Every path it is
+// asked to read or walk must resolve inside root, even after following
+// symlinks, so readers handed to different sessions cannot see each other's
+// files.
+func NewJailedReader(root string) (*DefaultReader, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	absRoot, err = filepath.EvalSymlinks(absRoot)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(absRoot)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%w: root is not a directory: %s", ErrInvalidInput, root)
+	}
+
+	return &DefaultReader{
+		workDir: absRoot,
+		root:    absRoot,
+	}, nil
+}
+
+// resolvePath converts path to a clean absolute path. For jailed readers the
+// result is checked against the root both before and after resolving symlinks.
+func (r *DefaultReader) resolvePath(path string) (string, error) {
+	absPath := path
+	if !filepath.IsAbs(path) {
+		absPath = filepath.Join(r.workDir, path)
+	}
+	absPath = filepath.Clean(absPath)
+
+	if r.root == "" {
+		return absPath, nil
+	}
+
+	if err := validatePath(r.root, absPath); err != nil {
+		return "", err
+	}
+
+	resolved, err := filepath.EvalSymlinks(absPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// Nothing to follow; the caller reports the missing file
+			return absPath, nil
+		}
+		return "", err
+	}
+	if err := validatePath(r.root, resolved); err != nil {
+		return "", err
+	}
+
+	return resolved, nil
+}
+
+// WithWorkDir sets the working directory for the reader. A jailed reader
+// resolves dir against its current working directory and stays confined to
+// its root, so paths under a dir outside the root cannot be read.
 func (r *DefaultReader) WithWorkDir(dir string) *DefaultReader {
+	if r.root != "" && !filepath.IsAbs(dir) {
+		dir = filepath.Join(r.workDir, dir)
+	}
 	r.workDir = dir
 	return r
 }
 
-// ReadFileWithFunctions reads a source file and returns its content along with function positions
+// WithLogger sets the logger of the reader, which logs nothing by default
+func (r *DefaultReader) WithLogger(l *slog.Logger) *DefaultReader {
+	r.logger = l
+	return r
+}
+
+// log returns the logger of the reader
+func (r *DefaultReader) log() *slog.Logger {
+	return loggerOrDiscard(r.logger)
+}
+
+// WithTracerProvider records OpenTelemetry spans of the tree walks and
+// package loads of the reader with tp
+func (r *DefaultReader) WithTracerProvider(tp trace.TracerProvider) *DefaultReader {
+	r.tp = tp
+	return r
+}
+
+// ReadFileWithFunctions reads a source file and returns its content along with
+// the positions of its functions, types and constants
 func (r *DefaultReader) ReadFileWithFunctions(ctx context.Context, path string) (*FileContent, error) {
 	content, err := r.ReadSourceFile(ctx, path, ReadOptions{})
 	if err != nil {
@@ -90,24 +185,102 @@ func (r *DefaultReader) ReadFileWithFunctions(ctx context.Context, path string)
 		return nil, err
 	}
 
-	// Extract function positions
-	var functions []FunctionPosition
+	// Extract declaration positions
+	result := &FileContent{Content: content}
 	for _, decl := range file.Decls {
-		if fn, ok := decl.(*ast.FuncDecl); ok {
-			pos := fset.Position(fn.Pos())
-			end := fset.Position(fn.End())
-			functions = append(functions, FunctionPosition{
-				Name:      fn.Name.Name,
-				StartLine: pos.Line,
-				EndLine:   end.Line,
-			})
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			pos := fset.Position(d.Pos())
+			end := fset.Position(d.End())
+			fn := FunctionPosition{
+				Name:         d.Name.Name,
+				Signature:    funcSignature(fset, d),
+				IsExported:   d.Name.IsExported(),
+				StartLine:    pos.Line,
+				EndLine:      end.Line,
+				DocStartLine: pos.Line,
+			}
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				fn.Receiver = types.ExprString(d.Recv.List[0].Type)
+			}
+			if d.Doc != nil {
+				fn.DocStartLine = fset.Position(d.Doc.Pos()).Line
+			}
+			result.Functions = append(result.Functions, fn)
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				// Ungrouped declarations include the keyword line
+				start, end := spec.Pos(), spec.End()
+				if !d.Lparen.IsValid() {
+					start, end = d.Pos(), d.End()
+				}
+
+				switch sp := spec.(type) {
+				case *ast.TypeSpec:
+					result.Types = append(result.Types, DeclPosition{
+						Name:      sp.Name.Name,
+						StartLine: fset.Position(start).Line,
+						EndLine:   fset.Position(end).Line,
+					})
+				case *ast.ValueSpec:
+					if d.Tok != token.CONST {
+						continue
+					}
+					for _, name := range sp.Names {
+						result.Consts = append(result.Consts, DeclPosition{
+							Name:      name.Name,
+							StartLine: fset.Position(start).Line,
+							EndLine:   fset.Position(end).Line,
+						})
+					}
+				}
+			}
 		}
 	}
 
-	return &FileContent{
-		Content:   content,
-		Functions: functions,
-	}, nil
+	return result, nil
+}
+
+// funcSignature renders a function declaration without its doc comment and body
+func funcSignature(fset *token.FileSet, fn *ast.FuncDecl) string {
+	var buf bytes.Buffer
+	decl := &ast.FuncDecl{Recv: fn.Recv, Name: fn.Name, Type: fn.Type}
+	if err := printer.Fprint(&buf, fset, decl); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// parsePackageName returns the package name declared by a Go file, parsing
+// only its package clause. If src is nil the file is read from filename.
+// It returns "" if the file cannot be parsed.
+func parsePackageName(filename string, src interface{}) string {
+	file, err := parser.ParseFile(token.NewFileSet(), filename, src, parser.PackageClauseOnly)
+	if err != nil || file.Name == nil {
+		return ""
+	}
+	return file.Name.Name
+}
+
+// hashFile returns the hex-encoded SHA256 of the file at path
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashBytes returns the hex-encoded SHA256 of content
+func hashBytes(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
 }
 
 // isGeneratedFile checks if a file is generated based on its content
@@ -128,123 +301,305 @@ func isGeneratedFile(content []byte) bool {
 	return false
 }
 
-// findNode finds a node in the tree by its path
-func findNode(root *FileTreeNode, path string) *FileTreeNode {
-	if root.Path == path {
-		return root
+// SearchFiles searches for files matching the given pattern.
+// opts.Offset and opts.Limit select a page of the matches.
+func (r *DefaultReader) SearchFiles(ctx context.Context, pattern string, opts TreeOptions) ([]*FileTreeNode, error) {
+	page, err := r.SearchFilesPage(ctx, pattern, opts)
+	if err != nil {
+		return nil, err
 	}
+	return page.Files, nil
+}
 
-	for _, child := range root.Children {
-		if child.Type == "directory" {
-			if node := findNode(child, path); node != nil {
-				return node
-			}
-		}
+// SearchFilesPage searches for files matching the given pattern and returns
+// the page selected by opts.Offset and opts.Limit
+func (r *DefaultReader) SearchFilesPage(ctx context.Context, pattern string, opts TreeOptions) (*FilePage, error) {
+	if pattern == "" {
+		return nil, ErrInvalidInput
 	}
 
-	return nil
+	tree, err := r.GetFileTree(ctx, ".", opts.unpaged())
+	if err != nil {
+		return nil, err
+	}
+
+	return pageFiles(searchTree(tree, pattern), opts.Offset, opts.Limit), nil
 }
 
-// SearchFiles searches for files matching the given pattern
-func (r *DefaultReader) SearchFiles(ctx context.Context, pattern string, opts TreeOptions) ([]*FileTreeNode, error) {
+// SearchContent searches the contents of files under the work directory for
+// lines matching the regular expression pattern. Binary and generated files
+// are skipped. opts.Offset and opts.Limit select a page of the matches.
+func (r *DefaultReader) SearchContent(ctx context.Context, pattern string, opts TreeOptions) ([]ContentMatch, error) {
 	if pattern == "" {
 		return nil, ErrInvalidInput
 	}
 
-	tree, err := r.GetFileTree(ctx, ".", opts)
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	tree, err := r.GetFileTree(ctx, ".", opts.unpaged())
 	if err != nil {
 		return nil, err
 	}
 
-	var matches []*FileTreeNode
-	var search func(*FileTreeNode)
-	search = func(node *FileTreeNode) {
-		if node.Type == "file" && strings.Contains(node.Name, pattern) {
-			matches = append(matches, node)
+	var matches []ContentMatch
+	var search func(*FileTreeNode) error
+	search = func(node *FileTreeNode) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if opts.Limit > 0 && len(matches) >= opts.Offset+opts.Limit {
+			return nil
+		}
+
+		if node.Type == "file" && node.Size <= maxFileSize {
+			content, err := r.ReadSourceFile(ctx, node.Path, ReadOptions{Transcode: true})
+			if err != nil && !errors.Is(err, ErrBinaryFile) {
+				return err
+			}
+			if err == nil && !isGeneratedFile(content) {
+				matches = append(matches, searchLines(node.Path, content, re)...)
+			}
 		}
+
 		for _, child := range node.Children {
-			search(child)
+			if err := search(child); err != nil {
+				return err
+			}
 		}
+		return nil
+	}
+	if err := search(tree); err != nil {
+		return nil, err
+	}
+
+	// Apply paging
+	if opts.Offset >= len(matches) {
+		return nil, nil
+	}
+	matches = matches[max(opts.Offset, 0):]
+	if opts.Limit > 0 && len(matches) > opts.Limit {
+		matches = matches[:opts.Limit]
 	}
-	search(tree)
 
 	return matches, nil
 }
 
-// GetPackageFiles returns all files in a package
+// searchLines returns the lines of content matching re
+func searchLines(path string, content []byte, re *regexp.Regexp) []ContentMatch {
+	var matches []ContentMatch
+	for i, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		groups := re.FindStringSubmatch(line)
+		if groups == nil {
+			continue
+		}
+		matches = append(matches, ContentMatch{
+			File:       path,
+			Line:       i + 1,
+			Text:       line,
+			Submatches: groups[1:],
+		})
+	}
+	return matches
+}
+
+// GetPackageFiles returns all files in a package. With opts.LocalImports,
+// the files of every package it transitively imports from the same module
+// are appended, giving the full source closure of the package.
 func (r *DefaultReader) GetPackageFiles(ctx context.Context, pkgPath string, opts TreeOptions) ([]*FileTreeNode, error) {
 	tree, err := r.GetFileTree(ctx, pkgPath, opts)
 	if err != nil {
 		return nil, err
 	}
+	files := collectFiles(tree)
 
-	var files []*FileTreeNode
-	var collect func(*FileTreeNode)
-	collect = func(node *FileTreeNode) {
-		if node.Type == "file" {
-			files = append(files, node)
+	if !opts.LocalImports {
+		return files, nil
+	}
+
+	dirs, err := r.localImportDirs(ctx, pkgPath)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(files))
+	for _, f := range files {
+		seen[f.Path] = true
+	}
+	for _, dir := range dirs {
+		sub, err := r.GetFileTree(ctx, dir, opts)
+		if err != nil {
+			if errors.Is(err, ErrPermission) {
+				continue
+			}
+			return nil, err
 		}
-		for _, child := range node.Children {
-			collect(child)
+		// Only the files directly in the package directory belong to it
+		for _, child := range sub.Children {
+			if child.Type == "file" && !seen[child.Path] {
+				seen[child.Path] = true
+				files = append(files, child)
+			}
 		}
 	}
-	collect(tree)
 
 	return files, nil
 }
 
+// localImportDirs returns the directories, relative to the work directory,
+// of the packages transitively imported by the package in pkgDir that belong
+// to the same module
+func (r *DefaultReader) localImportDirs(ctx context.Context, pkgDir string) ([]string, error) {
+	absWorkDir, err := filepath.Abs(r.workDir)
+	if err != nil {
+		return nil, err
+	}
+
+	pattern := filepath.ToSlash(filepath.Clean(pkgDir))
+	if !filepath.IsAbs(pkgDir) {
+		pattern = "./" + pattern
+	}
+
+	cfg := &packages.Config{
+		Context: ctx,
+		Mode:    packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedModule,
+		Dir:     absWorkDir,
+		Env:     append(os.Environ(), "GO111MODULE=on"),
+	}
+	start := time.Now()
+	pkgs, err := loadTraced(newTracer(r.tp), nil, packages.Load, cfg, pattern)
+	if err != nil {
+		return nil, &PackageError{Package: pkgDir, Op: "load imports", Wrapped: err}
+	}
+	r.log().DebugContext(ctx, "loaded packages", "dir", absWorkDir, "pattern", pattern, "packages", len(pkgs), "duration", time.Since(start))
+
+	// Walk the imports depth first, visiting every package once however
+	// many packages import it
+	var dirs []string
+	seen := make(map[string]bool)
+	var visit func(pkg *packages.Package, module string)
+	visit = func(pkg *packages.Package, module string) {
+		for _, imp := range pkg.Imports {
+			if seen[imp.PkgPath] || imp.Module == nil || imp.Module.Path != module || len(imp.GoFiles) == 0 {
+				continue
+			}
+			seen[imp.PkgPath] = true
+			if dir, err := filepath.Rel(absWorkDir, filepath.Dir(imp.GoFiles[0])); err == nil {
+				dirs = append(dirs, dir)
+			}
+			visit(imp, module)
+		}
+	}
+	for _, pkg := range pkgs {
+		if pkg.Module != nil {
+			seen[pkg.PkgPath] = true
+			visit(pkg, pkg.Module.Path)
+		}
+	}
+
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
 // GetFileTree returns the file tree starting from the given root
 func (r *DefaultReader) GetFileTree(ctx context.Context, root string, opts TreeOptions) (*FileTreeNode, error) {
+	ctx, span := newTracer(r.tp).Start(ctx, "readgo.GetFileTree", trace.WithAttributes(attribute.String("readgo.root", root)))
+	tree, err := r.getFileTree(ctx, root, opts)
+	if tree != nil {
+		span.SetAttributes(attribute.Bool("readgo.truncated", tree.Truncated))
+	}
+	endSpan(span, err)
+	return tree, err
+}
+
+// getFileTree walks the file tree starting from root
+func (r *DefaultReader) getFileTree(ctx context.Context, root string, opts TreeOptions) (*FileTreeNode, error) {
 	if root == "" {
 		root = "."
 	}
 
-	absRoot := filepath.Join(r.workDir, root)
-	absRoot, err := filepath.Abs(absRoot)
+	absRoot, err := r.resolvePath(root)
+	if err != nil {
+		return nil, err
+	}
+	r.log().Log(ctx, LevelTrace, "walking file tree", "root", absRoot)
+	absRoot, err = filepath.Abs(absRoot)
+	if err != nil {
+		return nil, err
+	}
+	absWorkDir, err := filepath.Abs(r.workDir)
 	if err != nil {
 		return nil, err
 	}
 
+	rootPath, err := filepath.Rel(absWorkDir, absRoot)
+	if err != nil {
+		rootPath = root
+	}
+
 	tree := &FileTreeNode{
 		Name: filepath.Base(absRoot),
-		Path: root,
+		Path: rootPath,
 		Type: "directory",
 	}
 
+	dirs := map[string]*FileTreeNode{rootPath: tree}
+	count := 0
+
 	err = filepath.Walk(absRoot, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 
-		// Skip if path matches exclude patterns
-		for _, pattern := range opts.ExcludePatterns {
-			if matched, _ := filepath.Match(pattern, info.Name()); matched {
-				if info.IsDir() {
-					return filepath.SkipDir
-				}
+		// Skip symlinks that point outside a jailed root
+		if r.root != "" && info.Mode()&os.ModeSymlink != 0 {
+			if _, err := r.resolvePath(path); err != nil {
 				return nil
 			}
 		}
 
-		// Skip if path doesn't match include patterns
-		if len(opts.IncludePatterns) > 0 {
-			matched := false
-			for _, pattern := range opts.IncludePatterns {
-				if m, _ := filepath.Match(pattern, info.Name()); m {
-					matched = true
-					break
-				}
+		if path != absRoot {
+			treePath, err := filepath.Rel(absRoot, path)
+			if err != nil {
+				return err
 			}
-			if !matched {
+			treePath = filepath.ToSlash(treePath)
+
+			// Skip vendored, dependency and hidden directories
+			if info.IsDir() && !opts.NoDefaultExcludes && isDefaultExcludedDir(info.Name()) {
+				return filepath.SkipDir
+			}
+
+			// Skip if path matches exclude patterns
+			if matchAnyPattern(opts.ExcludePatterns, treePath) {
 				if info.IsDir() {
 					return filepath.SkipDir
 				}
 				return nil
 			}
+
+			// Skip files that don't match include patterns
+			if !info.IsDir() && len(opts.IncludePatterns) > 0 && !matchAnyPattern(opts.IncludePatterns, treePath) {
+				return nil
+			}
+
+			// Skip files of other types
+			if !info.IsDir() && !matchesFileType(opts.FileTypes, info.Name(), func() bool {
+				content, err := os.ReadFile(path)
+				return err == nil && isGeneratedFile(content)
+			}) {
+				return nil
+			}
 		}
 
 		// Convert absolute path to relative path
-		relPath, err := filepath.Rel(r.workDir, path)
+		relPath, err := filepath.Rel(absWorkDir, path)
 		if err != nil {
 			return err
 		}
@@ -262,16 +617,44 @@ func (r *DefaultReader) GetFileTree(ctx context.Context, root string, opts TreeO
 			node.Type = "file"
 		}
 
-		// Find parent node
+		if (opts.SkipBinary || opts.DetectBinary) && info.Mode().IsRegular() {
+			binary, err := isBinaryFile(path)
+			if err != nil {
+				return err
+			}
+			if binary && opts.SkipBinary {
+				return nil
+			}
+			node.IsBinary = binary
+		}
+
+		if opts.PackageNames && info.Mode().IsRegular() && filepath.Ext(path) == ".go" {
+			node.Package = parsePackageName(path, nil)
+		}
+
+		if opts.ComputeHashes && info.Mode().IsRegular() {
+			hash, err := hashFile(path)
+			if err != nil {
+				return err
+			}
+			node.Hash = hash
+		}
+
+		// Attach to parent node
 		if path != absRoot {
-			parentPath := filepath.Dir(relPath)
-			parentNode := findNode(tree, parentPath)
-			if parentNode != nil {
-				parentNode.Children = append(parentNode.Children, node)
-				// Sort children by name
-				sort.Slice(parentNode.Children, func(i, j int) bool {
-					return parentNode.Children[i].Name < parentNode.Children[j].Name
-				})
+			parentNode, ok := dirs[filepath.Dir(relPath)]
+			if !ok {
+				return nil
+			}
+			if opts.Limit > 0 && count >= opts.Limit {
+				tree.Truncated = true
+				return filepath.SkipAll
+			}
+			count++
+
+			parentNode.Children = append(parentNode.Children, node)
+			if node.Type == "directory" {
+				dirs[relPath] = node
 			}
 		}
 
@@ -282,5 +665,6 @@ func (r *DefaultReader) GetFileTree(ctx context.Context, root string, opts TreeO
 		return nil, err
 	}
 
+	finishTree(tree)
 	return tree, nil
 }
@@ -2,6 +2,13 @@ package readgo
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
 	"strings"
 	"testing"
 )
@@ -174,3 +181,765 @@ func TestReadFileWithFunctions(t *testing.T) {
 		})
 	}
 }
+
+func TestJailedReader(t *testing.T) {
+	base := t.TempDir()
+	tenantA := filepath.Join(base, "tenant-a")
+	tenantB := filepath.Join(base, "tenant-b")
+	for _, dir := range []string{tenantA, tenantB, filepath.Join(tenantA, "pkg")} {
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			t.Fatalf("Failed to create directory %s: %v", dir, err)
+		}
+	}
+	files := map[string]string{
+		filepath.Join(tenantA, "pkg", "a.go"): "package pkg\n",
+		filepath.Join(tenantB, "secret.go"):   "package secret\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write file %s: %v", path, err)
+		}
+	}
+	if err := os.Symlink(filepath.Join(tenantB, "secret.go"), filepath.Join(tenantA, "link.go")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	if err := os.Symlink(tenantB, filepath.Join(tenantA, "linkdir")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	reader, err := NewJailedReader(tenantA)
+	if err != nil {
+		t.Fatalf("NewJailedReader() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr error
+	}{
+		{name: "Inside root", path: "pkg/a.go"},
+		{name: "Absolute inside root", path: filepath.Join(tenantA, "pkg", "a.go")},
+		{name: "Parent traversal", path: "../tenant-b/secret.go", wantErr: ErrPermission},
+		{name: "Nested traversal", path: "pkg/../../tenant-b/secret.go", wantErr: ErrPermission},
+		{name: "Absolute outside root", path: filepath.Join(tenantB, "secret.go"), wantErr: ErrPermission},
+		{name: "Sibling with common prefix", path: tenantA + "-evil/x.go", wantErr: ErrPermission},
+		{name: "Symlinked file escape", path: "link.go", wantErr: ErrPermission},
+		{name: "Symlinked directory escape", path: "linkdir/secret.go", wantErr: ErrPermission},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := reader.ReadSourceFile(context.Background(), tt.path, ReadOptions{})
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("ReadSourceFile() unexpected error = %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("ReadSourceFile() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("Tree outside root", func(t *testing.T) {
+		if _, err := reader.GetFileTree(context.Background(), "..", TreeOptions{}); !errors.Is(err, ErrPermission) {
+			t.Errorf("GetFileTree() error = %v, want %v", err, ErrPermission)
+		}
+	})
+
+	t.Run("Work directory outside root", func(t *testing.T) {
+		escaped, err := NewJailedReader(tenantA)
+		if err != nil {
+			t.Fatalf("NewJailedReader() error = %v", err)
+		}
+		escaped.WithWorkDir("/")
+		path, err := filepath.Rel("/", filepath.Join(tenantB, "secret.go"))
+		if err != nil {
+			t.Fatalf("Failed to make path relative: %v", err)
+		}
+		if _, err := escaped.ReadSourceFile(context.Background(), path, ReadOptions{}); !errors.Is(err, ErrPermission) {
+			t.Errorf("ReadSourceFile() error = %v, want %v", err, ErrPermission)
+		}
+		if _, err := escaped.WithWorkDir(tenantA).WithWorkDir("pkg").ReadSourceFile(context.Background(), "a.go", ReadOptions{}); err != nil {
+			t.Errorf("ReadSourceFile() in a nested work directory error = %v", err)
+		}
+	})
+
+	t.Run("Tree hides escaping symlinks", func(t *testing.T) {
+		tree, err := reader.GetFileTree(context.Background(), ".", TreeOptions{})
+		if err != nil {
+			t.Fatalf("GetFileTree() error = %v", err)
+		}
+		for _, child := range tree.Children {
+			if child.Name == "link.go" || child.Name == "linkdir" {
+				t.Errorf("GetFileTree() exposed escaping symlink %s", child.Name)
+			}
+		}
+	})
+}
+
+func TestSearchContent(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTestFiles(t, tmpDir)
+
+	extra := map[string][]byte{
+		"testdata/basic/blob.go":      []byte("func Method9(\x00\x01"),
+		"testdata/basic/generated.go": []byte("// Code generated by tool. DO NOT EDIT.\npackage basic\n\nfunc Method8() {}\n"),
+	}
+	for path, content := range extra {
+		if err := os.WriteFile(filepath.Join(tmpDir, path), content, 0600); err != nil {
+			t.Fatalf("Failed to write file %s: %v", path, err)
+		}
+	}
+
+	reader := NewDefaultReader().WithWorkDir(tmpDir)
+
+	matches, err := reader.SearchContent(context.Background(), `^func (Method\d)\(`, TreeOptions{})
+	if err != nil {
+		t.Fatalf("SearchContent() error = %v", err)
+	}
+	if len(matches) != 6 {
+		t.Fatalf("SearchContent() got %d matches, want 6: %v", len(matches), matches)
+	}
+	for _, m := range matches {
+		if m.File != filepath.Join("testdata", "basic", "main.go") {
+			t.Errorf("Unexpected match file %s", m.File)
+		}
+		if len(m.Submatches) != 1 || !strings.HasPrefix(m.Text, "func "+m.Submatches[0]) {
+			t.Errorf("Unexpected match %+v", m)
+		}
+		if m.Line <= 0 {
+			t.Errorf("Invalid line number %d", m.Line)
+		}
+	}
+
+	if _, err := reader.SearchContent(context.Background(), "(", TreeOptions{}); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("SearchContent() with invalid regex error = %v, want %v", err, ErrInvalidInput)
+	}
+}
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "internal/pkg/main.go", true},
+		{"*.go", "README.md", false},
+		{"internal/**/mock_*.go", "internal/mock_db.go", true},
+		{"internal/**/mock_*.go", "internal/a/b/mock_db.go", true},
+		{"internal/**/mock_*.go", "pkg/internal/mock_db.go", false},
+		{"internal/**/mock_*.go", "internal/a/db.go", false},
+		{"**/testdata", "testdata", true},
+		{"**/testdata", "a/b/testdata", true},
+		{"vendor/**", "vendor/github.com/x/y.go", true},
+		{"./cmd/*", "cmd/app", true},
+		{"cmd/*", "cmd/app/main.go", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			if got := matchPattern(tt.pattern, tt.path); got != tt.want {
+				t.Errorf("matchPattern(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetFileTreePathPatterns(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTestFiles(t, tmpDir)
+
+	reader := NewDefaultReader().WithWorkDir(tmpDir)
+	files, err := reader.GetPackageFiles(context.Background(), ".", TreeOptions{
+		IncludePatterns: []string{"*.go"},
+		ExcludePatterns: []string{"testdata/**/file2.go"},
+	})
+	if err != nil {
+		t.Fatalf("GetPackageFiles() error = %v", err)
+	}
+
+	got := make(map[string]bool)
+	for _, f := range files {
+		got[filepath.ToSlash(f.Path)] = true
+	}
+	if !got["testdata/basic/main.go"] || !got["testdata/multi/file1.go"] {
+		t.Errorf("Expected Go files in nested directories, got %v", got)
+	}
+	if got["testdata/multi/file2.go"] {
+		t.Error("Excluded file testdata/multi/file2.go was returned")
+	}
+	if got["go.mod"] {
+		t.Error("Non-matching file go.mod was returned")
+	}
+}
+
+func TestGetFileTreeHashes(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := []byte("package main\n")
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), content, 0600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	reader := NewDefaultReader().WithWorkDir(tmpDir)
+
+	tree, err := reader.GetFileTree(context.Background(), ".", TreeOptions{ComputeHashes: true})
+	if err != nil {
+		t.Fatalf("GetFileTree() error = %v", err)
+	}
+	if len(tree.Children) != 1 {
+		t.Fatalf("Expected 1 child, got %d", len(tree.Children))
+	}
+	sum := sha256.Sum256(content)
+	if want := hex.EncodeToString(sum[:]); tree.Children[0].Hash != want {
+		t.Errorf("Hash = %q, want %q", tree.Children[0].Hash, want)
+	}
+	if tree.Hash != "" {
+		t.Errorf("Directory node should not have a hash, got %q", tree.Hash)
+	}
+
+	tree, err = reader.GetFileTree(context.Background(), ".", TreeOptions{})
+	if err != nil {
+		t.Fatalf("GetFileTree() error = %v", err)
+	}
+	if tree.Children[0].Hash != "" {
+		t.Error("Hash computed without ComputeHashes")
+	}
+}
+
+func TestDiffTrees(t *testing.T) {
+	tmpDir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write file %s: %v", name, err)
+		}
+	}
+	write("kept.go", "package a\n")
+	write("changed.go", "package a\n")
+	write("removed.go", "package a\n")
+
+	reader := NewDefaultReader().WithWorkDir(tmpDir)
+	opts := TreeOptions{ComputeHashes: true}
+
+	before, err := reader.GetFileTree(context.Background(), ".", opts)
+	if err != nil {
+		t.Fatalf("GetFileTree() error = %v", err)
+	}
+
+	write("changed.go", "package b\n")
+	write("added.go", "package a\n")
+	if err := os.Remove(filepath.Join(tmpDir, "removed.go")); err != nil {
+		t.Fatalf("Failed to remove file: %v", err)
+	}
+
+	after, err := reader.GetFileTree(context.Background(), ".", opts)
+	if err != nil {
+		t.Fatalf("GetFileTree() error = %v", err)
+	}
+
+	diff := DiffTrees(before, after)
+	check := func(kind string, nodes []*FileTreeNode, want string) {
+		t.Helper()
+		if len(nodes) != 1 || nodes[0].Path != want {
+			t.Errorf("%s = %v, want [%s]", kind, nodes, want)
+		}
+	}
+	check("Added", diff.Added, "added.go")
+	check("Removed", diff.Removed, "removed.go")
+	check("Modified", diff.Modified, "changed.go")
+
+	if !DiffTrees(after, after).IsEmpty() {
+		t.Error("Expected no differences between identical trees")
+	}
+	if got := DiffTrees(nil, after); len(got.Added) != 3 {
+		t.Errorf("Expected all files added when diffing against nil, got %v", got.Added)
+	}
+}
+
+func TestBinaryFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string][]byte{
+		"main.go":  []byte("package main\n"),
+		"blob.dat": {0x7f, 'E', 'L', 'F', 0x00, 0x01},
+		"logo.png": []byte("not really a png"),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), content, 0600); err != nil {
+			t.Fatalf("Failed to write file %s: %v", name, err)
+		}
+	}
+
+	reader := NewDefaultReader().WithWorkDir(tmpDir)
+	ctx := context.Background()
+
+	if _, err := reader.ReadSourceFile(ctx, "blob.dat", ReadOptions{}); !errors.Is(err, ErrBinaryFile) {
+		t.Errorf("ReadSourceFile() error = %v, want %v", err, ErrBinaryFile)
+	}
+
+	tree, err := reader.GetFileTree(ctx, ".", TreeOptions{DetectBinary: true})
+	if err != nil {
+		t.Fatalf("GetFileTree() error = %v", err)
+	}
+	want := map[string]bool{"main.go": false, "blob.dat": true, "logo.png": true}
+	for _, node := range tree.Children {
+		if node.IsBinary != want[node.Name] {
+			t.Errorf("%s: IsBinary = %v, want %v", node.Name, node.IsBinary, want[node.Name])
+		}
+	}
+
+	matches, err := reader.SearchFiles(ctx, ".", TreeOptions{SkipBinary: true})
+	if err != nil {
+		t.Fatalf("SearchFiles() error = %v", err)
+	}
+	if len(matches) != 1 || matches[0].Name != "main.go" {
+		t.Errorf("SearchFiles() with SkipBinary = %v, want [main.go]", matches)
+	}
+}
+
+func TestDefaultExcludes(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, name := range []string{"main.go", "vendor/dep/dep.go", ".git/config", "node_modules/x/x.go", ".hidden/h.go", "pkg/.keep"} {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("package x\n"), 0600); err != nil {
+			t.Fatalf("Failed to write file %s: %v", name, err)
+		}
+	}
+
+	reader := NewDefaultReader().WithWorkDir(tmpDir)
+	collect := func(opts TreeOptions) map[string]bool {
+		t.Helper()
+		files, err := reader.GetPackageFiles(context.Background(), ".", opts)
+		if err != nil {
+			t.Fatalf("GetPackageFiles() error = %v", err)
+		}
+		got := make(map[string]bool)
+		for _, f := range files {
+			got[filepath.ToSlash(f.Path)] = true
+		}
+		return got
+	}
+
+	got := collect(TreeOptions{})
+	if len(got) != 2 || !got["main.go"] || !got["pkg/.keep"] {
+		t.Errorf("Default excludes: got %v, want main.go and pkg/.keep", got)
+	}
+
+	got = collect(TreeOptions{NoDefaultExcludes: true})
+	if len(got) != 6 {
+		t.Errorf("NoDefaultExcludes: got %d files %v, want 6", len(got), got)
+	}
+}
+
+func TestGetFileTreePackageNames(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTestFiles(t, tmpDir)
+
+	reader := NewDefaultReader().WithWorkDir(tmpDir)
+	files, err := reader.GetPackageFiles(context.Background(), "testdata", TreeOptions{PackageNames: true})
+	if err != nil {
+		t.Fatalf("GetPackageFiles() error = %v", err)
+	}
+
+	want := map[string]string{
+		"main.go":  "basic",
+		"file1.go": "multi",
+		"file2.go": "multi",
+	}
+	for _, f := range files {
+		if f.Package != want[f.Name] {
+			t.Errorf("%s: Package = %q, want %q", f.Path, f.Package, want[f.Name])
+		}
+	}
+
+	files, err = reader.GetPackageFiles(context.Background(), "testdata", TreeOptions{})
+	if err != nil {
+		t.Fatalf("GetPackageFiles() error = %v", err)
+	}
+	for _, f := range files {
+		if f.Package != "" {
+			t.Errorf("%s: Package set without PackageNames option", f.Path)
+		}
+	}
+}
+
+func TestReadFileDeclarationPositions(t *testing.T) {
+	tmpDir := t.TempDir()
+	src := `package decls
+
+const Single = 1
+
+const (
+	A = iota
+	B, C = 2, 3
+)
+
+var ignored = 0
+
+type Point struct {
+	X, Y int
+}
+
+type (
+	ID    string
+	Names []string
+)
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "decls.go"), []byte(src), 0600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	reader := NewDefaultReader().WithWorkDir(tmpDir)
+	result, err := reader.ReadFileWithFunctions(context.Background(), "decls.go")
+	if err != nil {
+		t.Fatalf("ReadFileWithFunctions() error = %v", err)
+	}
+
+	wantConsts := []DeclPosition{
+		{Name: "Single", StartLine: 3, EndLine: 3},
+		{Name: "A", StartLine: 6, EndLine: 6},
+		{Name: "B", StartLine: 7, EndLine: 7},
+		{Name: "C", StartLine: 7, EndLine: 7},
+	}
+	wantTypes := []DeclPosition{
+		{Name: "Point", StartLine: 12, EndLine: 14},
+		{Name: "ID", StartLine: 17, EndLine: 17},
+		{Name: "Names", StartLine: 18, EndLine: 18},
+	}
+	if !reflect.DeepEqual(result.Consts, wantConsts) {
+		t.Errorf("Consts = %v, want %v", result.Consts, wantConsts)
+	}
+	if !reflect.DeepEqual(result.Types, wantTypes) {
+		t.Errorf("Types = %v, want %v", result.Types, wantTypes)
+	}
+}
+
+func TestReadFileFunctionDetails(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTestFiles(t, tmpDir)
+
+	reader := NewDefaultReader().WithWorkDir(tmpDir)
+	result, err := reader.ReadFileWithFunctions(context.Background(), "testdata/basic/main.go")
+	if err != nil {
+		t.Fatalf("ReadFileWithFunctions() error = %v", err)
+	}
+
+	funcs := make(map[string]FunctionPosition)
+	for _, fn := range result.Functions {
+		funcs[fn.Name] = fn
+	}
+
+	str := funcs["String"]
+	if str.Receiver != "*User" {
+		t.Errorf("String receiver = %q, want *User", str.Receiver)
+	}
+	if str.Signature != "func (u *User) String() string" {
+		t.Errorf("String signature = %q", str.Signature)
+	}
+	if !str.IsExported {
+		t.Error("String should be exported")
+	}
+	if str.DocStartLine != str.StartLine-1 {
+		t.Errorf("String doc starts at %d, want %d", str.DocStartLine, str.StartLine-1)
+	}
+
+	m5 := funcs["Method5"]
+	if m5.Receiver != "" {
+		t.Errorf("Method5 should have no receiver, got %q", m5.Receiver)
+	}
+	if m5.Signature != "func Method5(prefix string, values ...interface{}) (string, error)" {
+		t.Errorf("Method5 signature = %q", m5.Signature)
+	}
+}
+
+func TestPagination(t *testing.T) {
+	tmpDir := t.TempDir()
+	for i := 0; i < 5; i++ {
+		name := filepath.Join(tmpDir, "dir", string(rune('a'+i))+".go")
+		if err := os.MkdirAll(filepath.Dir(name), 0750); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(name, []byte("package dir\n"), 0600); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	reader := NewDefaultReader().WithWorkDir(tmpDir)
+	ctx := context.Background()
+
+	page, err := reader.SearchFilesPage(ctx, ".go", TreeOptions{Offset: 1, Limit: 2})
+	if err != nil {
+		t.Fatalf("SearchFilesPage() error = %v", err)
+	}
+	if page.Total != 5 || len(page.Files) != 2 || !page.Truncated || page.NextOffset != 3 {
+		t.Errorf("SearchFilesPage() = %+v", page)
+	}
+	if page.Files[0].Name != "b.go" || page.Files[1].Name != "c.go" {
+		t.Errorf("Unexpected page contents: %s, %s", page.Files[0].Name, page.Files[1].Name)
+	}
+
+	page, err = reader.SearchFilesPage(ctx, ".go", TreeOptions{Offset: 3, Limit: 2})
+	if err != nil {
+		t.Fatalf("SearchFilesPage() error = %v", err)
+	}
+	if len(page.Files) != 2 || page.Truncated {
+		t.Errorf("Last page = %+v", page)
+	}
+
+	tree, err := reader.GetFileTree(ctx, ".", TreeOptions{Limit: 3})
+	if err != nil {
+		t.Fatalf("GetFileTree() error = %v", err)
+	}
+	if !tree.Truncated {
+		t.Error("Expected truncated tree")
+	}
+	if n := len(collectFiles(tree)); n != 2 {
+		t.Errorf("Expected 2 files in truncated tree (1 directory + 2 files), got %d", n)
+	}
+
+	matches, err := reader.SearchContent(ctx, "^package", TreeOptions{Offset: 4, Limit: 10})
+	if err != nil {
+		t.Fatalf("SearchContent() error = %v", err)
+	}
+	if len(matches) != 1 {
+		t.Errorf("SearchContent() returned %d matches, want 1", len(matches))
+	}
+}
+
+func TestReadWithLineNumbers(t *testing.T) {
+	tmpDir := t.TempDir()
+	src := "\n\npackage main\n\nfunc main() {}\n\n\n\n\n\n\n\n// end\n\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte(src), 0600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	reader := NewDefaultReader().WithWorkDir(tmpDir)
+	ctx := context.Background()
+
+	content, err := reader.ReadSourceFile(ctx, "main.go", ReadOptions{WithLineNumbers: true, StripSpaces: true})
+	if err != nil {
+		t.Fatalf("ReadSourceFile() error = %v", err)
+	}
+	want := " 3\tpackage main\n 4\t\n 5\tfunc main() {}\n 6\t\n 7\t\n 8\t\n 9\t\n10\t\n11\t\n12\t\n13\t// end\n"
+	if string(content) != want {
+		t.Errorf("ReadSourceFile() = %q, want %q", content, want)
+	}
+
+	lines, err := ReadLines(ctx, reader, "main.go", ReadOptions{StripSpaces: true})
+	if err != nil {
+		t.Fatalf("ReadLines() error = %v", err)
+	}
+	if len(lines) != 11 || lines[0] != (SourceLine{Number: 3, Text: "package main"}) || lines[10].Number != 13 {
+		t.Errorf("ReadLines() = %v", lines)
+	}
+}
+
+func TestValidatePath(t *testing.T) {
+	type testCase struct {
+		name    string
+		root    string
+		path    string
+		wantErr error
+	}
+
+	tests := []testCase{
+		{name: "Root itself", root: "/work", path: "/work"},
+		{name: "Nested file", root: "/work", path: "/work/pkg/a.go"},
+		{name: "Dotted name inside root", root: "/work", path: "/work/..hidden/a.go"},
+		{name: "Cleaned traversal inside root", root: "/work", path: "/work/pkg/../a.go"},
+		{name: "Parent directory", root: "/work", path: "/", wantErr: ErrPermission},
+		{name: "Traversal out of root", root: "/work", path: "/work/../etc/passwd", wantErr: ErrPermission},
+		{name: "Sibling with common prefix", root: "/work", path: "/work-evil/a.go", wantErr: ErrPermission},
+		{name: "Relative path", root: "/work", path: "pkg/a.go", wantErr: ErrInvalidInput},
+	}
+	if runtime.GOOS == "windows" {
+		tests = []testCase{
+			{name: "Case-insensitive match", root: `C:\Work`, path: `c:\work\pkg\a.go`},
+			{name: "Other drive", root: `C:\work`, path: `D:\work\a.go`, wantErr: ErrPermission},
+			{name: "UNC share", root: `C:\work`, path: `\\server\share\work\a.go`, wantErr: ErrPermission},
+			{name: "Sibling with common prefix", root: `C:\work`, path: `C:\work-evil\a.go`, wantErr: ErrPermission},
+			{name: "Traversal out of root", root: `C:\work`, path: `C:\work\..\secret`, wantErr: ErrPermission},
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePath(tt.root, tt.path)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("validatePath(%q, %q) unexpected error = %v", tt.root, tt.path, err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("validatePath(%q, %q) error = %v, want %v", tt.root, tt.path, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetFileTreeDirectoryTotals(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTestFiles(t, tmpDir)
+
+	reader := NewDefaultReader().WithWorkDir(tmpDir)
+	tree, err := reader.GetFileTree(context.Background(), "testdata", TreeOptions{})
+	if err != nil {
+		t.Fatalf("GetFileTree() error = %v", err)
+	}
+
+	if tree.FileCount != 3 || tree.GoFileCount != 3 {
+		t.Errorf("Root counts = %d files, %d Go files, want 3 and 3", tree.FileCount, tree.GoFileCount)
+	}
+	if tree.Size != tree.TotalSize() {
+		t.Errorf("Root size = %d, want %d", tree.Size, tree.TotalSize())
+	}
+
+	var sum int64
+	for _, child := range tree.Children {
+		sum += child.Size
+		if child.Name == "multi" && child.FileCount != 2 {
+			t.Errorf("multi FileCount = %d, want 2", child.FileCount)
+		}
+	}
+	if sum != tree.Size {
+		t.Errorf("Children sizes sum to %d, root size is %d", sum, tree.Size)
+	}
+}
+
+func TestGetPackageFilesLocalImports(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"cmd/app/main.go":         "package main\n\nimport (\n\t\"testmod/internal/lib\"\n\t\"testmod/internal/util\"\n)\n\nfunc main() { lib.Run(util.Name) }\n",
+		"internal/lib/lib.go":     "package lib\n\nimport (\n\t\"fmt\"\n\n\t\"testmod/internal/util\"\n)\n\nfunc Run(name string) { fmt.Println(name, util.Name) }\n",
+		"internal/util/util.go":   "package util\n\nimport \"testmod/internal/names\"\n\nconst Name = names.Util\n",
+		"internal/names/names.go": "package names\n\nconst Util = \"util\"\n",
+		"other/other.go":          "package other\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write file %s: %v", name, err)
+		}
+	}
+	setupTestModule(t, tmpDir)
+
+	reader := NewDefaultReader().WithWorkDir(tmpDir)
+
+	got, err := reader.GetPackageFiles(context.Background(), "./cmd/app", TreeOptions{LocalImports: true})
+	if err != nil {
+		t.Fatalf("GetPackageFiles() error = %v", err)
+	}
+	var paths []string
+	for _, f := range got {
+		paths = append(paths, filepath.ToSlash(f.Path))
+	}
+	want := []string{"cmd/app/main.go", "internal/lib/lib.go", "internal/names/names.go", "internal/util/util.go"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("GetPackageFiles() = %v, want %v", paths, want)
+	}
+
+	dirs, err := reader.localImportDirs(context.Background(), "./cmd/app")
+	if err != nil {
+		t.Fatalf("localImportDirs() error = %v", err)
+	}
+	wantDirs := []string{"internal/lib", "internal/names", "internal/util"}
+	for i := range dirs {
+		dirs[i] = filepath.ToSlash(dirs[i])
+	}
+	if !reflect.DeepEqual(dirs, wantDirs) {
+		t.Errorf("localImportDirs() = %v, want each import once: %v", dirs, wantDirs)
+	}
+
+	got, err = reader.GetPackageFiles(context.Background(), "./cmd/app", TreeOptions{})
+	if err != nil {
+		t.Fatalf("GetPackageFiles() error = %v", err)
+	}
+	if len(got) != 1 {
+		t.Errorf("GetPackageFiles() without LocalImports returned %d files, want 1", len(got))
+	}
+}
+
+func TestReadFormatted(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"messy.go":  "package main\nfunc  main ( ) {\nx:=1\n_ = x}\n",
+		"broken.go": "package main\nfunc main( {\n",
+		"notes.txt": "keep  as   is\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write file %s: %v", name, err)
+		}
+	}
+
+	reader := NewDefaultReader().WithWorkDir(tmpDir)
+	ctx := context.Background()
+	opts := ReadOptions{Format: true}
+
+	content, err := reader.ReadSourceFile(ctx, "messy.go", opts)
+	if err != nil {
+		t.Fatalf("ReadSourceFile() error = %v", err)
+	}
+	if want := "package main\n\nfunc main() {\n\tx := 1\n\t_ = x\n}\n"; string(content) != want {
+		t.Errorf("ReadSourceFile() = %q, want %q", content, want)
+	}
+
+	content, err = reader.ReadSourceFile(ctx, "notes.txt", opts)
+	if err != nil || string(content) != files["notes.txt"] {
+		t.Errorf("ReadSourceFile() on non-Go file = %q, %v", content, err)
+	}
+
+	var analysisErr *AnalysisError
+	if _, err := reader.ReadSourceFile(ctx, "broken.go", opts); !errors.As(err, &analysisErr) {
+		t.Errorf("ReadSourceFile() on invalid Go file error = %v, want AnalysisError", err)
+	}
+}
+
+func TestGetFileTreeFileTypes(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"main.go":      "package main\n",
+		"main_test.go": "package main\n",
+		"zz_gen.go":    "// Code generated by tool. DO NOT EDIT.\n\npackage main\n",
+		"asm_amd64.s":  "TEXT ·add(SB),$0\n",
+		"helper.c":     "int add(int a, int b) { return a + b; }\n",
+		"helper.h":     "int add(int a, int b);\n",
+		"README.md":    "# readme\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write file %s: %v", name, err)
+		}
+	}
+
+	reader := NewDefaultReader().WithWorkDir(tmpDir)
+	tests := []struct {
+		fileType FileType
+		want     []string
+	}{
+		{FileTypeAll, []string{"README.md", "asm_amd64.s", "helper.c", "helper.h", "main.go", "main_test.go", "zz_gen.go"}},
+		{FileTypeGo, []string{"main.go", "main_test.go", "zz_gen.go"}},
+		{FileTypeTest, []string{"main_test.go"}},
+		{FileTypeGenerated, []string{"zz_gen.go"}},
+		{FileTypeGoPackage, []string{"asm_amd64.s", "helper.c", "helper.h", "main.go", "main_test.go", "zz_gen.go"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.fileType), func(t *testing.T) {
+			got, err := reader.GetPackageFiles(context.Background(), ".", TreeOptions{FileTypes: tt.fileType})
+			if err != nil {
+				t.Fatalf("GetPackageFiles() error = %v", err)
+			}
+			var names []string
+			for _, f := range got {
+				names = append(names, f.Name)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("GetPackageFiles() = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package report
+
+import (
+	"encoding/xml"
+	"io"
+
+	"github.com/iamlongalong/readgo"
+)
+
+// checkstyleReport is the root element of a Checkstyle XML report
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+// checkstyleFile holds the findings of one file
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+// checkstyleError is a single finding
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// WriteCheckstyle writes result in the Checkstyle XML format read by code
+// review tools such as reviewdog. Findings without a file, such as load
+// errors, are reported against the validated path.
+func WriteCheckstyle(w io.Writer, result *readgo.ValidationResult) error {
+	doc := checkstyleReport{Version: "4.3"}
+	for _, is := range collectIssues(result) {
+		name := is.File
+		if name == "" {
+			name = result.Path
+		}
+		if len(doc.Files) == 0 || doc.Files[len(doc.Files)-1].Name != name {
+			doc.Files = append(doc.Files, checkstyleFile{Name: name})
+		}
+		f := &doc.Files[len(doc.Files)-1]
+		f.Errors = append(f.Errors, checkstyleError{
+			Line:     is.Line,
+			Column:   is.Column,
+			Severity: string(is.Severity),
+			Message:  is.Message,
+			Source:   "readgo." + is.Rule,
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,42 @@
+package report
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/iamlongalong/readgo"
+)
+
+func TestWriteCheckstyle(t *testing.T) {
+	result := &readgo.ValidationResult{
+		Path:   "proj",
+		Errors: []string{"b.go:7:2: missing return", "go list failed"},
+		Warnings: []readgo.ValidationWarning{
+			{Type: "unused_import", Severity: readgo.SeverityWarning, Message: `unused import: "os"`, File: "a.go", Line: 3, Column: 8},
+			{Type: "todo", Severity: readgo.SeverityInfo, Message: "TODO found", File: "a.go", Line: 9},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCheckstyle(&buf, result); err != nil {
+		t.Fatalf("Failed to write report: %v", err)
+	}
+
+	want := xml.Header + `<checkstyle version="4.3">
+  <file name="proj">
+    <error line="0" severity="error" message="go list failed" source="readgo.error"></error>
+  </file>
+  <file name="a.go">
+    <error line="3" column="8" severity="warning" message="unused import: &#34;os&#34;" source="readgo.unused_import"></error>
+    <error line="9" severity="info" message="TODO found" source="readgo.todo"></error>
+  </file>
+  <file name="b.go">
+    <error line="7" column="2" severity="error" message="missing return" source="readgo.error"></error>
+  </file>
+</checkstyle>
+`
+	if buf.String() != want {
+		t.Errorf("report =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
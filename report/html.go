@@ -0,0 +1,221 @@
+package report
+
+import (
+	"bytes"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/iamlongalong/readgo"
+)
+
+// HTMLOptions configures HTML reports
+type HTMLOptions struct {
+	// Title is the page title, defaulting to the name of the result
+	Title string
+
+	// SourceDir is the directory relative file paths are resolved against
+	// when reading source excerpts. Excerpts are omitted if it is empty.
+	SourceDir string
+
+	// ContextLines is the number of lines shown around each finding.
+	// If zero, 2 lines are shown.
+	ContextLines int
+}
+
+// htmlFile groups the issues of one file
+type htmlFile struct {
+	Path   string
+	Issues []htmlIssue
+}
+
+// htmlIssue is an issue with its source excerpt
+type htmlIssue struct {
+	issue
+	Excerpt []excerptLine
+}
+
+// excerptLine is a source line shown next to an issue
+type excerptLine struct {
+	Number    int
+	Text      string
+	Highlight bool
+}
+
+// WriteValidationHTML renders result as a self-contained HTML page that
+// groups the findings by file, lets the reader filter them by severity and
+// shows the source around each finding
+func WriteValidationHTML(w io.Writer, result *readgo.ValidationResult, opts HTMLOptions) error {
+	if opts.Title == "" {
+		opts.Title = "Validation report: " + result.Name
+	}
+	if opts.ContextLines <= 0 {
+		opts.ContextLines = 2
+	}
+
+	counts := map[readgo.Severity]int{}
+	var files []htmlFile
+	sources := map[string][]string{}
+	for _, is := range collectIssues(result) {
+		counts[is.Severity]++
+		if len(files) == 0 || files[len(files)-1].Path != is.File {
+			files = append(files, htmlFile{Path: is.File})
+		}
+		f := &files[len(files)-1]
+		f.Issues = append(f.Issues, htmlIssue{
+			issue:   is,
+			Excerpt: excerpt(sources, opts.SourceDir, is.File, is.Line, opts.ContextLines),
+		})
+	}
+
+	return validationTemplate.Execute(w, map[string]interface{}{
+		"Title":  opts.Title,
+		"Result": result,
+		"Files":  files,
+		"Counts": counts,
+		"Style":  template.CSS(reportStyle),
+	})
+}
+
+// WriteAnalysisHTML renders result as a self-contained HTML page listing the
+// types, functions and imports found by the analyzer
+func WriteAnalysisHTML(w io.Writer, result *readgo.AnalysisResult, opts HTMLOptions) error {
+	if opts.Title == "" {
+		opts.Title = "Analysis report: " + result.Name
+	}
+
+	return analysisTemplate.Execute(w, map[string]interface{}{
+		"Title":  opts.Title,
+		"Result": result,
+		"Style":  template.CSS(reportStyle),
+	})
+}
+
+// excerpt returns the lines of file around line, reading files through the
+// sources cache. It returns nil when the file cannot be read.
+func excerpt(sources map[string][]string, dir, file string, line, context int) []excerptLine {
+	if dir == "" || file == "" || line <= 0 {
+		return nil
+	}
+
+	lines, ok := sources[file]
+	if !ok {
+		path := file
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		if content, err := os.ReadFile(path); err == nil {
+			lines = strings.Split(string(bytes.TrimSuffix(content, []byte("\n"))), "\n")
+		}
+		sources[file] = lines
+	}
+	if line > len(lines) {
+		return nil
+	}
+
+	var out []excerptLine
+	for n := max(line-context, 1); n <= min(line+context, len(lines)); n++ {
+		out = append(out, excerptLine{
+			Number:    n,
+			Text:      strings.TrimSuffix(lines[n-1], "\r"),
+			Highlight: n == line,
+		})
+	}
+	return out
+}
+
+// reportStyle is the stylesheet embedded in every HTML report
+const reportStyle = `
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292f; }
+h1 { font-size: 1.5em; }
+h2 { font-size: 1.1em; margin-top: 1.5em; font-family: monospace; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: 4px 8px; border-bottom: 1px solid #d0d7de; vertical-align: top; }
+.summary span { margin-right: 1.5em; }
+.issue { margin: 0.5em 0 1em; }
+.badge { display: inline-block; min-width: 5em; padding: 0 6px; border-radius: 4px; font-size: 0.85em; text-align: center; }
+.error .badge { background: #ffebe9; color: #cf222e; }
+.warning .badge { background: #fff8c5; color: #9a6700; }
+.info .badge { background: #ddf4ff; color: #0969da; }
+.rule, .pos { color: #57606a; font-family: monospace; }
+pre { background: #f6f8fa; padding: 6px 0; margin: 4px 0; overflow-x: auto; }
+pre span { display: block; padding: 0 8px; }
+pre .hl { background: #fff8c5; }
+.filters label { margin-right: 1em; }
+body.hide-error .issue.error, body.hide-warning .issue.warning, body.hide-info .issue.info { display: none; }
+`
+
+// severities lists the severities in the order of the filter controls
+var severities = []readgo.Severity{readgo.SeverityError, readgo.SeverityWarning, readgo.SeverityInfo}
+
+var validationTemplate = template.Must(template.New("validation").Funcs(template.FuncMap{
+	"severities": func() []readgo.Severity { return severities },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>{{.Style}}</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="summary">
+<span>Path: <code>{{.Result.Path}}</code></span>
+<span>Level: {{.Result.Level}}</span>
+<span>Valid: {{.Result.Valid}}</span>
+<span>Analyzed: {{.Result.AnalyzedAt.Format "2006-01-02 15:04:05"}}</span>
+</p>
+<p class="filters">
+{{range $sev := severities}}<label><input type="checkbox" checked onchange="document.body.classList.toggle('hide-{{$sev}}', !this.checked)"> {{$sev}} ({{index $.Counts $sev}})</label>
+{{end}}</p>
+{{if not .Files}}<p>No findings.</p>{{end}}
+{{range .Files}}<section class="file">
+<h2>{{if .Path}}{{.Path}}{{else}}(no file){{end}}</h2>
+{{range .Issues}}<div class="issue {{.Severity}}">
+<span class="badge">{{.Severity}}</span>
+{{if .Line}}<span class="pos">{{.Line}}:{{.Column}}</span>{{end}}
+<span class="rule">{{.Rule}}</span>
+{{.Message}}
+{{if .Excerpt}}<pre>{{range .Excerpt}}<span{{if .Highlight}} class="hl"{{end}}>{{printf "%4d" .Number}}  {{.Text}}</span>{{end}}</pre>{{end}}
+</div>
+{{end}}</section>
+{{end}}
+</body>
+</html>
+`))
+
+var analysisTemplate = template.Must(template.New("analysis").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>{{.Style}}</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="summary">
+<span>Path: <code>{{.Result.Path}}</code></span>
+<span>Analyzed: {{.Result.AnalyzedAt.Format "2006-01-02 15:04:05"}}</span>
+</p>
+{{with .Result.Types}}<h2>Types ({{len .}})</h2>
+<table>
+<tr><th>Name</th><th>Package</th><th>Type</th><th>Exported</th></tr>
+{{range .}}<tr><td>{{.Name}}</td><td>{{.Package}}</td><td><code>{{.Type}}</code></td><td>{{.IsExported}}</td></tr>
+{{end}}</table>
+{{end}}
+{{with .Result.Functions}}<h2>Functions ({{len .}})</h2>
+<table>
+<tr><th>Name</th><th>Package</th><th>Exported</th></tr>
+{{range .}}<tr><td>{{.Name}}</td><td>{{.Package}}</td><td>{{.IsExported}}</td></tr>
+{{end}}</table>
+{{end}}
+{{with .Result.Imports}}<h2>Imports ({{len .}})</h2>
+<ul>
+{{range .}}<li><code>{{.}}</code></li>
+{{end}}</ul>
+{{end}}
+</body>
+</html>
+`))
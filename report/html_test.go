@@ -0,0 +1,92 @@
+package report
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iamlongalong/readgo"
+)
+
+func TestWriteValidationHTML(t *testing.T) {
+	tmpDir := t.TempDir()
+	src := "package a\n\nimport _ \"fmt\"\n\nfunc F() {}\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "a.go"), []byte(src), 0600); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	result := &readgo.ValidationResult{
+		Name:       "a",
+		Path:       tmpDir,
+		AnalyzedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Level:      readgo.ValidationLevelStandard,
+		Errors:     []string{"parse error: b.go:7:2: expected <declaration>"},
+		Warnings: []readgo.ValidationWarning{{
+			Type:     "unused_import",
+			Severity: readgo.SeverityWarning,
+			Message:  `unused import: "fmt"`,
+			File:     "a.go",
+			Line:     3,
+			Column:   8,
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteValidationHTML(&buf, result, HTMLOptions{SourceDir: tmpDir, ContextLines: 1}); err != nil {
+		t.Fatalf("Failed to write report: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"<title>Validation report: a</title>",
+		"<h2>a.go</h2>",
+		"<h2>b.go</h2>",
+		`<div class="issue warning">`,
+		`<div class="issue error">`,
+		"unused import: &#34;fmt&#34;",
+		"expected &lt;declaration&gt;",
+		`<span class="hl">   3  import _ &#34;fmt&#34;</span>`,
+		"<span>   4  </span>",
+		"hide-warning",
+		"warning (1)",
+		"info (0)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report does not contain %q", want)
+		}
+	}
+	if strings.Contains(out, "package a") {
+		t.Error("report shows source lines outside the excerpt")
+	}
+}
+
+func TestWriteAnalysisHTML(t *testing.T) {
+	result := &readgo.AnalysisResult{
+		Name:      "pkg",
+		Path:      "./pkg",
+		Types:     []readgo.TypeInfo{{Name: "User", Package: "pkg", Type: "struct{Name string}", IsExported: true}},
+		Functions: []readgo.FunctionInfo{{Name: "New", Package: "pkg", IsExported: true}},
+		Imports:   []string{"fmt"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteAnalysisHTML(&buf, result, HTMLOptions{Title: "<pkg>"}); err != nil {
+		t.Fatalf("Failed to write report: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"<title>&lt;pkg&gt;</title>",
+		"Types (1)",
+		"<code>struct{Name string}</code>",
+		"Functions (1)",
+		"<li><code>fmt</code></li>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report does not contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/iamlongalong/readgo"
+)
+
+// JUnitGrouping selects what a JUnit test case stands for
+type JUnitGrouping int
+
+const (
+	// JUnitByFile reports one test case per file
+	JUnitByFile JUnitGrouping = iota
+	// JUnitByRule reports one test case per rule
+	JUnitByRule
+)
+
+// junitSuites is the root element of a JUnit XML report
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+// junitSuite is a suite of test cases
+type junitSuite struct {
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Timestamp string      `xml:"timestamp,attr,omitempty"`
+	Cases     []junitCase `xml:"testcase"`
+}
+
+// junitCase is a test case, failed when Failure is set
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// junitFailure describes why a test case failed
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes result as JUnit XML so that CI systems such as Jenkins
+// and GitLab can display the findings as test results. Each file or rule,
+// depending on group, becomes a test case that fails when it has errors or
+// warnings; informational findings go to the test case output. A result
+// without findings is reported as a single passing test case.
+func WriteJUnit(w io.Writer, result *readgo.ValidationResult, group JUnitGrouping) error {
+	if group != JUnitByFile && group != JUnitByRule {
+		return fmt.Errorf("%w: unknown JUnit grouping %d", readgo.ErrInvalidInput, int(group))
+	}
+
+	suite := junitSuite{Name: "readgo." + result.Name}
+	if !result.AnalyzedAt.IsZero() {
+		suite.Timestamp = result.AnalyzedAt.Format("2006-01-02T15:04:05")
+	}
+
+	var keys []string
+	byKey := map[string][]issue{}
+	for _, is := range collectIssues(result) {
+		key := is.File
+		if group == JUnitByRule {
+			key = is.Rule
+		}
+		if _, ok := byKey[key]; !ok {
+			keys = append(keys, key)
+		}
+		byKey[key] = append(byKey[key], is)
+	}
+	if group == JUnitByRule {
+		sort.Strings(keys)
+	}
+
+	for _, key := range keys {
+		tc := junitCase{Name: key, ClassName: "readgo." + result.Name}
+		if group == JUnitByRule {
+			tc.ClassName = "readgo.rules"
+		} else if key == "" {
+			tc.Name = "(no file)"
+		}
+
+		var failures, infos []string
+		for _, is := range byKey[key] {
+			line := formatIssue(is)
+			if is.Severity == readgo.SeverityInfo {
+				infos = append(infos, line)
+				continue
+			}
+			failures = append(failures, line)
+		}
+		if len(failures) > 0 {
+			tc.Failure = &junitFailure{
+				Message: fmt.Sprintf("%d finding(s)", len(failures)),
+				Type:    "readgo",
+				Text:    strings.Join(failures, "\n"),
+			}
+			suite.Failures++
+		}
+		tc.SystemOut = strings.Join(infos, "\n")
+		suite.Cases = append(suite.Cases, tc)
+	}
+	if len(suite.Cases) == 0 {
+		suite.Cases = append(suite.Cases, junitCase{Name: result.Name, ClassName: "readgo." + result.Name})
+	}
+	suite.Tests = len(suite.Cases)
+
+	doc := junitSuites{
+		Name:     "readgo",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Suites:   []junitSuite{suite},
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// formatIssue renders an issue as a single "file:line:col: [rule] message" line
+func formatIssue(is issue) string {
+	var b strings.Builder
+	if is.File != "" {
+		b.WriteString(is.File)
+		if is.Line > 0 {
+			fmt.Fprintf(&b, ":%d:%d", is.Line, is.Column)
+		}
+		b.WriteString(": ")
+	}
+	fmt.Fprintf(&b, "%s [%s] %s", is.Severity, is.Rule, is.Message)
+	return b.String()
+}
@@ -0,0 +1,121 @@
+package report
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/iamlongalong/readgo"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	result := &readgo.ValidationResult{
+		Name:   "proj",
+		Errors: []string{"parse error: b.go:7:2: expected declaration"},
+		Warnings: []readgo.ValidationWarning{
+			{Type: "unused_import", Severity: readgo.SeverityWarning, Message: "unused import", File: "a.go", Line: 3, Column: 8},
+			{Type: "todo", Severity: readgo.SeverityInfo, Message: "TODO found", File: "a.go", Line: 9, Column: 2},
+			{Type: "todo", Severity: readgo.SeverityInfo, Message: "TODO found", File: "c.go", Line: 1, Column: 1},
+		},
+	}
+
+	type testCase struct {
+		Name    string `xml:"name,attr"`
+		Failure *struct {
+			Text string `xml:",chardata"`
+		} `xml:"failure"`
+		SystemOut string `xml:"system-out"`
+	}
+	type report struct {
+		Tests    int        `xml:"tests,attr"`
+		Failures int        `xml:"failures,attr"`
+		Cases    []testCase `xml:"testsuite>testcase"`
+	}
+
+	tests := []struct {
+		name     string
+		group    JUnitGrouping
+		failures int
+		cases    map[string]string // test case name to failure text
+	}{
+		{
+			name:     "by file",
+			group:    JUnitByFile,
+			failures: 2,
+			cases: map[string]string{
+				"a.go": "a.go:3:8: warning [unused_import] unused import",
+				"b.go": "b.go:7:2: error [syntax] parse error: b.go:7:2: expected declaration",
+				"c.go": "",
+			},
+		},
+		{
+			name:     "by rule",
+			group:    JUnitByRule,
+			failures: 2,
+			cases: map[string]string{
+				"syntax":        "b.go:7:2: error [syntax] parse error: b.go:7:2: expected declaration",
+				"todo":          "",
+				"unused_import": "a.go:3:8: warning [unused_import] unused import",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteJUnit(&buf, result, tt.group); err != nil {
+				t.Fatalf("Failed to write report: %v", err)
+			}
+
+			var got report
+			if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("Failed to parse report: %v\n%s", err, buf.String())
+			}
+			if got.Tests != len(tt.cases) || got.Failures != tt.failures {
+				t.Errorf("tests = %d, failures = %d, want %d, %d", got.Tests, got.Failures, len(tt.cases), tt.failures)
+			}
+
+			cases := map[string]string{}
+			for _, c := range got.Cases {
+				cases[c.Name] = ""
+				if c.Failure != nil {
+					cases[c.Name] = c.Failure.Text
+				}
+			}
+			if !reflect.DeepEqual(cases, tt.cases) {
+				t.Errorf("test cases = %q, want %q", cases, tt.cases)
+			}
+		})
+	}
+
+	// Informational findings are written to the test output
+	var buf bytes.Buffer
+	if err := WriteJUnit(&buf, result, JUnitByFile); err != nil {
+		t.Fatalf("Failed to write report: %v", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("<system-out>c.go:1:1: info [todo] TODO found</system-out>")) {
+		t.Errorf("report does not contain the info finding:\n%s", buf.String())
+	}
+
+	if err := WriteJUnit(&buf, result, JUnitGrouping(7)); !errors.Is(err, readgo.ErrInvalidInput) {
+		t.Errorf("WriteJUnit() error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestWriteJUnitNoFindings(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJUnit(&buf, &readgo.ValidationResult{Name: "proj", Valid: true}, JUnitByFile); err != nil {
+		t.Fatalf("Failed to write report: %v", err)
+	}
+	want := xml.Header + `<testsuites name="readgo" tests="1" failures="0">
+  <testsuite name="readgo.proj" tests="1" failures="0">
+    <testcase name="proj" classname="readgo.proj"></testcase>
+  </testsuite>
+</testsuites>
+`
+	if buf.String() != want {
+		t.Errorf("report = %s, want %s", buf.String(), want)
+	}
+}
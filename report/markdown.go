@@ -0,0 +1,135 @@
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/iamlongalong/readgo"
+)
+
+// severityHeadings are the section headings of the findings of each severity
+var severityHeadings = map[readgo.Severity]string{
+	readgo.SeverityError:   "Errors",
+	readgo.SeverityWarning: "Warnings",
+	readgo.SeverityInfo:    "Info",
+}
+
+// WriteValidationMarkdown renders result as a Markdown summary suitable for
+// posting as a pull request comment, with the findings grouped by severity
+// in tables ordered by file and position
+func WriteValidationMarkdown(w io.Writer, result *readgo.ValidationResult) error {
+	bySeverity := map[readgo.Severity][]issue{}
+	for _, is := range collectIssues(result) {
+		bySeverity[is.Severity] = append(bySeverity[is.Severity], is)
+	}
+
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, "## Validation report: %s\n\n", escapeMarkdown(result.Name))
+	status := "passed"
+	if !result.Valid {
+		status = "failed"
+	}
+	fmt.Fprintf(b, "**Validation %s** for %s at level %s: ", status, codeSpan(result.Path), result.Level)
+	for i, sev := range severities {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(b, "%s %d", strings.ToLower(severityHeadings[sev]), len(bySeverity[sev]))
+	}
+	b.WriteString("\n")
+	if result.Cancelled {
+		b.WriteString("\n> Validation was cancelled; the findings are partial.\n")
+	}
+
+	for _, sev := range severities {
+		issues := bySeverity[sev]
+		if len(issues) == 0 {
+			continue
+		}
+		fmt.Fprintf(b, "\n### %s (%d)\n\n", severityHeadings[sev], len(issues))
+		b.WriteString("| File | Line | Rule | Message |\n|------|-----:|------|---------|\n")
+		for _, is := range issues {
+			line := ""
+			if is.Line > 0 {
+				line = fmt.Sprint(is.Line)
+			}
+			fmt.Fprintf(b, "| %s | %s | %s | %s |\n", codeSpan(is.File), line, codeSpan(is.Rule), escapeMarkdown(is.Message))
+		}
+	}
+	return b.Flush()
+}
+
+// WriteAnalysisMarkdown renders result as a Markdown summary suitable for
+// posting as a pull request comment, with tables of the types and functions
+// found by the analyzer
+func WriteAnalysisMarkdown(w io.Writer, result *readgo.AnalysisResult) error {
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, "## Analysis report: %s\n\n", escapeMarkdown(result.Name))
+	fmt.Fprintf(b, "Analyzed %s: types %d, functions %d, imports %d\n",
+		codeSpan(result.Path), len(result.Types), len(result.Functions), len(result.Imports))
+	if result.Cancelled {
+		b.WriteString("\n> Analysis was cancelled; the result is partial.\n")
+	}
+
+	if len(result.Types) > 0 {
+		fmt.Fprintf(b, "\n### Types (%d)\n\n", len(result.Types))
+		b.WriteString("| Name | Package | Type | Exported |\n|------|---------|------|----------|\n")
+		for _, t := range result.Types {
+			fmt.Fprintf(b, "| %s | %s | %s | %s |\n", codeSpan(t.Name), codeSpan(t.Package), codeSpan(t.Type), yesNo(t.IsExported))
+		}
+	}
+	if len(result.Functions) > 0 {
+		fmt.Fprintf(b, "\n### Functions (%d)\n\n", len(result.Functions))
+		b.WriteString("| Name | Package | Exported |\n|------|---------|----------|\n")
+		for _, f := range result.Functions {
+			fmt.Fprintf(b, "| %s | %s | %s |\n", codeSpan(f.Name), codeSpan(f.Package), yesNo(f.IsExported))
+		}
+	}
+	return b.Flush()
+}
+
+// markdownEscaper escapes the characters that would otherwise start Markdown
+// or HTML markup, or end a table cell
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"<", "&lt;", ">", "&gt;", "|", `\|`, "\r\n", " ", "\n", " ",
+)
+
+// escapeMarkdown returns s as literal text that fits on one table row
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// codeSpan returns s as an inline code span that fits in a table cell, or ""
+// if s is empty. The fence is longer than any run of backticks in s.
+func codeSpan(s string) string {
+	if s == "" {
+		return ""
+	}
+	s = strings.NewReplacer("\r\n", " ", "\n", " ", "|", `\|`).Replace(s)
+	longest, run := 0, 0
+	for _, r := range s {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	fence := strings.Repeat("`", longest+1)
+	if longest > 0 {
+		// Spaces keep backticks at either end from joining the fence
+		return fence + " " + s + " " + fence
+	}
+	return fence + s + fence
+}
+
+// yesNo returns "yes" or "no"
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,73 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/iamlongalong/readgo"
+)
+
+func TestWriteValidationMarkdown(t *testing.T) {
+	result := &readgo.ValidationResult{
+		Name:   "a",
+		Path:   "./a",
+		Level:  readgo.ValidationLevelStandard,
+		Errors: []string{"parse error: b.go:7:2: expected <declaration>"},
+		Warnings: []readgo.ValidationWarning{
+			{Type: "unused_import", Message: `unused import: "fmt"`, File: "a.go", Line: 3},
+			{Type: "naming", Severity: readgo.SeverityWarning, Message: "rename my_var | myVar", File: "a.go", Line: 1},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteValidationMarkdown(&buf, result); err != nil {
+		t.Fatalf("Failed to write report: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"## Validation report: a\n",
+		"**Validation failed** for `./a` at level standard: errors 1, warnings 2, info 0\n",
+		"### Errors (1)\n",
+		"| `b.go` | 7 | `syntax` | parse error: b.go:7:2: expected &lt;declaration&gt; |\n",
+		"### Warnings (2)\n",
+		"| `a.go` | 1 | `naming` | rename my\\_var \\| myVar |\n| `a.go` | 3 | `unused_import` |",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report does not contain %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "### Info") {
+		t.Error("report has a section without findings")
+	}
+}
+
+func TestWriteAnalysisMarkdown(t *testing.T) {
+	result := &readgo.AnalysisResult{
+		Name:      "pkg",
+		Path:      "./pkg",
+		Types:     []readgo.TypeInfo{{Name: "User", Package: "pkg", Type: "struct{Name string `json:\"name\"`}", IsExported: true}},
+		Functions: []readgo.FunctionInfo{{Name: "new", Package: "pkg"}},
+		Imports:   []string{"fmt"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteAnalysisMarkdown(&buf, result); err != nil {
+		t.Fatalf("Failed to write report: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"## Analysis report: pkg\n",
+		"Analyzed `./pkg`: types 1, functions 1, imports 1\n",
+		"### Types (1)\n",
+		"| `User` | `pkg` | `` struct{Name string `json:\"name\"`} `` | yes |\n",
+		"### Functions (1)\n",
+		"| `new` | `pkg` | no |\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report does not contain %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Package report renders readgo results for people and CI systems
+package report
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/iamlongalong/readgo"
+)
+
+// issue is a validation error or warning with its position split out
+type issue struct {
+	File     string
+	Line     int
+	Column   int
+	Severity readgo.Severity
+	Rule     string
+	Message  string
+}
+
+// errorPosition matches the "file:line[:col]" position inside error strings
+// such as "parse error: a.go:3:1: expected declaration"
+var errorPosition = regexp.MustCompile(`(\S+\.go):(\d+)(?::(\d+))?(?::\s*|$)`)
+
+// collectIssues returns the errors and warnings of result, sorted by file
+// and position. Errors are reported as plain strings by the validator, so
+// their position is recovered from the text when present.
+func collectIssues(result *readgo.ValidationResult) []issue {
+	var issues []issue
+	for _, e := range result.Errors {
+		is := issue{Severity: readgo.SeverityError, Rule: "error", Message: e}
+		if m := errorPosition.FindStringSubmatchIndex(e); m != nil {
+			is.File = e[m[2]:m[3]]
+			is.Line, _ = strconv.Atoi(e[m[4]:m[5]])
+			if m[6] >= 0 {
+				is.Column, _ = strconv.Atoi(e[m[6]:m[7]])
+			}
+			// Drop the position from "file:line:col: message"
+			if m[0] == 0 && m[1] < len(e) {
+				is.Message = e[m[1]:]
+			}
+		}
+		if strings.HasPrefix(e, "parse error: ") || strings.HasPrefix(e, "syntax error ") {
+			is.Rule = "syntax"
+		}
+		issues = append(issues, is)
+	}
+	for _, w := range result.Warnings {
+		severity := w.Severity
+		if severity == "" {
+			severity = readgo.SeverityWarning
+		}
+		issues = append(issues, issue{
+			File:     w.File,
+			Line:     w.Line,
+			Column:   w.Column,
+			Severity: severity,
+			Rule:     w.Type,
+			Message:  w.Message,
+		})
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return issues
+}
@@ -0,0 +1,119 @@
+package report
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/iamlongalong/readgo"
+)
+
+//go:embed templates/*.tmpl
+var defaultTemplates embed.FS
+
+// Template renders results with a text/template, so teams can lay out
+// reports their own way without post-processing JSON
+type Template struct {
+	tmpl *template.Template
+}
+
+// Finding is a validation error or warning as seen by templates
+type Finding struct {
+	File     string
+	Line     int
+	Column   int
+	Severity readgo.Severity
+	Rule     string
+	Message  string
+}
+
+// ValidationData is what validation templates are executed with
+type ValidationData struct {
+	Result *readgo.ValidationResult
+
+	// Findings are the errors and warnings of Result, sorted by file and
+	// position, with the position of errors recovered from their text
+	Findings []Finding
+
+	// Counts is the number of findings of each severity, keyed by
+	// "error", "warning" and "info"
+	Counts map[string]int
+}
+
+// AnalysisData is what analysis templates are executed with
+type AnalysisData struct {
+	Result *readgo.AnalysisResult
+}
+
+// templateFuncs are the functions available to templates besides the
+// text/template builtins
+var templateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// NewTemplate parses text as a report template named name. Besides the
+// builtins, templates can call join, lower, upper and json.
+func NewTemplate(name, text string) (*Template, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: parse template %s: %v", readgo.ErrInvalidInput, name, err)
+	}
+	return &Template{tmpl: tmpl}, nil
+}
+
+// LoadTemplate reads and parses the report template at path
+func LoadTemplate(path string) (*Template, error) {
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read template: %w", err)
+	}
+	return NewTemplate(filepath.Base(path), string(text))
+}
+
+// DefaultTemplate returns a built-in template: "validation" lists findings
+// one per line as file:line:col: severity: message, and "analysis" lists
+// the types and functions found
+func DefaultTemplate(name string) (*Template, error) {
+	text, err := defaultTemplates.ReadFile("templates/" + name + ".tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("%w: template %q", readgo.ErrNotFound, name)
+	}
+	return NewTemplate(name, string(text))
+}
+
+// WriteValidation renders result with t, executing it with a ValidationData
+func (t *Template) WriteValidation(w io.Writer, result *readgo.ValidationResult) error {
+	data := ValidationData{Result: result, Findings: []Finding{}, Counts: map[string]int{}}
+	for _, sev := range severities {
+		data.Counts[string(sev)] = 0
+	}
+	for _, is := range collectIssues(result) {
+		data.Findings = append(data.Findings, Finding(is))
+		data.Counts[string(is.Severity)]++
+	}
+	return t.execute(w, data)
+}
+
+// WriteAnalysis renders result with t, executing it with an AnalysisData
+func (t *Template) WriteAnalysis(w io.Writer, result *readgo.AnalysisResult) error {
+	return t.execute(w, AnalysisData{Result: result})
+}
+
+// execute runs t on data
+func (t *Template) execute(w io.Writer, data interface{}) error {
+	if err := t.tmpl.Execute(w, data); err != nil {
+		return fmt.Errorf("execute template %s: %w", t.tmpl.Name(), err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iamlongalong/readgo"
+)
+
+func TestTemplate(t *testing.T) {
+	validation := &readgo.ValidationResult{
+		Path:   "./a",
+		Level:  readgo.ValidationLevelStandard,
+		Errors: []string{"parse error: b.go:7:2: expected declaration"},
+		Warnings: []readgo.ValidationWarning{
+			{Type: "unused_import", Message: `unused import: "fmt"`, File: "a.go", Line: 3, Column: 8},
+		},
+	}
+	analysis := &readgo.AnalysisResult{
+		Path:      "./pkg",
+		Types:     []readgo.TypeInfo{{Name: "User", Package: "pkg", Kind: readgo.TypeKindStruct, IsExported: true}},
+		Functions: []readgo.FunctionInfo{{Name: "new", Package: "pkg"}},
+	}
+
+	custom := filepath.Join(t.TempDir(), "report.tmpl")
+	text := `{{range .Findings}}{{upper .Rule}} {{.File}}:{{.Line}}|{{end}}{{json .Counts}}`
+	if err := os.WriteFile(custom, []byte(text), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		load  func() (*Template, error)
+		write func(*Template, *bytes.Buffer) error
+		want  []string
+	}{
+		{
+			name:  "default validation",
+			load:  func() (*Template, error) { return DefaultTemplate("validation") },
+			write: func(tmpl *Template, b *bytes.Buffer) error { return tmpl.WriteValidation(b, validation) },
+			want: []string{
+				"Validation failed for ./a at level standard: errors 1, warnings 1, info 0\n",
+				"\na.go:3:8: warning: unused import: \"fmt\" (unused_import)\nb.go:7:2: error: parse error: b.go:7:2: expected declaration (syntax)\n",
+			},
+		},
+		{
+			name:  "default analysis",
+			load:  func() (*Template, error) { return DefaultTemplate("analysis") },
+			write: func(tmpl *Template, b *bytes.Buffer) error { return tmpl.WriteAnalysis(b, analysis) },
+			want:  []string{"types 1, functions 1, imports 0\n", "  pkg.User struct exported\n", "  pkg.new\n"},
+		},
+		{
+			name:  "custom",
+			load:  func() (*Template, error) { return LoadTemplate(custom) },
+			write: func(tmpl *Template, b *bytes.Buffer) error { return tmpl.WriteValidation(b, validation) },
+			want:  []string{`UNUSED_IMPORT a.go:3|SYNTAX b.go:7|{"error":1,"info":0,"warning":1}`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := tt.load()
+			if err != nil {
+				t.Fatalf("Failed to load template: %v", err)
+			}
+			var buf bytes.Buffer
+			if err := tt.write(tmpl, &buf); err != nil {
+				t.Fatalf("Failed to write report: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("report does not contain %q:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+
+	if _, err := DefaultTemplate("xml"); !errors.Is(err, readgo.ErrNotFound) {
+		t.Errorf("DefaultTemplate(xml) error = %v, want ErrNotFound", err)
+	}
+	if _, err := NewTemplate("bad", "{{.Result"); !errors.Is(err, readgo.ErrInvalidInput) {
+		t.Errorf("NewTemplate() error = %v, want ErrInvalidInput", err)
+	}
+	tmpl, err := NewTemplate("analysis only", "{{.Findings}}")
+	if err != nil {
+		t.Fatalf("Failed to parse template: %v", err)
+	}
+	if err := tmpl.WriteAnalysis(&bytes.Buffer{}, analysis); err == nil {
+		t.Error("WriteAnalysis() with a validation template succeeded")
+	}
+}
@@ -0,0 +1,49 @@
+// Command readgo-grpc serves the ReadGo gRPC service for one project:
+//
+//	readgo-grpc -addr :50051 -root /path/to/project
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+
+	"github.com/iamlongalong/readgo/rpc"
+	"github.com/iamlongalong/readgo/rpc/readgopb"
+	"google.golang.org/grpc"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:50051", "address to listen on")
+	root := flag.String("root", ".", "directory of the project to serve")
+	flag.Parse()
+	if err := serve(*addr, *root); err != nil {
+		fmt.Fprintf(os.Stderr, "readgo-grpc: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// serve serves the project in root on addr until interrupted
+func serve(addr, root string) error {
+	srv, err := rpc.NewServer(root)
+	if err != nil {
+		return err
+	}
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	g := grpc.NewServer()
+	readgopb.RegisterReadGoServer(g, srv)
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		g.GracefulStop()
+	}()
+	fmt.Fprintf(os.Stderr, "serving %s on %s\n", root, lis.Addr())
+	return g.Serve(lis)
+}
@@ -0,0 +1,342 @@
+package rpc
+
+import (
+	"encoding/json"
+
+	"github.com/iamlongalong/readgo"
+	"github.com/iamlongalong/readgo/rpc/readgopb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// typeKinds maps the kinds of types to their protobuf enum values
+var typeKinds = map[readgo.TypeKind]readgopb.TypeKind{
+	readgo.TypeKindStruct:    readgopb.TypeKind_TYPE_KIND_STRUCT,
+	readgo.TypeKindInterface: readgopb.TypeKind_TYPE_KIND_INTERFACE,
+	readgo.TypeKindAlias:     readgopb.TypeKind_TYPE_KIND_ALIAS,
+	readgo.TypeKindFunc:      readgopb.TypeKind_TYPE_KIND_FUNC,
+	readgo.TypeKindMap:       readgopb.TypeKind_TYPE_KIND_MAP,
+	readgo.TypeKindSlice:     readgopb.TypeKind_TYPE_KIND_SLICE,
+	readgo.TypeKindArray:     readgopb.TypeKind_TYPE_KIND_ARRAY,
+	readgo.TypeKindPointer:   readgopb.TypeKind_TYPE_KIND_POINTER,
+	readgo.TypeKindChan:      readgopb.TypeKind_TYPE_KIND_CHAN,
+	readgo.TypeKindBasic:     readgopb.TypeKind_TYPE_KIND_BASIC,
+}
+
+// dependencyKinds maps the kinds of dependencies to their protobuf enum values
+var dependencyKinds = map[readgo.DependencyKind]readgopb.DependencyKind{
+	readgo.DependencyStandard: readgopb.DependencyKind_DEPENDENCY_KIND_STD,
+	readgo.DependencyInternal: readgopb.DependencyKind_DEPENDENCY_KIND_INTERNAL,
+	readgo.DependencyExternal: readgopb.DependencyKind_DEPENDENCY_KIND_EXTERNAL,
+}
+
+// severities maps the severities of findings to their protobuf enum values
+var severities = map[readgo.Severity]readgopb.Severity{
+	readgo.SeverityInfo:    readgopb.Severity_SEVERITY_INFO,
+	readgo.SeverityWarning: readgopb.Severity_SEVERITY_WARNING,
+	readgo.SeverityError:   readgopb.Severity_SEVERITY_ERROR,
+}
+
+// fileTypes maps the protobuf file types to readgo's, leaving out
+// FILE_TYPE_UNSPECIFIED, which selects all files
+var fileTypes = map[readgopb.FileType]readgo.FileType{
+	readgopb.FileType_FILE_TYPE_ALL:        readgo.FileTypeAll,
+	readgopb.FileType_FILE_TYPE_GO:         readgo.FileTypeGo,
+	readgopb.FileType_FILE_TYPE_TEST:       readgo.FileTypeTest,
+	readgopb.FileType_FILE_TYPE_GENERATED:  readgo.FileTypeGenerated,
+	readgopb.FileType_FILE_TYPE_GO_PACKAGE: readgo.FileTypeGoPackage,
+}
+
+// validationLevel converts a protobuf validation level, defaulting to the
+// standard level
+func validationLevel(level readgopb.ValidationLevel) readgo.ValidationLevel {
+	switch level {
+	case readgopb.ValidationLevel_VALIDATION_LEVEL_BASIC:
+		return readgo.ValidationLevelBasic
+	case readgopb.ValidationLevel_VALIDATION_LEVEL_STRICT:
+		return readgo.ValidationLevelStrict
+	default:
+		return readgo.ValidationLevelStandard
+	}
+}
+
+// fromValidationLevel converts a validation level to its protobuf enum value
+func fromValidationLevel(level readgo.ValidationLevel) readgopb.ValidationLevel {
+	switch level {
+	case readgo.ValidationLevelBasic:
+		return readgopb.ValidationLevel_VALIDATION_LEVEL_BASIC
+	case readgo.ValidationLevelStrict:
+		return readgopb.ValidationLevel_VALIDATION_LEVEL_STRICT
+	default:
+		return readgopb.ValidationLevel_VALIDATION_LEVEL_STANDARD
+	}
+}
+
+// treeOptions converts protobuf tree options; nil yields the defaults
+func treeOptions(o *readgopb.TreeOptions) readgo.TreeOptions {
+	opts := readgo.TreeOptions{
+		FileTypes:         readgo.FileTypeAll,
+		ExcludePatterns:   o.GetExcludePatterns(),
+		IncludePatterns:   o.GetIncludePatterns(),
+		ComputeHashes:     o.GetComputeHashes(),
+		SkipBinary:        o.GetSkipBinary(),
+		DetectBinary:      o.GetDetectBinary(),
+		NoDefaultExcludes: o.GetNoDefaultExcludes(),
+		PackageNames:      o.GetPackageNames(),
+		Limit:             int(o.GetLimit()),
+		Offset:            int(o.GetOffset()),
+	}
+	if fileType, ok := fileTypes[o.GetFileTypes()]; ok {
+		opts.FileTypes = fileType
+	}
+	return opts
+}
+
+// readOptions converts protobuf read options; nil yields the defaults
+func readOptions(o *readgopb.ReadOptions) readgo.ReadOptions {
+	return readgo.ReadOptions{
+		IncludeComments: o.GetIncludeComments(),
+		StripSpaces:     o.GetStripSpaces(),
+		WithLineNumbers: o.GetWithLineNumbers(),
+		Format:          o.GetFormat(),
+		Transcode:       o.GetTranscode(),
+	}
+}
+
+// fileEntry converts a file tree node, without its children
+func fileEntry(n *readgo.FileTreeNode, depth int) *readgopb.FileEntry {
+	e := &readgopb.FileEntry{
+		Name:        n.Name,
+		Path:        n.Path,
+		Type:        n.Type,
+		Size:        n.Size,
+		Hash:        n.Hash,
+		IsBinary:    n.IsBinary,
+		Package:     n.Package,
+		FileCount:   int32(n.FileCount),
+		GoFileCount: int32(n.GoFileCount),
+		Truncated:   n.Truncated,
+		Depth:       int32(depth),
+	}
+	if !n.ModTime.IsZero() {
+		e.ModTime = timestamppb.New(n.ModTime)
+	}
+	return e
+}
+
+// typeInfo converts a type
+func typeInfo(t *readgo.TypeInfo) *readgopb.TypeInfo {
+	return &readgopb.TypeInfo{
+		Name:       t.Name,
+		Package:    t.Package,
+		Type:       t.Type,
+		Kind:       typeKinds[t.Kind],
+		IsExported: t.IsExported,
+	}
+}
+
+// typeInfos converts a list of types
+func typeInfos(types []readgo.TypeInfo) []*readgopb.TypeInfo {
+	out := make([]*readgopb.TypeInfo, len(types))
+	for i := range types {
+		out[i] = typeInfo(&types[i])
+	}
+	return out
+}
+
+// functionInfos converts a list of functions
+func functionInfos(funcs []readgo.FunctionInfo) []*readgopb.FunctionInfo {
+	out := make([]*readgopb.FunctionInfo, len(funcs))
+	for i, f := range funcs {
+		out[i] = &readgopb.FunctionInfo{
+			Name:       f.Name,
+			Package:    f.Package,
+			IsExported: f.IsExported,
+			Signature:  f.Signature,
+			Receiver:   f.Receiver,
+			IsMethod:   f.IsMethod,
+			File:       f.File,
+			StartLine:  int32(f.StartLine),
+			EndLine:    int32(f.EndLine),
+			Doc:        f.Doc,
+		}
+	}
+	return out
+}
+
+// dependencies converts a list of dependencies
+func dependencies(deps []readgo.Dependency) []*readgopb.Dependency {
+	out := make([]*readgopb.Dependency, len(deps))
+	for i, d := range deps {
+		out[i] = &readgopb.Dependency{Path: d.Path, Kind: dependencyKinds[d.Kind]}
+	}
+	return out
+}
+
+// entityValue converts the value of an entity through its JSON encoding,
+// returning nil for values that do not encode
+func entityValue(v interface{}) *structpb.Value {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil
+	}
+	value, err := structpb.NewValue(decoded)
+	if err != nil {
+		return nil
+	}
+	return value
+}
+
+// moduleInfo converts module metadata, nil outside modules
+func moduleInfo(m *readgo.ModuleInfo) *readgopb.ModuleInfo {
+	if m == nil {
+		return nil
+	}
+	out := &readgopb.ModuleInfo{Path: m.Path, GoVersion: m.GoVersion}
+	for _, r := range m.Requires {
+		out.Requires = append(out.Requires, &readgopb.ModuleRequirement{
+			Path:     r.Path,
+			Version:  r.Version,
+			Indirect: r.Indirect,
+			Replace:  r.Replace,
+			Sum:      r.Sum,
+		})
+	}
+	return out
+}
+
+// analysisResult converts an analysis result
+func analysisResult(r *readgo.AnalysisResult) *readgopb.AnalysisResult {
+	out := &readgopb.AnalysisResult{
+		Name:          r.Name,
+		Path:          r.Path,
+		Types:         typeInfos(r.Types),
+		Functions:     functionInfos(r.Functions),
+		Imports:       r.Imports,
+		Dependencies:  dependencies(r.Dependencies),
+		Cancelled:     r.Cancelled,
+		Module:        moduleInfo(r.Module),
+		SchemaVersion: r.SchemaVersion,
+		ToolVersion:   r.ToolVersion,
+	}
+	if !r.AnalyzedAt.IsZero() {
+		out.AnalyzedAt = timestamppb.New(r.AnalyzedAt)
+	}
+	if len(r.Extensions) > 0 {
+		out.Extensions = make(map[string]*readgopb.Entities, len(r.Extensions))
+		for name, entities := range r.Extensions {
+			list := &readgopb.Entities{}
+			for _, e := range entities {
+				list.Entities = append(list.Entities, &readgopb.Entity{
+					Name:    e.Name,
+					Package: e.Package,
+					File:    e.File,
+					Line:    int32(e.Line),
+					Value:   entityValue(e.Value),
+				})
+			}
+			out.Extensions[name] = list
+		}
+	}
+	for _, p := range r.Packages {
+		out.Packages = append(out.Packages, &readgopb.PackageAnalysis{
+			Name:         p.Name,
+			Path:         p.Path,
+			Types:        typeInfos(p.Types),
+			Functions:    functionInfos(p.Functions),
+			Imports:      p.Imports,
+			Dependencies: dependencies(p.Dependencies),
+		})
+	}
+	return out
+}
+
+// validationWarning converts a warning; warnings without a severity are
+// reported as warnings
+func validationWarning(w readgo.ValidationWarning) *readgopb.ValidationWarning {
+	severity, ok := severities[w.Severity]
+	if !ok {
+		severity = readgopb.Severity_SEVERITY_WARNING
+	}
+	out := &readgopb.ValidationWarning{
+		Type:     w.Type,
+		Severity: severity,
+		Message:  w.Message,
+		File:     w.File,
+		Line:     int32(w.Line),
+		Column:   int32(w.Column),
+	}
+	for _, fix := range w.SuggestedFixes {
+		f := &readgopb.SuggestedFix{Message: fix.Message}
+		for _, e := range fix.Edits {
+			f.Edits = append(f.Edits, &readgopb.TextEdit{
+				File:        e.File,
+				Start:       int32(e.Start),
+				End:         int32(e.End),
+				StartLine:   int32(e.StartLine),
+				StartColumn: int32(e.StartColumn),
+				EndLine:     int32(e.EndLine),
+				EndColumn:   int32(e.EndColumn),
+				NewText:     e.NewText,
+			})
+		}
+		out.SuggestedFixes = append(out.SuggestedFixes, f)
+	}
+	return out
+}
+
+// validationResult converts a validation result
+func validationResult(r *readgo.ValidationResult) *readgopb.ValidationResult {
+	out := &readgopb.ValidationResult{
+		Name:          r.Name,
+		Path:          r.Path,
+		Level:         fromValidationLevel(r.Level),
+		Valid:         r.Valid,
+		Errors:        r.Errors,
+		Cancelled:     r.Cancelled,
+		SchemaVersion: r.SchemaVersion,
+		ToolVersion:   r.ToolVersion,
+		Stats: &readgopb.ValidationStats{
+			Suppressed: int32(r.Stats.Suppressed),
+			Coverage:   r.Stats.Coverage,
+		},
+	}
+	if !r.AnalyzedAt.IsZero() {
+		out.AnalyzedAt = timestamppb.New(r.AnalyzedAt)
+	}
+	if len(r.Stats.SuppressedByRule) > 0 {
+		out.Stats.SuppressedByRule = make(map[string]int32, len(r.Stats.SuppressedByRule))
+		for rule, n := range r.Stats.SuppressedByRule {
+			out.Stats.SuppressedByRule[rule] = int32(n)
+		}
+	}
+	for _, w := range r.Warnings {
+		out.Warnings = append(out.Warnings, validationWarning(w))
+	}
+	for _, c := range r.CircularDeps {
+		out.CircularDeps = append(out.CircularDeps, &readgopb.Cycle{
+			Packages: c.Packages,
+			File:     c.File,
+			Line:     int32(c.Line),
+			Column:   int32(c.Column),
+		})
+	}
+	for _, t := range r.Todos {
+		out.Todos = append(out.Todos, &readgopb.TodoComment{
+			Tag:    t.Tag,
+			Author: t.Author,
+			Text:   t.Text,
+			File:   t.File,
+			Line:   int32(t.Line),
+			Column: int32(t.Column),
+		})
+	}
+	return out
+}
@@ -0,0 +1,511 @@
+// Protocol buffer definitions of readgo's result types and of the ReadGo
+// service, which serves the reader, analyzer and validator over gRPC. The
+// messages mirror the Go types of github.com/iamlongalong/readgo field by
+// field; field names match their JSON names.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: readgo/v1/readgo.proto
+
+package readgopb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ReadGo_ReadFile_FullMethodName        = "/readgo.v1.ReadGo/ReadFile"
+	ReadGo_GetFileTree_FullMethodName     = "/readgo.v1.ReadGo/GetFileTree"
+	ReadGo_SearchFiles_FullMethodName     = "/readgo.v1.ReadGo/SearchFiles"
+	ReadGo_FindType_FullMethodName        = "/readgo.v1.ReadGo/FindType"
+	ReadGo_AnalyzeFile_FullMethodName     = "/readgo.v1.ReadGo/AnalyzeFile"
+	ReadGo_AnalyzePackage_FullMethodName  = "/readgo.v1.ReadGo/AnalyzePackage"
+	ReadGo_AnalyzeProject_FullMethodName  = "/readgo.v1.ReadGo/AnalyzeProject"
+	ReadGo_ValidateFile_FullMethodName    = "/readgo.v1.ReadGo/ValidateFile"
+	ReadGo_ValidatePackage_FullMethodName = "/readgo.v1.ReadGo/ValidatePackage"
+	ReadGo_ValidateProject_FullMethodName = "/readgo.v1.ReadGo/ValidateProject"
+)
+
+// ReadGoClient is the client API for ReadGo service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ReadGo reads, analyzes and validates the Go project the server is rooted
+// at. Paths are relative to that root. Operations whose results grow with
+// the project stream them.
+type ReadGoClient interface {
+	// ReadFile streams the content of a file in chunks
+	ReadFile(ctx context.Context, in *ReadFileRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ReadFileResponse], error)
+	// GetFileTree streams the nodes of a file tree in depth-first order
+	GetFileTree(ctx context.Context, in *GetFileTreeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FileEntry], error)
+	// SearchFiles streams the files whose name matches a glob
+	SearchFiles(ctx context.Context, in *SearchFilesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FileEntry], error)
+	// FindType looks up a type, interface or function of a package
+	FindType(ctx context.Context, in *FindTypeRequest, opts ...grpc.CallOption) (*TypeInfo, error)
+	// AnalyzeFile analyzes a single Go source file
+	AnalyzeFile(ctx context.Context, in *AnalyzeFileRequest, opts ...grpc.CallOption) (*AnalysisResult, error)
+	// AnalyzePackage analyzes a Go package
+	AnalyzePackage(ctx context.Context, in *AnalyzePackageRequest, opts ...grpc.CallOption) (*AnalysisResult, error)
+	// AnalyzeProject streams the analysis of every package of the project,
+	// one package at a time in import path order
+	AnalyzeProject(ctx context.Context, in *AnalyzeProjectRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AnalyzeProjectResponse], error)
+	// ValidateFile validates a single Go source file
+	ValidateFile(ctx context.Context, in *ValidateFileRequest, opts ...grpc.CallOption) (*ValidationResult, error)
+	// ValidatePackage validates a Go package
+	ValidatePackage(ctx context.Context, in *ValidatePackageRequest, opts ...grpc.CallOption) (*ValidationResult, error)
+	// ValidateProject validates the whole project
+	ValidateProject(ctx context.Context, in *ValidateProjectRequest, opts ...grpc.CallOption) (*ValidationResult, error)
+}
+
+type readGoClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewReadGoClient(cc grpc.ClientConnInterface) ReadGoClient {
+	return &readGoClient{cc}
+}
+
+func (c *readGoClient) ReadFile(ctx context.Context, in *ReadFileRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ReadFileResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ReadGo_ServiceDesc.Streams[0], ReadGo_ReadFile_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ReadFileRequest, ReadFileResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ReadGo_ReadFileClient = grpc.ServerStreamingClient[ReadFileResponse]
+
+func (c *readGoClient) GetFileTree(ctx context.Context, in *GetFileTreeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FileEntry], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ReadGo_ServiceDesc.Streams[1], ReadGo_GetFileTree_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GetFileTreeRequest, FileEntry]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ReadGo_GetFileTreeClient = grpc.ServerStreamingClient[FileEntry]
+
+func (c *readGoClient) SearchFiles(ctx context.Context, in *SearchFilesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FileEntry], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ReadGo_ServiceDesc.Streams[2], ReadGo_SearchFiles_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SearchFilesRequest, FileEntry]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ReadGo_SearchFilesClient = grpc.ServerStreamingClient[FileEntry]
+
+func (c *readGoClient) FindType(ctx context.Context, in *FindTypeRequest, opts ...grpc.CallOption) (*TypeInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TypeInfo)
+	err := c.cc.Invoke(ctx, ReadGo_FindType_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *readGoClient) AnalyzeFile(ctx context.Context, in *AnalyzeFileRequest, opts ...grpc.CallOption) (*AnalysisResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AnalysisResult)
+	err := c.cc.Invoke(ctx, ReadGo_AnalyzeFile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *readGoClient) AnalyzePackage(ctx context.Context, in *AnalyzePackageRequest, opts ...grpc.CallOption) (*AnalysisResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AnalysisResult)
+	err := c.cc.Invoke(ctx, ReadGo_AnalyzePackage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *readGoClient) AnalyzeProject(ctx context.Context, in *AnalyzeProjectRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AnalyzeProjectResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ReadGo_ServiceDesc.Streams[3], ReadGo_AnalyzeProject_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[AnalyzeProjectRequest, AnalyzeProjectResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ReadGo_AnalyzeProjectClient = grpc.ServerStreamingClient[AnalyzeProjectResponse]
+
+func (c *readGoClient) ValidateFile(ctx context.Context, in *ValidateFileRequest, opts ...grpc.CallOption) (*ValidationResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidationResult)
+	err := c.cc.Invoke(ctx, ReadGo_ValidateFile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *readGoClient) ValidatePackage(ctx context.Context, in *ValidatePackageRequest, opts ...grpc.CallOption) (*ValidationResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidationResult)
+	err := c.cc.Invoke(ctx, ReadGo_ValidatePackage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *readGoClient) ValidateProject(ctx context.Context, in *ValidateProjectRequest, opts ...grpc.CallOption) (*ValidationResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidationResult)
+	err := c.cc.Invoke(ctx, ReadGo_ValidateProject_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ReadGoServer is the server API for ReadGo service.
+// All implementations must embed UnimplementedReadGoServer
+// for forward compatibility.
+//
+// ReadGo reads, analyzes and validates the Go project the server is rooted
+// at. Paths are relative to that root. Operations whose results grow with
+// the project stream them.
+type ReadGoServer interface {
+	// ReadFile streams the content of a file in chunks
+	ReadFile(*ReadFileRequest, grpc.ServerStreamingServer[ReadFileResponse]) error
+	// GetFileTree streams the nodes of a file tree in depth-first order
+	GetFileTree(*GetFileTreeRequest, grpc.ServerStreamingServer[FileEntry]) error
+	// SearchFiles streams the files whose name matches a glob
+	SearchFiles(*SearchFilesRequest, grpc.ServerStreamingServer[FileEntry]) error
+	// FindType looks up a type, interface or function of a package
+	FindType(context.Context, *FindTypeRequest) (*TypeInfo, error)
+	// AnalyzeFile analyzes a single Go source file
+	AnalyzeFile(context.Context, *AnalyzeFileRequest) (*AnalysisResult, error)
+	// AnalyzePackage analyzes a Go package
+	AnalyzePackage(context.Context, *AnalyzePackageRequest) (*AnalysisResult, error)
+	// AnalyzeProject streams the analysis of every package of the project,
+	// one package at a time in import path order
+	AnalyzeProject(*AnalyzeProjectRequest, grpc.ServerStreamingServer[AnalyzeProjectResponse]) error
+	// ValidateFile validates a single Go source file
+	ValidateFile(context.Context, *ValidateFileRequest) (*ValidationResult, error)
+	// ValidatePackage validates a Go package
+	ValidatePackage(context.Context, *ValidatePackageRequest) (*ValidationResult, error)
+	// ValidateProject validates the whole project
+	ValidateProject(context.Context, *ValidateProjectRequest) (*ValidationResult, error)
+	mustEmbedUnimplementedReadGoServer()
+}
+
+// UnimplementedReadGoServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedReadGoServer struct{}
+
+func (UnimplementedReadGoServer) ReadFile(*ReadFileRequest, grpc.ServerStreamingServer[ReadFileResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ReadFile not implemented")
+}
+func (UnimplementedReadGoServer) GetFileTree(*GetFileTreeRequest, grpc.ServerStreamingServer[FileEntry]) error {
+	return status.Errorf(codes.Unimplemented, "method GetFileTree not implemented")
+}
+func (UnimplementedReadGoServer) SearchFiles(*SearchFilesRequest, grpc.ServerStreamingServer[FileEntry]) error {
+	return status.Errorf(codes.Unimplemented, "method SearchFiles not implemented")
+}
+func (UnimplementedReadGoServer) FindType(context.Context, *FindTypeRequest) (*TypeInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindType not implemented")
+}
+func (UnimplementedReadGoServer) AnalyzeFile(context.Context, *AnalyzeFileRequest) (*AnalysisResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AnalyzeFile not implemented")
+}
+func (UnimplementedReadGoServer) AnalyzePackage(context.Context, *AnalyzePackageRequest) (*AnalysisResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AnalyzePackage not implemented")
+}
+func (UnimplementedReadGoServer) AnalyzeProject(*AnalyzeProjectRequest, grpc.ServerStreamingServer[AnalyzeProjectResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method AnalyzeProject not implemented")
+}
+func (UnimplementedReadGoServer) ValidateFile(context.Context, *ValidateFileRequest) (*ValidationResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateFile not implemented")
+}
+func (UnimplementedReadGoServer) ValidatePackage(context.Context, *ValidatePackageRequest) (*ValidationResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidatePackage not implemented")
+}
+func (UnimplementedReadGoServer) ValidateProject(context.Context, *ValidateProjectRequest) (*ValidationResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateProject not implemented")
+}
+func (UnimplementedReadGoServer) mustEmbedUnimplementedReadGoServer() {}
+func (UnimplementedReadGoServer) testEmbeddedByValue()                {}
+
+// UnsafeReadGoServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ReadGoServer will
+// result in compilation errors.
+type UnsafeReadGoServer interface {
+	mustEmbedUnimplementedReadGoServer()
+}
+
+func RegisterReadGoServer(s grpc.ServiceRegistrar, srv ReadGoServer) {
+	// If the following call pancis, it indicates UnimplementedReadGoServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ReadGo_ServiceDesc, srv)
+}
+
+func _ReadGo_ReadFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReadFileRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ReadGoServer).ReadFile(m, &grpc.GenericServerStream[ReadFileRequest, ReadFileResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ReadGo_ReadFileServer = grpc.ServerStreamingServer[ReadFileResponse]
+
+func _ReadGo_GetFileTree_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetFileTreeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ReadGoServer).GetFileTree(m, &grpc.GenericServerStream[GetFileTreeRequest, FileEntry]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ReadGo_GetFileTreeServer = grpc.ServerStreamingServer[FileEntry]
+
+func _ReadGo_SearchFiles_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SearchFilesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ReadGoServer).SearchFiles(m, &grpc.GenericServerStream[SearchFilesRequest, FileEntry]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ReadGo_SearchFilesServer = grpc.ServerStreamingServer[FileEntry]
+
+func _ReadGo_FindType_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindTypeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReadGoServer).FindType(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReadGo_FindType_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReadGoServer).FindType(ctx, req.(*FindTypeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReadGo_AnalyzeFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnalyzeFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReadGoServer).AnalyzeFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReadGo_AnalyzeFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReadGoServer).AnalyzeFile(ctx, req.(*AnalyzeFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReadGo_AnalyzePackage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnalyzePackageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReadGoServer).AnalyzePackage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReadGo_AnalyzePackage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReadGoServer).AnalyzePackage(ctx, req.(*AnalyzePackageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReadGo_AnalyzeProject_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(AnalyzeProjectRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ReadGoServer).AnalyzeProject(m, &grpc.GenericServerStream[AnalyzeProjectRequest, AnalyzeProjectResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ReadGo_AnalyzeProjectServer = grpc.ServerStreamingServer[AnalyzeProjectResponse]
+
+func _ReadGo_ValidateFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReadGoServer).ValidateFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReadGo_ValidateFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReadGoServer).ValidateFile(ctx, req.(*ValidateFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReadGo_ValidatePackage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidatePackageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReadGoServer).ValidatePackage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReadGo_ValidatePackage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReadGoServer).ValidatePackage(ctx, req.(*ValidatePackageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReadGo_ValidateProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateProjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReadGoServer).ValidateProject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReadGo_ValidateProject_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReadGoServer).ValidateProject(ctx, req.(*ValidateProjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ReadGo_ServiceDesc is the grpc.ServiceDesc for ReadGo service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ReadGo_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "readgo.v1.ReadGo",
+	HandlerType: (*ReadGoServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "FindType",
+			Handler:    _ReadGo_FindType_Handler,
+		},
+		{
+			MethodName: "AnalyzeFile",
+			Handler:    _ReadGo_AnalyzeFile_Handler,
+		},
+		{
+			MethodName: "AnalyzePackage",
+			Handler:    _ReadGo_AnalyzePackage_Handler,
+		},
+		{
+			MethodName: "ValidateFile",
+			Handler:    _ReadGo_ValidateFile_Handler,
+		},
+		{
+			MethodName: "ValidatePackage",
+			Handler:    _ReadGo_ValidatePackage_Handler,
+		},
+		{
+			MethodName: "ValidateProject",
+			Handler:    _ReadGo_ValidateProject_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ReadFile",
+			Handler:       _ReadGo_ReadFile_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetFileTree",
+			Handler:       _ReadGo_GetFileTree_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SearchFiles",
+			Handler:       _ReadGo_SearchFiles_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "AnalyzeProject",
+			Handler:       _ReadGo_AnalyzeProject_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "readgo/v1/readgo.proto",
+}
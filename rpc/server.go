@@ -0,0 +1,244 @@
+// Package rpc serves readgo's reader, analyzer and validator over gRPC, for
+// services written in other languages. The service and its messages are
+// defined in proto/readgo/v1/readgo.proto; readgopb holds the generated Go
+// code, and clients in other languages generate theirs from the same file.
+//
+//	srv, err := rpc.NewServer("/path/to/project")
+//	if err != nil {
+//		return err
+//	}
+//	g := grpc.NewServer()
+//	readgopb.RegisterReadGoServer(g, srv)
+//	return g.Serve(lis)
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/iamlongalong/readgo"
+	"github.com/iamlongalong/readgo/rpc/readgopb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+//go:generate protoc -I proto --go_out=. --go_opt=module=github.com/iamlongalong/readgo/rpc --go-grpc_out=. --go-grpc_opt=module=github.com/iamlongalong/readgo/rpc readgo/v1/readgo.proto
+
+// chunkSize is the most file content sent in one ReadFile message
+const chunkSize = 64 << 10
+
+// Server implements the ReadGo service for the project in one directory.
+// Files are read through a jailed reader, and paths that leave the
+// directory are rejected.
+type Server struct {
+	readgopb.UnimplementedReadGoServer
+
+	reader    *readgo.DefaultReader
+	analyzer  *readgo.DefaultAnalyzer
+	validator *readgo.DefaultValidator
+}
+
+var _ readgopb.ReadGoServer = (*Server)(nil)
+
+// NewServer creates a server for the project in root. The options configure
+// the analyzer and validator as for readgo.NewAnalyzer; a working directory
+// among them is overridden by root.
+func NewServer(root string, opts ...readgo.Option) (*Server, error) {
+	reader, err := readgo.NewJailedReader(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open project: %w", err)
+	}
+	opts = append(opts, readgo.WithWorkDir(root))
+	return &Server{
+		reader:    reader,
+		analyzer:  readgo.NewAnalyzer(opts...),
+		validator: readgo.NewValidator(root, opts...),
+	}, nil
+}
+
+// ReadFile streams the content of a file in chunks of up to 64 KiB
+func (s *Server) ReadFile(req *readgopb.ReadFileRequest, stream grpc.ServerStreamingServer[readgopb.ReadFileResponse]) error {
+	content, err := s.reader.ReadSourceFile(stream.Context(), req.GetPath(), readOptions(req.GetOptions()))
+	if err != nil {
+		return toStatus(err)
+	}
+	for len(content) > 0 {
+		n := min(len(content), chunkSize)
+		if err := stream.Send(&readgopb.ReadFileResponse{Content: content[:n]}); err != nil {
+			return err
+		}
+		content = content[n:]
+	}
+	return nil
+}
+
+// GetFileTree streams the nodes of a file tree in depth-first order, each
+// directory before its children
+func (s *Server) GetFileTree(req *readgopb.GetFileTreeRequest, stream grpc.ServerStreamingServer[readgopb.FileEntry]) error {
+	tree, err := s.reader.GetFileTree(stream.Context(), req.GetRoot(), treeOptions(req.GetOptions()))
+	if err != nil {
+		return toStatus(err)
+	}
+	var send func(n *readgo.FileTreeNode, depth int) error
+	send = func(n *readgo.FileTreeNode, depth int) error {
+		if err := stream.Send(fileEntry(n, depth)); err != nil {
+			return err
+		}
+		for _, child := range n.Children {
+			if err := send(child, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return send(tree, 0)
+}
+
+// SearchFiles streams the files whose name matches a glob
+func (s *Server) SearchFiles(req *readgopb.SearchFilesRequest, stream grpc.ServerStreamingServer[readgopb.FileEntry]) error {
+	files, err := s.reader.SearchFiles(stream.Context(), req.GetPattern(), treeOptions(req.GetOptions()))
+	if err != nil {
+		return toStatus(err)
+	}
+	for _, f := range files {
+		if err := stream.Send(fileEntry(f, 0)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindType looks up a type, interface or function of a package
+func (s *Server) FindType(ctx context.Context, req *readgopb.FindTypeRequest) (*readgopb.TypeInfo, error) {
+	if err := checkPath(req.GetPackage()); err != nil {
+		return nil, err
+	}
+	var info *readgo.TypeInfo
+	var err error
+	switch req.GetKind() {
+	case readgopb.LookupKind_LOOKUP_KIND_INTERFACE:
+		info, err = s.analyzer.FindInterface(ctx, req.GetPackage(), req.GetName())
+	case readgopb.LookupKind_LOOKUP_KIND_FUNCTION:
+		info, err = s.analyzer.FindFunction(ctx, req.GetPackage(), req.GetName())
+	default:
+		info, err = s.analyzer.FindType(ctx, req.GetPackage(), req.GetName())
+	}
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return typeInfo(info), nil
+}
+
+// AnalyzeFile analyzes a single Go source file
+func (s *Server) AnalyzeFile(ctx context.Context, req *readgopb.AnalyzeFileRequest) (*readgopb.AnalysisResult, error) {
+	if err := checkPath(req.GetPath()); err != nil {
+		return nil, err
+	}
+	result, err := s.analyzer.AnalyzeFile(ctx, req.GetPath())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return analysisResult(result), nil
+}
+
+// AnalyzePackage analyzes a Go package
+func (s *Server) AnalyzePackage(ctx context.Context, req *readgopb.AnalyzePackageRequest) (*readgopb.AnalysisResult, error) {
+	if err := checkPath(req.GetPackage()); err != nil {
+		return nil, err
+	}
+	result, err := s.analyzer.AnalyzePackage(ctx, req.GetPackage())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return analysisResult(result), nil
+}
+
+// AnalyzeProject streams the analysis of every package of the project. A
+// package that fails to load is sent with its error and the stream goes on.
+func (s *Server) AnalyzeProject(req *readgopb.AnalyzeProjectRequest, stream grpc.ServerStreamingServer[readgopb.AnalyzeProjectResponse]) error {
+	if err := checkPath(req.GetPath()); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel() // Releases the analysis if sending fails
+	for r := range s.analyzer.AnalyzeProjectStream(ctx, req.GetPath()) {
+		resp := &readgopb.AnalyzeProjectResponse{Package: r.Path}
+		switch {
+		case r.Err != nil && r.Path == "":
+			return toStatus(r.Err) // The packages could not be listed
+		case r.Err != nil:
+			resp.Error = r.Err.Error()
+		default:
+			resp.Result = analysisResult(r.Result)
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return toStatus(err)
+	}
+	return nil
+}
+
+// ValidateFile validates a single Go source file
+func (s *Server) ValidateFile(ctx context.Context, req *readgopb.ValidateFileRequest) (*readgopb.ValidationResult, error) {
+	if err := checkPath(req.GetPath()); err != nil {
+		return nil, err
+	}
+	result, err := s.validator.ValidateFile(ctx, req.GetPath(), validationLevel(req.GetLevel()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return validationResult(result), nil
+}
+
+// ValidatePackage validates a Go package
+func (s *Server) ValidatePackage(ctx context.Context, req *readgopb.ValidatePackageRequest) (*readgopb.ValidationResult, error) {
+	if err := checkPath(req.GetPackage()); err != nil {
+		return nil, err
+	}
+	result, err := s.validator.ValidatePackage(ctx, req.GetPackage(), validationLevel(req.GetLevel()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return validationResult(result), nil
+}
+
+// ValidateProject validates the whole project
+func (s *Server) ValidateProject(ctx context.Context, req *readgopb.ValidateProjectRequest) (*readgopb.ValidationResult, error) {
+	result, err := s.validator.ValidateProject(ctx, validationLevel(req.GetLevel()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return validationResult(result), nil
+}
+
+// checkPath rejects file paths and package patterns that are absolute or
+// climb out of the project; import paths pass
+func checkPath(path string) error {
+	if path == "" || filepath.IsLocal(path) {
+		return nil
+	}
+	return status.Errorf(codes.InvalidArgument, "path %q is outside the project", path)
+}
+
+// toStatus converts an error of readgo to a gRPC status error
+func toStatus(err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	case errors.Is(err, readgo.ErrNotFound), errors.Is(err, fs.ErrNotExist):
+		code = codes.NotFound
+	case errors.Is(err, readgo.ErrInvalidInput), errors.Is(err, readgo.ErrBinaryFile):
+		code = codes.InvalidArgument
+	case errors.Is(err, readgo.ErrPermission), errors.Is(err, fs.ErrPermission):
+		code = codes.PermissionDenied
+	}
+	return status.Error(code, err.Error())
+}
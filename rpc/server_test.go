@@ -0,0 +1,184 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iamlongalong/readgo/rpc/readgopb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newClient serves a ReadGo server for dir over an in-memory connection and
+// returns a client of it
+func newClient(t *testing.T, dir string) readgopb.ReadGoClient {
+	t.Helper()
+	srv, err := NewServer(dir)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	lis := bufconn.Listen(1 << 20)
+	g := grpc.NewServer()
+	readgopb.RegisterReadGoServer(g, srv)
+	go g.Serve(lis)
+	t.Cleanup(g.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial server: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return readgopb.NewReadGoClient(conn)
+}
+
+// receiveAll reads a stream to its end
+func receiveAll[T any](t *testing.T, stream grpc.ServerStreamingClient[T]) ([]*T, error) {
+	t.Helper()
+	var msgs []*T
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return msgs, nil
+		}
+		if err != nil {
+			return msgs, err
+		}
+		msgs = append(msgs, msg)
+	}
+}
+
+func TestServer(t *testing.T) {
+	dir := t.TempDir()
+	big := "package store\n\n// Padding makes the file span several chunks\nvar Padding = `" + strings.Repeat("x", 2*chunkSize) + "`\n"
+	files := map[string]string{
+		"go.mod":         "module example.com/rpc\n\ngo 1.22\n",
+		"store/store.go": "package store\n\n// Store keeps things\ntype Store interface {\n\tGet(key string) string\n}\n\n// Open opens a store\nfunc Open() Store { return nil }\n",
+		"store/big.go":   big,
+		"api/api.go":     "package api\n\nimport _ \"example.com/rpc/store\"\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+	client := newClient(t, dir)
+	ctx := context.Background()
+
+	t.Run("ReadFile", func(t *testing.T) {
+		stream, err := client.ReadFile(ctx, &readgopb.ReadFileRequest{Path: "store/big.go"})
+		if err != nil {
+			t.Fatalf("ReadFile() error = %v", err)
+		}
+		chunks, err := receiveAll(t, stream)
+		if err != nil {
+			t.Fatalf("Failed to receive file: %v", err)
+		}
+		var content bytes.Buffer
+		for _, c := range chunks {
+			content.Write(c.GetContent())
+		}
+		if len(chunks) != 3 || content.String() != big {
+			t.Errorf("ReadFile() sent %d chunks of %d bytes, want 3 chunks of %d bytes", len(chunks), content.Len(), len(big))
+		}
+	})
+
+	t.Run("GetFileTree", func(t *testing.T) {
+		stream, err := client.GetFileTree(ctx, &readgopb.GetFileTreeRequest{
+			Options: &readgopb.TreeOptions{FileTypes: readgopb.FileType_FILE_TYPE_GO},
+		})
+		if err != nil {
+			t.Fatalf("GetFileTree() error = %v", err)
+		}
+		entries, err := receiveAll(t, stream)
+		if err != nil {
+			t.Fatalf("Failed to receive tree: %v", err)
+		}
+		var got []string
+		for _, e := range entries {
+			got = append(got, strings.Repeat(" ", int(e.GetDepth()))+e.GetName())
+		}
+		want := []string{filepath.Base(dir), " api", "  api.go", " store", "  big.go", "  store.go"}
+		if strings.Join(got, "\n") != strings.Join(want, "\n") {
+			t.Errorf("GetFileTree() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("FindType", func(t *testing.T) {
+		info, err := client.FindType(ctx, &readgopb.FindTypeRequest{
+			Package: "./store",
+			Name:    "Store",
+			Kind:    readgopb.LookupKind_LOOKUP_KIND_INTERFACE,
+		})
+		if err != nil {
+			t.Fatalf("FindType() error = %v", err)
+		}
+		if info.GetName() != "Store" || info.GetKind() != readgopb.TypeKind_TYPE_KIND_INTERFACE {
+			t.Errorf("FindType() = %v, want interface Store", info)
+		}
+		_, err = client.FindType(ctx, &readgopb.FindTypeRequest{Package: "./store", Name: "Missing"})
+		if status.Code(err) != codes.NotFound {
+			t.Errorf("FindType(Missing) error = %v, want NotFound", err)
+		}
+	})
+
+	t.Run("AnalyzeProject", func(t *testing.T) {
+		stream, err := client.AnalyzeProject(ctx, &readgopb.AnalyzeProjectRequest{})
+		if err != nil {
+			t.Fatalf("AnalyzeProject() error = %v", err)
+		}
+		resps, err := receiveAll(t, stream)
+		if err != nil {
+			t.Fatalf("Failed to receive analysis: %v", err)
+		}
+		if len(resps) != 2 || resps[0].GetPackage() != "example.com/rpc/api" || resps[1].GetPackage() != "example.com/rpc/store" {
+			t.Fatalf("AnalyzeProject() sent %v, want api then store", resps)
+		}
+		store := resps[1].GetResult()
+		if len(store.GetFunctions()) != 1 || store.GetFunctions()[0].GetSignature() != "func Open() Store" {
+			t.Errorf("store functions = %v, want Open", store.GetFunctions())
+		}
+		if store.GetModule().GetPath() != "example.com/rpc" {
+			t.Errorf("store module = %v, want example.com/rpc", store.GetModule())
+		}
+	})
+
+	t.Run("ValidateProject", func(t *testing.T) {
+		result, err := client.ValidateProject(ctx, &readgopb.ValidateProjectRequest{})
+		if err != nil {
+			t.Fatalf("ValidateProject() error = %v", err)
+		}
+		if !result.GetValid() || result.GetLevel() != readgopb.ValidationLevel_VALIDATION_LEVEL_STANDARD {
+			t.Errorf("ValidateProject() = %v, want a valid standard result", result)
+		}
+	})
+
+	t.Run("paths outside the project", func(t *testing.T) {
+		_, err := client.AnalyzeFile(ctx, &readgopb.AnalyzeFileRequest{Path: "../outside.go"})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("AnalyzeFile(../outside.go) error = %v, want InvalidArgument", err)
+		}
+		stream, err := client.ReadFile(ctx, &readgopb.ReadFileRequest{Path: "/etc/passwd"})
+		if err == nil {
+			_, err = receiveAll(t, stream)
+		}
+		if err == nil {
+			t.Error("ReadFile(/etc/passwd) succeeded outside the project")
+		}
+	})
+}
@@ -0,0 +1,141 @@
+package readgo
+
+import (
+	"context"
+	"go/token"
+	"path/filepath"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/packages"
+)
+
+// RuleAnalyzers returns the validator's built-in rules as go/analysis
+// analyzers, so they can run under go vet -vettool, golangci-lint and other
+// analysis drivers alongside existing linters. Rules are configured by cfg,
+// which may be nil, and those it does not enable are left out. The rules
+// that wrap analyzers themselves, shadow and vet, are left out as well, and
+// so is import_depth, which needs the whole program rather than one package.
+func RuleAnalyzers(cfg *ValidatorConfig) []*analysis.Analyzer {
+	var analyzers []*analysis.Analyzer
+	for _, r := range builtinRules() {
+		switch r.(type) {
+		case *AnalyzerRule, *importDepthRule:
+			continue
+		}
+		if !cfg.ruleEnabled(r.Name()) {
+			continue
+		}
+		if c, ok := r.(configurableRule); ok {
+			c.configure(cfg)
+		}
+		analyzers = append(analyzers, newRuleAnalyzer(r, cfg))
+	}
+	return analyzers
+}
+
+// NewRuleAnalyzer wraps a rule as a go/analysis analyzer named after it.
+// Findings are reported with the check that produced them as category and
+// their suggested fixes, and //nolint comments suppress them as during
+// validation. Severities are left to the driver.
+func NewRuleAnalyzer(r Rule) *analysis.Analyzer {
+	return newRuleAnalyzer(r, nil)
+}
+
+// newRuleAnalyzer wraps a rule as an analyzer, dropping the findings of
+// checks cfg disables
+func newRuleAnalyzer(r Rule, cfg *ValidatorConfig) *analysis.Analyzer {
+	return &analysis.Analyzer{
+		Name: r.Name(),
+		Doc:  "report the findings of readgo's " + r.Name() + " rule",
+		Run: func(pass *analysis.Pass) (interface{}, error) {
+			runRule(r, cfg, pass)
+			return nil, nil
+		},
+	}
+}
+
+// runRule checks the package of pass with r and reports its findings
+func runRule(r Rule, cfg *ValidatorConfig, pass *analysis.Pass) {
+	pkg := &packages.Package{
+		ID:           pass.Pkg.Path(),
+		Name:         pass.Pkg.Name(),
+		PkgPath:      pass.Pkg.Path(),
+		Fset:         pass.Fset,
+		Syntax:       pass.Files,
+		OtherFiles:   pass.OtherFiles,
+		IgnoredFiles: pass.IgnoredFiles,
+		Types:        pass.Pkg,
+		TypesInfo:    pass.TypesInfo,
+		TypesSizes:   pass.TypesSizes,
+	}
+	files := make(map[string]*token.File, len(pass.Files))
+	nolint := nolintIndex{}
+	for _, file := range pass.Files {
+		tf := pass.Fset.File(file.Pos())
+		files[tf.Name()] = tf
+		pkg.GoFiles = append(pkg.GoFiles, tf.Name())
+		nolint.addFile(pass.Fset, file)
+	}
+	pkg.CompiledGoFiles = pkg.GoFiles
+	// Drivers pass no module, which the go_version rule reads its go
+	// directive from
+	if len(pkg.GoFiles) > 0 {
+		gomod := findGoMod(filepath.Dir(pkg.GoFiles[0]))
+		if info := readModuleInfo(gomod); info != nil {
+			pkg.Module = &packages.Module{Path: info.Path, GoVersion: info.GoVersion, GoMod: gomod}
+		}
+	}
+
+	for _, f := range r.Check(context.Background(), pkg) {
+		if f.Rule == "" {
+			f.Rule = r.Name()
+		}
+		if f.Rule != r.Name() && !cfg.ruleEnabled(f.Rule) {
+			continue
+		}
+		if nolint.suppressed(f.File, f.Line, f.Rule) {
+			continue
+		}
+		d := analysis.Diagnostic{
+			Pos:      findingPos(files[f.File], f.Line, f.Column),
+			Category: f.Rule,
+			Message:  f.Message,
+		}
+		if !d.Pos.IsValid() && len(pass.Files) > 0 {
+			d.Pos = pass.Files[0].Package // Findings about the whole package
+		}
+		for _, fix := range f.SuggestedFixes {
+			if edits, ok := analysisEdits(files, fix.Edits); ok {
+				d.SuggestedFixes = append(d.SuggestedFixes, analysis.SuggestedFix{Message: fix.Message, TextEdits: edits})
+			}
+		}
+		pass.Report(d)
+	}
+}
+
+// findingPos returns the position of the 1-based line and column of tf, or
+// token.NoPos if they are not in it
+func findingPos(tf *token.File, line, column int) token.Pos {
+	if tf == nil || line < 1 || line > tf.LineCount() {
+		return token.NoPos
+	}
+	pos := tf.LineStart(line)
+	if column > 1 && tf.Offset(pos)+column-1 <= tf.Size() {
+		pos += token.Pos(column - 1)
+	}
+	return pos
+}
+
+// analysisEdits converts the edits of a suggested fix, reporting false if
+// any falls outside the files of the package
+func analysisEdits(files map[string]*token.File, edits []TextEdit) ([]analysis.TextEdit, bool) {
+	out := make([]analysis.TextEdit, 0, len(edits))
+	for _, e := range edits {
+		tf := files[e.File]
+		if tf == nil || e.Start < 0 || e.Start > e.End || e.End > tf.Size() {
+			return nil, false
+		}
+		out = append(out, analysis.TextEdit{Pos: tf.Pos(e.Start), End: tf.Pos(e.End), NewText: []byte(e.NewText)})
+	}
+	return out, true
+}
@@ -0,0 +1,57 @@
+package readgo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestRuleAnalyzers(t *testing.T) {
+	analyzers := RuleAnalyzers(&ValidatorConfig{Enable: []string{"error_handling"}})
+	if err := analysis.Validate(analyzers); err != nil {
+		t.Fatalf("Failed to validate analyzers: %v", err)
+	}
+	byName := make(map[string]*analysis.Analyzer)
+	for _, a := range analyzers {
+		byName[a.Name] = a
+	}
+	for _, name := range []string{"shadow", "vet", "import_depth", "naming"} {
+		if byName[name] != nil {
+			t.Errorf("RuleAnalyzers() includes %s", name)
+		}
+	}
+	errorHandling := byName["error_handling"]
+	if errorHandling == nil {
+		t.Fatalf("RuleAnalyzers() lacks error_handling")
+	}
+
+	dir := t.TempDir()
+	src := "package wrap\n\n" +
+		"import (\n\t\"errors\"\n\t\"fmt\"\n)\n\n" +
+		"var errBase = errors.New(\"base\")\n\n" +
+		"func wrap() error {\n" +
+		"\treturn fmt.Errorf(\"failed: %v\", errBase) // want `fmt.Errorf formats error errBase with %v; use %w to wrap it`\n" +
+		"}\n\n" +
+		"func suppressed() error {\n" +
+		"\treturn fmt.Errorf(\"failed: %v\", errBase) //nolint:error_wrap\n" +
+		"}\n"
+	path := filepath.Join(dir, "src", "wrap", "wrap.go")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	results := analysistest.Run(t, dir, errorHandling, "wrap")
+	if len(results) != 1 || len(results[0].Diagnostics) != 1 {
+		t.Fatalf("Run() = %v, want one diagnostic", results)
+	}
+	d := results[0].Diagnostics[0]
+	if d.Category != "error_wrap" || len(d.SuggestedFixes) != 1 {
+		t.Errorf("diagnostic category %q with %d fixes, want error_wrap with 1", d.Category, len(d.SuggestedFixes))
+	}
+}
@@ -0,0 +1,319 @@
+package readgo
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis/passes/shadow"
+	"golang.org/x/tools/go/packages"
+)
+
+// builtinRules returns the rules every validator starts with. Only those in
+// defaultRules run unless enabled in the configuration.
+func builtinRules() []Rule {
+	return []Rule{
+		unusedImportRule{},
+		&complexityRule{},
+		&functionSizeRule{},
+		namingRule{},
+		NewAnalyzerRule("shadow", shadow.Analyzer),
+		unusedRule{},
+		emptyFuncRule{},
+		errorWrapRule{},
+		&uncheckedErrorRule{},
+		&docCommentRule{},
+		&headerRule{},
+		&importPolicyRule{},
+		&importDepthRule{},
+		goVersionRule{},
+		unreachableRule{},
+		&fieldAlignmentRule{},
+		syncRule{},
+		contextRule{},
+		exhaustiveRule{},
+		unkeyedFieldsRule{},
+		NewAnalyzerRule("vet", VetAnalyzers()...),
+	}
+}
+
+// defaultRules names the built-in rules that run without being enabled: the
+// unused import check validation has always made, and the rules that report
+// nothing until given settings of their own
+var defaultRules = map[string]bool{
+	"unused_import": true,
+	"doc_comment":   true,
+	"file_header":   true,
+	"import_policy": true,
+	"import_depth":  true,
+}
+
+// optInRules names the built-in rules that only run once enabled
+var optInRules = func() map[string]bool {
+	names := make(map[string]bool)
+	for _, r := range builtinRules() {
+		if !defaultRules[r.Name()] {
+			names[r.Name()] = true
+		}
+	}
+	return names
+}()
+
+// unusedImportRule reports imports that are never used, and under the check
+// name blank_import also blank imports, which are only kept for their side
+// effects. Unused imports are type errors, which validation does not report
+// on its own, and come with a fix deleting the import.
+type unusedImportRule struct{}
+
+// Name returns the rule name
+func (unusedImportRule) Name() string {
+	return "unused_import"
+}
+
+// Check reports every blank and unused import in the package
+func (unusedImportRule) Check(ctx context.Context, pkg *packages.Package) []Finding {
+	used := make(map[*types.PkgName]bool)
+	if pkg.TypesInfo != nil {
+		for _, obj := range pkg.TypesInfo.Uses {
+			if name, ok := obj.(*types.PkgName); ok {
+				used[name] = true
+			}
+		}
+	}
+
+	var findings []Finding
+	for _, file := range pkg.Syntax {
+		if ctx.Err() != nil {
+			break
+		}
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.IMPORT {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				imp := spec.(*ast.ImportSpec)
+				pos := pkg.Fset.Position(imp.Pos())
+				finding := Finding{
+					Message: fmt.Sprintf("unused import: %s", imp.Path.Value),
+					File:    pos.Filename,
+					Line:    pos.Line,
+					Column:  pos.Column,
+				}
+
+				blank := imp.Name != nil && imp.Name.Name == "_"
+				dot := imp.Name != nil && imp.Name.Name == "."
+				name := importName(pkg.TypesInfo, imp)
+				unused := !blank && !dot && name != nil && !used[name]
+				if !blank && !unused {
+					continue
+				}
+				if blank {
+					finding.Rule = "blank_import"
+					finding.Message = fmt.Sprintf("blank import: %s", imp.Path.Value)
+				}
+				if unused {
+					// Delete the whole declaration when it is the only import
+					node := ast.Node(imp)
+					if !gen.Lparen.IsValid() {
+						node = gen
+					}
+					finding.SuggestedFixes = []SuggestedFix{{
+						Message: fmt.Sprintf("Remove import %s", imp.Path.Value),
+						Edits:   []TextEdit{deleteLinesEdit(pkg.Fset, node.Pos(), node.End())},
+					}}
+				}
+				findings = append(findings, finding)
+			}
+		}
+	}
+	return findings
+}
+
+// importName returns the package name object declared by an import
+func importName(info *types.Info, imp *ast.ImportSpec) *types.PkgName {
+	if info == nil {
+		return nil
+	}
+	obj := info.Implicits[imp]
+	if imp.Name != nil {
+		obj = info.Defs[imp.Name]
+	}
+	name, _ := obj.(*types.PkgName)
+	return name
+}
+
+// complexityRule reports functions whose cyclomatic complexity exceeds the
+// configured limit
+type complexityRule struct {
+	max int
+}
+
+// Name returns the rule name
+func (r *complexityRule) Name() string {
+	return "complexity"
+}
+
+// configure reads the complexity limit
+func (r *complexityRule) configure(cfg *ValidatorConfig) {
+	r.max = defaultMaxComplexity
+	if cfg != nil {
+		r.max = limit(cfg.MaxComplexity, defaultMaxComplexity)
+	}
+}
+
+// Check reports every function above the complexity limit
+func (r *complexityRule) Check(ctx context.Context, pkg *packages.Package) []Finding {
+	var findings []Finding
+	for _, file := range pkg.Syntax {
+		if ctx.Err() != nil {
+			break
+		}
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil {
+				continue
+			}
+			complexity := cyclomaticComplexity(fn.Body)
+			if complexity <= r.max {
+				continue
+			}
+			pos := pkg.Fset.Position(fn.Name.Pos())
+			findings = append(findings, Finding{
+				Message: fmt.Sprintf("function %s has cyclomatic complexity %d (limit %d)", funcDisplayName(fn), complexity, r.max),
+				File:    pos.Filename,
+				Line:    pos.Line,
+				Column:  pos.Column,
+			})
+		}
+	}
+	return findings
+}
+
+// cyclomaticComplexity returns one plus the number of decision points in
+// body, including those of nested function literals
+func cyclomaticComplexity(body *ast.BlockStmt) int {
+	complexity := 1
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			complexity++
+		case *ast.CaseClause:
+			if n.List != nil {
+				complexity++
+			}
+		case *ast.CommClause:
+			if n.Comm != nil {
+				complexity++
+			}
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				complexity++
+			}
+		}
+		return true
+	})
+	return complexity
+}
+
+// funcDisplayName returns the name of a function, qualified by its receiver
+// type for methods, e.g. "User.Save"
+func funcDisplayName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	recv := fn.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+	switch t := recv.(type) {
+	case *ast.IndexExpr:
+		recv = t.X
+	case *ast.IndexListExpr:
+		recv = t.X
+	}
+	if ident, ok := recv.(*ast.Ident); ok {
+		return ident.Name + "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
+
+// functionSizeRule reports functions that are too long or take or return
+// too many values. Its findings use the check names function_length,
+// param_count and result_count.
+type functionSizeRule struct {
+	maxLines   int
+	maxParams  int
+	maxResults int
+}
+
+// Name returns the rule name
+func (r *functionSizeRule) Name() string {
+	return "function_size"
+}
+
+// configure reads the size limits
+func (r *functionSizeRule) configure(cfg *ValidatorConfig) {
+	if cfg == nil {
+		cfg = &ValidatorConfig{}
+	}
+	r.maxLines = limit(cfg.MaxFunctionLines, defaultMaxFunctionLines)
+	r.maxParams = limit(cfg.MaxParams, defaultMaxParams)
+	r.maxResults = limit(cfg.MaxResults, defaultMaxResults)
+}
+
+// Check reports every function above one of the size limits
+func (r *functionSizeRule) Check(ctx context.Context, pkg *packages.Package) []Finding {
+	var findings []Finding
+	for _, file := range pkg.Syntax {
+		if ctx.Err() != nil {
+			break
+		}
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			pos := pkg.Fset.Position(fn.Name.Pos())
+			report := func(check, format string, args ...interface{}) {
+				findings = append(findings, Finding{
+					Rule:    check,
+					Message: fmt.Sprintf(format, args...),
+					File:    pos.Filename,
+					Line:    pos.Line,
+					Column:  pos.Column,
+				})
+			}
+
+			name := funcDisplayName(fn)
+			if fn.Body != nil {
+				lines := pkg.Fset.Position(fn.End()).Line - pkg.Fset.Position(fn.Pos()).Line + 1
+				if lines > r.maxLines {
+					report("function_length", "function %s has %d lines (limit %d)", name, lines, r.maxLines)
+				}
+			}
+			if n := fieldCount(fn.Type.Params); n > r.maxParams {
+				report("param_count", "function %s has %d parameters (limit %d)", name, n, r.maxParams)
+			}
+			if n := fieldCount(fn.Type.Results); n > r.maxResults {
+				report("result_count", "function %s has %d results (limit %d)", name, n, r.maxResults)
+			}
+		}
+	}
+	return findings
+}
+
+// fieldCount returns the number of values declared by a field list,
+// counting unnamed fields once
+func fieldCount(fields *ast.FieldList) int {
+	if fields == nil {
+		return 0
+	}
+	n := 0
+	for _, f := range fields.List {
+		n += max(len(f.Names), 1)
+	}
+	return n
+}
@@ -0,0 +1,1386 @@
+package readgo
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// ruleWarnings writes a single source file into a temporary directory
+// and returns the validator warnings of the given type for it
+func ruleWarnings(t *testing.T, src string, cfg *ValidatorConfig, typ string) []ValidationWarning {
+	t.Helper()
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "fixture.go"), []byte(src), 0600); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	result, err := NewValidator(tmpDir).WithConfig(allRules(cfg)).ValidateFile(context.Background(), "fixture.go", ValidationLevelStandard)
+	if err != nil {
+		t.Fatalf("ValidateFile() error = %v", err)
+	}
+
+	var warnings []ValidationWarning
+	for _, w := range result.Warnings {
+		if w.Type == typ {
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
+
+// allRules returns a copy of cfg, which may be nil, with every opt-in rule
+// enabled
+func allRules(cfg *ValidatorConfig) *ValidatorConfig {
+	enabled := ValidatorConfig{}
+	if cfg != nil {
+		enabled = *cfg
+	}
+	enabled.Enable = nil
+	for name := range optInRules {
+		enabled.Enable = append(enabled.Enable, name)
+	}
+	return &enabled
+}
+
+func TestCyclomaticComplexity(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"empty", "{}", 1},
+		{"if else", "{ if a { } else if b { } }", 3},
+		{"loops", "{ for { }; for range x { } }", 3},
+		{"switch", "{ switch { case a: case b, c: default: } }", 3},
+		{"select", "{ select { case <-c: default: } }", 2},
+		{"boolean operators", "{ if a && b || c { } }", 4},
+		{"function literal", "{ f := func() { if a { } }; _ = f }", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := "package p\nfunc f() " + tt.body
+			file, err := parser.ParseFile(token.NewFileSet(), "p.go", src, 0)
+			if err != nil {
+				t.Fatalf("Failed to parse: %v", err)
+			}
+			fn := file.Decls[0].(*ast.FuncDecl)
+			if got := cyclomaticComplexity(fn.Body); got != tt.want {
+				t.Errorf("cyclomaticComplexity() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComplexityRule(t *testing.T) {
+	src := `package test
+
+type T struct{}
+
+func (T) Branchy(a, b, c bool) int {
+	if a {
+		return 1
+	}
+	if b && c {
+		return 2
+	}
+	return 3
+}
+
+func Simple() {}
+`
+	warnings := ruleWarnings(t, src, &ValidatorConfig{MaxComplexity: 3}, "complexity")
+	if len(warnings) != 1 {
+		t.Fatalf("complexity warnings = %+v, want 1", warnings)
+	}
+	w := warnings[0]
+	if w.Line != 5 || !strings.Contains(w.Message, "T.Branchy has cyclomatic complexity 4 (limit 3)") {
+		t.Errorf("complexity warning = %+v", w)
+	}
+
+	if warnings := ruleWarnings(t, src, nil, "complexity"); len(warnings) != 0 {
+		t.Errorf("complexity warnings with default limit = %+v, want none", warnings)
+	}
+}
+
+func TestFunctionSizeRule(t *testing.T) {
+	src := `package test
+
+func Many(a, b, c int, d string) (int, error) {
+	return a + b + c + len(d), nil
+}
+
+func Results() (a, b int, err error) {
+	return 0, 0, nil
+}
+
+func Long() {
+	_ = 1
+	_ = 2
+	_ = 3
+}
+`
+	cfg := &ValidatorConfig{MaxFunctionLines: 4, MaxParams: 3, MaxResults: 2}
+	tests := []struct {
+		check    string
+		wantLine int
+		wantMsg  string
+	}{
+		{"param_count", 3, "function Many has 4 parameters (limit 3)"},
+		{"result_count", 7, "function Results has 3 results (limit 2)"},
+		{"function_length", 11, "function Long has 5 lines (limit 4)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.check, func(t *testing.T) {
+			warnings := ruleWarnings(t, src, cfg, tt.check)
+			if len(warnings) != 1 {
+				t.Fatalf("%s warnings = %+v, want 1", tt.check, warnings)
+			}
+			if warnings[0].Line != tt.wantLine || warnings[0].Message != tt.wantMsg {
+				t.Errorf("%s warning = %+v, want line %d %q", tt.check, warnings[0], tt.wantLine, tt.wantMsg)
+			}
+		})
+	}
+
+	if warnings := ruleWarnings(t, src, nil, "param_count"); len(warnings) != 0 {
+		t.Errorf("param_count warnings with default limits = %+v, want none", warnings)
+	}
+}
+
+func TestFixInitialisms(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"userId", "userID"},
+		{"UserId", "UserID"},
+		{"id", "id"},
+		{"urlPath", "urlPath"},
+		{"HttpServer", "HTTPServer"},
+		{"HTTPServer", "HTTPServer"},
+		{"parseJson", "parseJSON"},
+		{"Identity", "Identity"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fixInitialisms(tt.name); got != tt.want {
+				t.Errorf("fixInitialisms(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNamingRule(t *testing.T) {
+	src := `package Test_pkg
+
+type Server struct{}
+
+func (s *Server) Start() {}
+
+func (srv *Server) Stop() {}
+
+func (self Server) Name() string { return "" }
+
+var max_size = 10
+
+func GetUserId() int { return max_size }
+`
+	tests := []struct {
+		check    string
+		wantLine int
+		wantMsg  string
+	}{
+		{"package_name", 1, "package name Test_pkg should be lowercase"},
+		{"receiver_name", 7, "receiver name srv should be consistent with previous receiver name s for Server"},
+		{"underscore_name", 11, "name max_size should not contain underscores"},
+		{"initialism", 13, "name GetUserId should be GetUserID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.check, func(t *testing.T) {
+			warnings := ruleWarnings(t, src, nil, tt.check)
+			if len(warnings) == 0 {
+				t.Fatalf("no %s warnings", tt.check)
+			}
+			if warnings[0].Line != tt.wantLine || warnings[0].Message != tt.wantMsg {
+				t.Errorf("%s warning = %+v, want line %d %q", tt.check, warnings[0], tt.wantLine, tt.wantMsg)
+			}
+		})
+	}
+
+	cfg := &ValidatorConfig{Rules: map[string]bool{"initialism": false}}
+	if warnings := ruleWarnings(t, src, cfg, "initialism"); len(warnings) != 0 {
+		t.Errorf("initialism warnings when disabled = %+v, want none", warnings)
+	}
+}
+
+func TestShadowRule(t *testing.T) {
+	src := `package test
+
+import "os"
+
+func Load(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	if true {
+		_, err := f.Stat()
+		if err != nil {
+			return err
+		}
+	}
+	return err
+}
+`
+	warnings := ruleWarnings(t, src, nil, "shadow")
+	if len(warnings) != 1 {
+		t.Fatalf("shadow warnings = %+v, want 1", warnings)
+	}
+	if warnings[0].Line != 11 || !strings.Contains(warnings[0].Message, `declaration of "err" shadows declaration at line 6`) {
+		t.Errorf("shadow warning = %+v", warnings[0])
+	}
+}
+
+func TestUnusedRule(t *testing.T) {
+	src := `package test
+
+func Exported(a, b int) int {
+	return helper(a)
+}
+
+func helper(x int) int { return x }
+
+func orphan() {}
+
+func callback(unused string) { println("called") }
+
+var handlers = []func(string){callback}
+
+func stub(ignored int) {}
+
+var (
+	Setting = 1
+	cached  = 2
+	counter = 3
+)
+
+func loop(items []int) int {
+	total := counter
+	for i, item := range items {
+		total += item
+	}
+	var spare int
+	return total
+}
+`
+	tests := []struct {
+		check string
+		want  []string
+	}{
+		{"unused_param", []string{"parameter b of Exported is never used"}},
+		{"unused_func", []string{"function orphan is never used", "function stub is never used", "function loop is never used"}},
+		{"unused_var", []string{"variable handlers is never used", "variable cached is never used", "variable i is never used", "variable spare is never used"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.check, func(t *testing.T) {
+			var got []string
+			for _, w := range ruleWarnings(t, src, nil, tt.check) {
+				got = append(got, w.Message)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s warnings = %v, want %v", tt.check, got, tt.want)
+			}
+		})
+	}
+
+	testFiles := map[string]string{
+		"go.mod": "module example.com/p\n\ngo 1.22\n",
+		"p.go":   "package p\n\nfunc Double(n int) int { return 2 * n }\n",
+		"p_test.go": `package p
+
+import "testing"
+
+func TestMain(m *testing.M) { println("main") }
+
+func TestDouble(t *testing.T) { check(nil, 2) }
+
+func BenchmarkDouble(b *testing.B) { println(Double(2)) }
+
+func FuzzDouble(f *testing.F) { println(Double(2)) }
+
+func check(t *testing.T, n int) { println(Double(n)) }
+`,
+	}
+	tmpDir := t.TempDir()
+	for name, content := range testFiles {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+	result, err := NewValidator(tmpDir).WithConfig(allRules(nil)).ValidateProject(context.Background(), ValidationLevelStandard)
+	if err != nil {
+		t.Fatalf("ValidateProject() error = %v", err)
+	}
+	var got []string
+	for _, w := range result.Warnings {
+		if w.Type == "unused_param" {
+			got = append(got, w.Message)
+		}
+	}
+	if want := []string{"parameter t of check is never used"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unused_param warnings in tests = %v, want %v", got, want)
+	}
+}
+
+func TestEmptyFuncRule(t *testing.T) {
+	src := `package test
+
+type T struct{}
+
+func (T) marker() {}
+
+func Stub() {}
+
+func Planned() {
+	// Filled in by the next release
+}
+
+func Full() { println() }
+`
+	warnings := ruleWarnings(t, src, nil, "empty_func")
+	if len(warnings) != 1 || warnings[0].Line != 7 || warnings[0].Message != "function Stub has an empty body" {
+		t.Errorf("empty_func warnings = %+v, want one for Stub", warnings)
+	}
+}
+
+func TestFormatVerbs(t *testing.T) {
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{"plain", nil},
+		{"%s: %v", []string{"0:s", "1:v"}},
+		{"100%% %w", []string{"0:w"}},
+		{"%-*d %.2f", []string{"0:*", "1:d", "2:f"}},
+		{"%[2]v %[1]s", []string{"0:s", "1:v"}},
+		{"%v %[1]s", []string{"0:s"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var got []string
+			for _, d := range formatVerbs(tt.format) {
+				got = append(got, fmt.Sprintf("%d:%c", d.arg, d.verb))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("formatVerbs(%q) = %v, want %v", tt.format, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorWrapRule(t *testing.T) {
+	src := `package test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+var ErrClosed = errors.New("closed")
+
+func Read(r io.Reader) error {
+	_, err := r.Read(nil)
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read %s: %v", "input", err)
+	}
+	return fmt.Errorf("read: %w", ErrClosed)
+}
+
+type closedError struct{}
+
+func (closedError) Error() string { return "closed" }
+
+func (closedError) Is(target error) bool { return target == ErrClosed }
+`
+	tests := []struct {
+		check    string
+		wantLine int
+		wantMsg  string
+	}{
+		{"error_compare", 13, "comparing errors with ==; use errors.Is"},
+		{"error_wrap", 17, "fmt.Errorf formats error err with %v; use %w to wrap it"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.check, func(t *testing.T) {
+			warnings := ruleWarnings(t, src, nil, tt.check)
+			if len(warnings) != 1 {
+				t.Fatalf("%s warnings = %+v, want 1", tt.check, warnings)
+			}
+			if warnings[0].Line != tt.wantLine || warnings[0].Message != tt.wantMsg {
+				t.Errorf("%s warning = %+v, want line %d %q", tt.check, warnings[0], tt.wantLine, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestMatchAnyPackage(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		pkgPath  string
+		want     bool
+	}{
+		{nil, "example.com/mod", false},
+		{[]string{"..."}, "example.com/mod", true},
+		{[]string{"example.com/mod"}, "example.com/mod", true},
+		{[]string{"example.com/mod"}, "example.com/mod/api", false},
+		{[]string{"example.com/mod/..."}, "example.com/mod/api", true},
+		{[]string{"example.com/mod/..."}, "example.com/module", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchAnyPackage(tt.patterns, tt.pkgPath); got != tt.want {
+			t.Errorf("matchAnyPackage(%v, %q) = %v, want %v", tt.patterns, tt.pkgPath, got, tt.want)
+		}
+	}
+}
+
+func TestDocCommentRule(t *testing.T) {
+	src := `// Package test is a fixture.
+package test
+
+// Server serves requests.
+type Server struct{}
+
+// A Client sends requests.
+type Client struct{}
+
+type Handler func()
+
+// starts the server
+func (s *Server) Start() {}
+
+func Stop() {}
+
+func helper() {}
+`
+	if warnings := ruleWarnings(t, src, nil, "doc_comment"); len(warnings) != 0 {
+		t.Errorf("doc_comment warnings without configured packages = %+v, want none", warnings)
+	}
+
+	cfg := &ValidatorConfig{DocPackages: []string{"..."}}
+	var got []string
+	for _, w := range ruleWarnings(t, src, cfg, "doc_comment") {
+		got = append(got, w.Message)
+	}
+	want := []string{
+		"exported type Handler should have a comment",
+		`exported method Server.Start should have a comment of the form "Start ..."`,
+		"exported function Stop should have a comment",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("doc_comment warnings = %v, want %v", got, want)
+	}
+}
+
+func TestHeaderRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		exclude []string
+		want    []string
+	}{
+		{
+			name: "valid header",
+			src:  "// Copyright 2024 Example Inc.\n\npackage test\n",
+		},
+		{
+			name: "missing header",
+			src:  "package test\n",
+			want: []string{"file is missing the required header"},
+		},
+		{
+			name: "malformed header",
+			src:  "// Copyright Example\n\npackage test\n",
+			want: []string{"file header does not match the required pattern"},
+		},
+		{
+			name: "generated file",
+			src:  "// Code generated by tool. DO NOT EDIT.\n\npackage test\n",
+		},
+		{
+			name:    "excluded file",
+			src:     "package test\n",
+			exclude: []string{"fixture.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &ValidatorConfig{Header: `^// Copyright \d{4} Example Inc`, HeaderExclude: tt.exclude}
+			var got []string
+			for _, w := range ruleWarnings(t, tt.src, cfg, "file_header") {
+				got = append(got, w.Message)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("file_header warnings = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImportPolicyRule(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFiles := map[string]string{
+		"go.mod":              "module example.com/policy\n\ngo 1.20\n",
+		"cmd/app/main.go":     "package main\n\nimport (\n\t\"database/sql\"\n\t\"fmt\"\n)\n\nvar _ sql.DB\n\nfunc main() { fmt.Println() }\n",
+		"internal/db/db.go":   "package db\n\nimport \"database/sql\"\n\nvar _ sql.DB\n",
+		"domain/user.go":      "package domain\n\nimport (\n\t\"strings\"\n\n\t\"example.com/policy/internal/db\"\n)\n\nvar _ = strings.ToUpper\nvar _ = db.X\n",
+		"internal/db/x.go":    "package db\n\nvar X int\n",
+		"domain/user_test.go": "package domain\n\nimport \"testing\"\n\nfunc TestUser(t *testing.T) {}\n",
+	}
+	for name, content := range testFiles {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	cfg := &ValidatorConfig{ImportPolicies: []ImportPolicy{
+		{Packages: []string{"cmd/**"}, Deny: []string{"database/sql"}, Message: "use internal/db"},
+		{Packages: []string{"domain"}, Allow: []string{"std"}, Deny: []string{"testing"}},
+	}}
+	result, err := NewValidator(tmpDir).WithConfig(allRules(cfg)).ValidateProject(context.Background(), ValidationLevelStandard)
+	if err != nil {
+		t.Fatalf("ValidateProject() error = %v", err)
+	}
+
+	var got []string
+	for _, w := range result.Warnings {
+		if w.Type == "import_policy" {
+			got = append(got, fmt.Sprintf("%s:%d:%d: %s", filepath.ToSlash(w.File), w.Line, w.Column, w.Message))
+		}
+	}
+	sort.Strings(got)
+	want := []string{
+		"cmd/app/main.go:4:2: import of database/sql in example.com/policy/cmd/app is banned: use internal/db",
+		"domain/user.go:6:2: import of example.com/policy/internal/db in example.com/policy/domain is not allowed",
+		"domain/user_test.go:3:8: import of testing in example.com/policy/domain is banned",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("import_policy warnings = %q, want %q", got, want)
+	}
+}
+
+func TestImportPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  ImportPolicy
+		wantErr bool
+	}{
+		{"valid", ImportPolicy{Packages: []string{"cmd/**"}, Deny: []string{"database/sql"}}, false},
+		{"no packages", ImportPolicy{Deny: []string{"database/sql"}}, true},
+		{"no restriction", ImportPolicy{Packages: []string{"cmd/**"}}, true},
+		{"bad pattern", ImportPolicy{Packages: []string{"cmd/["}, Deny: []string{"os"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestImportDepthRule(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFiles := map[string]string{
+		"go.mod":          "module example.com/deep\n\ngo 1.20\n",
+		"cmd/app/main.go": "package main\n\nimport (\n\t_ \"example.com/deep/a\"\n\t_ \"example.com/deep/c\"\n)\n\nfunc main() {}\n",
+		"a/a.go":          "package a\n\nimport _ \"example.com/deep/b\"\n",
+		"b/b.go":          "package b\n\nimport _ \"example.com/deep/c\"\n",
+		"c/c.go":          "package c\n\nimport _ \"fmt\"\n",
+	}
+	for name, content := range testFiles {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	tests := []struct {
+		max  int
+		want []string
+	}{
+		{0, nil},
+		{3, nil},
+		{2, []string{
+			"c/c.go:1:9: package example.com/deep/c is 3 imports deep from entrypoint example.com/deep/cmd/app (limit 2): " +
+				"example.com/deep/cmd/app -> example.com/deep/a -> example.com/deep/b -> example.com/deep/c",
+		}},
+		{1, []string{
+			"b/b.go:1:9: package example.com/deep/b is 2 imports deep from entrypoint example.com/deep/cmd/app (limit 1): " +
+				"example.com/deep/cmd/app -> example.com/deep/a -> example.com/deep/b",
+			"c/c.go:1:9: package example.com/deep/c is 3 imports deep from entrypoint example.com/deep/cmd/app (limit 1): " +
+				"example.com/deep/cmd/app -> example.com/deep/a -> example.com/deep/b -> example.com/deep/c",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("max %d", tt.max), func(t *testing.T) {
+			cfg := &ValidatorConfig{MaxImportDepth: tt.max}
+			result, err := NewValidator(tmpDir).WithConfig(allRules(cfg)).ValidateProject(context.Background(), ValidationLevelStandard)
+			if err != nil {
+				t.Fatalf("ValidateProject() error = %v", err)
+			}
+
+			var got []string
+			for _, w := range result.Warnings {
+				if w.Type == "import_depth" {
+					got = append(got, fmt.Sprintf("%s:%d:%d: %s", filepath.ToSlash(w.File), w.Line, w.Column, w.Message))
+				}
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("import_depth warnings = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoVersionRule(t *testing.T) {
+	tests := []struct {
+		name   string
+		goVer  string
+		source string
+		want   []string
+	}{
+		{
+			name:   "type parameters",
+			goVer:  "1.16",
+			source: "package p\n\nfunc Clone[T any](s []T) []T { return append([]T(nil), s...) }\n",
+			want: []string{
+				"p.go:3:11: type parameters requires go1.18 but go.mod declares go 1.16",
+				"p.go:3:14: predeclared any requires go1.18 but go.mod declares go 1.16",
+			},
+		},
+		{
+			name:   "new package",
+			goVer:  "1.20",
+			source: "package p\n\nimport \"slices\"\n\nfunc Has(s []int) bool { return slices.Contains(s, 1) }\n",
+			want: []string{
+				"p.go:3:8: package slices requires go1.21 but go.mod declares go 1.20",
+				"p.go:5:40: slices.Contains requires go1.21 but go.mod declares go 1.20",
+			},
+		},
+		{
+			name:   "new package within version",
+			goVer:  "1.21",
+			source: "package p\n\nimport \"slices\"\n\nfunc Has(s []int) bool { return slices.Contains(s, 1) }\n",
+		},
+		{
+			name:   "new method",
+			goVer:  "1.20",
+			source: "package p\n\nimport \"bytes\"\n\nfunc Spare(b *bytes.Buffer) []byte { return b.AvailableBuffer() }\n",
+			want:   []string{"p.go:5:45: bytes.Buffer.AvailableBuffer requires go1.21 but go.mod declares go 1.20"},
+		},
+		{
+			name:   "new builtin",
+			goVer:  "1.20",
+			source: "package p\n\nfunc Low(a, b int) int { return min(a, b) }\n",
+			want:   []string{"p.go:3:33: builtin min requires go1.21 but go.mod declares go 1.20"},
+		},
+		{
+			name:   "range over int",
+			goVer:  "1.21",
+			source: "package p\n\nfunc Sum() (n int) {\n\tfor i := range 10 {\n\t\tn += i\n\t}\n\treturn n\n}\n",
+			want:   []string{"p.go:4:2: range over int requires go1.22 but go.mod declares go 1.21"},
+		},
+		{
+			name:   "old features",
+			goVer:  "1.12",
+			source: "package p\n\nimport \"strings\"\n\nfunc Upper(s string) string { return strings.ToUpper(s) }\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			gomod := "module example.com/p\n\ngo " + tt.goVer + "\n"
+			if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte(gomod), 0600); err != nil {
+				t.Fatalf("Failed to write go.mod: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(tmpDir, "p.go"), []byte(tt.source), 0600); err != nil {
+				t.Fatalf("Failed to write test file: %v", err)
+			}
+
+			result, err := NewValidator(tmpDir).WithConfig(allRules(nil)).ValidateProject(context.Background(), ValidationLevelStandard)
+			if err != nil {
+				t.Fatalf("ValidateProject() error = %v", err)
+			}
+
+			var got []string
+			for _, w := range result.Warnings {
+				if w.Type == "go_version" {
+					got = append(got, fmt.Sprintf("%s:%d:%d: %s", filepath.ToSlash(w.File), w.Line, w.Column, w.Message))
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("go_version warnings = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAPILine(t *testing.T) {
+	tests := []struct {
+		line    string
+		pkgPath string
+		name    string
+	}{
+		{"pkg slices, func Contains[$0 interface{ ~[]$1 }, $1 comparable]($0, $1) bool #57433", "slices", "Contains"},
+		{"pkg bytes, method (*Buffer) AvailableBuffer() []uint8 #53685", "bytes", "Buffer.AvailableBuffer"},
+		{"pkg sync, method (*Map[$0, $1]) Load($0) ($1, bool)", "sync", "Map.Load"},
+		{"pkg net/http, type Request struct, Pattern string #61410", "net/http", "Request.Pattern"},
+		{"pkg container/heap, type Interface interface { Len, Less, Pop, Push, Swap }", "container/heap", "Interface"},
+		{"pkg runtime, type BlockProfileRecord struct, embedded StackRecord", "runtime", "BlockProfileRecord.StackRecord"},
+		{"pkg syscall (linux-386), const AF_ALG = 38", "syscall", "AF_ALG"},
+		{"pkg io, var ErrShortWrite error", "io", "ErrShortWrite"},
+	}
+
+	for _, tt := range tests {
+		pkgPath, name, ok := parseAPILine(tt.line)
+		if !ok || pkgPath != tt.pkgPath || name != tt.name {
+			t.Errorf("parseAPILine(%q) = %q, %q, %v, want %q, %q", tt.line, pkgPath, name, ok, tt.pkgPath, tt.name)
+		}
+	}
+}
+
+func TestUnreachableRule(t *testing.T) {
+	source := `package p
+
+import (
+	"log"
+	"os"
+)
+
+func afterReturn() int {
+	return 1
+	println("dead")
+}
+
+func afterPanic(ok bool) {
+	if !ok {
+		panic("not ok")
+		ok = true
+	}
+}
+
+func afterExit(code int) {
+	switch code {
+	case 0:
+		os.Exit(0)
+		println("dead")
+	default:
+		log.Fatalf("code %d", code)
+	}
+}
+
+func afterGoto() {
+	goto end
+end:
+	println("reachable")
+	return
+}
+
+func reachable(ok bool) int {
+	if ok {
+		return 1
+	}
+	return 0
+}
+`
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example.com/p\n\ngo 1.22\n"), 0600); err != nil {
+		t.Fatalf("Failed to write go.mod: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "p.go"), []byte(source), 0600); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	cfg := &ValidatorConfig{Rules: map[string]bool{"vet": false}}
+	result, err := NewValidator(tmpDir).WithConfig(allRules(cfg)).ValidateProject(context.Background(), ValidationLevelStandard)
+	if err != nil {
+		t.Fatalf("ValidateProject() error = %v", err)
+	}
+
+	var got []string
+	for _, w := range result.Warnings {
+		if w.Type == "unreachable_code" {
+			got = append(got, fmt.Sprintf("%s:%d:%d: %s", filepath.ToSlash(w.File), w.Line, w.Column, w.Message))
+		}
+	}
+	want := []string{
+		"p.go:10:2: unreachable code after return",
+		"p.go:16:3: unreachable code after panic",
+		"p.go:24:3: unreachable code after os.Exit",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unreachable_code warnings = %q, want %q", got, want)
+	}
+}
+
+func TestFieldAlignmentRule(t *testing.T) {
+	source := `package p
+
+type padded struct {
+	ok    bool
+	count int64
+	done  bool
+}
+
+type packed struct {
+	count int64
+	ok    bool
+	done  bool
+}
+
+type small struct {
+	ok    bool
+	count int32
+	done  bool
+}
+
+type marker struct {
+	count int64
+	_     struct{}
+}
+`
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example.com/p\n\ngo 1.22\n"), 0600); err != nil {
+		t.Fatalf("Failed to write go.mod: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "p.go"), []byte(source), 0600); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	tests := []struct {
+		min  int
+		want []string
+	}{
+		{0, []string{
+			"p.go:3:6: struct padded is 24 bytes but would be 16 with its fields ordered count, ok, done",
+			"p.go:21:6: struct marker is 16 bytes but would be 8 with its fields ordered _, count",
+		}},
+		{4, []string{
+			"p.go:3:6: struct padded is 24 bytes but would be 16 with its fields ordered count, ok, done",
+			"p.go:15:6: struct small is 12 bytes but would be 8 with its fields ordered count, ok, done",
+			"p.go:21:6: struct marker is 16 bytes but would be 8 with its fields ordered _, count",
+		}},
+		{16, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("min %d", tt.min), func(t *testing.T) {
+			cfg := &ValidatorConfig{MinPaddingWaste: tt.min}
+			result, err := NewValidator(tmpDir).WithConfig(allRules(cfg)).ValidateProject(context.Background(), ValidationLevelStandard)
+			if err != nil {
+				t.Fatalf("ValidateProject() error = %v", err)
+			}
+
+			var got []string
+			for _, w := range result.Warnings {
+				if w.Type == "field_alignment" {
+					got = append(got, fmt.Sprintf("%s:%d:%d: %s", filepath.ToSlash(w.File), w.Line, w.Column, w.Message))
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("field_alignment warnings = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyncRule(t *testing.T) {
+	source := `package p
+
+import "sync"
+
+type counter struct {
+	mu sync.Mutex
+	n  int
+}
+
+func (c counter) value() int {
+	return c.n
+}
+
+func (c *counter) inc() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.n++
+}
+
+func (c *counter) add(n int) {
+	c.mu.Lock()
+	c.n += n
+	c.mu.Unlock()
+}
+
+func (c *counter) snapshot() int {
+	copy := *c
+	return copy.n
+}
+
+type registry struct {
+	sync.RWMutex
+	items map[string]int
+}
+
+func (r *registry) get(key string) int {
+	r.RLock()
+	defer func() {
+		r.RUnlock()
+	}()
+	return r.items[key]
+}
+
+func run(tasks []func()) {
+	var wg sync.WaitGroup
+	for _, task := range tasks {
+		go func(task func()) {
+			wg.Add(1)
+			defer wg.Done()
+			task()
+		}(task)
+	}
+	wg.Wait()
+}
+
+func runAll(counters []counter) {
+	var wg sync.WaitGroup
+	for _, c := range counters {
+		wg.Add(1)
+		go func(c *counter) {
+			defer wg.Done()
+			c.inc()
+		}(&c)
+	}
+	wg.Wait()
+}
+`
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example.com/p\n\ngo 1.22\n"), 0600); err != nil {
+		t.Fatalf("Failed to write go.mod: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "p.go"), []byte(source), 0600); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	cfg := &ValidatorConfig{Rules: map[string]bool{"vet": false}}
+	result, err := NewValidator(tmpDir).WithConfig(allRules(cfg)).ValidateProject(context.Background(), ValidationLevelStandard)
+	if err != nil {
+		t.Fatalf("ValidateProject() error = %v", err)
+	}
+
+	var got []string
+	for _, w := range result.Warnings {
+		switch w.Type {
+		case "lock_copy", "waitgroup_add", "lock_defer":
+			got = append(got, fmt.Sprintf("%s:%d:%d: %s: %s", filepath.ToSlash(w.File), w.Line, w.Column, w.Type, w.Message))
+		}
+	}
+	sort.Strings(got)
+	want := []string{
+		"p.go:10:9: lock_copy: receiver counter passes a sync.Mutex by value",
+		"p.go:21:2: lock_defer: c.mu.Lock() is not released by a deferred c.mu.Unlock()",
+		"p.go:27:10: lock_copy: assignment copies a sync.Mutex value",
+		"p.go:48:4: waitgroup_add: wg.Add is called inside the goroutine it waits for; call it before the go statement",
+		"p.go:58:9: lock_copy: range variable c copies a sync.Mutex value",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sync warnings = %q, want %q", got, want)
+	}
+}
+
+func TestContextRule(t *testing.T) {
+	testFiles := map[string]string{
+		"go.mod": "module example.com/p\n\ngo 1.22\n",
+		"p.go": `package p
+
+import "context"
+
+func fetch(ctx context.Context, key string) error {
+	ctx2 := context.Background()
+	return lookup(ctx2, key)
+}
+
+func lookup(_ context.Context, key string) error {
+	return nil
+}
+
+func handler(ctx context.Context) func() error {
+	return func() error {
+		return lookup(context.Background(), "k")
+	}
+}
+
+func start() error {
+	return lookup(context.TODO(), "k")
+}
+
+func entry() error {
+	return fetch(context.Background(), "k")
+}
+
+func worker() {
+	run := func(ctx context.Context) error {
+		return lookup(context.Background(), "k")
+	}
+	_ = run
+}
+`,
+		"p_test.go": `package p
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFetch(t *testing.T) {
+	if err := fetch(context.TODO(), "k"); err != nil {
+		t.Fatal(err)
+	}
+}
+`,
+	}
+	tmpDir := t.TempDir()
+	for name, content := range testFiles {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	result, err := NewValidator(tmpDir).WithConfig(allRules(nil)).ValidateProject(context.Background(), ValidationLevelStandard)
+	if err != nil {
+		t.Fatalf("ValidateProject() error = %v", err)
+	}
+
+	var got []string
+	for _, w := range result.Warnings {
+		if strings.HasPrefix(w.Type, "context_") {
+			got = append(got, fmt.Sprintf("%s:%d:%d: %s: %s", filepath.ToSlash(w.File), w.Line, w.Column, w.Type, w.Message))
+		}
+	}
+	sort.Strings(got)
+	want := []string{
+		"p.go:16:17: context_background: context.Background() in a function that receives ctx; derive from it to keep cancellation",
+		"p.go:21:16: context_todo: context.TODO() outside tests; accept a context.Context from the caller",
+		"p.go:30:17: context_background: context.Background() in a function that receives ctx; derive from it to keep cancellation",
+		"p.go:6:10: context_background: context.Background() in a function that receives ctx; derive from it to keep cancellation",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("context warnings = %q, want %q", got, want)
+	}
+}
+
+func TestExhaustiveRule(t *testing.T) {
+	testFiles := map[string]string{
+		"go.mod": "module example.com/p\n\ngo 1.22\n",
+		"p.go": `package p
+
+import "time"
+
+type Color int
+
+const (
+	Red Color = iota
+	Green
+	Blue
+	Crimson = Red
+)
+
+type Mode string
+
+const (
+	ModeRead  Mode = "r"
+	ModeWrite Mode = "w"
+)
+
+func name(c Color) string {
+	switch c {
+	case Red:
+		return "red"
+	case Green:
+		return "green"
+	}
+	return ""
+}
+
+func aliased(c Color) bool {
+	switch c {
+	case Crimson, Green, Blue:
+		return true
+	}
+	return false
+}
+
+func fallback(c Color) string {
+	switch c {
+	case Red:
+		return "red"
+	default:
+		return "other"
+	}
+}
+
+func writable(m Mode) bool {
+	switch m {
+	case ModeWrite:
+		return true
+	}
+	return false
+}
+
+func dynamic(c, other Color) bool {
+	switch c {
+	case other:
+		return true
+	}
+	return false
+}
+
+func weekend(d time.Weekday) bool {
+	switch d {
+	case time.Saturday, time.Sunday:
+		return true
+	}
+	return false
+}
+`,
+	}
+	tmpDir := t.TempDir()
+	for name, content := range testFiles {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	result, err := NewValidator(tmpDir).WithConfig(allRules(nil)).ValidateProject(context.Background(), ValidationLevelStandard)
+	if err != nil {
+		t.Fatalf("ValidateProject() error = %v", err)
+	}
+
+	var got []string
+	for _, w := range result.Warnings {
+		if w.Type == "exhaustive" {
+			got = append(got, fmt.Sprintf("%s:%d:%d: %s", filepath.ToSlash(w.File), w.Line, w.Column, w.Message))
+		}
+	}
+	want := []string{
+		"p.go:22:2: switch on Color does not handle Blue and has no default",
+		"p.go:49:2: switch on Mode does not handle ModeRead and has no default",
+		"p.go:65:2: switch on Weekday does not handle Monday, Tuesday, Wednesday, Thursday, Friday and has no default",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("exhaustive warnings = %q, want %q", got, want)
+	}
+}
+
+func TestUncheckedErrorRule(t *testing.T) {
+	testFiles := map[string]string{
+		"go.mod": "module example.com/p\n\ngo 1.22\n",
+		"p.go": `package p
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+func save(name string, data []byte) {
+	os.WriteFile(name, data, 0600)
+	f, _ := os.Create(name)
+	defer f.Close()
+	_ = f.Sync()
+	n, _ := strconv.Atoi("1")
+	_, err := strconv.Atoi("2")
+	fmt.Println(n, err)
+	var buf bytes.Buffer
+	buf.WriteString("x")
+	fmt.Fprintf(&buf, "%d", n)
+	fmt.Fprintf(os.Stderr, "%d", n)
+	go f.Close()
+	_, _ = f.Sync(), f.Chmod(0600)
+}
+`,
+		"buffer.go": `package p
+
+import (
+	"bytes"
+	"io"
+)
+
+func copyTo(r io.Reader, w io.Writer) {
+	var buf bytes.Buffer
+	buf.WriteByte('x')
+	buf.WriteRune('y')
+	buf.Write(nil)
+	buf.ReadFrom(r)
+	buf.WriteTo(w)
+	buf.UnreadByte()
+}
+`,
+		"hash.go": `package p
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"hash/crc32"
+	"hash/maphash"
+)
+
+func digest(data []byte) {
+	h := sha256.New()
+	h.Write(data)
+	fmt.Fprintf(h, "%d", len(data))
+	c := crc32.NewIEEE()
+	c.Write(data)
+	var m maphash.Hash
+	m.WriteString("x")
+	fmt.Fprint(&m, data)
+}
+`,
+	}
+	tmpDir := t.TempDir()
+	for name, content := range testFiles {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		config *ValidatorConfig
+		want   []string
+	}{
+		{
+			name: "statements",
+			want: []string{
+				"buffer.go:13:2: error returned by buf.ReadFrom is not checked",
+				"buffer.go:14:2: error returned by buf.WriteTo is not checked",
+				"buffer.go:15:2: error returned by buf.UnreadByte is not checked",
+				"p.go:11:2: error returned by os.WriteFile is not checked",
+				"p.go:21:2: error returned by fmt.Fprintf is not checked",
+			},
+		},
+		{
+			name:   "blank assignments",
+			config: &ValidatorConfig{CheckBlankErrors: true},
+			want: []string{
+				"buffer.go:13:2: error returned by buf.ReadFrom is not checked",
+				"buffer.go:14:2: error returned by buf.WriteTo is not checked",
+				"buffer.go:15:2: error returned by buf.UnreadByte is not checked",
+				"p.go:11:2: error returned by os.WriteFile is not checked",
+				"p.go:12:10: error returned by os.Create is assigned to _",
+				"p.go:14:6: error returned by f.Sync is assigned to _",
+				"p.go:15:10: error returned by strconv.Atoi is assigned to _",
+				"p.go:21:2: error returned by fmt.Fprintf is not checked",
+				"p.go:23:9: error returned by f.Sync is assigned to _",
+				"p.go:23:19: error returned by f.Chmod is assigned to _",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewValidator(tmpDir).WithConfig(allRules(tt.config)).ValidateProject(context.Background(), ValidationLevelStandard)
+			if err != nil {
+				t.Fatalf("ValidateProject() error = %v", err)
+			}
+
+			var got []string
+			for _, w := range result.Warnings {
+				if w.Type == "unchecked_error" {
+					got = append(got, fmt.Sprintf("%s:%d:%d: %s", filepath.ToSlash(w.File), w.Line, w.Column, w.Message))
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("unchecked_error warnings = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnkeyedFieldsRule(t *testing.T) {
+	testFiles := map[string]string{
+		"go.mod": "module example.com/p\n\ngo 1.22\n",
+		"p.go": `package p
+
+import (
+	"image"
+	"net/url"
+)
+
+type pair struct{ a, b int }
+
+var (
+	local   = pair{1, 2}
+	keyed   = image.Point{X: 1, Y: 2}
+	points  = []image.Point{{1, 2}, {X: 3, Y: 4}}
+	rects   = []*image.Rectangle{{image.Point{}, image.Pt(1, 1)}}
+	userURL = url.URL{}
+)
+`,
+	}
+	tmpDir := t.TempDir()
+	for name, content := range testFiles {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	result, err := NewValidator(tmpDir).WithConfig(allRules(nil)).ValidateProject(context.Background(), ValidationLevelStandard)
+	if err != nil {
+		t.Fatalf("ValidateProject() error = %v", err)
+	}
+
+	var got []string
+	for _, w := range result.Warnings {
+		if w.Type == "unkeyed_fields" {
+			got = append(got, fmt.Sprintf("%s:%d:%d: %s", filepath.ToSlash(w.File), w.Line, w.Column, w.Message))
+		}
+	}
+	want := []string{
+		"p.go:13:26: image.Point literal uses unkeyed fields",
+		"p.go:14:31: image.Rectangle literal uses unkeyed fields",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unkeyed_fields warnings = %q, want %q", got, want)
+	}
+}
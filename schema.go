@@ -0,0 +1,200 @@
+package readgo
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+//go:generate go run ./internal/genschema schema
+
+// SchemaVersion is the version of the JSON output format described by the
+// schemas in the schema directory. It changes whenever a field is removed,
+// renamed or changes type; adding a field does not change it.
+const SchemaVersion = "1"
+
+// jsonSchemaDialect is the JSON Schema draft the schemas are written in
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// schemaTypes are the output types a schema is shipped for
+var schemaTypes = []interface{}{
+	AnalysisResult{},
+	ValidationResult{},
+	FileTreeNode{},
+	FilePage{},
+	ContentMatch{},
+	FileContent{},
+	TypeInfo{},
+	CacheStats{},
+}
+
+// schemaEnums lists the values of the string types that take only a fixed
+// set of values in JSON
+var schemaEnums = map[reflect.Type][]string{
+	reflect.TypeOf(ValidationLevel(0)): {"basic", "standard", "strict"},
+	reflect.TypeOf(Severity("")):       {string(SeverityInfo), string(SeverityWarning), string(SeverityError)},
+	reflect.TypeOf(DependencyKind("")): {string(DependencyStandard), string(DependencyInternal), string(DependencyExternal)},
+	reflect.TypeOf(TypeKind("")): {
+		string(TypeKindStruct), string(TypeKindInterface), string(TypeKindAlias), string(TypeKindFunc), string(TypeKindMap),
+		string(TypeKindSlice), string(TypeKindArray), string(TypeKindPointer), string(TypeKindChan), string(TypeKindBasic),
+	},
+	reflect.TypeOf(FileType("")): {
+		string(FileTypeAll), string(FileTypeGo), string(FileTypeTest), string(FileTypeGenerated), string(FileTypeGoPackage),
+	},
+}
+
+// Schemas returns the JSON Schema of every output type, keyed by the name
+// of the file it is shipped in, e.g. "analysis_result.schema.json"
+func Schemas() (map[string][]byte, error) {
+	schemas := make(map[string][]byte, len(schemaTypes))
+	for _, v := range schemaTypes {
+		schema, err := JSONSchema(v)
+		if err != nil {
+			return nil, err
+		}
+		schemas[schemaFileName(reflect.TypeOf(v).Name())] = schema
+	}
+	return schemas, nil
+}
+
+// JSONSchema returns the JSON Schema of the JSON encoding of values of the
+// struct type of v. Struct types it refers to are defined under $defs.
+func JSONSchema(v interface{}) ([]byte, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct || t.Name() == "" {
+		return nil, fmt.Errorf("%w: JSON schema of %T, want a named struct", ErrInvalidInput, v)
+	}
+
+	g := &schemaGenerator{defs: make(map[string]map[string]interface{})}
+	root := map[string]interface{}{
+		"$schema":          jsonSchemaDialect,
+		"title":            t.Name(),
+		"x-schema-version": SchemaVersion,
+		"$ref":             g.schemaOf(t)["$ref"],
+		"$defs":            g.defs,
+	}
+	data, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode JSON schema of %s: %w", t.Name(), err)
+	}
+	return append(data, '\n'), nil
+}
+
+// schemaGenerator builds the schemas of Go types, collecting the struct
+// types it meets as definitions
+type schemaGenerator struct {
+	defs map[string]map[string]interface{}
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// schemaOf returns the schema of values of t
+func (g *schemaGenerator) schemaOf(t reflect.Type) map[string]interface{} {
+	if values, ok := schemaEnums[t]; ok {
+		return map[string]interface{}{"type": "string", "enum": values}
+	}
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+	if t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		return map[string]interface{}{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schemaOf(t.Elem())
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]interface{}{"type": "array", "items": g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		ref := map[string]interface{}{"$ref": "#/$defs/" + t.Name()}
+		if _, ok := g.defs[t.Name()]; ok {
+			return ref
+		}
+		def := map[string]interface{}{"type": "object"}
+		g.defs[t.Name()] = def // Before the fields, which may refer to t
+		properties := make(map[string]interface{})
+		var required []string
+		g.addFields(t, properties, &required)
+		def["properties"] = properties
+		if len(required) > 0 {
+			def["required"] = required
+		}
+		return ref
+	default:
+		return map[string]interface{}{} // Any value, e.g. of an interface{}
+	}
+}
+
+// addFields adds the schemas of the JSON fields of the struct type t to
+// properties, and the names of those always present to required. Fields of
+// embedded structs without a JSON name are promoted as encoding/json does.
+func (g *schemaGenerator) addFields(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			g.addFields(f.Type, properties, required)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		schema := g.schemaOf(f.Type)
+		omitEmpty := strings.Contains(","+opts+",", ",omitempty,")
+		switch f.Type.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Map:
+			if !omitEmpty {
+				// A nil value is encoded as null
+				schema = map[string]interface{}{"anyOf": []interface{}{schema, map[string]interface{}{"type": "null"}}}
+			}
+		}
+		properties[name] = schema
+		if !omitEmpty {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// schemaFileName returns the file name of the schema of the type name,
+// e.g. "analysis_result.schema.json" for AnalysisResult
+func schemaFileName(name string) string {
+	snake := upperRun.ReplaceAllStringFunc(name, func(s string) string {
+		return "_" + strings.ToLower(s)
+	})
+	return strings.TrimPrefix(snake, "_") + ".schema.json"
+}
+
+// upperRun matches a run of upper case letters
+var upperRun = regexp.MustCompile(`[A-Z]+`)
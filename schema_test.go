@@ -0,0 +1,224 @@
+package readgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSchemasUpToDate(t *testing.T) {
+	schemas, err := Schemas()
+	if err != nil {
+		t.Fatalf("Failed to generate schemas: %v", err)
+	}
+	files, err := filepath.Glob(filepath.Join("schema", "*.schema.json"))
+	if err != nil {
+		t.Fatalf("Failed to list schema files: %v", err)
+	}
+	if len(files) != len(schemas) {
+		t.Errorf("schema directory has %d files, want %d; run go generate", len(files), len(schemas))
+	}
+	for name, schema := range schemas {
+		shipped, err := os.ReadFile(filepath.Join("schema", name))
+		if err != nil {
+			t.Errorf("Failed to read shipped schema: %v; run go generate", err)
+			continue
+		}
+		if !bytes.Equal(shipped, schema) {
+			t.Errorf("schema/%s is out of date; run go generate", name)
+		}
+	}
+}
+
+func TestJSONSchemaMatchesOutput(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+	}{
+		{
+			name: "analysis result",
+			value: &AnalysisResult{
+				Name:         "p",
+				AnalyzedAt:   time.Now(),
+				Types:        []TypeInfo{{Name: "T", Package: "p", Type: "int"}},
+				Dependencies: []Dependency{{Path: "fmt", Kind: DependencyStandard}},
+				Extensions:   map[string][]Entity{"routes": {{Name: "GET /", Value: map[string]int{"line": 1}}}},
+			},
+		},
+		{
+			name: "validation result",
+			value: &ValidationResult{
+				Level: ValidationLevelStrict,
+				Warnings: []ValidationWarning{{
+					Type:           "naming",
+					Severity:       SeverityError,
+					Message:        "bad name",
+					SuggestedFixes: []SuggestedFix{{Message: "Rename", Edits: []TextEdit{{File: "a.go", NewText: "x"}}}},
+				}},
+				Stats:        ValidationStats{SuppressedByRule: map[string]int{"naming": 1}},
+				CircularDeps: []Cycle{{Packages: []string{"a", "b"}}},
+			},
+		},
+		{
+			name:  "file tree",
+			value: &FileTreeNode{Name: "root", Type: "directory", Children: []*FileTreeNode{{Name: "a.go", Type: "file"}}},
+		},
+		{
+			name:  "empty file content",
+			value: &FileContent{},
+		},
+		{
+			name:  "cache stats",
+			value: NewCache(time.Minute).Stats(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := JSONSchema(tt.value)
+			if err != nil {
+				t.Fatalf("Failed to generate schema: %v", err)
+			}
+			var schema map[string]interface{}
+			if err := json.Unmarshal(data, &schema); err != nil {
+				t.Fatalf("Failed to decode schema: %v", err)
+			}
+			if schema["x-schema-version"] != SchemaVersion {
+				t.Errorf("schema version = %v, want %s", schema["x-schema-version"], SchemaVersion)
+			}
+
+			output, err := json.Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("Failed to marshal value: %v", err)
+			}
+			var value interface{}
+			if err := json.Unmarshal(output, &value); err != nil {
+				t.Fatalf("Failed to decode value: %v", err)
+			}
+			defs := schema["$defs"].(map[string]interface{})
+			for _, problem := range checkSchema(schema, defs, value, "$") {
+				t.Error(problem)
+			}
+		})
+	}
+
+	if _, err := JSONSchema(42); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("JSONSchema(42) error = %v, want ErrInvalidInput", err)
+	}
+}
+
+// checkSchema returns the ways value violates schema, covering the subset of
+// JSON Schema that JSONSchema generates
+func checkSchema(schema, defs map[string]interface{}, value interface{}, path string) []string {
+	if ref, ok := schema["$ref"].(string); ok {
+		return checkSchema(defs[filepath.Base(ref)].(map[string]interface{}), defs, value, path)
+	}
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		for _, s := range anyOf {
+			if len(checkSchema(s.(map[string]interface{}), defs, value, path)) == 0 {
+				return nil
+			}
+		}
+		return []string{path + ": matches no alternative"}
+	}
+
+	var problems []string
+	switch schema["type"] {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return []string{path + ": not an object"}
+		}
+		for _, name := range asSlice(schema["required"]) {
+			if _, ok := obj[name.(string)]; !ok {
+				problems = append(problems, path+": missing "+name.(string))
+			}
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		additional, _ := schema["additionalProperties"].(map[string]interface{})
+		for name, v := range obj {
+			s, ok := properties[name].(map[string]interface{})
+			if !ok {
+				s = additional
+			}
+			if s == nil {
+				problems = append(problems, path+": unexpected property "+name)
+				continue
+			}
+			problems = append(problems, checkSchema(s, defs, v, path+"."+name)...)
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return []string{path + ": not an array"}
+		}
+		for _, item := range items {
+			problems = append(problems, checkSchema(schema["items"].(map[string]interface{}), defs, item, path+"[]")...)
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return []string{path + ": not a string"}
+		}
+		if enum := asSlice(schema["enum"]); enum != nil {
+			found := false
+			for _, e := range enum {
+				found = found || e == s
+			}
+			if !found {
+				problems = append(problems, path+": "+s+" is not in the enum")
+			}
+		}
+	case "integer", "number":
+		if _, ok := value.(float64); !ok {
+			return []string{path + ": not a number"}
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return []string{path + ": not a boolean"}
+		}
+	case "null":
+		if value != nil {
+			return []string{path + ": not null"}
+		}
+	}
+	return problems
+}
+
+// asSlice returns v as a slice, or nil if it is none
+func asSlice(v interface{}) []interface{} {
+	s, _ := v.([]interface{})
+	return s
+}
+
+func TestResultVersions(t *testing.T) {
+	if v := Version(); v != develVersion {
+		t.Errorf("Version() = %q, want %q in tests", v, develVersion)
+	}
+
+	ctx := context.Background()
+	analysis, err := NewAnalyzer(WithWorkDir("testdata/basic")).AnalyzeProject(ctx, ".")
+	if err != nil {
+		t.Fatalf("Failed to analyze project: %v", err)
+	}
+	validation, err := NewValidator("testdata/basic").ValidateProject(ctx, ValidationLevelBasic)
+	if err != nil {
+		t.Fatalf("Failed to validate project: %v", err)
+	}
+	merged := MergeResults()
+
+	for name, got := range map[string][2]string{
+		"analysis":   {analysis.SchemaVersion, analysis.ToolVersion},
+		"validation": {validation.SchemaVersion, validation.ToolVersion},
+		"merged":     {merged.SchemaVersion, merged.ToolVersion},
+	} {
+		if got != [2]string{SchemaVersion, Version()} {
+			t.Errorf("%s versions = %q, want %q and %q", name, got, SchemaVersion, Version())
+		}
+	}
+}
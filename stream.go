@@ -0,0 +1,141 @@
+package readgo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// PackageResult is the analysis of one package sent by AnalyzeProjectStream
+type PackageResult struct {
+	Path   string          // Import path of the package
+	Result *AnalysisResult // Analysis of the package alone, nil if Err is set
+	Err    error
+}
+
+// AnalyzeProjectStream analyzes the packages of the project at projectPath
+// one at a time, sending the result of each to the returned channel in
+// import path order; the channel is closed after the last one. Unlike
+// AnalyzeProject, only one package is held in memory at a time, which
+// bounds memory on very large projects at the cost of type checking shared
+// dependencies once per package. Loaded packages bypass the cache.
+//
+// A package that fails to load is sent with its error and the stream goes
+// on; if the packages cannot be listed at all, a single result with the
+// error is sent. Cancelling ctx ends the stream early, so callers that stop
+// reading must cancel it to release the goroutine.
+func (a *DefaultAnalyzer) AnalyzeProjectStream(ctx context.Context, projectPath string) <-chan PackageResult {
+	results := make(chan PackageResult)
+	go func() {
+		defer close(results)
+		send := func(r PackageResult) bool {
+			select {
+			case results <- r:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		if projectPath == "" {
+			projectPath = "."
+		}
+		absPath, err := filepath.Abs(filepath.Join(a.workDir, projectPath))
+		if err != nil {
+			send(PackageResult{Err: &AnalysisError{Op: "analyze project", Path: projectPath, Wrapped: err}})
+			return
+		}
+		paths, err := a.listPackages(ctx, absPath)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			send(PackageResult{Err: &AnalysisError{
+				Op:      "analyze project",
+				Path:    projectPath,
+				Wrapped: fmt.Errorf("failed to list packages: %w", err),
+			}})
+			return
+		}
+
+		for _, path := range paths {
+			if ctx.Err() != nil {
+				return
+			}
+			result, err := a.analyzeStreamed(ctx, absPath, path)
+			if ctx.Err() != nil {
+				return // The result may be partial
+			}
+			if !send(PackageResult{Path: path, Result: result, Err: err}) {
+				return
+			}
+		}
+	}()
+	return results
+}
+
+// listPackages returns the import paths of the packages below dir, without
+// loading more than their names
+func (a *DefaultAnalyzer) listPackages(ctx context.Context, dir string) ([]string, error) {
+	cfg := &packages.Config{
+		Context: ctx,
+		Mode:    packages.NeedName,
+		Dir:     dir,
+		Env:     append(os.Environ(), "GO111MODULE=on"),
+	}
+	pkgs, err := loadTraced(a.tracer, a.observer, loadPackages, cfg, "./...")
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, 0, len(pkgs))
+	for _, pkg := range pkgs {
+		if pkg.PkgPath != "" {
+			paths = append(paths, pkg.PkgPath)
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// analyzeStreamed loads and analyzes the package pkgPath from dir on its
+// own, so that it can be dropped once its result is sent
+func (a *DefaultAnalyzer) analyzeStreamed(ctx context.Context, dir, pkgPath string) (result *AnalysisResult, err error) {
+	start := time.Now()
+	defer func() { a.observeAnalysis("package", start, err) }()
+
+	cfg := &packages.Config{
+		Context: ctx,
+		Mode:    analyzerLoadMode,
+		Dir:     dir,
+		Env:     append(os.Environ(), "GO111MODULE=on"),
+	}
+	pkgs, err := loadTraced(a.tracer, a.observer, loadPackages, cfg, pkgPath)
+	if err == nil && len(pkgs) == 0 {
+		err = fmt.Errorf("%w: no package %s", ErrNotFound, pkgPath)
+	}
+	if err != nil {
+		return nil, &AnalysisError{Op: "analyze package", Path: pkgPath, Wrapped: fmt.Errorf("failed to load package: %w", err)}
+	}
+	a.logger.DebugContext(ctx, "loaded package", "dir", dir, "package", pkgPath, "duration", time.Since(start))
+
+	pkg := pkgs[0]
+	result = &AnalysisResult{
+		Name:          pkg.Name,
+		Path:          pkg.PkgPath,
+		StartTime:     time.Now().Format(time.RFC3339),
+		AnalyzedAt:    time.Now(),
+		SchemaVersion: SchemaVersion,
+		ToolVersion:   Version(),
+	}
+	if err := a.analyzeInto(ctx, pkg, result); err != nil {
+		return nil, err
+	}
+	result.Dependencies = dependencies(result.Imports, packageModulePath(pkg))
+	result.Module = readModuleInfo(packageGoMod(pkg))
+	return result, nil
+}
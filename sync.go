@@ -0,0 +1,236 @@
+package readgo
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// syncRule reports misuse of the sync package: values containing locks that
+// are copied, sync.WaitGroup.Add called inside the goroutine it waits for
+// and locks that are not released by a defer in the same function. Its
+// findings use the check names lock_copy, waitgroup_add and lock_defer.
+type syncRule struct{}
+
+// Name returns the rule name
+func (syncRule) Name() string {
+	return "sync"
+}
+
+// Check reports the sync misuse in the package
+func (syncRule) Check(ctx context.Context, pkg *packages.Package) []Finding {
+	if pkg.TypesInfo == nil {
+		return nil
+	}
+	info := pkg.TypesInfo
+
+	var findings []Finding
+	report := func(check string, node ast.Node, format string, args ...interface{}) {
+		pos := pkg.Fset.Position(node.Pos())
+		findings = append(findings, Finding{
+			Rule:    check,
+			Message: fmt.Sprintf(format, args...),
+			File:    pos.Filename,
+			Line:    pos.Line,
+			Column:  pos.Column,
+		})
+	}
+
+	// copied reports expr if evaluating it copies a lock
+	copied := func(expr ast.Expr, what string) {
+		switch ast.Unparen(expr).(type) {
+		case *ast.Ident, *ast.SelectorExpr, *ast.IndexExpr, *ast.StarExpr:
+		default:
+			return
+		}
+		tv, ok := info.Types[expr]
+		if !ok || tv.Type == nil || !tv.IsValue() {
+			return
+		}
+		if lock := lockIn(tv.Type, nil); lock != "" {
+			report("lock_copy", expr, "%s copies a %s value", what, lock)
+		}
+	}
+
+	// byValue reports the fields of a receiver or parameter list that pass
+	// a lock by value
+	byValue := func(fields *ast.FieldList, what string) {
+		if fields == nil {
+			return
+		}
+		for _, field := range fields.List {
+			tv, ok := info.Types[field.Type]
+			if !ok || tv.Type == nil {
+				continue
+			}
+			if lock := lockIn(tv.Type, nil); lock != "" {
+				report("lock_copy", field.Type, "%s %s passes a %s by value", what, types.ExprString(field.Type), lock)
+			}
+		}
+	}
+
+	for _, file := range pkg.Syntax {
+		if ctx.Err() != nil {
+			break
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncDecl:
+				byValue(n.Recv, "receiver")
+				byValue(n.Type.Params, "parameter")
+				if n.Body != nil {
+					checkDeferredUnlocks(info, n.Body, report)
+				}
+			case *ast.FuncLit:
+				byValue(n.Type.Params, "parameter")
+				checkDeferredUnlocks(info, n.Body, report)
+			case *ast.AssignStmt:
+				for _, rhs := range n.Rhs {
+					copied(rhs, "assignment")
+				}
+			case *ast.ValueSpec:
+				for _, value := range n.Values {
+					copied(value, "variable declaration")
+				}
+			case *ast.RangeStmt:
+				if n.Value == nil {
+					return true
+				}
+				if t := info.TypeOf(n.Value); t != nil {
+					if lock := lockIn(t, nil); lock != "" {
+						report("lock_copy", n.Value, "range variable %s copies a %s value", types.ExprString(n.Value), lock)
+					}
+				}
+			case *ast.GoStmt:
+				lit, ok := ast.Unparen(n.Call.Fun).(*ast.FuncLit)
+				if !ok {
+					return true
+				}
+				ast.Inspect(lit.Body, func(n ast.Node) bool {
+					if _, ok := n.(*ast.GoStmt); ok {
+						return false
+					}
+					if call, ok := n.(*ast.CallExpr); ok && isSyncMethod(info, call, "WaitGroup", "Add") {
+						report("waitgroup_add", call, "%s is called inside the goroutine it waits for; call it before the go statement", types.ExprString(call.Fun))
+					}
+					return true
+				})
+			}
+			return true
+		})
+	}
+	return findings
+}
+
+// checkDeferredUnlocks reports the Lock and RLock calls in body that are
+// not matched by a deferred Unlock or RUnlock of the same lock, either
+// directly or in a deferred function literal. Function literals in body
+// are checked on their own.
+func checkDeferredUnlocks(info *types.Info, body *ast.BlockStmt, report func(string, ast.Node, string, ...interface{})) {
+	var locks []*ast.CallExpr
+	deferred := make(map[string]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.DeferStmt:
+			calls := []*ast.CallExpr{n.Call}
+			if lit, ok := ast.Unparen(n.Call.Fun).(*ast.FuncLit); ok {
+				ast.Inspect(lit.Body, func(n ast.Node) bool {
+					if call, ok := n.(*ast.CallExpr); ok {
+						calls = append(calls, call)
+					}
+					return true
+				})
+			}
+			for _, call := range calls {
+				for _, unlock := range []string{"Unlock", "RUnlock"} {
+					if isSyncMethod(info, call, "Mutex", unlock) || isSyncMethod(info, call, "RWMutex", unlock) {
+						sel := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+						deferred[types.ExprString(sel.X)+"."+unlock] = true
+					}
+				}
+			}
+			return false
+		case *ast.CallExpr:
+			for _, lock := range []string{"Lock", "RLock"} {
+				if isSyncMethod(info, n, "Mutex", lock) || isSyncMethod(info, n, "RWMutex", lock) {
+					locks = append(locks, n)
+				}
+			}
+		}
+		return true
+	})
+
+	for _, call := range locks {
+		sel := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+		unlock := "Unlock"
+		if sel.Sel.Name == "RLock" {
+			unlock = "RUnlock"
+		}
+		x := types.ExprString(sel.X)
+		if !deferred[x+"."+unlock] {
+			report("lock_defer", call, "%s.%s() is not released by a deferred %s.%s()", x, sel.Sel.Name, x, unlock)
+		}
+	}
+}
+
+// isSyncMethod reports whether call calls the method name of the named type
+// of package sync, directly or promoted through embedding
+func isSyncMethod(info *types.Info, call *ast.CallExpr, typeName, name string) bool {
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	fn, ok := info.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "sync" {
+		return false
+	}
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return false
+	}
+	t := recv.Type()
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Name() == typeName
+}
+
+// syncLocks lists the types of package sync that must not be copied
+var syncLocks = map[string]bool{
+	"Cond": true, "Map": true, "Mutex": true, "Once": true, "Pool": true, "RWMutex": true, "WaitGroup": true,
+}
+
+// lockIn returns the name of the sync type t is or contains by value, or ""
+func lockIn(t types.Type, seen map[types.Type]bool) string {
+	if seen[t] {
+		return ""
+	}
+	if seen == nil {
+		seen = make(map[types.Type]bool)
+	}
+	seen[t] = true
+
+	if named, ok := t.(*types.Named); ok {
+		obj := named.Obj()
+		if obj.Pkg() != nil && obj.Pkg().Path() == "sync" && syncLocks[obj.Name()] {
+			return "sync." + obj.Name()
+		}
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Struct:
+		for i := 0; i < u.NumFields(); i++ {
+			if lock := lockIn(u.Field(i).Type(), seen); lock != "" {
+				return lock
+			}
+		}
+	case *types.Array:
+		return lockIn(u.Elem(), seen)
+	}
+	return ""
+}
@@ -0,0 +1,70 @@
+package readgo
+
+import (
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strings"
+)
+
+// todoCheck is the check name that controls the collection of TODO comments
+const todoCheck = "todo"
+
+// TodoComment is a TODO, FIXME or HACK comment, recorded to inventory
+// technical debt
+type TodoComment struct {
+	Tag    string `json:"tag" yaml:"tag"`                           // TODO, FIXME or HACK
+	Author string `json:"author,omitempty" yaml:"author,omitempty"` // Author from TODO(name) or a leading @name
+	Text   string `json:"text" yaml:"text"`                         // Text following the tag
+	File   string `json:"file" yaml:"file"`
+	Line   int    `json:"line" yaml:"line"`
+	Column int    `json:"column" yaml:"column"`
+}
+
+// todoPattern matches a tag at the start of a comment line with an
+// optional (author) and colon
+var todoPattern = regexp.MustCompile(`^(TODO|FIXME|HACK)\b(?:\(([^)]*)\))?:?\s*(.*)$`)
+
+// todoAuthorPattern matches an @author at the start of the text of a tag
+var todoAuthorPattern = regexp.MustCompile(`^@([\w.-]+):?\s*(.*)$`)
+
+// collectTodos returns the TODO, FIXME and HACK comments of file. Tags are
+// recognized at the start of a comment line only, so prose that mentions
+// them is skipped.
+func collectTodos(fset *token.FileSet, file *ast.File) []TodoComment {
+	var todos []TodoComment
+	for _, group := range file.Comments {
+		for _, c := range group.List {
+			text := c.Text
+			offset := 2 // Skip "//" or "/*"
+			if strings.HasPrefix(text, "/*") {
+				text = strings.TrimSuffix(text, "*/")
+			}
+			for i, line := range strings.Split(text[2:], "\n") {
+				if i > 0 {
+					offset = 0
+				}
+				trimmed := strings.TrimLeft(line, " \t*")
+				m := todoPattern.FindStringSubmatch(trimmed)
+				if m == nil {
+					continue
+				}
+				todo := TodoComment{Tag: m[1], Author: strings.TrimPrefix(strings.TrimSpace(m[2]), "@"), Text: strings.TrimSpace(m[3])}
+				if todo.Author == "" {
+					if a := todoAuthorPattern.FindStringSubmatch(todo.Text); a != nil {
+						todo.Author, todo.Text = a[1], a[2]
+					}
+				}
+				pos := fset.Position(c.Pos())
+				todo.File = pos.Filename
+				todo.Line = pos.Line + i
+				todo.Column = offset + len(line) - len(trimmed) + 1
+				if i == 0 {
+					todo.Column += pos.Column - 1
+				}
+				todos = append(todos, todo)
+			}
+		}
+	}
+	return todos
+}
@@ -0,0 +1,77 @@
+package readgo
+
+import (
+	"context"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCollectTodos(t *testing.T) {
+	source := `package p
+
+// TODO(alice): split this function
+func a() {} // FIXME: off by one
+
+// HACK @bob: works around the cgo bug
+// This is not a TODO because it is mid-sentence.
+/*
+ * TODO handle errors
+ */
+func b() {}
+
+// TODOS is not a tag
+var c int
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", source, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("Failed to parse source: %v", err)
+	}
+
+	got := collectTodos(fset, file)
+	want := []TodoComment{
+		{Tag: "TODO", Author: "alice", Text: "split this function", File: "p.go", Line: 3, Column: 4},
+		{Tag: "FIXME", Text: "off by one", File: "p.go", Line: 4, Column: 16},
+		{Tag: "HACK", Author: "bob", Text: "works around the cgo bug", File: "p.go", Line: 6, Column: 4},
+		{Tag: "TODO", Text: "handle errors", File: "p.go", Line: 9, Column: 4},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("collectTodos() = %+v, want %+v", got, want)
+	}
+}
+
+func TestValidateProjectTodos(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFiles := map[string]string{
+		"go.mod": "module example.com/p\n\ngo 1.22\n",
+		"p.go":   "package p\n\n// TODO(carol): remove once the API is stable\nfunc Old() {}\n",
+	}
+	for name, content := range testFiles {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	v := NewValidator(tmpDir)
+	result, err := v.ValidateProject(context.Background(), ValidationLevelBasic)
+	if err != nil {
+		t.Fatalf("Failed to validate project: %v", err)
+	}
+	want := []TodoComment{{Tag: "TODO", Author: "carol", Text: "remove once the API is stable", File: "p.go", Line: 3, Column: 4}}
+	if !reflect.DeepEqual(result.Todos, want) {
+		t.Errorf("Todos = %+v, want %+v", result.Todos, want)
+	}
+
+	v.WithConfig(&ValidatorConfig{Rules: map[string]bool{"todo": false}})
+	result, err = v.ValidateProject(context.Background(), ValidationLevelBasic)
+	if err != nil {
+		t.Fatalf("Failed to validate project: %v", err)
+	}
+	if len(result.Todos) != 0 {
+		t.Errorf("Todos = %+v, want none with the todo check disabled", result.Todos)
+	}
+}
@@ -0,0 +1,60 @@
+package readgo
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"golang.org/x/tools/go/packages"
+)
+
+// tracerName is the instrumentation scope of the spans readgo starts
+const tracerName = "github.com/iamlongalong/readgo"
+
+// newTracer returns the readgo tracer of tp, or one that records nothing if
+// tp is nil
+func newTracer(tp trace.TracerProvider) trace.Tracer {
+	if tp == nil {
+		tp = noop.NewTracerProvider()
+	}
+	return tp.Tracer(tracerName, trace.WithInstrumentationVersion(Version()))
+}
+
+// endSpan ends span, recording err as its status if it is not nil
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// cacheSpanAttributes describes the outcome of a cache lookup: whether it
+// hit and, if so, whether in memory or on disk
+func cacheSpanAttributes(hit bool, source string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{attribute.Bool("readgo.cache.hit", hit)}
+	if hit {
+		attrs = append(attrs, attribute.String("readgo.cache.source", source))
+	}
+	return attrs
+}
+
+// loadTraced calls load for pattern within a packages.Load span, started
+// from the context of cfg, and reports the load to observer if it is not nil
+func loadTraced(tracer trace.Tracer, observer Observer, load func(*packages.Config, ...string) ([]*packages.Package, error), cfg *packages.Config, pattern string) ([]*packages.Package, error) {
+	ctx, span := tracer.Start(cfg.Context, "packages.Load", trace.WithAttributes(
+		attribute.String("readgo.dir", cfg.Dir),
+		attribute.String("readgo.pattern", pattern),
+	))
+	cfg.Context = ctx
+	start := time.Now()
+	pkgs, err := load(cfg, pattern)
+	if observer != nil {
+		observer.PackagesLoaded(time.Since(start), err)
+	}
+	span.SetAttributes(attribute.Int("readgo.packages", len(pkgs)))
+	endSpan(span, err)
+	return pkgs, err
+}
@@ -0,0 +1,82 @@
+package readgo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestWithTracerProvider(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupTestFiles(t, tmpDir)
+	ctx := context.Background()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	analyzer := NewAnalyzer(WithWorkDir(tmpDir), WithCacheTTL(time.Minute), WithTracerProvider(tp))
+	for i := 0; i < 2; i++ {
+		if _, err := analyzer.AnalyzePackage(ctx, "./testdata/basic"); err != nil {
+			t.Fatalf("Failed to analyze package: %v", err)
+		}
+	}
+	reader := NewDefaultReader().WithWorkDir(tmpDir).WithTracerProvider(tp)
+	if _, err := reader.GetFileTree(ctx, "testdata", TreeOptions{}); err != nil {
+		t.Fatalf("Failed to get file tree: %v", err)
+	}
+	if _, err := reader.GetFileTree(ctx, "missing", TreeOptions{}); err == nil {
+		t.Fatal("GetFileTree() of a missing directory succeeded")
+	}
+	validator := NewValidator(tmpDir, WithTracerProvider(tp))
+	if _, err := validator.ValidatePackage(ctx, "testdata/basic", ValidationLevelBasic); err != nil {
+		t.Fatalf("Failed to validate package: %v", err)
+	}
+
+	spans := map[string][]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = append(spans[span.Name()], span)
+		if span.InstrumentationScope().Name != tracerName {
+			t.Errorf("span %s has scope %q, want %q", span.Name(), span.InstrumentationScope().Name, tracerName)
+		}
+	}
+	attr := func(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+		for _, kv := range span.Attributes() {
+			if kv.Key == key {
+				return kv.Value
+			}
+		}
+		return attribute.Value{}
+	}
+
+	if n := len(spans["packages.Load"]); n != 2 {
+		t.Errorf("got %d packages.Load spans, want one by the analyzer and one by the validator", n)
+	}
+	var hits, misses int
+	for _, span := range spans["readgo.cache.lookup"] {
+		if attr(span, "readgo.cache.kind").AsString() != "analysis" {
+			continue
+		}
+		if attr(span, "readgo.cache.hit").AsBool() {
+			hits++
+		} else {
+			misses++
+		}
+	}
+	if hits != 1 || misses != 1 {
+		t.Errorf("got %d analysis cache hits and %d misses, want 1 and 1", hits, misses)
+	}
+	if trees := spans["readgo.GetFileTree"]; len(trees) != 2 || trees[1].Status().Code != codes.Error {
+		t.Errorf("got GetFileTree spans %v, want two, the second failed", trees)
+	}
+	validate := spans["readgo.validate"]
+	if len(validate) != 1 || len(spans["readgo.checkPackage"]) != 1 {
+		t.Fatalf("got %d validate and %d checkPackage spans, want 1 of each", len(validate), len(spans["readgo.checkPackage"]))
+	}
+	if parent := spans["readgo.checkPackage"][0].Parent(); parent.SpanID() != validate[0].SpanContext().SpanID() {
+		t.Error("checkPackage span is not a child of the validate span")
+	}
+}
@@ -0,0 +1,256 @@
+package readgo
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// Flatten returns the node and all of its descendants in depth-first order
+func (n *FileTreeNode) Flatten() []*FileTreeNode {
+	if n == nil {
+		return nil
+	}
+	nodes := []*FileTreeNode{n}
+	for _, child := range n.Children {
+		nodes = append(nodes, child.Flatten()...)
+	}
+	return nodes
+}
+
+// Filter returns a copy of the tree containing only the files for which keep
+// returns true, together with the directories leading to them. The root is
+// always kept, and the totals of the kept directories only count the kept
+// files. The original tree is not modified.
+func (n *FileTreeNode) Filter(keep func(*FileTreeNode) bool) *FileTreeNode {
+	if n == nil {
+		return nil
+	}
+	filtered := *n
+	filtered.Children = nil
+	if filtered.Type == "directory" {
+		filtered.Size, filtered.FileCount, filtered.GoFileCount = 0, 0, 0
+	}
+	for _, child := range n.Children {
+		if child.Type == "directory" {
+			if sub := child.Filter(keep); len(sub.Children) > 0 {
+				filtered.Children = append(filtered.Children, sub)
+				filtered.Size += sub.Size
+				filtered.FileCount += sub.FileCount
+				filtered.GoFileCount += sub.GoFileCount
+			}
+			continue
+		}
+		if keep(child) {
+			c := *child
+			filtered.Children = append(filtered.Children, &c)
+			filtered.Size += c.Size
+			filtered.FileCount++
+			if strings.HasSuffix(c.Name, ".go") {
+				filtered.GoFileCount++
+			}
+		}
+	}
+	return &filtered
+}
+
+// TotalSize returns the combined size of all files in the subtree
+func (n *FileTreeNode) TotalSize() int64 {
+	if n == nil {
+		return 0
+	}
+	if n.Type == "file" {
+		return n.Size
+	}
+	var total int64
+	for _, child := range n.Children {
+		total += child.TotalSize()
+	}
+	return total
+}
+
+// MarshalIndent encodes the tree as indented JSON like json.MarshalIndent.
+// The totals of every directory are recomputed from its subtree, so its
+// size is the TotalSize of the subtree even in hand-built trees.
+func (n *FileTreeNode) MarshalIndent(prefix, indent string) ([]byte, error) {
+	if n == nil {
+		return []byte("null"), nil
+	}
+	export := cloneTree(n)
+	summarizeTree(export)
+	return json.MarshalIndent(export, prefix, indent)
+}
+
+// cloneTree returns a deep copy of the tree
+func cloneTree(n *FileTreeNode) *FileTreeNode {
+	c := *n
+	c.Children = nil
+	for _, child := range n.Children {
+		c.Children = append(c.Children, cloneTree(child))
+	}
+	return &c
+}
+
+// TreeDiff represents the file-level differences between two tree snapshots
+type TreeDiff struct {
+	Added    []*FileTreeNode `json:"added,omitempty" yaml:"added,omitempty"`
+	Removed  []*FileTreeNode `json:"removed,omitempty" yaml:"removed,omitempty"`
+	Modified []*FileTreeNode `json:"modified,omitempty" yaml:"modified,omitempty"` // nodes from the newer tree
+}
+
+// IsEmpty reports whether the diff contains no changes
+func (d *TreeDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+// DiffTrees compares two GetFileTree snapshots and reports added, removed
+// and modified files. Files are matched by path; a file counts as modified
+// when its hash differs (if both snapshots have hashes) or otherwise when
+// its size or modification time differs. Either tree may be nil.
+func DiffTrees(oldTree, newTree *FileTreeNode) *TreeDiff {
+	oldFiles := fileIndex(oldTree)
+	newFiles := fileIndex(newTree)
+
+	diff := &TreeDiff{}
+	for path, newNode := range newFiles {
+		oldNode, ok := oldFiles[path]
+		if !ok {
+			diff.Added = append(diff.Added, newNode)
+			continue
+		}
+		if fileChanged(oldNode, newNode) {
+			diff.Modified = append(diff.Modified, newNode)
+		}
+	}
+	for path, oldNode := range oldFiles {
+		if _, ok := newFiles[path]; !ok {
+			diff.Removed = append(diff.Removed, oldNode)
+		}
+	}
+
+	sortByPath(diff.Added)
+	sortByPath(diff.Removed)
+	sortByPath(diff.Modified)
+
+	return diff
+}
+
+// fileIndex maps the path of every file in the tree to its node
+func fileIndex(root *FileTreeNode) map[string]*FileTreeNode {
+	index := make(map[string]*FileTreeNode)
+	var walk func(*FileTreeNode)
+	walk = func(node *FileTreeNode) {
+		if node == nil {
+			return
+		}
+		if node.Type == "file" {
+			index[node.Path] = node
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(root)
+	return index
+}
+
+// fileChanged reports whether two snapshots of the same file differ
+func fileChanged(oldNode, newNode *FileTreeNode) bool {
+	if oldNode.Hash != "" && newNode.Hash != "" {
+		return oldNode.Hash != newNode.Hash
+	}
+	return oldNode.Size != newNode.Size || !oldNode.ModTime.Equal(newNode.ModTime)
+}
+
+// sortByPath sorts nodes by path
+func sortByPath(nodes []*FileTreeNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Path < nodes[j].Path
+	})
+}
+
+// collectFiles returns all file nodes in the tree in depth-first order
+func collectFiles(root *FileTreeNode) []*FileTreeNode {
+	var files []*FileTreeNode
+	var collect func(*FileTreeNode)
+	collect = func(node *FileTreeNode) {
+		if node.Type == "file" {
+			files = append(files, node)
+		}
+		for _, child := range node.Children {
+			collect(child)
+		}
+	}
+	collect(root)
+	return files
+}
+
+// searchTree returns the file nodes whose name contains pattern
+func searchTree(root *FileTreeNode, pattern string) []*FileTreeNode {
+	var matches []*FileTreeNode
+	for _, node := range collectFiles(root) {
+		if strings.Contains(node.Name, pattern) {
+			matches = append(matches, node)
+		}
+	}
+	return matches
+}
+
+// pageFiles returns the page of files starting at offset with at most limit
+// entries. A limit of zero or less returns all remaining files.
+func pageFiles(files []*FileTreeNode, offset, limit int) *FilePage {
+	page := &FilePage{Total: len(files)}
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(files) {
+		offset = len(files)
+	}
+	end := len(files)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+		page.Truncated = true
+		page.NextOffset = end
+	}
+	page.Files = files[offset:end]
+	return page
+}
+
+// finishTree sorts the tree and fills in the directory totals
+func finishTree(tree *FileTreeNode) {
+	sortTree(tree)
+	summarizeTree(tree)
+}
+
+// summarizeTree sets Size, FileCount and GoFileCount of every directory to
+// the totals of its subtree
+func summarizeTree(node *FileTreeNode) {
+	if node.Type != "directory" {
+		return
+	}
+	node.Size, node.FileCount, node.GoFileCount = 0, 0, 0
+	for _, child := range node.Children {
+		if child.Type == "directory" {
+			summarizeTree(child)
+			node.Size += child.Size
+			node.FileCount += child.FileCount
+			node.GoFileCount += child.GoFileCount
+			continue
+		}
+		node.Size += child.Size
+		node.FileCount++
+		if strings.HasSuffix(child.Name, ".go") {
+			node.GoFileCount++
+		}
+	}
+}
+
+// sortTree sorts the children of every directory by name
+func sortTree(node *FileTreeNode) {
+	sort.Slice(node.Children, func(i, j int) bool {
+		return node.Children[i].Name < node.Children[j].Name
+	})
+	for _, child := range node.Children {
+		sortTree(child)
+	}
+}
@@ -0,0 +1,105 @@
+package readgo
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func newTestTree() *FileTreeNode {
+	return &FileTreeNode{
+		Name: "root", Path: ".", Type: "directory",
+		Children: []*FileTreeNode{
+			{Name: "README.md", Path: "README.md", Type: "file", Size: 10},
+			{
+				Name: "pkg", Path: "pkg", Type: "directory",
+				Children: []*FileTreeNode{
+					{Name: "a.go", Path: "pkg/a.go", Type: "file", Size: 100},
+					{Name: "a_test.go", Path: "pkg/a_test.go", Type: "file", Size: 50},
+				},
+			},
+			{
+				Name: "docs", Path: "docs", Type: "directory",
+				Children: []*FileTreeNode{
+					{Name: "guide.md", Path: "docs/guide.md", Type: "file", Size: 5},
+				},
+			},
+		},
+	}
+}
+
+func TestFileTreeFlatten(t *testing.T) {
+	nodes := newTestTree().Flatten()
+	var paths []string
+	for _, n := range nodes {
+		paths = append(paths, n.Path)
+	}
+	want := ".,README.md,pkg,pkg/a.go,pkg/a_test.go,docs,docs/guide.md"
+	if got := strings.Join(paths, ","); got != want {
+		t.Errorf("Flatten() = %s, want %s", got, want)
+	}
+}
+
+func TestFileTreeFilter(t *testing.T) {
+	tree := newTestTree()
+	goFiles := tree.Filter(func(n *FileTreeNode) bool {
+		return strings.HasSuffix(n.Name, ".go")
+	})
+
+	if len(goFiles.Children) != 1 || goFiles.Children[0].Name != "pkg" {
+		t.Fatalf("Filter() kept %v, want only pkg", goFiles.Children)
+	}
+	if len(goFiles.Children[0].Children) != 2 {
+		t.Errorf("Filter() kept %d files in pkg, want 2", len(goFiles.Children[0].Children))
+	}
+	if len(tree.Children) != 3 || len(tree.Children[1].Children) != 2 {
+		t.Error("Filter() modified the original tree")
+	}
+
+	finishTree(tree)
+	sources := tree.Filter(func(n *FileTreeNode) bool {
+		return n.Name == "a.go" || n.Name == "README.md"
+	})
+	if sources.Size != 110 || sources.FileCount != 2 || sources.GoFileCount != 1 {
+		t.Errorf("Filter() root totals = %d bytes, %d files, %d Go files, want 110, 2, 1", sources.Size, sources.FileCount, sources.GoFileCount)
+	}
+	if pkg := sources.Children[1]; pkg.Name != "pkg" || pkg.Size != 100 || pkg.FileCount != 1 || pkg.Size != pkg.TotalSize() {
+		t.Errorf("Filter() pkg = %+v, want 100 bytes in 1 file", pkg)
+	}
+	if tree.Size != 165 || tree.FileCount != 4 {
+		t.Errorf("Filter() changed the original totals to %d bytes, %d files", tree.Size, tree.FileCount)
+	}
+}
+
+func TestFileTreeMarshalIndent(t *testing.T) {
+	tree := newTestTree()
+	if got := tree.TotalSize(); got != 165 {
+		t.Errorf("TotalSize() = %d, want 165", got)
+	}
+
+	data, err := tree.MarshalIndent("", "  ")
+	if err != nil {
+		t.Fatalf("MarshalIndent() error = %v", err)
+	}
+	if !strings.Contains(string(data), "\n  \"name\": \"root\"") {
+		t.Errorf("MarshalIndent() output is not indented:\n%s", data)
+	}
+
+	var decoded struct {
+		Name     string `json:"name"`
+		Size     int64  `json:"size"`
+		Children []struct {
+			Name string `json:"name"`
+			Size int64  `json:"size"`
+		} `json:"children"`
+	}
+	if strings.Contains(string(data), "total_size") {
+		t.Errorf("MarshalIndent() output has a total_size field:\n%s", data)
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to decode output: %v", err)
+	}
+	if decoded.Size != 165 || len(decoded.Children) != 3 || decoded.Children[1].Size != 150 {
+		t.Errorf("Unexpected totals: %+v", decoded)
+	}
+}
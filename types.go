@@ -1,6 +1,8 @@
 package readgo
 
 import (
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -16,85 +18,360 @@ const (
 	FileTypeTest FileType = "test"
 	// FileTypeGenerated includes only generated files
 	FileTypeGenerated FileType = "generated"
+	// FileTypeGoPackage includes all files the go tool considers part of a
+	// package: Go sources plus assembly, C/C++ and other companion files
+	FileTypeGoPackage FileType = "go_package"
 )
 
 // TreeOptions represents options for file tree operations
 type TreeOptions struct {
-	FileTypes       FileType `json:"file_types"`
-	ExcludePatterns []string `json:"exclude_patterns,omitempty"`
-	IncludePatterns []string `json:"include_patterns,omitempty"`
+	FileTypes         FileType `json:"file_types" yaml:"file_types"`
+	ExcludePatterns   []string `json:"exclude_patterns,omitempty" yaml:"exclude_patterns,omitempty"`
+	IncludePatterns   []string `json:"include_patterns,omitempty" yaml:"include_patterns,omitempty"`
+	ComputeHashes     bool     `json:"compute_hashes,omitempty" yaml:"compute_hashes,omitempty"`           // fill FileTreeNode.Hash for files
+	SkipBinary        bool     `json:"skip_binary,omitempty" yaml:"skip_binary,omitempty"`                 // leave binary files out of the tree
+	DetectBinary      bool     `json:"detect_binary,omitempty" yaml:"detect_binary,omitempty"`             // set FileTreeNode.IsBinary for files
+	NoDefaultExcludes bool     `json:"no_default_excludes,omitempty" yaml:"no_default_excludes,omitempty"` // keep vendor/, node_modules/ and hidden dirs
+	PackageNames      bool     `json:"package_names,omitempty" yaml:"package_names,omitempty"`             // set FileTreeNode.Package for .go files
+	LocalImports      bool     `json:"local_imports,omitempty" yaml:"local_imports,omitempty"`             // GetPackageFiles: add files of imported packages in the same module
+	Limit             int      `json:"limit,omitempty" yaml:"limit,omitempty"`                             // max nodes in a tree or results in a search, 0 for no limit
+	Offset            int      `json:"offset,omitempty" yaml:"offset,omitempty"`                           // number of search results to skip
+}
+
+// unpaged returns a copy of the options without Limit and Offset
+func (o TreeOptions) unpaged() TreeOptions {
+	o.Limit = 0
+	o.Offset = 0
+	return o
 }
 
 // ReadOptions represents options for reading source files
 type ReadOptions struct {
-	IncludeComments bool `json:"include_comments"`
-	StripSpaces     bool `json:"strip_spaces"`
+	IncludeComments bool `json:"include_comments" yaml:"include_comments"`
+	StripSpaces     bool `json:"strip_spaces" yaml:"strip_spaces"`
+	WithLineNumbers bool `json:"with_line_numbers,omitempty" yaml:"with_line_numbers,omitempty"` // prefix each line with its original line number
+	Format          bool `json:"format,omitempty" yaml:"format,omitempty"`                       // gofmt .go files before returning them
+	Transcode       bool `json:"transcode,omitempty" yaml:"transcode,omitempty"`                 // convert UTF-16 and Latin-1 content to UTF-8
+}
+
+// SourceLine represents a single line of a source file
+type SourceLine struct {
+	Number int    `json:"number" yaml:"number"` // 1-based line number in the original file
+	Text   string `json:"text" yaml:"text"`     // Line content without the trailing newline
 }
 
 // FileTreeNode represents a node in the file tree
 type FileTreeNode struct {
-	Name     string          `json:"name"`
-	Path     string          `json:"path"`
-	Type     string          `json:"type"` // "file" or "directory"
-	Size     int64           `json:"size,omitempty"`
-	ModTime  time.Time       `json:"mod_time,omitempty"`
-	Children []*FileTreeNode `json:"children,omitempty"`
+	Name        string          `json:"name" yaml:"name"`
+	Path        string          `json:"path" yaml:"path"`
+	Type        string          `json:"type" yaml:"type"`                     // "file" or "directory"
+	Size        int64           `json:"size,omitempty" yaml:"size,omitempty"` // cumulative for directories
+	ModTime     time.Time       `json:"mod_time,omitempty" yaml:"mod_time,omitempty"`
+	Hash        string          `json:"hash,omitempty" yaml:"hash,omitempty"` // hex SHA256, files only
+	IsBinary    bool            `json:"is_binary,omitempty" yaml:"is_binary,omitempty"`
+	Package     string          `json:"package,omitempty" yaml:"package,omitempty"`             // Go package name, .go files only
+	FileCount   int             `json:"file_count,omitempty" yaml:"file_count,omitempty"`       // files in the subtree, directories only
+	GoFileCount int             `json:"go_file_count,omitempty" yaml:"go_file_count,omitempty"` // .go files in the subtree, directories only
+	Truncated   bool            `json:"truncated,omitempty" yaml:"truncated,omitempty"`         // set on the root when cut off by TreeOptions.Limit
+	Children    []*FileTreeNode `json:"children,omitempty" yaml:"children,omitempty"`
+}
+
+// ContentMatch represents a line matched by a content search
+type ContentMatch struct {
+	File       string   `json:"file" yaml:"file"`
+	Line       int      `json:"line" yaml:"line"`
+	Text       string   `json:"text" yaml:"text"`
+	Submatches []string `json:"submatches,omitempty" yaml:"submatches,omitempty"`
+}
+
+// FilePage represents one page of file search results
+type FilePage struct {
+	Files      []*FileTreeNode `json:"files" yaml:"files"`
+	Total      int             `json:"total" yaml:"total"`                                 // total number of matches
+	Truncated  bool            `json:"truncated" yaml:"truncated"`                         // more matches follow this page
+	NextOffset int             `json:"next_offset,omitempty" yaml:"next_offset,omitempty"` // offset of the next page if truncated
 }
 
 // TypeInfo represents information about a Go type
 type TypeInfo struct {
-	Name       string `json:"name"`
-	Package    string `json:"package"`
-	Type       string `json:"type"`
-	IsExported bool   `json:"is_exported"`
+	Name       string   `json:"name" yaml:"name"`
+	Package    string   `json:"package" yaml:"package"`
+	Type       string   `json:"type" yaml:"type"`
+	Kind       TypeKind `json:"kind,omitempty" yaml:"kind,omitempty"` // Empty if the underlying type is not known
+	IsExported bool     `json:"is_exported" yaml:"is_exported"`
 }
 
+// TypeKind classifies a type by its underlying type
+type TypeKind string
+
+const (
+	// TypeKindStruct is a struct type
+	TypeKindStruct TypeKind = "struct"
+	// TypeKindInterface is an interface type
+	TypeKindInterface TypeKind = "interface"
+	// TypeKindAlias is an alias declared with "type A = B"
+	TypeKindAlias TypeKind = "alias"
+	// TypeKindFunc is a function type
+	TypeKindFunc TypeKind = "func"
+	// TypeKindMap is a map type
+	TypeKindMap TypeKind = "map"
+	// TypeKindSlice is a slice type
+	TypeKindSlice TypeKind = "slice"
+	// TypeKindArray is an array type
+	TypeKindArray TypeKind = "array"
+	// TypeKindPointer is a pointer type
+	TypeKindPointer TypeKind = "pointer"
+	// TypeKindChan is a channel type
+	TypeKindChan TypeKind = "chan"
+	// TypeKindBasic is a boolean, numeric or string type
+	TypeKindBasic TypeKind = "basic"
+)
+
 // FunctionInfo represents information about a Go function
 type FunctionInfo struct {
-	Name       string `json:"name"`
-	Package    string `json:"package"`
-	IsExported bool   `json:"is_exported"`
+	Name       string `json:"name" yaml:"name"`
+	Package    string `json:"package" yaml:"package"`
+	IsExported bool   `json:"is_exported" yaml:"is_exported"`
+	Signature  string `json:"signature,omitempty" yaml:"signature,omitempty"` // Declaration without body, e.g. "func (u *User) Name() string"
+	Receiver   string `json:"receiver,omitempty" yaml:"receiver,omitempty"`   // Receiver type for methods, e.g. "*User"
+	IsMethod   bool   `json:"is_method,omitempty" yaml:"is_method,omitempty"`
+	File       string `json:"file,omitempty" yaml:"file,omitempty"` // Relative to the analyzer's working directory when inside it
+	StartLine  int    `json:"start_line,omitempty" yaml:"start_line,omitempty"`
+	EndLine    int    `json:"end_line,omitempty" yaml:"end_line,omitempty"`
+	Doc        string `json:"doc,omitempty" yaml:"doc,omitempty"` // Text of the doc comment
 }
 
 // AnalysisResult represents the result of code analysis
 type AnalysisResult struct {
-	Name       string         `json:"name"`
-	Path       string         `json:"path"`
-	StartTime  string         `json:"start_time"`
-	AnalyzedAt time.Time      `json:"analyzed_at"`
-	Types      []TypeInfo     `json:"types,omitempty"`
-	Functions  []FunctionInfo `json:"functions,omitempty"`
-	Imports    []string       `json:"imports,omitempty"`
+	Name       string         `json:"name" yaml:"name"`
+	Path       string         `json:"path" yaml:"path"`
+	StartTime  string         `json:"start_time" yaml:"start_time"`
+	AnalyzedAt time.Time      `json:"analyzed_at" yaml:"analyzed_at"`
+	Types      []TypeInfo     `json:"types,omitempty" yaml:"types,omitempty"`
+	Functions  []FunctionInfo `json:"functions,omitempty" yaml:"functions,omitempty"`
+	Imports    []string       `json:"imports,omitempty" yaml:"imports,omitempty"`
+	// Dependencies lists the imported packages once each, classified as
+	// standard library, internal to the module or external
+	Dependencies []Dependency `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
+	// Cancelled is set when the context was cancelled before every package
+	// was analyzed, leaving the result partial
+	Cancelled bool `json:"cancelled,omitempty" yaml:"cancelled,omitempty"`
+	// Extensions holds entities found by custom extractors, keyed by extractor name
+	Extensions map[string][]Entity `json:"extensions,omitempty" yaml:"extensions,omitempty"`
+	// Packages breaks a project analysis down by package, sorted by import
+	// path; Types, Functions, Imports and Dependencies above hold the union
+	Packages []PackageAnalysis `json:"packages,omitempty" yaml:"packages,omitempty"`
+	// Module describes the module the analyzed code belongs to, with the
+	// versions of its requirements; nil outside modules
+	Module *ModuleInfo `json:"module,omitempty" yaml:"module,omitempty"`
+
+	// SchemaVersion and ToolVersion identify the output format and the
+	// readgo release that produced the result
+	SchemaVersion string `json:"schema_version,omitempty" yaml:"schema_version,omitempty"`
+	ToolVersion   string `json:"tool_version,omitempty" yaml:"tool_version,omitempty"`
+}
+
+// PackageAnalysis is the part of a project analysis found in one package
+type PackageAnalysis struct {
+	Name         string         `json:"name" yaml:"name"`
+	Path         string         `json:"path" yaml:"path"` // Import path
+	Types        []TypeInfo     `json:"types,omitempty" yaml:"types,omitempty"`
+	Functions    []FunctionInfo `json:"functions,omitempty" yaml:"functions,omitempty"`
+	Imports      []string       `json:"imports,omitempty" yaml:"imports,omitempty"`
+	Dependencies []Dependency   `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
+}
+
+// Entity represents a domain-specific item found by an Extractor
+type Entity struct {
+	Name    string      `json:"name" yaml:"name"`
+	Package string      `json:"package" yaml:"package"`
+	File    string      `json:"file,omitempty" yaml:"file,omitempty"`
+	Line    int         `json:"line,omitempty" yaml:"line,omitempty"`
+	Value   interface{} `json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+// ValidationLevel controls how thorough validation is
+type ValidationLevel int
+
+const (
+	// ValidationLevelBasic reports syntax errors only
+	ValidationLevelBasic ValidationLevel = iota
+	// ValidationLevelStandard reports syntax errors and warnings
+	ValidationLevelStandard
+	// ValidationLevelStrict reports warnings as errors
+	ValidationLevelStrict
+)
+
+// String returns the name of the validation level
+func (l ValidationLevel) String() string {
+	switch l {
+	case ValidationLevelBasic:
+		return "basic"
+	case ValidationLevelStandard:
+		return "standard"
+	case ValidationLevelStrict:
+		return "strict"
+	default:
+		return fmt.Sprintf("ValidationLevel(%d)", int(l))
+	}
+}
+
+// MarshalText encodes the level by name
+func (l ValidationLevel) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText decodes a level name
+func (l *ValidationLevel) UnmarshalText(text []byte) error {
+	level, err := ParseValidationLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
+// ParseValidationLevel parses a validation level name
+func ParseValidationLevel(name string) (ValidationLevel, error) {
+	switch strings.ToLower(name) {
+	case "basic":
+		return ValidationLevelBasic, nil
+	case "standard":
+		return ValidationLevelStandard, nil
+	case "strict":
+		return ValidationLevelStrict, nil
+	default:
+		return 0, fmt.Errorf("%w: unknown validation level %q", ErrInvalidInput, name)
+	}
+}
+
+// valid reports whether l is one of the defined levels
+func (l ValidationLevel) valid() bool {
+	return l >= ValidationLevelBasic && l <= ValidationLevelStrict
+}
+
+// Severity classifies how serious a finding is
+type Severity string
+
+const (
+	// SeverityInfo marks findings that are reported but never fail validation
+	SeverityInfo Severity = "info"
+	// SeverityWarning marks findings reported as warnings
+	SeverityWarning Severity = "warning"
+	// SeverityError marks findings reported as errors
+	SeverityError Severity = "error"
+)
+
+// valid reports whether s is one of the defined severities
+func (s Severity) valid() bool {
+	return s == SeverityInfo || s == SeverityWarning || s == SeverityError
+}
+
+// TextEdit replaces the text between two positions of a file
+type TextEdit struct {
+	File        string `json:"file" yaml:"file"`                 // File to edit
+	Start       int    `json:"start" yaml:"start"`               // Byte offset of the first replaced byte
+	End         int    `json:"end" yaml:"end"`                   // Byte offset just past the last replaced byte
+	StartLine   int    `json:"start_line" yaml:"start_line"`     // Line of Start
+	StartColumn int    `json:"start_column" yaml:"start_column"` // Column of Start
+	EndLine     int    `json:"end_line" yaml:"end_line"`         // Line of End
+	EndColumn   int    `json:"end_column" yaml:"end_column"`     // Column of End
+	NewText     string `json:"new_text" yaml:"new_text"`         // Replacement text, empty to delete
+}
+
+// SuggestedFix is a set of edits that resolves a finding
+type SuggestedFix struct {
+	Message string     `json:"message" yaml:"message"`
+	Edits   []TextEdit `json:"edits" yaml:"edits"`
 }
 
 // ValidationWarning represents a warning during validation
 type ValidationWarning struct {
-	Type    string `json:"type"`
-	Message string `json:"message"`
-	File    string `json:"file,omitempty"`
-	Line    int    `json:"line,omitempty"`
-	Column  int    `json:"column,omitempty"`
+	Type           string         `json:"type" yaml:"type"`
+	Severity       Severity       `json:"severity,omitempty" yaml:"severity,omitempty"`
+	Message        string         `json:"message" yaml:"message"`
+	File           string         `json:"file,omitempty" yaml:"file,omitempty"`
+	Line           int            `json:"line,omitempty" yaml:"line,omitempty"`
+	Column         int            `json:"column,omitempty" yaml:"column,omitempty"`
+	SuggestedFixes []SuggestedFix `json:"suggested_fixes,omitempty" yaml:"suggested_fixes,omitempty"`
+}
+
+// Finding represents an issue reported by a validation Rule
+type Finding struct {
+	Rule     string   `json:"rule" yaml:"rule"`                             // Name of the reporting rule, defaults to Rule.Name()
+	Severity Severity `json:"severity,omitempty" yaml:"severity,omitempty"` // Severity of the issue, defaults to SeverityWarning
+	Message  string   `json:"message" yaml:"message"`                       // Description of the issue
+	File     string   `json:"file,omitempty" yaml:"file,omitempty"`         // File containing the issue
+	Line     int      `json:"line,omitempty" yaml:"line,omitempty"`         // Line number of the issue
+	Column   int      `json:"column,omitempty" yaml:"column,omitempty"`     // Column number of the issue
+
+	SuggestedFixes []SuggestedFix `json:"suggested_fixes,omitempty" yaml:"suggested_fixes,omitempty"` // Edits that resolve the issue
+}
+
+// ValidationStats summarizes a validation run
+type ValidationStats struct {
+	Suppressed       int            `json:"suppressed" yaml:"suppressed"`                                     // Findings suppressed by //nolint comments
+	SuppressedByRule map[string]int `json:"suppressed_by_rule,omitempty" yaml:"suppressed_by_rule,omitempty"` // Suppressed findings per check
+
+	// Coverage maps import paths to their statement coverage in percent, as
+	// recorded by CheckCoverage
+	Coverage map[string]float64 `json:"coverage,omitempty" yaml:"coverage,omitempty"`
 }
 
 // ValidationResult represents the result of code validation
 type ValidationResult struct {
-	Name       string              `json:"name"`
-	Path       string              `json:"path"`
-	StartTime  string              `json:"start_time"`
-	AnalyzedAt time.Time           `json:"analyzed_at"`
-	Errors     []string            `json:"errors,omitempty"`
-	Warnings   []ValidationWarning `json:"warnings,omitempty"`
+	Name       string              `json:"name" yaml:"name"`
+	Path       string              `json:"path" yaml:"path"`
+	StartTime  string              `json:"start_time" yaml:"start_time"`
+	AnalyzedAt time.Time           `json:"analyzed_at" yaml:"analyzed_at"`
+	Level      ValidationLevel     `json:"level" yaml:"level"`
+	Valid      bool                `json:"valid" yaml:"valid"`
+	Errors     []string            `json:"errors,omitempty" yaml:"errors,omitempty"`
+	Warnings   []ValidationWarning `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+	Stats      ValidationStats     `json:"stats" yaml:"stats"`
+
+	// Cancelled is set when the context was cancelled before every package
+	// was checked; the findings are then partial and Valid is false
+	Cancelled bool `json:"cancelled,omitempty" yaml:"cancelled,omitempty"`
+
+	// CircularDeps lists the import cycles found by CheckCircularDependencies
+	CircularDeps []Cycle `json:"circular_deps,omitempty" yaml:"circular_deps,omitempty"`
+
+	// Todos lists the TODO, FIXME and HACK comments of the validated files
+	Todos []TodoComment `json:"todos,omitempty" yaml:"todos,omitempty"`
+
+	// SchemaVersion and ToolVersion identify the output format and the
+	// readgo release that produced the result
+	SchemaVersion string `json:"schema_version,omitempty" yaml:"schema_version,omitempty"`
+	ToolVersion   string `json:"tool_version,omitempty" yaml:"tool_version,omitempty"`
+
+	// packages lists the import paths of the checked packages, so that
+	// Cache.InvalidatePackage finds the results covering a package
+	packages []string
 }
 
 // FunctionPosition represents the position of a function in the source code
 type FunctionPosition struct {
-	Name      string `json:"name"`       // Function name
-	StartLine int    `json:"start_line"` // Starting line number
-	EndLine   int    `json:"end_line"`   // Ending line number
+	Name         string `json:"name" yaml:"name"`                             // Function name
+	Receiver     string `json:"receiver,omitempty" yaml:"receiver,omitempty"` // Receiver type for methods, e.g. "*User"
+	Signature    string `json:"signature" yaml:"signature"`                   // Declaration without body
+	IsExported   bool   `json:"is_exported" yaml:"is_exported"`               // Whether the function is exported
+	StartLine    int    `json:"start_line" yaml:"start_line"`                 // Starting line number
+	EndLine      int    `json:"end_line" yaml:"end_line"`                     // Ending line number
+	DocStartLine int    `json:"doc_start_line" yaml:"doc_start_line"`         // First line of the doc comment, or StartLine if none
+}
+
+// DeclPosition represents the position of a type or constant declaration
+type DeclPosition struct {
+	Name      string `json:"name" yaml:"name"`             // Declared name
+	StartLine int    `json:"start_line" yaml:"start_line"` // Starting line number
+	EndLine   int    `json:"end_line" yaml:"end_line"`     // Ending line number
 }
 
-// FileContent represents the content of a file with function positions
+// FileContent represents the content of a file with declaration positions
 type FileContent struct {
-	Content   []byte             `json:"content"`   // File content
-	Functions []FunctionPosition `json:"functions"` // Function positions
+	Content   []byte             `json:"content" yaml:"content"`     // File content
+	Functions []FunctionPosition `json:"functions" yaml:"functions"` // Function positions
+	Types     []DeclPosition     `json:"types" yaml:"types"`         // Type positions
+	Consts    []DeclPosition     `json:"consts" yaml:"consts"`       // Constant positions
 }
@@ -0,0 +1,72 @@
+package readgo
+
+import (
+	"context"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// unkeyedFieldsRule reports struct literals of types from other packages
+// that list their fields by position, which breaks as soon as the upstream
+// struct gains or reorders a field. Each finding comes with a fix keying
+// the fields.
+type unkeyedFieldsRule struct{}
+
+// Name returns the rule name
+func (unkeyedFieldsRule) Name() string {
+	return "unkeyed_fields"
+}
+
+// Check reports the unkeyed struct literals in the package
+func (unkeyedFieldsRule) Check(ctx context.Context, pkg *packages.Package) []Finding {
+	if pkg.TypesInfo == nil || pkg.Types == nil {
+		return nil
+	}
+
+	var findings []Finding
+	for _, file := range pkg.Syntax {
+		if ctx.Err() != nil {
+			break
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			lit, ok := n.(*ast.CompositeLit)
+			if !ok || len(lit.Elts) == 0 {
+				return true
+			}
+			if _, keyed := lit.Elts[0].(*ast.KeyValueExpr); keyed {
+				return true
+			}
+
+			t := pkg.TypesInfo.TypeOf(lit)
+			if p, ok := t.(*types.Pointer); ok {
+				t = p.Elem() // &T elided in a slice or map literal
+			}
+			named, ok := types.Unalias(t).(*types.Named)
+			if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() == pkg.Types.Path() {
+				return true
+			}
+			st, ok := named.Underlying().(*types.Struct)
+			if !ok || st.NumFields() != len(lit.Elts) {
+				return true
+			}
+
+			edits := make([]TextEdit, len(lit.Elts))
+			for i, elt := range lit.Elts {
+				edits[i] = newTextEdit(pkg.Fset, elt.Pos(), elt.Pos(), st.Field(i).Name()+": ")
+			}
+			name := types.TypeString(named, func(p *types.Package) string { return p.Name() })
+			pos := pkg.Fset.Position(lit.Pos())
+			findings = append(findings, Finding{
+				Message:        name + " literal uses unkeyed fields",
+				File:           pos.Filename,
+				Line:           pos.Line,
+				Column:         pos.Column,
+				SuggestedFixes: []SuggestedFix{{Message: "Key the fields", Edits: edits}},
+			})
+			return true
+		})
+	}
+	return findings
+}
@@ -0,0 +1,104 @@
+package readgo
+
+import (
+	"context"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// unreachableRule reports statements that follow a return, a panic or a
+// call ending the program or goroutine in the same block. Only the first
+// unreachable statement of a block is reported, and a labeled statement is
+// taken to be reachable through goto.
+type unreachableRule struct{}
+
+// Name returns the rule name
+func (unreachableRule) Name() string {
+	return "unreachable_code"
+}
+
+// Check reports the unreachable statements in the package
+func (unreachableRule) Check(ctx context.Context, pkg *packages.Package) []Finding {
+	if pkg.TypesInfo == nil {
+		return nil
+	}
+
+	var findings []Finding
+	check := func(stmts []ast.Stmt) {
+		for i, stmt := range stmts {
+			what := terminator(pkg.TypesInfo, stmt)
+			if what == "" {
+				continue
+			}
+			for _, next := range stmts[i+1:] {
+				if _, ok := next.(*ast.EmptyStmt); ok {
+					continue
+				}
+				if _, ok := next.(*ast.LabeledStmt); ok {
+					return
+				}
+				pos := pkg.Fset.Position(next.Pos())
+				findings = append(findings, Finding{
+					Message: "unreachable code after " + what,
+					File:    pos.Filename,
+					Line:    pos.Line,
+					Column:  pos.Column,
+				})
+				return
+			}
+			return
+		}
+	}
+
+	for _, file := range pkg.Syntax {
+		if ctx.Err() != nil {
+			break
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.BlockStmt:
+				check(n.List)
+			case *ast.CaseClause:
+				check(n.Body)
+			case *ast.CommClause:
+				check(n.Body)
+			}
+			return true
+		})
+	}
+	return findings
+}
+
+// terminator describes stmt if control never continues past it, or
+// returns ""
+func terminator(info *types.Info, stmt ast.Stmt) string {
+	switch stmt := stmt.(type) {
+	case *ast.ReturnStmt:
+		return "return"
+	case *ast.ExprStmt:
+		call, ok := ast.Unparen(stmt.X).(*ast.CallExpr)
+		if !ok {
+			return ""
+		}
+		if id, ok := ast.Unparen(call.Fun).(*ast.Ident); ok {
+			if b, ok := info.Uses[id].(*types.Builtin); ok && b.Name() == "panic" {
+				return "panic"
+			}
+			return ""
+		}
+		for _, fn := range []struct{ path, name string }{
+			{"os", "Exit"},
+			{"log", "Fatal"},
+			{"log", "Fatalf"},
+			{"log", "Fatalln"},
+			{"runtime", "Goexit"},
+		} {
+			if isPkgFunc(info, call.Fun, fn.path, fn.name) {
+				return fn.path + "." + fn.name
+			}
+		}
+	}
+	return ""
+}
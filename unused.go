@@ -0,0 +1,156 @@
+package readgo
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// unusedRule reports unused function parameters, unexported functions that
+// are never referenced, and variables that are never used. Its findings use
+// the check names unused_param, unused_func and unused_var; exported package
+// variables are not reported.
+//
+// Methods are skipped because their signatures are often fixed by the
+// interfaces they implement, as are functions used as values and the test,
+// benchmark, fuzz and example functions of _test.go files for the same
+// reason. Unexported functions cannot be referenced outside their package,
+// so checking the package, including its tests, covers the whole module.
+type unusedRule struct{}
+
+// Name returns the rule name
+func (unusedRule) Name() string {
+	return "unused"
+}
+
+// Check reports the unused parameters, functions and variables in the package
+func (unusedRule) Check(ctx context.Context, pkg *packages.Package) []Finding {
+	if pkg.TypesInfo == nil {
+		return nil
+	}
+
+	// Count references to every object and note which functions are
+	// referenced other than by being called
+	uses := make(map[types.Object]int)
+	called := make(map[*ast.Ident]bool)
+	for _, file := range pkg.Syntax {
+		if ctx.Err() != nil {
+			break
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			if call, ok := n.(*ast.CallExpr); ok {
+				switch fun := ast.Unparen(call.Fun).(type) {
+				case *ast.Ident:
+					called[fun] = true
+				case *ast.IndexExpr:
+					if id, ok := fun.X.(*ast.Ident); ok {
+						called[id] = true
+					}
+				}
+			}
+			return true
+		})
+	}
+	asValue := make(map[types.Object]bool)
+	for id, obj := range pkg.TypesInfo.Uses {
+		uses[obj]++
+		if _, ok := obj.(*types.Func); ok && !called[id] {
+			asValue[obj] = true
+		}
+	}
+
+	var findings []Finding
+	report := func(check string, id *ast.Ident, format string, args ...interface{}) {
+		pos := pkg.Fset.Position(id.Pos())
+		findings = append(findings, Finding{
+			Rule:    check,
+			Message: fmt.Sprintf(format, args...),
+			File:    pos.Filename,
+			Line:    pos.Line,
+			Column:  pos.Column,
+		})
+	}
+
+	for _, file := range pkg.Syntax {
+		if ctx.Err() != nil {
+			break
+		}
+		isTest := strings.HasSuffix(pkg.Fset.File(file.Pos()).Name(), "_test.go")
+		for _, id := range declaredVars(file) {
+			v, ok := pkg.TypesInfo.Defs[id].(*types.Var)
+			if !ok || id.Name == "_" || uses[v] > 0 || (v.Parent() == pkg.Types.Scope() && id.IsExported()) {
+				continue
+			}
+			report("unused_var", id, "variable %s is never used", id.Name)
+		}
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || fn.Body == nil {
+				continue
+			}
+			obj := pkg.TypesInfo.Defs[fn.Name]
+			if obj == nil {
+				continue
+			}
+
+			if !fn.Name.IsExported() && fn.Name.Name != "init" && fn.Name.Name != "main" && fn.Name.Name != "_" && uses[obj] == 0 {
+				report("unused_func", fn.Name, "function %s is never used", fn.Name.Name)
+			}
+
+			if asValue[obj] || len(fn.Body.List) == 0 || (isTest && isTestFuncName(fn.Name.Name)) {
+				continue
+			}
+			for _, field := range fn.Type.Params.List {
+				for _, name := range field.Names {
+					if name.Name == "_" {
+						continue
+					}
+					if param := pkg.TypesInfo.Defs[name]; param != nil && uses[param] == 0 {
+						report("unused_param", name, "parameter %s of %s is never used", name.Name, fn.Name.Name)
+					}
+				}
+			}
+		}
+	}
+	return findings
+}
+
+// declaredVars returns the identifiers declared as variables in file by var
+// declarations, short variable declarations and range clauses. Identifiers
+// that are only reassigned by := are included, but have no definition.
+func declaredVars(file *ast.File) []*ast.Ident {
+	var ids []*ast.Ident
+	add := func(exprs ...ast.Expr) {
+		for _, e := range exprs {
+			if id, ok := e.(*ast.Ident); ok {
+				ids = append(ids, id)
+			}
+		}
+	}
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.GenDecl:
+			if n.Tok != token.VAR {
+				return false
+			}
+			for _, spec := range n.Specs {
+				ids = append(ids, spec.(*ast.ValueSpec).Names...)
+			}
+		case *ast.AssignStmt:
+			if n.Tok == token.DEFINE {
+				add(n.Lhs...)
+			}
+		case *ast.RangeStmt:
+			if n.Tok == token.DEFINE {
+				add(n.Key, n.Value)
+			}
+		}
+		return true
+	})
+	return ids
+}
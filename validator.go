@@ -2,191 +2,794 @@ package readgo
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"io"
+	"io/fs"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/tools/go/packages"
 )
 
+// validatorLoadMode is the information loaded for every validated package
+const validatorLoadMode = packages.NeedName |
+	packages.NeedFiles |
+	packages.NeedCompiledGoFiles |
+	packages.NeedImports |
+	packages.NeedTypes |
+	packages.NeedTypesSizes |
+	packages.NeedSyntax |
+	packages.NeedTypesInfo |
+	packages.NeedDeps |
+	packages.NeedModule
+
 // DefaultValidator implements the code validator
 type DefaultValidator struct {
-	baseDir string
+	baseDir       string
+	rules         []Rule
+	config        *ValidatorConfig
+	cache         *Cache
+	maxConcurrent int // packages checked at the same time; 0 means runtime.NumCPU()
+	progress      ProgressFunc
+	logger        *slog.Logger
+	tracer        trace.Tracer
+	observer      Observer
+}
+
+// NewValidator creates a new validator. Of the options only those controlling
+// caching, concurrency, progress, logging, tracing and observing apply:
+// packages are checked by at most MaxConcurrentAnalysis workers, or one at a
+// time when concurrent analysis is disabled, up to MaxCacheSize results are
+// cached unless CacheTTL is zero, Progress is called after every checked
+// package, package loads are logged to Logger, spans are recorded with
+// TracerProvider, and loads and validations are reported to Observer.
+func NewValidator(baseDir string, opts ...Option) *DefaultValidator {
+	options := DefaultOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	v := &DefaultValidator{
+		baseDir:       baseDir,
+		rules:         builtinRules(),
+		cache:         NewCache(options.CacheTTL).WithMaxEntries(options.MaxCacheSize).WithMaxBytes(options.MaxCacheBytes),
+		maxConcurrent: options.MaxConcurrentAnalysis,
+		progress:      options.Progress,
+		logger:        loggerOrDiscard(options.Logger),
+		tracer:        newTracer(options.TracerProvider),
+		observer:      options.Observer,
+	}
+	if !options.EnableConcurrentAnalysis {
+		v.maxConcurrent = 1
+	}
+	v.configureRules()
+	return v
 }
 
-// NewValidator creates a new validator
-func NewValidator(baseDir string) *DefaultValidator {
-	return &DefaultValidator{baseDir: baseDir}
+// RegisterRule adds a custom rule to the validator.
+// It is not safe to call concurrently with validation.
+func (v *DefaultValidator) RegisterRule(r Rule) {
+	v.rules = append(v.rules, r)
+	v.cache.clearValidations()
 }
 
-// ValidateFile validates a Go source file
-func (v *DefaultValidator) ValidateFile(ctx context.Context, filePath string) (*ValidationResult, error) {
+// WithConfig sets the configuration used to select checks
+func (v *DefaultValidator) WithConfig(cfg *ValidatorConfig) *DefaultValidator {
+	v.config = cfg
+	v.configureRules()
+	v.cache.clearValidations()
+	return v
+}
+
+// GetCacheStats returns cache statistics
+func (v *DefaultValidator) GetCacheStats() CacheStats {
+	return v.cache.Stats()
+}
+
+// ExportCache writes the cache to w, see Cache.Export
+func (v *DefaultValidator) ExportCache(w io.Writer) error {
+	return v.cache.Export(w)
+}
+
+// ImportCache restores a cache written by ExportCache, see Cache.Import
+func (v *DefaultValidator) ImportCache(r io.Reader) error {
+	return v.cache.Import(r)
+}
+
+// configureRules passes the current configuration to the built-in rules
+func (v *DefaultValidator) configureRules() {
+	for _, r := range v.rules {
+		if c, ok := r.(configurableRule); ok {
+			c.configure(v.config)
+		}
+	}
+}
+
+// ValidateFile validates a Go source file at the given level. The file is
+// checked as part of its package, so references to sibling files and
+// dependencies resolve.
+func (v *DefaultValidator) ValidateFile(ctx context.Context, filePath string, level ValidationLevel) (*ValidationResult, error) {
+	return v.validateFile(ctx, filePath, nil, level)
+}
+
+// ValidateFileContent validates content as the Go source file at filePath,
+// for example an unsaved editor buffer. The file need not exist on disk, but
+// its directory must; the rest of its package is read from disk.
+func (v *DefaultValidator) ValidateFileContent(ctx context.Context, filePath string, content []byte, level ValidationLevel) (*ValidationResult, error) {
+	if content == nil {
+		content = []byte{}
+	}
+	return v.validateFile(ctx, filePath, content, level)
+}
+
+// validateFile validates the file at filePath, using content in place of
+// the file on disk when it is non-nil
+func (v *DefaultValidator) validateFile(ctx context.Context, filePath string, content []byte, level ValidationLevel) (*ValidationResult, error) {
 	if filePath == "" {
 		return nil, fmt.Errorf("empty file path")
 	}
+	if !level.valid() {
+		return nil, fmt.Errorf("%w: unknown validation level %d", ErrInvalidInput, int(level))
+	}
 
-	absPath := filepath.Join(v.baseDir, filePath)
-	if _, err := os.Stat(absPath); err != nil {
+	absPath, err := filepath.Abs(filepath.Join(v.baseDir, filePath))
+	if err != nil {
+		return nil, fmt.Errorf("file access error: %w", err)
+	}
+	statPath := absPath
+	if content != nil {
+		statPath = filepath.Dir(absPath)
+	}
+	if _, err := os.Stat(statPath); err != nil {
 		return nil, fmt.Errorf("file access error: %w", err)
 	}
 
-	result := &ValidationResult{
-		Name:       filepath.Base(filePath),
-		Path:       filePath,
-		StartTime:  time.Now().Format(time.RFC3339),
-		AnalyzedAt: time.Now(),
+	var overlay map[string][]byte
+	var src interface{}
+	if content != nil {
+		overlay = map[string][]byte{absPath: content}
+		src = content
 	}
 
-	// Parse the file
-	fset := token.NewFileSet()
-	file, err := parser.ParseFile(fset, absPath, nil, parser.ParseComments)
-	if err != nil {
-		result.Errors = append(result.Errors, fmt.Sprintf("parse error: %v", err))
-		return result, nil
+	key := v.validationKey("file:"+filepath.ToSlash(filePath), level, filepath.Dir(absPath), false, overlay)
+	if cached, ok := v.lookupValidation(ctx, key); ok {
+		return cached, nil
 	}
 
-	// Check for basic syntax issues
-	ast.Inspect(file, func(n ast.Node) bool {
-		if n == nil {
-			return true
-		}
+	result := &ValidationResult{
+		Name:          filepath.Base(filePath),
+		Path:          filePath,
+		StartTime:     time.Now().Format(time.RFC3339),
+		AnalyzedAt:    time.Now(),
+		SchemaVersion: SchemaVersion,
+		ToolVersion:   Version(),
+		Level:         level,
+	}
 
-		// Check for unused imports
-		if imp, ok := n.(*ast.ImportSpec); ok {
-			if imp.Name != nil && imp.Name.Name == "_" {
-				result.Warnings = append(result.Warnings, ValidationWarning{
-					Type:    "unused_import",
-					Message: fmt.Sprintf("unused import: %s", imp.Path.Value),
-					File:    filePath,
-					Line:    fset.Position(imp.Pos()).Line,
-					Column:  fset.Position(imp.Pos()).Column,
-				})
-			}
-		}
+	pkgs, err := v.loadPackages(ctx, filepath.Dir(absPath), false, overlay)
+	if err != nil {
+		return nil, fmt.Errorf("file validation error: %w", err)
+	}
+	result.packages = packagePaths(pkgs)
 
-		// Check for syntax errors
-		if _, ok := n.(*ast.BadExpr); ok {
-			result.Errors = append(result.Errors, fmt.Sprintf("syntax error at %v", fset.Position(n.Pos())))
-		}
-		if _, ok := n.(*ast.BadStmt); ok {
-			result.Errors = append(result.Errors, fmt.Sprintf("syntax error at %v", fset.Position(n.Pos())))
-		}
-		if _, ok := n.(*ast.BadDecl); ok {
-			result.Errors = append(result.Errors, fmt.Sprintf("syntax error at %v", fset.Position(n.Pos())))
+	keep := func(name string, _ int) bool { return name == absPath }
+	if !containsFile(pkgs, absPath) {
+		// The file is not part of a buildable package, e.g. because of
+		// build constraints, so only its syntax can be checked
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, absPath, src, parser.ParseComments)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("parse error: %v", err))
+			finishResult(result, level)
+			v.cache.SetValidation(key, result)
+			v.observeValidation(result)
+			return result, nil
 		}
-
-		return true
-	})
+		inspectSyntax(fset, file, result)
+	} else {
+		v.checkPackages(ctx, pkgs, keep, result)
+	}
+	finishResult(result, level)
+	v.cache.SetValidation(key, result, DependsOn(loadedSources(filepath.Dir(absPath), pkgs)...))
+	v.observeValidation(result)
 
 	return result, nil
 }
 
-// ValidatePackage validates a Go package
-func (v *DefaultValidator) ValidatePackage(ctx context.Context, pkgPath string) (*ValidationResult, error) {
+// ValidatePackage validates a Go package at the given level
+func (v *DefaultValidator) ValidatePackage(ctx context.Context, pkgPath string, level ValidationLevel) (*ValidationResult, error) {
 	if pkgPath == "" {
 		return nil, fmt.Errorf("empty package path")
 	}
+	if !level.valid() {
+		return nil, fmt.Errorf("%w: unknown validation level %d", ErrInvalidInput, int(level))
+	}
 
-	absPath := filepath.Join(v.baseDir, pkgPath)
+	absPath, err := filepath.Abs(filepath.Join(v.baseDir, pkgPath))
+	if err != nil {
+		return nil, fmt.Errorf("package access error: %w", err)
+	}
 	if _, err := os.Stat(absPath); err != nil {
 		return nil, fmt.Errorf("package access error: %w", err)
 	}
 
+	key := v.validationKey("package:"+filepath.ToSlash(pkgPath), level, absPath, false, nil)
+	if cached, ok := v.lookupValidation(ctx, key); ok {
+		return cached, nil
+	}
+
 	result := &ValidationResult{
-		Name:       filepath.Base(pkgPath),
-		Path:       pkgPath,
-		StartTime:  time.Now().Format(time.RFC3339),
-		AnalyzedAt: time.Now(),
+		Name:          filepath.Base(pkgPath),
+		Path:          pkgPath,
+		StartTime:     time.Now().Format(time.RFC3339),
+		AnalyzedAt:    time.Now(),
+		SchemaVersion: SchemaVersion,
+		ToolVersion:   Version(),
+		Level:         level,
 	}
 
-	// Parse package files
-	fset := token.NewFileSet()
-	pkgs, err := parser.ParseDir(fset, absPath, nil, parser.ParseComments)
+	pkgs, err := v.loadPackages(ctx, absPath, false, nil)
 	if err != nil {
-		result.Errors = append(result.Errors, fmt.Sprintf("parse error: %v", err))
-		return result, nil
+		return nil, fmt.Errorf("package validation error: %w", err)
 	}
+	result.packages = packagePaths(pkgs)
+	v.checkPackages(ctx, pkgs, nil, result)
+	finishResult(result, level)
+	v.cache.SetValidation(key, result, DependsOn(loadedSources(absPath, pkgs)...))
+	v.observeValidation(result)
 
-	for _, pkg := range pkgs {
-		for fileName, file := range pkg.Files {
-			// Check for basic syntax issues
-			ast.Inspect(file, func(n ast.Node) bool {
-				if n == nil {
-					return true
-				}
+	return result, nil
+}
 
-				// Check for unused imports
-				if imp, ok := n.(*ast.ImportSpec); ok {
-					if imp.Name != nil && imp.Name.Name == "_" {
-						result.Warnings = append(result.Warnings, ValidationWarning{
-							Type:    "unused_import",
-							Message: fmt.Sprintf("unused import: %s", imp.Path.Value),
-							File:    fileName,
-							Line:    fset.Position(imp.Pos()).Line,
-							Column:  fset.Position(imp.Pos()).Column,
-						})
-					}
-				}
+// ValidateProject validates the entire project at the given level
+func (v *DefaultValidator) ValidateProject(ctx context.Context, level ValidationLevel) (*ValidationResult, error) {
+	if !level.valid() {
+		return nil, fmt.Errorf("%w: unknown validation level %d", ErrInvalidInput, int(level))
+	}
 
-				// Check for syntax errors
-				if _, ok := n.(*ast.BadExpr); ok {
-					result.Errors = append(result.Errors, fmt.Sprintf("syntax error at %v", fset.Position(n.Pos())))
-				}
-				if _, ok := n.(*ast.BadStmt); ok {
-					result.Errors = append(result.Errors, fmt.Sprintf("syntax error at %v", fset.Position(n.Pos())))
-				}
-				if _, ok := n.(*ast.BadDecl); ok {
-					result.Errors = append(result.Errors, fmt.Sprintf("syntax error at %v", fset.Position(n.Pos())))
-				}
+	absPath, err := filepath.Abs(v.baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("project validation error: %w", err)
+	}
+	key := v.validationKey("project", level, absPath, true, nil)
+	if cached, ok := v.lookupValidation(ctx, key); ok {
+		return cached, nil
+	}
 
-				return true
-			})
-		}
+	result := &ValidationResult{
+		Name:          filepath.Base(v.baseDir),
+		Path:          v.baseDir,
+		StartTime:     time.Now().Format(time.RFC3339),
+		AnalyzedAt:    time.Now(),
+		SchemaVersion: SchemaVersion,
+		ToolVersion:   Version(),
+		Level:         level,
 	}
 
+	pkgs, err := v.loadPackages(ctx, absPath, true, nil)
+	if err != nil {
+		return nil, fmt.Errorf("project validation error: %w", err)
+	}
+	result.packages = packagePaths(pkgs)
+	v.checkPackages(ctx, pkgs, nil, result)
+	finishResult(result, level)
+	v.cache.SetValidation(key, result)
+	v.observeValidation(result)
+
 	return result, nil
 }
 
-// ValidateProject validates the entire project
-func (v *DefaultValidator) ValidateProject(ctx context.Context) (*ValidationResult, error) {
-	result := &ValidationResult{
-		Name:       filepath.Base(v.baseDir),
-		Path:       v.baseDir,
-		StartTime:  time.Now().Format(time.RFC3339),
-		AnalyzedAt: time.Now(),
+// lookupValidation looks key up in the cache
+func (v *DefaultValidator) lookupValidation(ctx context.Context, key ValidationCacheKey) (*ValidationResult, bool) {
+	ctx, span := v.tracer.Start(ctx, "readgo.cache.lookup", trace.WithAttributes(
+		attribute.String("readgo.cache.kind", "validation"),
+		attribute.String("readgo.scope", key.Scope),
+	))
+	defer span.End()
+
+	cached, ok := v.cache.GetValidation(key)
+	if ok {
+		v.logger.Log(ctx, LevelTrace, "validation cache hit", "scope", key.Scope, "level", key.Level)
 	}
+	span.SetAttributes(cacheSpanAttributes(ok, "memory")...)
+	return cached, ok
+}
+
+// observeValidation reports a completed validation to the observer
+func (v *DefaultValidator) observeValidation(result *ValidationResult) {
+	if v.observer != nil {
+		v.observer.ValidationDone(result, time.Since(result.AnalyzedAt))
+	}
+}
+
+// validationKey returns the cache key of validating scope at level. The hash
+// covers the Go source and module files in dir, and below it if recursive is
+// set, with overlay replacing or adding files. Files and packages are keyed
+// on their own directory, and their results are stored depending on the
+// packages they import, which keeps single-file validation from reading the
+// whole project. The key has no hash when the sources cannot be read.
+func (v *DefaultValidator) validationKey(scope string, level ValidationLevel, dir string, recursive bool, overlay map[string][]byte) ValidationCacheKey {
+	key := ValidationCacheKey{Scope: scope, Level: level}
+	if v.cache == nil || v.cache.ttl <= 0 {
+		return key
+	}
+	key.Hash, _ = sourceHash(dir, recursive, overlay)
+	return key
+}
 
-	// Walk through all Go files in the project
-	err := filepath.Walk(v.baseDir, func(path string, info os.FileInfo, err error) error {
+// sourceHash returns a hash of every Go source and module file in base, and
+// below it if recursive is set, with overlay replacing or adding files.
+// Hidden, excluded and testdata directories are skipped.
+func sourceHash(base string, recursive bool, overlay map[string][]byte) (string, error) {
+	h := sha256.New()
+	add := func(path string, content []byte) {
+		rel, _ := filepath.Rel(base, path)
+		fmt.Fprintf(h, "%s %d\n", filepath.ToSlash(rel), len(content))
+		h.Write(content)
+	}
+	err := filepath.WalkDir(base, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != base && (!recursive || isHiddenDir(d.Name()) || isDefaultExcludedDir(d.Name()) || d.Name() == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if name := d.Name(); filepath.Ext(name) != ".go" && name != "go.mod" && name != "go.sum" {
+			return nil
+		}
+		if content, ok := overlay[path]; ok {
+			add(path, content)
+			return nil
+		}
+		content, err := os.ReadFile(path)
 		if err != nil {
 			return err
 		}
+		add(path, content)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
 
-		if !info.IsDir() && strings.HasSuffix(path, ".go") {
-			relPath, err := filepath.Rel(v.baseDir, path)
-			if err != nil {
-				return err
+	// Overlay files that do not exist on disk
+	var extra []string
+	for path := range overlay {
+		if _, err := os.Stat(path); err != nil {
+			extra = append(extra, path)
+		}
+	}
+	sort.Strings(extra)
+	for _, path := range extra {
+		add(path, overlay[path])
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// loadPackages loads the package in dir, or all packages below it when
+// recursive is set, including their tests. Directories outside a module are
+// loaded in GOPATH mode so that loose source trees can still be validated.
+// overlay maps absolute file paths to content used instead of the files on
+// disk.
+func (v *DefaultValidator) loadPackages(ctx context.Context, dir string, recursive bool, overlay map[string][]byte) ([]*packages.Package, error) {
+	pkgs, err := v.load(ctx, dir, recursive, validatorLoadMode, overlay)
+	if err != nil {
+		return nil, err
+	}
+	return validationPackages(pkgs), nil
+}
+
+// load loads the packages selected as by loadPackages with the given mode
+// and returns them as reported by packages.Load
+func (v *DefaultValidator) load(ctx context.Context, dir string, recursive bool, mode packages.LoadMode, overlay map[string][]byte) ([]*packages.Package, error) {
+	base, err := filepath.Abs(v.baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// GOPATH mode only resolves relative directory patterns
+	pattern := dir
+	if rel, err := filepath.Rel(base, dir); err == nil && !strings.HasPrefix(rel, "..") {
+		pattern = "./" + filepath.ToSlash(rel)
+	}
+	if recursive {
+		pattern = strings.TrimSuffix(pattern, "/.") + "/..."
+	}
+
+	module := "GO111MODULE=on"
+	if !insideModule(dir) {
+		module = "GO111MODULE=off"
+	}
+
+	cfg := &packages.Config{
+		Context: ctx,
+		Mode:    mode,
+		Dir:     base,
+		Env:     append(os.Environ(), module),
+		Tests:   true,
+		Overlay: overlay,
+	}
+	v.logger.DebugContext(ctx, "loading packages", "dir", base, "pattern", pattern, "env", module)
+	start := time.Now()
+	pkgs, err := loadTraced(v.tracer, v.observer, packages.Load, cfg, pattern)
+	if err != nil {
+		v.logger.DebugContext(ctx, "loading packages failed", "dir", base, "pattern", pattern, "error", err)
+		return nil, err
+	}
+	v.logger.DebugContext(ctx, "loaded packages", "dir", base, "pattern", pattern, "packages", len(pkgs), "duration", time.Since(start))
+	return pkgs, nil
+}
+
+// insideModule reports whether dir or one of its parents contains a go.mod
+func insideModule(dir string) bool {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
+}
+
+// packagePaths returns the import paths of pkgs
+func packagePaths(pkgs []*packages.Package) []string {
+	paths := make([]string, len(pkgs))
+	for i, pkg := range pkgs {
+		paths[i] = pkg.PkgPath
+	}
+	return paths
+}
+
+// validationPackages drops the packages that would otherwise be validated
+// twice when tests are loaded: synthesized test mains and packages whose
+// test variant, which covers the same files, is also present
+func validationPackages(pkgs []*packages.Package) []*packages.Package {
+	tested := make(map[string]bool)
+	for _, pkg := range pkgs {
+		if strings.Contains(pkg.ID, " [") {
+			tested[pkg.PkgPath] = true
+		}
+	}
+
+	var result []*packages.Package
+	for _, pkg := range pkgs {
+		if strings.HasSuffix(pkg.ID, ".test") {
+			continue
+		}
+		if !strings.Contains(pkg.ID, " [") && tested[pkg.PkgPath] {
+			continue
+		}
+		result = append(result, pkg)
+	}
+	return result
+}
+
+// containsFile reports whether any of the packages was built from file
+func containsFile(pkgs []*packages.Package, file string) bool {
+	for _, pkg := range pkgs {
+		for _, f := range pkg.CompiledGoFiles {
+			if f == file {
+				return true
 			}
+		}
+	}
+	return false
+}
+
+// checkPackages runs the built-in checks and all rules over pkgs and records
+// the findings in result. When keep is non-nil only findings at positions it
+// accepts are recorded; errors that concern a whole file are passed line 0.
+// Packages are checked concurrently, but findings are recorded in package
+// order. Checking stops when ctx is cancelled, leaving result partial and
+// marked as Cancelled. The recorded rule findings are also returned.
+func (v *DefaultValidator) checkPackages(ctx context.Context, pkgs []*packages.Package, keep func(file string, line int) bool, result *ValidationResult) []Finding {
+	ctx, span := v.tracer.Start(ctx, "readgo.validate", trace.WithAttributes(
+		attribute.String("readgo.level", result.Level.String()),
+		attribute.Int("readgo.packages", len(pkgs)),
+	))
+	defer span.End()
+
+	accept := func(file string, line int) bool {
+		return keep == nil || keep(file, line)
+	}
 
-			fileResult, err := v.ValidateFile(ctx, relPath)
-			if err != nil {
-				result.Errors = append(result.Errors, fmt.Sprintf("error validating %s: %v", relPath, err))
+	partial := make([]ValidationResult, len(pkgs))
+	found := make([][]Finding, len(pkgs))
+	var mu sync.Mutex
+	done := 0
+	var g errgroup.Group
+	g.SetLimit(v.concurrency())
+	for i, pkg := range pkgs {
+		g.Go(func() error {
+			if ctx.Err() != nil {
 				return nil
 			}
+			found[i] = v.checkPackage(ctx, pkg, accept, &partial[i])
+			if v.progress != nil {
+				mu.Lock()
+				defer mu.Unlock()
+				done++
+				v.progress(done, len(pkgs), pkg.PkgPath)
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
 
-			result.Errors = append(result.Errors, fileResult.Errors...)
-			result.Warnings = append(result.Warnings, fileResult.Warnings...)
+	var recorded []Finding
+	for i := range pkgs {
+		result.Errors = append(result.Errors, partial[i].Errors...)
+		result.Warnings = append(result.Warnings, partial[i].Warnings...)
+		result.Todos = append(result.Todos, partial[i].Todos...)
+		result.Stats.merge(partial[i].Stats)
+		recorded = append(recorded, found[i]...)
+	}
+	if ctx.Err() != nil {
+		result.Cancelled = true
+	}
+	span.SetAttributes(attribute.Int("readgo.findings", len(recorded)), attribute.Bool("readgo.cancelled", result.Cancelled))
+	return recorded
+}
+
+// checkPackage runs the built-in checks and all rules over a single package
+// and records the accepted findings in result
+func (v *DefaultValidator) checkPackage(ctx context.Context, pkg *packages.Package, accept func(file string, line int) bool, result *ValidationResult) []Finding {
+	ctx, span := v.tracer.Start(ctx, "readgo.checkPackage", trace.WithAttributes(attribute.String("readgo.package", pkg.ID)))
+	defer span.End()
+
+	var recorded []Finding
+	for _, e := range pkg.Errors {
+		if e.Kind == packages.TypeError || !accept(errorFile(e.Pos), 0) {
+			continue
 		}
+		result.Errors = append(result.Errors, fmt.Sprintf("parse error: %v", e))
+	}
 
-		return nil
-	})
+	nolint := nolintIndex{}
+	for _, file := range pkg.Syntax {
+		if ctx.Err() != nil {
+			break
+		}
+		nolint.addFile(pkg.Fset, file)
+		if accept(pkg.Fset.File(file.Pos()).Name(), 0) {
+			inspectSyntax(pkg.Fset, file, result)
+		}
+		if v.config.ruleEnabled(todoCheck) {
+			for _, todo := range collectTodos(pkg.Fset, file) {
+				if accept(todo.File, todo.Line) {
+					todo.File = v.relPath(todo.File)
+					result.Todos = append(result.Todos, todo)
+				}
+			}
+		}
+	}
 
+	for _, rule := range v.rules {
+		if ctx.Err() != nil {
+			break
+		}
+		if !v.config.ruleEnabled(rule.Name()) {
+			continue
+		}
+		for _, f := range rule.Check(ctx, pkg) {
+			if !accept(f.File, f.Line) {
+				continue
+			}
+			if f.Rule == "" {
+				f.Rule = rule.Name()
+			}
+			if f.Rule != rule.Name() && !v.config.ruleEnabled(f.Rule) {
+				continue
+			}
+			if nolint.suppressed(f.File, f.Line, f.Rule) {
+				result.Stats.suppress(f.Rule)
+				continue
+			}
+			v.addFinding(result, f)
+			recorded = append(recorded, f)
+		}
+	}
+	v.logger.Log(ctx, LevelTrace, "checked package", "package", pkg.ID, "findings", len(recorded))
+	span.SetAttributes(attribute.Int("readgo.findings", len(recorded)))
+	return recorded
+}
+
+// concurrency returns the number of packages checked at the same time
+func (v *DefaultValidator) concurrency() int {
+	if v.maxConcurrent > 0 {
+		return v.maxConcurrent
+	}
+	return runtime.NumCPU()
+}
+
+// addFinding records a finding in result with its configured severity
+func (v *DefaultValidator) addFinding(result *ValidationResult, f Finding) {
+	w := ValidationWarning{
+		Type:     f.Rule,
+		Severity: v.config.severity(f.Rule, f.Severity),
+		Message:  f.Message,
+		File:     v.relPath(f.File),
+		Line:     f.Line,
+		Column:   f.Column,
+	}
+	for _, fix := range f.SuggestedFixes {
+		edits := make([]TextEdit, len(fix.Edits))
+		for i, edit := range fix.Edits {
+			edit.File = v.relPath(edit.File)
+			edits[i] = edit
+		}
+		w.SuggestedFixes = append(w.SuggestedFixes, SuggestedFix{Message: fix.Message, Edits: edits})
+	}
+	if w.Severity == SeverityError {
+		result.Errors = append(result.Errors, warningError(w))
+		return
+	}
+	result.Warnings = append(result.Warnings, w)
+}
+
+// suppress counts a finding suppressed by a //nolint comment
+func (s *ValidationStats) suppress(check string) {
+	if s.SuppressedByRule == nil {
+		s.SuppressedByRule = make(map[string]int)
+	}
+	s.Suppressed++
+	s.SuppressedByRule[check]++
+}
+
+// cover records the coverage of pkg
+func (s *ValidationStats) cover(pkg string, percent float64) {
+	if s.Coverage == nil {
+		s.Coverage = make(map[string]float64)
+	}
+	s.Coverage[pkg] = percent
+}
+
+// merge adds the counts of other to s. Coverage of a package recorded in
+// both is taken from other.
+func (s *ValidationStats) merge(other ValidationStats) {
+	s.Suppressed += other.Suppressed
+	for check, n := range other.SuppressedByRule {
+		if s.SuppressedByRule == nil {
+			s.SuppressedByRule = make(map[string]int)
+		}
+		s.SuppressedByRule[check] += n
+	}
+	for pkg, percent := range other.Coverage {
+		s.cover(pkg, percent)
+	}
+}
+
+// relPath returns file relative to the validator's base directory when it
+// lies below it
+func (v *DefaultValidator) relPath(file string) string {
+	if !filepath.IsAbs(file) {
+		return file
+	}
+	base, err := filepath.Abs(v.baseDir)
 	if err != nil {
-		return nil, fmt.Errorf("project validation error: %w", err)
+		return file
+	}
+	rel, err := filepath.Rel(base, file)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return file
 	}
+	return rel
+}
 
-	return result, nil
+// errorFile returns the file name of a "file:line:col" error position
+func errorFile(pos string) string {
+	for i := 0; i < 2; i++ {
+		j := strings.LastIndex(pos, ":")
+		if j < 0 {
+			break
+		}
+		if _, err := strconv.Atoi(pos[j+1:]); err != nil {
+			break
+		}
+		pos = pos[:j]
+	}
+	return pos
+}
+
+// inspectSyntax reports the syntax errors left in a parsed file
+func inspectSyntax(fset *token.FileSet, file *ast.File, result *ValidationResult) {
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.BadExpr, *ast.BadStmt, *ast.BadDecl:
+			result.Errors = append(result.Errors, fmt.Sprintf("syntax error at %v", fset.Position(n.Pos())))
+		}
+		return true
+	})
+}
+
+// finishResult adjusts the findings in result to the validation level and
+// computes its Valid flag. Basic drops warnings and strict reports them as
+// errors; informational findings are kept at every level but basic.
+func finishResult(result *ValidationResult, level ValidationLevel) {
+	switch level {
+	case ValidationLevelBasic:
+		result.Warnings = nil
+	case ValidationLevelStrict:
+		var kept []ValidationWarning
+		for _, w := range result.Warnings {
+			if w.Severity == SeverityInfo {
+				kept = append(kept, w)
+				continue
+			}
+			result.Errors = append(result.Errors, warningError(w))
+		}
+		result.Warnings = kept
+	}
+	result.Valid = len(result.Errors) == 0 && !result.Cancelled
+}
+
+// covers reports whether r holds the findings of the package pkgPath
+func (r *ValidationResult) covers(pkgPath string) bool {
+	for _, path := range r.packages {
+		if path == pkgPath {
+			return true
+		}
+	}
+	return false
+}
+
+// clone returns a copy of r that shares no slices or maps with it
+func (r *ValidationResult) clone() *ValidationResult {
+	c := *r
+	c.packages = append([]string(nil), r.packages...)
+	c.Errors = append([]string(nil), r.Errors...)
+	c.CircularDeps = append([]Cycle(nil), r.CircularDeps...)
+	c.Todos = append([]TodoComment(nil), r.Todos...)
+	c.Warnings = make([]ValidationWarning, len(r.Warnings))
+	for i, w := range r.Warnings {
+		w.SuggestedFixes = append([]SuggestedFix(nil), w.SuggestedFixes...)
+		c.Warnings[i] = w
+	}
+	if r.Warnings == nil {
+		c.Warnings = nil
+	}
+	if r.Stats.SuppressedByRule != nil {
+		c.Stats.SuppressedByRule = make(map[string]int, len(r.Stats.SuppressedByRule))
+		for check, n := range r.Stats.SuppressedByRule {
+			c.Stats.SuppressedByRule[check] = n
+		}
+	}
+	if r.Stats.Coverage != nil {
+		c.Stats.Coverage = make(map[string]float64, len(r.Stats.Coverage))
+		for pkg, percent := range r.Stats.Coverage {
+			c.Stats.Coverage[pkg] = percent
+		}
+	}
+	return &c
+}
+
+// warningError formats a warning reported as an error, prefixed with its
+// position if it has one
+func warningError(w ValidationWarning) string {
+	if w.File == "" {
+		return w.Message
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", w.File, w.Line, w.Column, w.Message)
 }
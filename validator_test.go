@@ -2,9 +2,18 @@ package readgo
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"go/ast"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"golang.org/x/tools/go/packages"
 )
 
 func TestValidateFile(t *testing.T) {
@@ -69,7 +78,7 @@ func main() {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := validator.ValidateFile(context.Background(), tt.file)
+			result, err := validator.ValidateFile(context.Background(), tt.file, ValidationLevelStandard)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ValidateFile() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -144,7 +153,7 @@ type User struct {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := validator.ValidatePackage(context.Background(), tt.pkg)
+			result, err := validator.ValidatePackage(context.Background(), tt.pkg, ValidationLevelStandard)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ValidatePackage() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -201,7 +210,7 @@ type User struct {
 
 	validator := NewValidator(tmpDir)
 
-	result, err := validator.ValidateProject(context.Background())
+	result, err := validator.ValidateProject(context.Background(), ValidationLevelStandard)
 	if err != nil {
 		t.Errorf("ValidateProject() error = %v", err)
 		return
@@ -211,3 +220,509 @@ type User struct {
 		t.Errorf("ValidateProject() got errors = %v", result.Errors)
 	}
 }
+
+// Ensure DefaultValidator implements Validator
+var _ Validator = (*DefaultValidator)(nil)
+
+func TestValidationLevels(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := `package test
+
+import _ "embed"
+
+func main() {}
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "blank.go"), []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	validator := NewValidator(tmpDir)
+	tests := []struct {
+		level        ValidationLevel
+		wantErrors   int
+		wantWarnings int
+	}{
+		{ValidationLevelBasic, 0, 0},
+		{ValidationLevelStandard, 0, 1},
+		{ValidationLevelStrict, 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			result, err := validator.ValidateFile(context.Background(), "blank.go", tt.level)
+			if err != nil {
+				t.Fatalf("ValidateFile() error = %v", err)
+			}
+			if len(result.Errors) != tt.wantErrors {
+				t.Errorf("ValidateFile() errors = %v, want %d", result.Errors, tt.wantErrors)
+			}
+			if len(result.Warnings) != tt.wantWarnings {
+				t.Errorf("ValidateFile() warnings = %v, want %d", result.Warnings, tt.wantWarnings)
+			}
+			if result.Level != tt.level {
+				t.Errorf("ValidateFile() level = %v, want %v", result.Level, tt.level)
+			}
+		})
+	}
+
+	if _, err := validator.ValidateFile(context.Background(), "blank.go", ValidationLevel(42)); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("ValidateFile() with unknown level error = %v, want ErrInvalidInput", err)
+	}
+	if level, err := ParseValidationLevel("Strict"); err != nil || level != ValidationLevelStrict {
+		t.Errorf("ParseValidationLevel() = %v, %v, want strict", level, err)
+	}
+}
+
+// todoRule reports every function named todo
+type todoRule struct{}
+
+func (todoRule) Name() string { return "todo_func" }
+
+func (todoRule) Check(ctx context.Context, pkg *packages.Package) []Finding {
+	var findings []Finding
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Name.Name == "todo" {
+				pos := pkg.Fset.Position(fn.Pos())
+				findings = append(findings, Finding{
+					Message: "unfinished function",
+					File:    pos.Filename,
+					Line:    pos.Line,
+					Column:  pos.Column,
+				})
+			}
+		}
+	}
+	return findings
+}
+
+func TestRegisterRule(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFiles := map[string]string{
+		"pkg/a.go": "package pkg\n\nfunc todo() {}\n",
+		"pkg/b.go": "package pkg\n\nfunc done() {}\n",
+	}
+	for name, content := range testFiles {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	validator := NewValidator(tmpDir).WithConfig(&ValidatorConfig{
+		Rules: map[string]bool{"unused": false},
+	})
+	validator.RegisterRule(todoRule{})
+
+	result, err := validator.ValidatePackage(context.Background(), "pkg", ValidationLevelStandard)
+	if err != nil {
+		t.Fatalf("ValidatePackage() error = %v", err)
+	}
+	want := []ValidationWarning{{
+		Type:     "todo_func",
+		Severity: SeverityWarning,
+		Message:  "unfinished function",
+		File:     filepath.Join("pkg", "a.go"),
+		Line:     3,
+		Column:   1,
+	}}
+	if !reflect.DeepEqual(result.Warnings, want) {
+		t.Errorf("ValidatePackage() warnings = %+v, want %+v", result.Warnings, want)
+	}
+
+	result, err = validator.ValidateFile(context.Background(), filepath.Join("pkg", "b.go"), ValidationLevelStandard)
+	if err != nil {
+		t.Fatalf("ValidateFile() error = %v", err)
+	}
+	if len(result.Warnings) != 0 {
+		t.Errorf("ValidateFile() warnings = %+v, want none", result.Warnings)
+	}
+}
+
+// concurrencyRule records the highest number of packages checked at once
+type concurrencyRule struct {
+	active, peak atomic.Int32
+}
+
+func (*concurrencyRule) Name() string { return "concurrency_probe" }
+
+func (r *concurrencyRule) Check(ctx context.Context, pkg *packages.Package) []Finding {
+	n := r.active.Add(1)
+	defer r.active.Add(-1)
+	for {
+		peak := r.peak.Load()
+		if n <= peak || r.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+	return []Finding{{Message: "checked " + pkg.Name, File: pkg.GoFiles[0], Line: 1, Column: 1}}
+}
+
+func TestValidateProjectConcurrency(t *testing.T) {
+	tmpDir := t.TempDir()
+	for i := 0; i < 6; i++ {
+		name := fmt.Sprintf("pkg%d", i)
+		if err := os.MkdirAll(filepath.Join(tmpDir, name), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		content := fmt.Sprintf("package %s\n", name)
+		if err := os.WriteFile(filepath.Join(tmpDir, name, "a.go"), []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	var want []ValidationWarning
+	for _, limit := range []int{1, 2} {
+		rule := &concurrencyRule{}
+		validator := NewValidator(tmpDir, WithMaxConcurrentAnalysis(limit))
+		validator.RegisterRule(rule)
+
+		result, err := validator.ValidateProject(context.Background(), ValidationLevelStandard)
+		if err != nil {
+			t.Fatalf("ValidateProject() error = %v", err)
+		}
+		if peak := rule.peak.Load(); peak > int32(limit) {
+			t.Errorf("limit %d: %d packages checked at once", limit, peak)
+		}
+		if len(result.Warnings) != 6 {
+			t.Fatalf("limit %d: got %d warnings, want 6", limit, len(result.Warnings))
+		}
+
+		// Findings keep the package order whatever the concurrency
+		if want == nil {
+			want = result.Warnings
+		} else if !reflect.DeepEqual(result.Warnings, want) {
+			t.Errorf("limit %d: warnings = %+v, want %+v", limit, result.Warnings, want)
+		}
+	}
+}
+
+func TestValidateProjectProgress(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example.com/progress\n\ngo 1.22\n"), 0600); err != nil {
+		t.Fatalf("Failed to write go.mod: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		name := fmt.Sprintf("pkg%d", i)
+		if err := os.MkdirAll(filepath.Join(tmpDir, name), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		content := fmt.Sprintf("package %s\n", name)
+		if err := os.WriteFile(filepath.Join(tmpDir, name, "a.go"), []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	var done []int
+	var pkgs []string
+	progress := func(n, total int, currentPkg string) {
+		if total != 4 {
+			t.Errorf("progress total = %d, want 4", total)
+		}
+		done = append(done, n)
+		pkgs = append(pkgs, currentPkg)
+	}
+	validator := NewValidator(tmpDir, WithMaxConcurrentAnalysis(2), WithProgress(progress))
+	if _, err := validator.ValidateProject(context.Background(), ValidationLevelStandard); err != nil {
+		t.Fatalf("ValidateProject() error = %v", err)
+	}
+
+	if want := []int{1, 2, 3, 4}; !reflect.DeepEqual(done, want) {
+		t.Errorf("progress done = %v, want %v", done, want)
+	}
+	sort.Strings(pkgs)
+	want := []string{"example.com/progress/pkg0", "example.com/progress/pkg1", "example.com/progress/pkg2", "example.com/progress/pkg3"}
+	if !reflect.DeepEqual(pkgs, want) {
+		t.Errorf("progress packages = %v, want %v", pkgs, want)
+	}
+}
+
+// cancelRule cancels validation when it checks its first package
+type cancelRule struct {
+	cancel  context.CancelFunc
+	checked atomic.Int32
+}
+
+func (*cancelRule) Name() string { return "cancel_probe" }
+
+func (r *cancelRule) Check(ctx context.Context, pkg *packages.Package) []Finding {
+	r.checked.Add(1)
+	r.cancel()
+	return []Finding{{Message: "checked " + pkg.Name, File: pkg.GoFiles[0], Line: 1, Column: 1}}
+}
+
+func TestValidateProjectCancelled(t *testing.T) {
+	tmpDir := t.TempDir()
+	for i := 0; i < 4; i++ {
+		name := fmt.Sprintf("pkg%d", i)
+		if err := os.MkdirAll(filepath.Join(tmpDir, name), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		content := fmt.Sprintf("package %s\n", name)
+		if err := os.WriteFile(filepath.Join(tmpDir, name, "a.go"), []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rule := &cancelRule{cancel: cancel}
+	validator := NewValidator(tmpDir, WithConcurrentAnalysis(false), WithCacheTTL(time.Minute))
+	validator.RegisterRule(rule)
+
+	result, err := validator.ValidateProject(ctx, ValidationLevelStandard)
+	if err != nil {
+		t.Fatalf("ValidateProject() error = %v", err)
+	}
+	if !result.Cancelled || result.Valid {
+		t.Errorf("Cancelled = %v, Valid = %v, want a cancelled, invalid result", result.Cancelled, result.Valid)
+	}
+	if n := rule.checked.Load(); n != 1 {
+		t.Errorf("%d packages checked after cancellation, want 1", n)
+	}
+	if len(result.Warnings) != 1 {
+		t.Errorf("got %d warnings, want the 1 of the checked package", len(result.Warnings))
+	}
+
+	// Cancelled results are not cached
+	rule.cancel = func() {}
+	result, err = validator.ValidateProject(context.Background(), ValidationLevelStandard)
+	if err != nil {
+		t.Fatalf("ValidateProject() error = %v", err)
+	}
+	if result.Cancelled || len(result.Warnings) != 4 {
+		t.Errorf("Cancelled = %v with %d warnings, want a complete result with 4", result.Cancelled, len(result.Warnings))
+	}
+}
+
+func TestValidateFileContent(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFiles := map[string]string{
+		"go.mod":   "module example.com/overlay\n\ngo 1.20\n",
+		"pkg/a.go": "package pkg\n\nfunc helper() int { return 1 }\n",
+		"pkg/b.go": "package pkg\n\nfunc Value() int { return helper() }\n",
+	}
+	for name, content := range testFiles {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "edited file",
+			file:    "pkg/b.go",
+			content: "package pkg\n\nimport \"os\"\n\nfunc Value() int { return helper() }\n",
+			want:    []string{`unused_import: unused import: "os"`},
+		},
+		{
+			name:    "new file",
+			file:    "pkg/c.go",
+			content: "package pkg\n\nfunc Other() int { return helper() + 1 }\n",
+		},
+		{
+			name:    "syntax error",
+			file:    "pkg/b.go",
+			content: "package pkg\n\nfunc Value( {\n",
+			want:    []string{"error"},
+		},
+	}
+
+	validator := NewValidator(tmpDir)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := validator.ValidateFileContent(context.Background(), filepath.FromSlash(tt.file), []byte(tt.content), ValidationLevelStandard)
+			if err != nil {
+				t.Fatalf("ValidateFileContent() error = %v", err)
+			}
+
+			var got []string
+			for _, w := range result.Warnings {
+				got = append(got, w.Type+": "+w.Message)
+			}
+			if len(result.Errors) > 0 {
+				got = append(got, "error")
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateFileContent() findings = %q (errors %q), want %q", got, result.Errors, tt.want)
+			}
+		})
+	}
+
+	// The file on disk is unchanged
+	result, err := validator.ValidateFile(context.Background(), filepath.Join("pkg", "b.go"), ValidationLevelStandard)
+	if err != nil {
+		t.Fatalf("ValidateFile() error = %v", err)
+	}
+	if len(result.Errors) > 0 || len(result.Warnings) > 0 {
+		t.Errorf("ValidateFile() = %+v, want no findings", result)
+	}
+}
+
+func TestValidationCache(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "a.go")
+	if err := os.WriteFile(path, []byte("package a\n\nimport _ \"fmt\"\n"), 0600); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	ctx := context.Background()
+	validator := NewValidator(tmpDir)
+	hits := func() int64 {
+		return validator.GetCacheStats().Validations.Hits
+	}
+
+	first, err := validator.ValidateProject(ctx, ValidationLevelStandard)
+	if err != nil {
+		t.Fatalf("ValidateProject() error = %v", err)
+	}
+	first.Warnings[0].Message = "changed by caller"
+
+	second, err := validator.ValidateProject(ctx, ValidationLevelStandard)
+	if err != nil {
+		t.Fatalf("ValidateProject() error = %v", err)
+	}
+	if hits() != 1 {
+		t.Errorf("validation hits = %d, want 1", hits())
+	}
+	if second.Warnings[0].Message == "changed by caller" {
+		t.Error("cached result shares warnings with a returned result")
+	}
+
+	// Another level and changed content miss the cache
+	if _, err := validator.ValidateProject(ctx, ValidationLevelStrict); err != nil {
+		t.Fatalf("ValidateProject() error = %v", err)
+	}
+	if err := os.WriteFile(path, []byte("package a\n"), 0600); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	third, err := validator.ValidateProject(ctx, ValidationLevelStandard)
+	if err != nil {
+		t.Fatalf("ValidateProject() error = %v", err)
+	}
+	if hits() != 1 {
+		t.Errorf("validation hits = %d, want 1", hits())
+	}
+	if len(third.Warnings) != 0 {
+		t.Errorf("ValidateProject() warnings = %+v, want none after the change", third.Warnings)
+	}
+
+	// Caching is disabled without a TTL
+	uncached := NewValidator(tmpDir, WithCacheTTL(0))
+	for i := 0; i < 2; i++ {
+		if _, err := uncached.ValidateFile(ctx, "a.go", ValidationLevelStandard); err != nil {
+			t.Fatalf("ValidateFile() error = %v", err)
+		}
+	}
+	if stats := uncached.GetCacheStats(); stats.Enabled || stats.Validations.Entries != 0 {
+		t.Errorf("GetCacheStats() = %v, want caching disabled", stats)
+	}
+}
+
+func TestValidateFileCacheScope(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFiles := map[string]string{
+		"go.mod":         "module example.com/scope\n\ngo 1.20\n",
+		"app/app.go":     "package app\n\nimport \"example.com/scope/lib\"\n\nvar Name = lib.Name\n",
+		"lib/lib.go":     "package lib\n\nconst Name = \"lib\"\n",
+		"other/other.go": "package other\n",
+	}
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+	for name, content := range testFiles {
+		write(name, content)
+	}
+
+	ctx := context.Background()
+	validator := NewValidator(tmpDir)
+	validate := func() *ValidationResult {
+		t.Helper()
+		result, err := validator.ValidateFile(ctx, filepath.Join("app", "app.go"), ValidationLevelStandard)
+		if err != nil {
+			t.Fatalf("ValidateFile() error = %v", err)
+		}
+		return result
+	}
+	hits := func() int64 {
+		return validator.GetCacheStats().Validations.Hits
+	}
+
+	validate()
+	write("other/other.go", "package other\n\nconst Changed = true\n")
+	validate()
+	if hits() != 1 {
+		t.Errorf("validation hits after changing an unrelated package = %d, want 1", hits())
+	}
+
+	write("lib/lib.go", "package lib\n\nimport _ \"fmt\"\n\nconst Name = \"lib\"\n")
+	validate()
+	if hits() != 1 {
+		t.Errorf("validation hits after changing an imported package = %d, want 1", hits())
+	}
+}
+
+func TestCacheInvalidatePackageValidations(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFiles := map[string]string{
+		"go.mod":         "module example.com/inv\n\ngo 1.20\n",
+		"lib/lib.go":     "package lib\n\nconst Name = \"lib\"\n",
+		"other/other.go": "package other\n",
+	}
+	for name, content := range testFiles {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	ctx := context.Background()
+	validator := NewValidator(tmpDir)
+	validate := func() {
+		t.Helper()
+		if _, err := validator.ValidateFile(ctx, filepath.Join("lib", "lib.go"), ValidationLevelStandard); err != nil {
+			t.Fatalf("ValidateFile() error = %v", err)
+		}
+		if _, err := validator.ValidatePackage(ctx, "lib", ValidationLevelStandard); err != nil {
+			t.Fatalf("ValidatePackage() error = %v", err)
+		}
+		if _, err := validator.ValidatePackage(ctx, "other", ValidationLevelStandard); err != nil {
+			t.Fatalf("ValidatePackage() error = %v", err)
+		}
+		if _, err := validator.ValidateProject(ctx, ValidationLevelStandard); err != nil {
+			t.Fatalf("ValidateProject() error = %v", err)
+		}
+	}
+
+	validate()
+	if n := validator.cache.InvalidatePackage("example.com/inv/lib"); n != 3 {
+		t.Errorf("InvalidatePackage() = %d, want 3 entries dropped", n)
+	}
+	validate()
+	if stats := validator.GetCacheStats().Validations; stats.Hits != 1 || stats.Misses != 7 {
+		t.Errorf("validation hits = %d, misses = %d after InvalidatePackage, want 1 and 7", stats.Hits, stats.Misses)
+	}
+}
@@ -0,0 +1,47 @@
+package readgo
+
+import (
+	"reflect"
+	"runtime/debug"
+	"sync"
+)
+
+// develVersion is reported when the version of readgo cannot be determined,
+// e.g. in tests or builds from a checkout
+const develVersion = "devel"
+
+// Version returns the version of readgo in use, as recorded in the build
+// information of the binary: the required module version when readgo is a
+// dependency, or the version of the main module when readgo is built
+// itself. It returns "devel" for builds without a module version.
+func Version() string {
+	return buildVersion()
+}
+
+// buildVersion reads the version of readgo from the build information once
+var buildVersion = sync.OnceValue(func() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return develVersion
+	}
+	path := reflect.TypeOf(Cache{}).PkgPath()
+	var mod *debug.Module
+	if info.Main.Path == path {
+		mod = &info.Main
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == path {
+			mod = dep
+		}
+	}
+	if mod == nil {
+		return develVersion
+	}
+	if mod.Replace != nil {
+		mod = mod.Replace
+	}
+	if mod.Version == "" || mod.Version == "(devel)" {
+		return develVersion
+	}
+	return mod.Version
+})
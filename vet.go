@@ -0,0 +1,241 @@
+package readgo
+
+import (
+	"context"
+	"fmt"
+	"go/types"
+	"reflect"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/appends"
+	"golang.org/x/tools/go/analysis/passes/assign"
+	"golang.org/x/tools/go/analysis/passes/atomic"
+	"golang.org/x/tools/go/analysis/passes/bools"
+	"golang.org/x/tools/go/analysis/passes/composite"
+	"golang.org/x/tools/go/analysis/passes/copylock"
+	"golang.org/x/tools/go/analysis/passes/defers"
+	"golang.org/x/tools/go/analysis/passes/errorsas"
+	"golang.org/x/tools/go/analysis/passes/httpresponse"
+	"golang.org/x/tools/go/analysis/passes/ifaceassert"
+	"golang.org/x/tools/go/analysis/passes/loopclosure"
+	"golang.org/x/tools/go/analysis/passes/lostcancel"
+	"golang.org/x/tools/go/analysis/passes/nilfunc"
+	"golang.org/x/tools/go/analysis/passes/printf"
+	"golang.org/x/tools/go/analysis/passes/shift"
+	"golang.org/x/tools/go/analysis/passes/sigchanyzer"
+	"golang.org/x/tools/go/analysis/passes/slog"
+	"golang.org/x/tools/go/analysis/passes/stdmethods"
+	"golang.org/x/tools/go/analysis/passes/stringintconv"
+	"golang.org/x/tools/go/analysis/passes/structtag"
+	"golang.org/x/tools/go/analysis/passes/testinggoroutine"
+	"golang.org/x/tools/go/analysis/passes/tests"
+	"golang.org/x/tools/go/analysis/passes/timeformat"
+	"golang.org/x/tools/go/analysis/passes/unmarshal"
+	"golang.org/x/tools/go/analysis/passes/unreachable"
+	"golang.org/x/tools/go/analysis/passes/unsafeptr"
+	"golang.org/x/tools/go/analysis/passes/unusedresult"
+	"golang.org/x/tools/go/packages"
+)
+
+// VetAnalyzers returns the analyzers of go vet that work on Go source alone
+func VetAnalyzers() []*analysis.Analyzer {
+	return []*analysis.Analyzer{
+		appends.Analyzer,
+		assign.Analyzer,
+		atomic.Analyzer,
+		bools.Analyzer,
+		composite.Analyzer,
+		copylock.Analyzer,
+		defers.Analyzer,
+		errorsas.Analyzer,
+		httpresponse.Analyzer,
+		ifaceassert.Analyzer,
+		loopclosure.Analyzer,
+		lostcancel.Analyzer,
+		nilfunc.Analyzer,
+		printf.Analyzer,
+		shift.Analyzer,
+		sigchanyzer.Analyzer,
+		slog.Analyzer,
+		stdmethods.Analyzer,
+		stringintconv.Analyzer,
+		structtag.Analyzer,
+		testinggoroutine.Analyzer,
+		tests.Analyzer,
+		timeformat.Analyzer,
+		unmarshal.Analyzer,
+		unreachable.Analyzer,
+		unsafeptr.Analyzer,
+		unusedresult.Analyzer,
+	}
+}
+
+// AnalyzerRule adapts go/analysis analyzers to the Rule interface. Findings
+// are reported under the name of the analyzer that produced them, so each
+// analyzer can be configured and suppressed on its own.
+//
+// Facts are only shared between analyzers within a package, so analyzers
+// that rely on facts about dependencies, such as printf wrapper detection,
+// see less than they would under go vet.
+type AnalyzerRule struct {
+	name      string
+	analyzers []*analysis.Analyzer
+}
+
+// NewAnalyzerRule creates a rule that runs the given analyzers
+func NewAnalyzerRule(name string, analyzers ...*analysis.Analyzer) *AnalyzerRule {
+	return &AnalyzerRule{name: name, analyzers: analyzers}
+}
+
+// Name returns the rule name
+func (r *AnalyzerRule) Name() string {
+	return r.name
+}
+
+// Check runs the analyzers over a type-checked package
+func (r *AnalyzerRule) Check(ctx context.Context, pkg *packages.Package) []Finding {
+	if pkg.Types == nil || pkg.TypesInfo == nil || pkg.IllTyped {
+		return nil
+	}
+
+	run := &analysisRun{
+		pkg:     pkg,
+		results: make(map[*analysis.Analyzer]analysisResult),
+		facts:   make(map[factKey]analysis.Fact),
+	}
+	var findings []Finding
+	for _, a := range r.analyzers {
+		if ctx.Err() != nil {
+			break
+		}
+		res := run.exec(a)
+		if res.err != nil {
+			findings = append(findings, Finding{
+				Rule:    a.Name,
+				Message: fmt.Sprintf("analysis failed: %v", res.err),
+			})
+			continue
+		}
+		for _, d := range res.diagnostics {
+			pos := pkg.Fset.Position(d.Pos)
+			findings = append(findings, Finding{
+				Rule:    a.Name,
+				Message: d.Message,
+				File:    pos.Filename,
+				Line:    pos.Line,
+				Column:  pos.Column,
+			})
+		}
+	}
+	return findings
+}
+
+// analysisResult is the outcome of running one analyzer over a package
+type analysisResult struct {
+	result      interface{}
+	diagnostics []analysis.Diagnostic
+	err         error
+}
+
+// factKey identifies a fact by the object or package it describes and its type
+type factKey struct {
+	obj types.Object
+	pkg *types.Package
+	typ reflect.Type
+}
+
+// analysisRun runs analyzers and their requirements over a single package,
+// running each analyzer at most once
+type analysisRun struct {
+	pkg     *packages.Package
+	results map[*analysis.Analyzer]analysisResult
+	facts   map[factKey]analysis.Fact
+}
+
+// exec runs a and everything it requires
+func (r *analysisRun) exec(a *analysis.Analyzer) analysisResult {
+	if res, ok := r.results[a]; ok {
+		return res
+	}
+
+	resultOf := make(map[*analysis.Analyzer]interface{}, len(a.Requires))
+	for _, req := range a.Requires {
+		res := r.exec(req)
+		if res.err != nil {
+			res = analysisResult{err: fmt.Errorf("%s: %w", req.Name, res.err)}
+			r.results[a] = res
+			return res
+		}
+		resultOf[req] = res.result
+	}
+
+	var res analysisResult
+	pass := &analysis.Pass{
+		Analyzer:     a,
+		Fset:         r.pkg.Fset,
+		Files:        r.pkg.Syntax,
+		OtherFiles:   r.pkg.OtherFiles,
+		IgnoredFiles: r.pkg.IgnoredFiles,
+		Pkg:          r.pkg.Types,
+		TypesInfo:    r.pkg.TypesInfo,
+		TypesSizes:   r.pkg.TypesSizes,
+		ResultOf:     resultOf,
+		Report: func(d analysis.Diagnostic) {
+			res.diagnostics = append(res.diagnostics, d)
+		},
+		ImportObjectFact: func(obj types.Object, fact analysis.Fact) bool {
+			return r.importFact(factKey{obj: obj, typ: reflect.TypeOf(fact)}, fact)
+		},
+		ImportPackageFact: func(pkg *types.Package, fact analysis.Fact) bool {
+			return r.importFact(factKey{pkg: pkg, typ: reflect.TypeOf(fact)}, fact)
+		},
+		ExportObjectFact: func(obj types.Object, fact analysis.Fact) {
+			r.facts[factKey{obj: obj, typ: reflect.TypeOf(fact)}] = fact
+		},
+		ExportPackageFact: func(fact analysis.Fact) {
+			r.facts[factKey{pkg: r.pkg.Types, typ: reflect.TypeOf(fact)}] = fact
+		},
+		AllObjectFacts: func() []analysis.ObjectFact {
+			var facts []analysis.ObjectFact
+			for key, fact := range r.facts {
+				if key.obj != nil && hasFactType(a, key.typ) {
+					facts = append(facts, analysis.ObjectFact{Object: key.obj, Fact: fact})
+				}
+			}
+			return facts
+		},
+		AllPackageFacts: func() []analysis.PackageFact {
+			var facts []analysis.PackageFact
+			for key, fact := range r.facts {
+				if key.pkg != nil && hasFactType(a, key.typ) {
+					facts = append(facts, analysis.PackageFact{Package: key.pkg, Fact: fact})
+				}
+			}
+			return facts
+		},
+	}
+
+	res.result, res.err = a.Run(pass)
+	r.results[a] = res
+	return res
+}
+
+// importFact copies the stored fact for key into fact
+func (r *analysisRun) importFact(key factKey, fact analysis.Fact) bool {
+	stored, ok := r.facts[key]
+	if !ok {
+		return false
+	}
+	reflect.ValueOf(fact).Elem().Set(reflect.ValueOf(stored).Elem())
+	return true
+}
+
+// hasFactType reports whether a declares facts of type t
+func hasFactType(a *analysis.Analyzer, t reflect.Type) bool {
+	for _, f := range a.FactTypes {
+		if reflect.TypeOf(f) == t {
+			return true
+		}
+	}
+	return false
+}
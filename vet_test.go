@@ -0,0 +1,75 @@
+package readgo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVetAnalyzers(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := `package test
+
+import "fmt"
+
+type User struct {
+	Name string ` + "`json:name`" + `
+}
+
+func Greet(name string) {
+	fmt.Printf("hello %d\n", name)
+}
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "vet.go"), []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		config    *ValidatorConfig
+		wantTypes map[string]int
+	}{
+		{
+			name:      "all analyzers",
+			config:    &ValidatorConfig{Enable: []string{"vet"}},
+			wantTypes: map[string]int{"printf": 10, "structtag": 6},
+		},
+		{
+			name:      "printf disabled",
+			config:    &ValidatorConfig{Enable: []string{"vet"}, Rules: map[string]bool{"printf": false}},
+			wantTypes: map[string]int{"structtag": 6},
+		},
+		{
+			name:      "vet disabled",
+			config:    &ValidatorConfig{Enable: []string{"vet"}, Rules: map[string]bool{"vet": false}},
+			wantTypes: map[string]int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := NewValidator(tmpDir).WithConfig(tt.config)
+			result, err := validator.ValidateFile(context.Background(), "vet.go", ValidationLevelStandard)
+			if err != nil {
+				t.Fatalf("ValidateFile() error = %v", err)
+			}
+
+			got := make(map[string]int)
+			for _, w := range result.Warnings {
+				got[w.Type] = w.Line
+				if w.File != "vet.go" {
+					t.Errorf("warning %s file = %q, want vet.go", w.Type, w.File)
+				}
+			}
+			if len(got) != len(tt.wantTypes) {
+				t.Errorf("ValidateFile() warnings = %+v, want %v", result.Warnings, tt.wantTypes)
+			}
+			for typ, line := range tt.wantTypes {
+				if got[typ] != line {
+					t.Errorf("warning %s line = %d, want %d", typ, got[typ], line)
+				}
+			}
+		})
+	}
+}
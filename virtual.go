@@ -0,0 +1,240 @@
+package readgo
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// virtualFile is a file that does not live on the local file system,
+// such as an archive entry or a blob in a git revision
+type virtualFile struct {
+	path    string // slash-separated, relative to the reader root
+	size    int64
+	modTime time.Time
+	read    func() ([]byte, error)
+}
+
+// memoryFile creates a virtualFile backed by content held in memory
+func memoryFile(name string, content []byte, modTime time.Time) *virtualFile {
+	return &virtualFile{
+		path:    name,
+		size:    int64(len(content)),
+		modTime: modTime,
+		read: func() ([]byte, error) {
+			return content, nil
+		},
+	}
+}
+
+// virtualReader implements SourceReader over a fixed set of virtual files
+type virtualReader struct {
+	files map[string]*virtualFile
+}
+
+// newVirtualReader creates a virtualReader from the given files
+func newVirtualReader(files []*virtualFile) virtualReader {
+	index := make(map[string]*virtualFile, len(files))
+	for _, f := range files {
+		index[f.path] = f
+	}
+	return virtualReader{files: index}
+}
+
+// cleanVirtualPath normalizes a user supplied path to the slash-separated
+// form used as key. It returns "" for the root.
+func cleanVirtualPath(p string) string {
+	p = path.Clean("/" + filepath.ToSlash(p))
+	return strings.TrimPrefix(p, "/")
+}
+
+// ReadSourceFile reads a source file with the given options
+func (r *virtualReader) ReadSourceFile(ctx context.Context, filePath string, opts ReadOptions) ([]byte, error) {
+	if filePath == "" {
+		return nil, fmt.Errorf("empty path")
+	}
+
+	f, ok := r.files[cleanVirtualPath(filePath)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, filePath)
+	}
+
+	content, err := f.read()
+	if err != nil {
+		return nil, err
+	}
+	content, err = decodeContent(filePath, content, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return applyReadOptions(filePath, content, opts)
+}
+
+// GetFileTree returns the file tree starting from the given root
+func (r *virtualReader) GetFileTree(ctx context.Context, root string, opts TreeOptions) (*FileTreeNode, error) {
+	if root == "" {
+		root = "."
+	}
+	prefix := cleanVirtualPath(root)
+
+	tree := &FileTreeNode{
+		Name: path.Base("/" + prefix),
+		Path: root,
+		Type: "directory",
+	}
+	if prefix == "" {
+		tree.Name = "."
+	}
+
+	paths := make([]string, 0, len(r.files))
+	for p := range r.files {
+		if prefix == "" || strings.HasPrefix(p, prefix+"/") {
+			paths = append(paths, p)
+		}
+	}
+	if len(paths) == 0 && prefix != "" {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, root)
+	}
+	sort.Strings(paths)
+
+	dirs := map[string]*FileTreeNode{prefix: tree}
+	files := 0
+	for _, p := range paths {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		treePath := strings.TrimPrefix(strings.TrimPrefix(p, prefix), "/")
+		if matchAnyPattern(opts.ExcludePatterns, treePath) || excludedDir(opts, treePath) {
+			continue
+		}
+		if len(opts.IncludePatterns) > 0 && !matchAnyPattern(opts.IncludePatterns, treePath) {
+			continue
+		}
+		if !matchesFileType(opts.FileTypes, p, func() bool {
+			content, err := r.files[p].read()
+			return err == nil && isGeneratedFile(content)
+		}) {
+			continue
+		}
+
+		if opts.Limit > 0 && len(dirs)-1+files >= opts.Limit {
+			tree.Truncated = true
+			break
+		}
+
+		f := r.files[p]
+		node := &FileTreeNode{
+			Name:    path.Base(p),
+			Path:    filepath.FromSlash(p),
+			Type:    "file",
+			Size:    f.size,
+			ModTime: f.modTime,
+		}
+		if opts.ComputeHashes || opts.SkipBinary || opts.DetectBinary || opts.PackageNames {
+			content, err := f.read()
+			if err != nil {
+				return nil, err
+			}
+			binary := binaryExtensions[strings.ToLower(path.Ext(p))] || isBinaryContent(content)
+			if binary && opts.SkipBinary {
+				continue
+			}
+			if opts.DetectBinary {
+				node.IsBinary = binary
+			}
+			if opts.ComputeHashes {
+				node.Hash = hashBytes(content)
+			}
+			if opts.PackageNames && path.Ext(p) == ".go" {
+				node.Package = parsePackageName(p, content)
+			}
+		}
+
+		parent := r.ensureDir(dirs, path.Dir(p))
+		parent.Children = append(parent.Children, node)
+		files++
+	}
+
+	finishTree(tree)
+	return tree, nil
+}
+
+// ensureDir returns the directory node for dir, creating it and any missing
+// ancestors below the tree root
+func (r *virtualReader) ensureDir(dirs map[string]*FileTreeNode, dir string) *FileTreeNode {
+	if dir == "." {
+		dir = ""
+	}
+	if node, ok := dirs[dir]; ok {
+		return node
+	}
+
+	node := &FileTreeNode{
+		Name: path.Base(dir),
+		Path: filepath.FromSlash(dir),
+		Type: "directory",
+	}
+	dirs[dir] = node
+
+	parent := r.ensureDir(dirs, path.Dir(dir))
+	parent.Children = append(parent.Children, node)
+	return node
+}
+
+// excludedDir reports whether any directory containing treePath is excluded,
+// either by default or by the exclude patterns, mirroring the directory
+// skipping of a file system walk
+func excludedDir(opts TreeOptions, treePath string) bool {
+	for dir := path.Dir(treePath); dir != "."; dir = path.Dir(dir) {
+		if !opts.NoDefaultExcludes && isDefaultExcludedDir(path.Base(dir)) {
+			return true
+		}
+		if matchAnyPattern(opts.ExcludePatterns, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPackageFiles returns all files in a package
+func (r *virtualReader) GetPackageFiles(ctx context.Context, pkgPath string, opts TreeOptions) ([]*FileTreeNode, error) {
+	if opts.LocalImports {
+		return nil, fmt.Errorf("%w: LocalImports requires a file system reader", ErrInvalidInput)
+	}
+
+	tree, err := r.GetFileTree(ctx, pkgPath, opts)
+	if err != nil {
+		return nil, err
+	}
+	return collectFiles(tree), nil
+}
+
+// SearchFiles searches for files matching the given pattern.
+// opts.Offset and opts.Limit select a page of the matches.
+func (r *virtualReader) SearchFiles(ctx context.Context, pattern string, opts TreeOptions) ([]*FileTreeNode, error) {
+	page, err := r.SearchFilesPage(ctx, pattern, opts)
+	if err != nil {
+		return nil, err
+	}
+	return page.Files, nil
+}
+
+// SearchFilesPage searches for files matching the given pattern and returns
+// the page selected by opts.Offset and opts.Limit
+func (r *virtualReader) SearchFilesPage(ctx context.Context, pattern string, opts TreeOptions) (*FilePage, error) {
+	if pattern == "" {
+		return nil, ErrInvalidInput
+	}
+
+	tree, err := r.GetFileTree(ctx, ".", opts.unpaged())
+	if err != nil {
+		return nil, err
+	}
+	return pageFiles(searchTree(tree, pattern), opts.Offset, opts.Limit), nil
+}